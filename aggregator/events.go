@@ -1,14 +1,18 @@
 package aggregator
 
 import (
+	"app/base"
 	"app/base/database"
 	"app/base/mqueue"
+	"app/base/tracing"
 	"app/base/utils"
+	"context"
 	"sync"
 	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -17,6 +21,8 @@ var (
 	advisoryBuffer []mqueue.AdvisoryUpdateEvent
 	bufferLock     sync.Mutex
 	flushTimer     *time.Timer
+	// traces of buffered events, linked from the flush span
+	advisoryLinks []trace.Link
 )
 
 func initBuffer() {
@@ -37,10 +43,13 @@ func flushAdvisoryBuffer() {
 	batch := make([]mqueue.AdvisoryUpdateEvent, len(advisoryBuffer))
 	copy(batch, advisoryBuffer)
 	advisoryBuffer = advisoryBuffer[:0]
+	ctx, span := tracing.Start(base.Context, "aggregator flush advisory updates", trace.WithLinks(advisoryLinks...))
+	advisoryLinks = advisoryLinks[:0]
 	bufferLock.Unlock()
+	defer span.End()
 
 	grouped := groupAdvisoryUpdates(batch)
-	processAdvisoryBatch(grouped)
+	processAdvisoryBatch(ctx, grouped)
 }
 
 func advisoryUpdateHandler(m mqueue.KafkaMessage) error {
@@ -52,6 +61,9 @@ func advisoryUpdateHandler(m mqueue.KafkaMessage) error {
 
 	bufferLock.Lock()
 	advisoryBuffer = append(advisoryBuffer, event)
+	if link, ok := tracing.Link(m.Context()); ok {
+		advisoryLinks = append(advisoryLinks, link)
+	}
 	flushTimer.Reset(flushTimeout)
 	shouldFlush := len(advisoryBuffer) >= batchSize
 	bufferLock.Unlock()
@@ -85,10 +97,10 @@ func groupAdvisoryUpdates(events []mqueue.AdvisoryUpdateEvent) map[int][]int64 {
 	return grouped
 }
 
-func processAdvisoryBatch(grouped map[int][]int64) {
+func processAdvisoryBatch(ctx context.Context, grouped map[int][]int64) {
 	for rhAccountID, advisoryIDs := range grouped {
 		utils.LogInfo("rh_account_id", rhAccountID, "advisory_count", len(advisoryIDs), "refreshing account advisory caches")
-		err := database.DB.Exec("SELECT refresh_account_advisory_caches_multi(?, ?)", pq.Array(advisoryIDs), rhAccountID).Error //nolint:lll
+		err := database.DB.WithContext(ctx).Exec("SELECT refresh_account_advisory_caches_multi(?, ?)", pq.Array(advisoryIDs), rhAccountID).Error //nolint:lll
 		if err != nil {
 			utils.LogError("err", err, "rh_account_id", rhAccountID, "failed to refresh account advisory caches")
 			continue
@@ -96,7 +108,7 @@ func processAdvisoryBatch(grouped map[int][]int64) {
 
 		CheckAdvisoryDrift(rhAccountID, advisoryIDs)
 
-		if err := publishNewAdvisoryNotification(rhAccountID, advisoryIDs); err != nil {
+		if err := publishNewAdvisoryNotification(ctx, rhAccountID, advisoryIDs); err != nil {
 			utils.LogError("err", err, "rh_account_id", rhAccountID, "failed to publish new advisory notification")
 		}
 	}
@@ -1,11 +1,11 @@
 package aggregator
 
 import (
-	"app/base"
 	"app/base/database"
 	"app/base/mqueue"
 	ntf "app/base/notification"
 	"app/base/utils"
+	"context"
 	"time"
 
 	"gorm.io/gorm"
@@ -32,12 +32,12 @@ func getUnnotifiedAdvisories(tx *gorm.DB, rhAccountID int, advisoryIDs []int64)
 	return advisories, err
 }
 
-func publishNewAdvisoryNotification(rhAccountID int, advisoryIDs []int64) error {
+func publishNewAdvisoryNotification(ctx context.Context, rhAccountID int, advisoryIDs []int64) error {
 	if notificationsPublisher == nil || !enableNotifications {
 		return nil
 	}
 
-	tx := database.DB.WithContext(base.Context).Begin()
+	tx := database.DB.WithContext(ctx).Begin()
 	defer tx.Rollback() //nolint:errcheck
 
 	advisories, err := getUnnotifiedAdvisories(tx, rhAccountID, advisoryIDs)
@@ -69,7 +69,7 @@ func publishNewAdvisoryNotification(rhAccountID int, advisoryIDs []int64) error
 		return err
 	}
 
-	err = notificationsPublisher.WriteMessages(base.Context, msg)
+	err = notificationsPublisher.WriteMessages(ctx, msg)
 	if err != nil {
 		return err
 	}
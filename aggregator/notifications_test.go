@@ -34,7 +34,7 @@ func TestPublishNewAdvisoryNotificationSkipsAlreadyNotified(t *testing.T) {
 		Update("notified", "2026-01-01").Error)
 	defer database.DeleteAccountAdvisoryByAccount(t, 1)
 
-	err := publishNewAdvisoryNotification(1, advisoryIDs)
+	err := publishNewAdvisoryNotification(t.Context(), 1, advisoryIDs)
 	assert.NoError(t, err)
 	assert.Empty(t, mockWriter.Messages)
 }
@@ -58,7 +58,7 @@ func TestPublishNewAdvisoryNotificationSuccess(t *testing.T) {
 	// Advisory IDs 1-8 exist for rh_account_id=1 in test data
 	advisoryIDs := []int64{1, 2}
 
-	err := publishNewAdvisoryNotification(1, advisoryIDs)
+	err := publishNewAdvisoryNotification(t.Context(), 1, advisoryIDs)
 	assert.NoError(t, err)
 
 	assert.Equal(t, 1, len(mockWriter.Messages))
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"app/base/tracing"
 	"app/base/utils"
 	"bytes"
 	"context"
@@ -9,12 +10,17 @@ import (
 
 	"github.com/bytedance/sonic"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Client struct {
 	HTTPClient     *http.Client
 	Debug          bool
 	DefaultHeaders map[string]string
+	// Upstream adds timeout, circuit breaker and retry budget of the called service, optional
+	Upstream *Upstream
 }
 
 func (o *Client) Request(ctx *context.Context, method, url string,
@@ -27,28 +33,60 @@ func (o *Client) Request(ctx *context.Context, method, url string,
 		}
 	}
 
-	httpReq, err := http.NewRequestWithContext(*ctx, method, url, body)
-	if err != nil {
-		return nil, errors.Wrap(err, "Request failed")
+	send := func(ctx context.Context) (_ *http.Response, err error) {
+		ctx, span := o.startSpan(ctx, method, url)
+		defer func() { tracing.End(span, err) }()
+		httpReq, err := http.NewRequestWithContext(ctx, method, url, body)
+		if err != nil {
+			return nil, errors.Wrap(err, "Request failed")
+		}
+		httpReq.Header.Add("Content-Type", "application/json")
+		httpReq.Header.Set(RequestIDHeader, requestID(ctx))
+		tracing.Inject(ctx, propagation.HeaderCarrier(httpReq.Header))
+		addHeaders(httpReq, o.DefaultHeaders)
+
+		httpResp, err := utils.CallAPI(o.HTTPClient, httpReq, o.Debug)
+		if httpResp != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", httpResp.StatusCode))
+		}
+		if err != nil {
+			return httpResp, errors.Wrap(err, "Request failed")
+		}
+
+		err = sonic.ConfigDefault.NewDecoder(httpResp.Body).Decode(responseOutPtr)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				// empty response body
+				return httpResp, nil
+			}
+			return httpResp, errors.Wrap(err, "Response body reading failed")
+		}
+		return httpResp, nil
 	}
-	httpReq.Header.Add("Content-Type", "application/json")
-	addHeaders(httpReq, o.DefaultHeaders)
 
-	httpResp, err := utils.CallAPI(o.HTTPClient, httpReq, o.Debug)
-	if err != nil {
-		return httpResp, errors.Wrap(err, "Request failed")
+	if o.Upstream == nil {
+		return send(*ctx)
 	}
+	return o.Upstream.do(*ctx, send)
+}
 
-	err = sonic.ConfigDefault.NewDecoder(httpResp.Body).Decode(responseOutPtr)
-	if err != nil {
-		if errors.Is(err, io.EOF) {
-			// empty response body
-			return httpResp, nil
-		}
-		return httpResp, errors.Wrap(err, "Response body reading failed")
+// CallRetry calls `httpCallFun` the same way as utils.HTTPCallRetry,
+// retries are limited by upstream retry budget and stopped when its circuit breaker opens
+func (o *Client) CallRetry(httpCallFun func() (interface{}, *http.Response, error),
+	exponentialRetry bool, maxRetries int, codesToRetry ...int) (interface{}, error) {
+	if o == nil || o.Upstream == nil {
+		return utils.HTTPCallRetry(httpCallFun, exponentialRetry, maxRetries, codesToRetry...)
 	}
+	return o.Upstream.callRetry(httpCallFun, exponentialRetry, maxRetries, codesToRetry...)
+}
 
-	return httpResp, nil
+func (o *Client) startSpan(ctx context.Context, method, url string) (context.Context, trace.Span) {
+	name := "HTTP " + method
+	if o.Upstream != nil {
+		name = o.Upstream.Name + " " + name
+	}
+	return tracing.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("http.request.method", method), attribute.String("url.full", url)))
 }
 
 func addHeaders(request *http.Request, headersMap map[string]string) {
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	upstreamRequestCnt = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Help:      "How many requests were sent to upstream service, by result",
+		Namespace: "patchman_engine",
+		Subsystem: "upstream",
+		Name:      "requests",
+	}, []string{"service", "result"})
+
+	upstreamRetryCnt = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Help:      "How many retries of upstream requests were allowed or denied by retry budget",
+		Namespace: "patchman_engine",
+		Subsystem: "upstream",
+		Name:      "retries",
+	}, []string{"service", "result"})
+
+	upstreamRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Help:      "Upstream service request duration",
+		Namespace: "patchman_engine",
+		Subsystem: "upstream",
+		Name:      "request_duration_seconds",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"service"})
+
+	upstreamBreakerState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Help:      "Circuit breaker state of upstream service (0 = closed, 1 = half-open, 2 = open)",
+		Namespace: "patchman_engine",
+		Subsystem: "upstream",
+		Name:      "circuit_breaker_state",
+	}, []string{"service"})
+)
+
+func init() {
+	prometheus.MustRegister(upstreamRequestCnt, upstreamRetryCnt, upstreamRequestDuration, upstreamBreakerState)
+}
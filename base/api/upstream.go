@@ -0,0 +1,201 @@
+package api
+
+import (
+	"app/base/utils"
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	UpstreamVmaas          = "vmaas"
+	UpstreamCandlepin      = "candlepin"
+	UpstreamRbac           = "rbac"
+	UpstreamContentSources = "content_sources"
+)
+
+// RequestIDHeader propagates request id of the processed upload or API request to upstream services
+const RequestIDHeader = "X-Rh-Insights-Request-Id"
+
+var (
+	ErrCircuitOpen          = errors.Wrap(utils.ErrNoRetry, "circuit breaker open")
+	ErrRetryBudgetExhausted = errors.Wrap(utils.ErrNoRetry, "retry budget exhausted")
+)
+
+var upstreamTimeoutDefaults = map[string]int{
+	UpstreamVmaas:          120,
+	UpstreamCandlepin:      30,
+	UpstreamRbac:           10,
+	UpstreamContentSources: 30,
+}
+
+var (
+	upstreams     = map[string]*Upstream{}
+	upstreamsLock sync.Mutex
+)
+
+// Upstream holds settings and state shared by all clients of an upstream service within the process
+type Upstream struct {
+	Name    string
+	Timeout time.Duration
+	breaker *circuitBreaker
+	budget  *retryBudget
+}
+
+type UpstreamStatus struct {
+	Name       string `json:"name"`
+	State      string `json:"state"`
+	Failures   int    `json:"failures"`
+	TimeoutSec int    `json:"timeout_sec"`
+}
+
+// GetUpstream returns upstream configured by `upstream_<name>_*` pod config options
+func GetUpstream(name string) *Upstream {
+	upstreamsLock.Lock()
+	defer upstreamsLock.Unlock()
+
+	if u, ok := upstreams[name]; ok {
+		return u
+	}
+	prefix := "upstream_" + name
+	timeoutSec := utils.PodConfig.GetInt(prefix+"_timeout_sec", upstreamTimeoutDefaults[name])
+	u := &Upstream{
+		Name:    name,
+		Timeout: time.Duration(timeoutSec) * time.Second,
+		breaker: &circuitBreaker{
+			threshold: utils.PodConfig.GetInt(prefix+"_breaker_threshold", 5),
+			cooldown:  time.Duration(utils.PodConfig.GetInt(prefix+"_breaker_cooldown_sec", 30)) * time.Second,
+			onChange: func(state BreakerState) {
+				upstreamBreakerState.WithLabelValues(name).Set(float64(state))
+				utils.LogWarn("service", name, "state", state.String(), "Upstream circuit breaker state changed")
+			},
+		},
+		budget: &retryBudget{
+			percent:    utils.PodConfig.GetInt(prefix+"_retry_budget_pct", 20),
+			minRetries: utils.PodConfig.GetInt(prefix+"_retry_budget_min", 10),
+			window:     time.Minute,
+		},
+	}
+	upstreamBreakerState.WithLabelValues(name).Set(float64(BreakerClosed))
+	upstreams[name] = u
+	return u
+}
+
+// UpstreamsStatus returns circuit breaker state of all upstreams used by the process
+func UpstreamsStatus() []UpstreamStatus {
+	upstreamsLock.Lock()
+	defer upstreamsLock.Unlock()
+
+	res := make([]UpstreamStatus, 0, len(upstreams))
+	for _, u := range upstreams {
+		state, failures := u.breaker.status()
+		res = append(res, UpstreamStatus{
+			Name:       u.Name,
+			State:      state.String(),
+			Failures:   failures,
+			TimeoutSec: int(u.Timeout.Seconds()),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+func (u *Upstream) do(ctx context.Context, send func(context.Context) (*http.Response, error)) (*http.Response, error) {
+	if !u.breaker.allow() {
+		upstreamRequestCnt.WithLabelValues(u.Name, "rejected").Inc()
+		return nil, errors.Wrap(ErrCircuitOpen, u.Name)
+	}
+	if u.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, u.Timeout)
+		defer cancel()
+	}
+
+	defer utils.ObserveSecondsSince(time.Now(), upstreamRequestDuration.WithLabelValues(u.Name))
+	resp, err := send(ctx)
+	// client errors mean the service is up and responding
+	success := err == nil || (resp != nil && resp.StatusCode < http.StatusInternalServerError)
+	u.breaker.record(success)
+	if err != nil {
+		upstreamRequestCnt.WithLabelValues(u.Name, "error").Inc()
+	} else {
+		upstreamRequestCnt.WithLabelValues(u.Name, "success").Inc()
+	}
+	return resp, err
+}
+
+func (u *Upstream) callRetry(httpCallFun func() (interface{}, *http.Response, error),
+	exponentialRetry bool, maxRetries int, codesToRetry ...int) (interface{}, error) {
+	attempt := 0
+	budgetedCallFun := func() (interface{}, *http.Response, error) {
+		attempt++
+		if attempt > 1 {
+			if !u.budget.allowRetry() {
+				upstreamRetryCnt.WithLabelValues(u.Name, "denied").Inc()
+				return nil, nil, errors.Wrap(ErrRetryBudgetExhausted, u.Name)
+			}
+			upstreamRetryCnt.WithLabelValues(u.Name, "allowed").Inc()
+		} else {
+			u.budget.request()
+		}
+		return httpCallFun()
+	}
+	return utils.HTTPCallRetry(budgetedCallFun, exponentialRetry, maxRetries, codesToRetry...)
+}
+
+// retryBudget allows retries up to `percent` of requests sent within `window`, but at least `minRetries`,
+// so an unavailable service is not flooded by retries of all callers
+type retryBudget struct {
+	lock       sync.Mutex
+	percent    int
+	minRetries int
+	window     time.Duration
+	start      time.Time
+	requests   int
+	retries    int
+}
+
+func (b *retryBudget) rotate() {
+	if time.Since(b.start) >= b.window {
+		b.start = time.Now()
+		b.requests = 0
+		b.retries = 0
+	}
+}
+
+func (b *retryBudget) request() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.rotate()
+	b.requests++
+}
+
+func (b *retryBudget) allowRetry() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.rotate()
+	if b.retries >= max(b.minRetries, b.requests*b.percent/100) {
+		return false
+	}
+	b.retries++
+	return true
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID sets request id sent to upstream services in RequestIDHeader
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func requestID(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok && id != "" {
+		return id
+	}
+	return uuid.NewString()
+}
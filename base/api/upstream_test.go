@@ -0,0 +1,102 @@
+package api
+
+import (
+	"app/base/utils"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	b := circuitBreaker{threshold: 2, cooldown: 50 * time.Millisecond}
+	assert.True(t, b.allow())
+	b.record(false)
+	assert.True(t, b.allow())
+	b.record(false)
+	state, failures := b.status()
+	assert.Equal(t, BreakerOpen, state)
+	assert.Equal(t, 2, failures)
+	assert.False(t, b.allow())
+
+	time.Sleep(60 * time.Millisecond)
+	// single probe is allowed after cooldown
+	assert.True(t, b.allow())
+	assert.False(t, b.allow())
+	b.record(false)
+	state, _ = b.status()
+	assert.Equal(t, BreakerOpen, state)
+
+	time.Sleep(60 * time.Millisecond)
+	assert.True(t, b.allow())
+	b.record(true)
+	state, failures = b.status()
+	assert.Equal(t, BreakerClosed, state)
+	assert.Equal(t, 0, failures)
+}
+
+func TestRetryBudget(t *testing.T) {
+	b := retryBudget{percent: 50, minRetries: 1, window: time.Minute}
+	b.request()
+	assert.True(t, b.allowRetry())
+	assert.False(t, b.allowRetry())
+	for i := 0; i < 4; i++ {
+		b.request()
+	}
+	// 5 requests allow 2 retries
+	assert.True(t, b.allowRetry())
+	assert.False(t, b.allowRetry())
+}
+
+func TestUpstreamClient(t *testing.T) {
+	var calls atomic.Int32
+	var lock sync.Mutex
+	var requestIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		lock.Lock()
+		defer lock.Unlock()
+		requestIDs = append(requestIDs, r.Header.Get(RequestIDHeader))
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	upstream := &Upstream{
+		Name:    "test",
+		Timeout: time.Second,
+		breaker: &circuitBreaker{threshold: 2, cooldown: time.Minute},
+		budget:  &retryBudget{percent: 0, minRetries: 10, window: time.Minute},
+	}
+	client := Client{HTTPClient: &http.Client{}, Upstream: upstream}
+	ctx := ContextWithRequestID(context.Background(), "request-1")
+
+	callFun := func() (interface{}, *http.Response, error) {
+		var out map[string]interface{}
+		resp, err := client.Request(&ctx, http.MethodGet, server.URL, nil, &out)
+		return &out, resp, err
+	}
+	_, err := client.CallRetry(callFun, false, 5)
+	// breaker opens after 2 failures and stops retrying
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorIs(t, err, utils.ErrNoRetry)
+	assert.Equal(t, int32(2), calls.Load())
+	lock.Lock()
+	assert.Equal(t, []string{"request-1", "request-1"}, requestIDs)
+	lock.Unlock()
+
+	state, failures := upstream.breaker.status()
+	assert.Equal(t, BreakerOpen, state)
+	assert.Equal(t, 2, failures)
+}
+
+func TestUpstreamsStatus(t *testing.T) {
+	GetUpstream(UpstreamRbac)
+	assert.Same(t, GetUpstream(UpstreamRbac), GetUpstream(UpstreamRbac))
+	status := UpstreamsStatus()
+	assert.Contains(t, status, UpstreamStatus{Name: UpstreamRbac, State: "closed", TimeoutSec: 10})
+}
@@ -80,6 +80,7 @@ func CreateCandlepinClient() api.Client {
 			DisableCompression: !CandlepinCallCmp,
 			TLSClientConfig:    tlsConfig,
 		}},
-		Debug: debugRequest,
+		Debug:    debugRequest,
+		Upstream: api.GetUpstream(api.UpstreamCandlepin),
 	}
 }
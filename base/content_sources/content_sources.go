@@ -16,5 +16,6 @@ func CreateContentSourcesClient() *api.Client {
 	return &api.Client{
 		HTTPClient: &http.Client{},
 		Debug:      debugRequest,
+		Upstream:   api.GetUpstream(api.UpstreamContentSources),
 	}
 }
@@ -3,6 +3,7 @@ package core
 import (
 	"app/base/database"
 	"app/base/metrics"
+	"app/base/tracing"
 	"app/base/utils"
 	"testing"
 )
@@ -17,6 +18,7 @@ var (
 func configureBaseApp() {
 	utils.ConfigureLogging()
 	metrics.Configure()
+	tracing.Configure()
 	database.DBWait(dbWait)
 }
 
@@ -0,0 +1,30 @@
+package core
+
+import (
+	"app/base/mqueue"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KafkaLagResponse struct {
+	// messages waiting in partitions consumed by the component, used as KEDA metrics-api `valueLocation`
+	Lag      int64                 `json:"lag"`
+	InFlight int64                 `json:"in_flight"`
+	Readers  []mqueue.ReaderStatus `json:"readers"`
+}
+
+// KafkaLag reports backlog of kafka readers in the component for autoscaling, optionally filtered by `topic`
+func KafkaLag(c *gin.Context) {
+	topic := c.Query("topic")
+	resp := KafkaLagResponse{Readers: []mqueue.ReaderStatus{}}
+	for _, r := range mqueue.ReadersStatus() {
+		if topic != "" && r.Topic != topic {
+			continue
+		}
+		resp.Lag += r.Lag
+		resp.InFlight += r.InFlight
+		resp.Readers = append(resp.Readers, r)
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,22 @@
+package core
+
+import (
+	"app/base/mqueue"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafkaLag(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/?topic=patchman.evaluator.recalc", nil)
+	InitRouter(KafkaLag).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp KafkaLagResponse
+	assert.NoError(t, sonic.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, KafkaLagResponse{Readers: []mqueue.ReaderStatus{}}, resp)
+}
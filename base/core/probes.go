@@ -34,4 +34,6 @@ func InitProbes(app *gin.Engine) {
 	app.GET("/healthz", Liveness)
 	app.GET("/livez", Liveness)
 	app.GET("/readyz", Readiness)
+	app.GET("/status/detail", StatusDetail)
+	app.GET("/kafka/lag", KafkaLag)
 }
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"app/base"
+	"app/base/api"
+	"app/base/database"
+	"app/base/models"
+	"app/base/mqueue"
+	"app/base/utils"
+	"app/base/vmaas"
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+const (
+	StatusOk          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+)
+
+const statusCheckTimeout = 5 * time.Second
+
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Healthy   bool   `json:"healthy"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type VmaasStatus struct {
+	DependencyStatus
+	// api or dump
+	Source   string     `json:"source"`
+	Exported *time.Time `json:"exported,omitempty"`
+}
+
+type CacheStatus struct {
+	Accounts             int64 `json:"accounts"`
+	InvalidPackageCache  int64 `json:"invalid_package_cache"`
+	InvalidAdvisoryCache int64 `json:"invalid_advisory_cache"`
+}
+
+type StatusDetailResponse struct {
+	// ok, degraded (optional dependency failing) or unavailable (primary database failing)
+	Status    string                `json:"status"`
+	Database  []DependencyStatus    `json:"database"`
+	Kafka     []mqueue.ReaderStatus `json:"kafka"`
+	Vmaas     *VmaasStatus          `json:"vmaas,omitempty"`
+	Upstreams []api.UpstreamStatus  `json:"upstreams"`
+	// last runs of tasks and vmaas sync timestamps from timestamp_kv
+	Timestamps map[string]time.Time `json:"timestamps"`
+	Caches     *CacheStatus         `json:"caches,omitempty"`
+}
+
+// StatusDetail reports state of dependencies, responds 503 when the component is not ready
+func StatusDetail(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), statusCheckTimeout)
+	defer cancel()
+
+	resp := GetStatusDetail(ctx)
+	if resp.Status == StatusUnavailable {
+		c.JSON(http.StatusServiceUnavailable, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func GetStatusDetail(ctx context.Context) StatusDetailResponse {
+	resp := StatusDetailResponse{
+		Status:     StatusOk,
+		Database:   []DependencyStatus{checkDB(ctx, "primary", database.DB)},
+		Kafka:      mqueue.ReadersStatus(),
+		Vmaas:      checkVmaas(ctx),
+		Upstreams:  api.UpstreamsStatus(),
+		Timestamps: map[string]time.Time{},
+	}
+	if utils.CoreCfg.DBReadReplicaEnabled && database.DBReadReplica != nil {
+		resp.Database = append(resp.Database, checkDB(ctx, "read_replica", database.DBReadReplica))
+	}
+
+	if !resp.Database[0].Healthy {
+		resp.Status = StatusUnavailable
+		return resp
+	}
+	for _, db := range resp.Database[1:] {
+		if !db.Healthy {
+			resp.Status = StatusDegraded
+		}
+	}
+	if resp.Vmaas != nil && !resp.Vmaas.Healthy {
+		resp.Status = StatusDegraded
+	}
+	for _, u := range resp.Upstreams {
+		if u.State != api.BreakerClosed.String() {
+			resp.Status = StatusDegraded
+		}
+	}
+
+	tx := database.DB.WithContext(ctx)
+	var timestamps []models.TimestampKV
+	if err := tx.Find(&timestamps).Error; err != nil {
+		utils.LogWarn("err", err, "Unable to load timestamps")
+	}
+	for _, ts := range timestamps {
+		resp.Timestamps[ts.Name] = ts.Value
+	}
+
+	var caches CacheStatus
+	err := tx.Table("rh_account").
+		Select("count(*) AS accounts, " +
+			"count(*) FILTER (WHERE NOT valid_package_cache) AS invalid_package_cache, " +
+			"count(*) FILTER (WHERE NOT valid_advisory_cache) AS invalid_advisory_cache").
+		Scan(&caches).Error
+	if err != nil {
+		utils.LogWarn("err", err, "Unable to load cache status")
+	} else {
+		resp.Caches = &caches
+	}
+	return resp
+}
+
+func checkDB(ctx context.Context, name string, db *gorm.DB) DependencyStatus {
+	status := DependencyStatus{Name: name}
+	tStart := time.Now()
+	sqlDB, err := db.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	status.LatencyMs = time.Since(tStart).Milliseconds()
+	status.setError(err)
+	return status
+}
+
+// checkVmaas returns nil if the component does not use VMaaS
+func checkVmaas(ctx context.Context) *VmaasStatus {
+	status := VmaasStatus{DependencyStatus: DependencyStatus{Name: "vmaas"}}
+	tStart := time.Now()
+	switch {
+	case utils.CoreCfg.VmaasDumpPath != "":
+		// dump modification time approximates the export, the whole dump is not loaded for status check
+		status.Source = "dump"
+		info, err := os.Stat(utils.CoreCfg.VmaasDumpPath)
+		if err == nil {
+			modified := info.ModTime()
+			status.Exported = &modified
+		}
+		status.setError(err)
+	case utils.CoreCfg.VmaasAddress != "":
+		status.Source = "api"
+		// probe bypasses upstream circuit breaker so health checks neither trip it nor are rejected by it
+		timeout := api.GetUpstream(api.UpstreamVmaas).Timeout
+		client := api.Client{HTTPClient: &http.Client{Timeout: timeout}}
+		dbchange := vmaas.DBChangeResponse{}
+		url := utils.CoreCfg.VmaasAddress + base.VMaaSAPIPrefix + "/dbchange"
+		resp, err := client.Request(&ctx, http.MethodGet, url, nil, &dbchange)
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+		if err == nil && resp.StatusCode != http.StatusOK {
+			err = errors.Errorf("status code %d", resp.StatusCode)
+		}
+		if exported := dbchange.GetExported(); err == nil && exported != nil {
+			status.Exported = exported.Time()
+		}
+		status.setError(err)
+	default:
+		return nil
+	}
+	status.LatencyMs = time.Since(tStart).Milliseconds()
+	return &status
+}
+
+func (s *DependencyStatus) setError(err error) {
+	s.Healthy = err == nil
+	if err != nil {
+		s.Error = err.Error()
+	}
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"app/base/database"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+)
+
+// openTestDB opens new connection, so closing it doesn't affect other tests
+func openTestDB(t *testing.T) {
+	SetupTest(t)
+	prev := database.DB
+	database.DB = nil
+	database.InitDB(database.User)
+	t.Cleanup(func() { database.DB = prev })
+}
+
+func TestStatusDetail(t *testing.T) {
+	openTestDB(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	InitRouter(StatusDetail).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp StatusDetailResponse
+	assert.NoError(t, sonic.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "primary", resp.Database[0].Name)
+	assert.True(t, resp.Database[0].Healthy)
+	assert.Contains(t, resp.Timestamps, "last_eval_repo_based")
+	assert.NotNil(t, resp.Caches)
+	assert.Positive(t, resp.Caches.Accounts)
+}
+
+func TestStatusDetailDBFail(t *testing.T) {
+	openTestDB(t)
+
+	sqlDB, _ := database.DB.DB()
+	assert.Nil(t, sqlDB.Close())
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	InitRouter(StatusDetail).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var resp StatusDetailResponse
+	assert.NoError(t, sonic.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, StatusUnavailable, resp.Status)
+	assert.False(t, resp.Database[0].Healthy)
+}
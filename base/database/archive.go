@@ -0,0 +1,26 @@
+package database
+
+import (
+	"app/base/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Move data of deleted systems to system_archive so they can be restored until pruned
+var SoftDeleteSystems = utils.PodConfig.GetBool("soft_delete_systems", false)
+
+// Delete system data, the data are archived when soft delete is enabled
+func DeleteSystem(tx *gorm.DB, inventoryID uuid.UUID) *gorm.DB {
+	if SoftDeleteSystems {
+		return tx.Exec("select archive_system(?)", inventoryID)
+	}
+	return tx.Exec("select delete_system(?)", inventoryID)
+}
+
+// Restore archived system data, returns nil inventory ID when the system is not archived
+func RestoreSystem(tx *gorm.DB, inventoryID uuid.UUID) (*uuid.UUID, error) {
+	var restored *uuid.UUID
+	err := tx.Raw("select restore_system(?)", inventoryID).Scan(&restored).Error
+	return restored, err
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"app/base/tracing"
 	"app/base/utils"
 	"fmt"
 	"time"
@@ -93,6 +94,9 @@ func openPostgreSQL(dbConfig *PostgreSQLConfig) *gorm.DB {
 		panic(err)
 	}
 
+	if err = db.Use(tracing.GormPlugin{}); err != nil {
+		panic(err)
+	}
 	if dbConfig.Debug {
 		db = db.Debug()
 	}
@@ -71,7 +71,11 @@ func CheckCachesValidRet() (bool, error) {
 		Table("system_advisories sa").
 		Joins("JOIN system_inventory si ON sa.rh_account_id = si.rh_account_id AND sa.system_id = si.id").
 		Joins("JOIN system_patch spatch ON si.id = spatch.system_id AND si.rh_account_id = spatch.rh_account_id").
-		Where("si.stale = false AND spatch.last_evaluation IS NOT NULL").
+		Where("si.stale = false AND spatch.last_evaluation IS NOT NULL AND sa.status_id <> 2").
+		Where(`NOT EXISTS (SELECT 1 FROM advisory_exclusion_system aes
+			JOIN advisory_exclusion ae ON ae.rh_account_id = aes.rh_account_id AND ae.id = aes.exclusion_id
+			WHERE aes.rh_account_id = sa.rh_account_id AND aes.system_id = sa.system_id
+			AND aes.advisory_id = sa.advisory_id AND (ae.expires IS NULL OR ae.expires > now()))`).
 		Order("si.rh_account_id, sa.advisory_id").
 		Group("si.rh_account_id, sa.advisory_id").
 		Find(&counts).Error
@@ -382,6 +386,88 @@ func DeleteSystemRepos(t *testing.T, rhAccountID int, systemID int64, repoIDs []
 	assert.Nil(t, err)
 }
 
+func CreateRepoClassification(t *testing.T, rhAccountID int, repoID int64, classification string) {
+	err := OnConflictUpdateMulti(DB, []string{"rh_account_id", "repo_id"}, "classification").
+		Create(&models.RepoClassification{RhAccountID: rhAccountID, RepoID: repoID, Classification: classification}).
+		Error
+	assert.Nil(t, err)
+}
+
+func DeleteRepoClassification(t *testing.T, rhAccountID int, repoID int64) {
+	err := DB.Where("rh_account_id = ? AND repo_id = ?", rhAccountID, repoID).
+		Delete(&models.RepoClassification{}).Error
+	assert.Nil(t, err)
+}
+
+func GetSystemPackageChanges(t *testing.T, rhAccountID int, systemID int64) []models.SystemPackageChange {
+	var changes []models.SystemPackageChange
+	err := DB.Where("rh_account_id = ? AND system_id = ?", rhAccountID, systemID).Order("id").Find(&changes).Error
+	assert.Nil(t, err)
+	return changes
+}
+
+func DeleteSystemPackageChanges(t *testing.T, rhAccountID int, systemID int64) {
+	err := DB.Where("rh_account_id = ? AND system_id = ?", rhAccountID, systemID).
+		Delete(&models.SystemPackageChange{}).Error
+	assert.Nil(t, err)
+}
+
+func CreateBaseline(t *testing.T, account int, name string, referenceSystemID *int64, advisoryCutoff *time.Time,
+	systemIDs []int64) int64 {
+	baseline := models.Baseline{
+		RhAccountID:       account,
+		Name:              name,
+		ReferenceSystemID: referenceSystemID,
+		AdvisoryCutoff:    advisoryCutoff,
+	}
+	tx := DB.Begin()
+	defer tx.Rollback()
+
+	err := tx.Create(&baseline).Error
+	assert.Nil(t, err)
+
+	if len(systemIDs) > 0 {
+		err = tx.Model(&models.SystemPatch{}).
+			Where("rh_account_id = ? AND system_id IN (?)", account, systemIDs).
+			Update("baseline_id", baseline.ID).Error
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tx.Commit().Error)
+	return baseline.ID
+}
+
+func DeleteBaseline(t *testing.T, account int, name string) {
+	tx := DB.Begin()
+	defer tx.Rollback()
+
+	err := tx.Model(&models.SystemPatch{}).
+		Where("rh_account_id = ? AND baseline_id IN (SELECT id FROM baseline WHERE rh_account_id = ? AND name = ?)",
+			account, account, name).
+		Updates(map[string]interface{}{"baseline_id": nil, "baseline_advisory_count_cache": 0}).Error
+	assert.Nil(t, err)
+
+	err = tx.Delete(&models.Baseline{}, "rh_account_id = ? AND name = ?", account, name).Error
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Commit().Error)
+}
+
+func CheckBaselineSystems(t *testing.T, account int, baselineID int64, inventoryIDs []uuid.UUID) {
+	var foundInventoryIDs []uuid.UUID
+	err := DB.Table("system_inventory si").Select("si.inventory_id as id").
+		Joins("JOIN system_patch spatch ON si.id = spatch.system_id AND si.rh_account_id = spatch.rh_account_id").
+		Where("si.rh_account_id = ? AND spatch.baseline_id = ?", account, baselineID).
+		Order("id").
+		Find(&foundInventoryIDs).Error
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(inventoryIDs), len(foundInventoryIDs))
+	if len(inventoryIDs) == len(foundInventoryIDs) {
+		for index, inventoryID := range inventoryIDs {
+			assert.Equal(t, inventoryID, foundInventoryIDs[index])
+		}
+	}
+}
+
 func DeleteNewlyAddedPackages(t *testing.T) {
 	query := DB.Table("package p").
 		Where("id >= 100").
@@ -524,3 +610,111 @@ func DeleteTemplateAdvisories(t *testing.T, templateID int64, advisoryIDs []int6
 	assert.Nil(t, query.Count(&count).Error)
 	assert.Equal(t, int64(0), count)
 }
+
+func SetAdvisoriesRebootRequired(t *testing.T, advisoryIDs []int64, rebootRequired bool) {
+	assert.Nil(t, DB.Model(&models.AdvisoryMetadata{}).Where("id IN (?)", advisoryIDs).
+		Update("reboot_required", rebootRequired).Error)
+}
+
+// Create patch plan with items given as system_id -> advisory_ids
+func CreatePatchPlan(t *testing.T, account int, name string, items map[int64][]int64) int64 {
+	plan := &models.PatchPlan{
+		RhAccountID: account,
+		Name:        name,
+		WindowStart: time.Now().Add(-time.Hour),
+		WindowEnd:   time.Now().Add(time.Hour),
+	}
+	assert.Nil(t, DB.Create(plan).Error)
+	for systemID, advisoryIDs := range items {
+		for _, advisoryID := range advisoryIDs {
+			assert.Nil(t, DB.Create(&models.PatchPlanItem{
+				RhAccountID: account, PlanID: plan.ID, SystemID: systemID, AdvisoryID: advisoryID}).Error)
+		}
+	}
+	return plan.ID
+}
+
+func CheckPatchPlanItems(t *testing.T, account int, planID int64, nExpected int) {
+	var count int64
+	assert.Nil(t, DB.Model(&models.PatchPlanItem{}).Where("rh_account_id = ? AND plan_id = ?", account, planID).
+		Count(&count).Error)
+	assert.Equal(t, int64(nExpected), count)
+}
+
+func CreateAdvisoryExclusion(t *testing.T, account int, advisoryID int64, expires *time.Time,
+	systemIDs []int64) int64 {
+	exclusion := models.AdvisoryExclusion{
+		RhAccountID:   account,
+		AdvisoryID:    advisoryID,
+		Kind:          models.AdvisoryExclusionAcceptedRisk,
+		Justification: "test justification",
+		Owner:         "test owner",
+		Expires:       expires,
+	}
+	tx := DB.Begin()
+	defer tx.Rollback()
+
+	assert.Nil(t, tx.Create(&exclusion).Error)
+	for _, systemID := range systemIDs {
+		assert.Nil(t, tx.Create(&models.AdvisoryExclusionSystem{
+			RhAccountID: account, ExclusionID: exclusion.ID, SystemID: systemID, AdvisoryID: advisoryID,
+		}).Error)
+	}
+	assert.Nil(t, tx.Commit().Error)
+	return exclusion.ID
+}
+
+func DeleteAdvisoryExclusion(t *testing.T, account int, exclusionID int64) {
+	assert.Nil(t, DB.Delete(&models.AdvisoryExclusion{}, "rh_account_id = ? AND id = ?", account, exclusionID).Error)
+}
+
+func CheckAdvisoryExclusionSystems(t *testing.T, account int, exclusionID int64, count int) {
+	var cnt int64
+	assert.Nil(t, DB.Model(&models.AdvisoryExclusionSystem{}).
+		Where("rh_account_id = ? AND exclusion_id = ?", account, exclusionID).
+		Count(&cnt).Error)
+	assert.Equal(t, int64(count), cnt)
+}
+
+func CreatePackageHold(t *testing.T, account int, nameID int64, evraPattern string, systemIDs []int64) int64 {
+	hold := models.PackageHold{RhAccountID: account, NameID: nameID, EvraPattern: evraPattern}
+	tx := DB.Begin()
+	defer tx.Rollback()
+
+	assert.Nil(t, tx.Create(&hold).Error)
+	for _, systemID := range systemIDs {
+		assert.Nil(t, tx.Create(&models.PackageHoldSystem{
+			RhAccountID: account, HoldID: hold.ID, SystemID: systemID,
+		}).Error)
+	}
+	assert.Nil(t, tx.Commit().Error)
+	return hold.ID
+}
+
+func DeletePackageHold(t *testing.T, account int, holdID int64) {
+	assert.Nil(t, DB.Delete(&models.PackageHold{}, "rh_account_id = ? AND id = ?", account, holdID).Error)
+}
+
+func CheckPackageHoldSystems(t *testing.T, account int, holdID int64, count int) {
+	var cnt int64
+	assert.Nil(t, DB.Model(&models.PackageHoldSystem{}).
+		Where("rh_account_id = ? AND hold_id = ?", account, holdID).
+		Count(&cnt).Error)
+	assert.Equal(t, int64(count), cnt)
+}
+
+func DeletePatchPlan(t *testing.T, account int, planID int64) {
+	assert.Nil(t, DB.Delete(&models.PatchPlan{}, "rh_account_id = ? AND id = ?", account, planID).Error)
+}
+
+func CreateCullingPolicy(t *testing.T, account, cullingDelayDays, noticeDays int) {
+	policy := models.CullingPolicy{
+		RhAccountID: account, CullingDelayDays: cullingDelayDays, NoticeDays: noticeDays, LastEdited: time.Now(),
+	}
+	assert.Nil(t, OnConflictUpdate(DB, "rh_account_id", "culling_delay_days", "notice_days", "last_edited").
+		Create(&policy).Error)
+}
+
+func DeleteCullingPolicy(t *testing.T, account int) {
+	assert.Nil(t, DB.Delete(&models.CullingPolicy{}, "rh_account_id = ?", account).Error)
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -241,6 +242,31 @@ func ReadReplicaConfigured() bool {
 	return len(utils.CoreCfg.DBReadReplicaHost) > 0 && utils.CoreCfg.DBReadReplicaPort != 0
 }
 
+// ReplicaLag returns how far the replica is behind primary, zero when all received WAL is replayed.
+// Lag of replica which does not stream WAL from primary is unknown, e.g. received and replayed positions match
+// when the connection to primary is lost, an error is returned instead.
+func ReplicaLag(db *gorm.DB) (time.Duration, error) {
+	var lag struct {
+		Streaming bool
+		Seconds   float64
+	}
+	// status of wal receiver is visible to roles with pg_read_all_stats only, running receiver is visible to all
+	err := db.Raw(`SELECT
+			NOT pg_is_in_recovery() OR coalesce((SELECT coalesce(status, 'streaming') = 'streaming'
+				FROM pg_stat_wal_receiver), false) AS streaming,
+			CASE
+				WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+				ELSE coalesce(extract(epoch FROM now() - pg_last_xact_replay_timestamp()), 0)
+			END AS seconds`).Scan(&lag).Error
+	if err != nil {
+		return 0, err
+	}
+	if !lag.Streaming {
+		return 0, errors.New("replica is not streaming WAL from primary")
+	}
+	return time.Duration(lag.Seconds * float64(time.Second)), nil
+}
+
 func ApplyInventoryWorkspaceFilter(tx *gorm.DB, workspaceIDs []string) *gorm.DB {
 	if len(workspaceIDs) == 0 {
 		utils.LogWarn("there should always be some workspaces, at least root workspace")
@@ -254,6 +280,41 @@ func JoinTemplates(tx *gorm.DB) *gorm.DB {
 	return tx.Joins("LEFT JOIN template t ON spatch.template_id = t.id AND spatch.rh_account_id = t.rh_account_id")
 }
 
+// LEFT JOIN baselines to spatch (system_patch)
+func JoinBaselines(tx *gorm.DB) *gorm.DB {
+	return tx.Joins("LEFT JOIN baseline bl ON spatch.baseline_id = bl.id AND spatch.rh_account_id = bl.rh_account_id")
+}
+
+// Recompute advisory counts of systems covered by an advisory exclusion, per-workspace and per-account counts
+// of the advisory after the exclusion has been created or removed
+func RefreshAdvisoryExclusionCaches(tx *gorm.DB, accountID int, advisoryID int64, systemIDs []int64) error {
+	if len(systemIDs) > 0 {
+		err := tx.Exec(`SELECT refresh_system_caches(si.id, si.rh_account_id)
+			FROM system_inventory si WHERE si.rh_account_id = ? AND si.id IN (?)`, accountID, systemIDs).Error
+		if err != nil {
+			return err
+		}
+	}
+	err := tx.Exec("SELECT refresh_advisory_caches_multi(?, ?)", pq.Array([]int64{advisoryID}), accountID).Error
+	if err != nil {
+		return err
+	}
+	return tx.Exec("SELECT refresh_account_advisory_caches_multi(?, ?)", pq.Array([]int64{advisoryID}), accountID).Error
+}
+
+// LEFT JOIN non-expired advisory exclusion of the system/advisory pair to sa (system_advisories),
+// "excluded" takes precedence over "accepted_risk" when the pair is covered by more exclusions
+func JoinAdvisoryExclusions(tx *gorm.DB) *gorm.DB {
+	return tx.Joins(`LEFT JOIN LATERAL (
+		SELECT ae.kind
+		  FROM advisory_exclusion_system aes
+		  JOIN advisory_exclusion ae ON ae.rh_account_id = aes.rh_account_id AND ae.id = aes.exclusion_id
+		 WHERE aes.rh_account_id = sa.rh_account_id AND aes.system_id = sa.system_id
+		   AND aes.advisory_id = sa.advisory_id AND (ae.expires IS NULL OR ae.expires > now())
+		 ORDER BY ae.kind DESC
+		 LIMIT 1) aex ON true`)
+}
+
 // JOIN advisory_metadata to sa (system_advisories)
 func JoinAdvisoryMetadata(tx *gorm.DB) *gorm.DB {
 	return tx.Joins("JOIN advisory_metadata am ON am.id = sa.advisory_id")
@@ -269,6 +330,19 @@ func JoinInstallableApplicablePackages(tx *gorm.DB) *gorm.DB {
 		Joins("LEFT JOIN package pa ON pa.id = spkg.applicable_id")
 }
 
+// JOIN latest update violating a package hold
+func JoinHeldPackages(tx *gorm.DB) *gorm.DB {
+	return tx.Joins("LEFT JOIN package ph ON ph.id = spkg.held_id")
+}
+
+// Date when system is deleted by culling, inventory culled timestamp delayed by org culling policy
+const CullingDateExpr = "si.culled_timestamp + make_interval(days => coalesce(cp.culling_delay_days, 0))"
+
+// LEFT JOIN org culling policy, requires `si` alias for system_inventory
+func JoinCullingPolicy(tx *gorm.DB) *gorm.DB {
+	return tx.Joins("LEFT JOIN culling_policy cp ON cp.rh_account_id = si.rh_account_id")
+}
+
 // JOIN package description, summary, advisory
 func JoinPackageDetails(tx *gorm.DB) *gorm.DB {
 	return tx.Joins("JOIN strings descr ON p.description_hash = descr.id").
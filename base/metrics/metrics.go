@@ -28,6 +28,7 @@ var (
 func init() {
 	if utils.CoreCfg.KafkaAddress != "" {
 		prometheus.MustRegister(KafkaConnectionErrorCnt)
+		prometheus.MustRegister(mqueue.ReaderCollectors()...)
 	}
 	prometheus.MustRegister(EngineVersion)
 	engineVersion, _ := os.ReadFile("VERSION")
@@ -137,6 +137,9 @@ type SystemPatch struct {
 	ApplicableAdvisoryBugCountCache  int
 	ApplicableAdvisorySecCountCache  int
 	TemplateID                       *int64 `gorm:"column:template_id"`
+	BaselineID                       *int64 `gorm:"column:baseline_id"`
+	BaselineAdvisoryCountCache       int
+	PackagesHeld                     int
 }
 
 func (SystemPatch) TableName() string {
@@ -206,12 +209,36 @@ type SystemPackage struct {
 	NameID        int64
 	InstallableID *int64
 	ApplicableID  *int64
+	HeldID        *int64
 }
 
 func (SystemPackage) TableName() string {
 	return "system_package2"
 }
 
+const (
+	PackageChangeInstalled  = "installed"
+	PackageChangeRemoved    = "removed"
+	PackageChangeUpgraded   = "upgraded"
+	PackageChangeDowngraded = "downgraded"
+)
+
+// Append-only journal of system package changes
+type SystemPackageChange struct {
+	ID          int64 `gorm:"primaryKey"`
+	RhAccountID int   `gorm:"primaryKey"`
+	SystemID    int64 `gorm:"primaryKey"`
+	NameID      int64
+	Change      string
+	OldEvra     *string
+	NewEvra     *string
+	Changed     time.Time
+}
+
+func (SystemPackageChange) TableName() string {
+	return "system_package_change"
+}
+
 type PackageUpdate struct {
 	EVRA     string `json:"evra"`
 	Advisory string `json:"-"` // don't show it in API, we can probably remove it completely later
@@ -227,6 +254,56 @@ func (DeletedSystem) TableName() string {
 	return "deleted_system"
 }
 
+// Soft deleted system, rows of system tables are kept as json until restored or pruned
+type SystemArchive struct {
+	InventoryID uuid.UUID `gorm:"primaryKey"`
+	RhAccountID int
+	SystemID    int64
+	Archived    time.Time
+	Inventory   []byte
+	Patch       []byte
+	Advisories  []byte
+	Packages    []byte
+	Repos       []byte
+}
+
+func (SystemArchive) TableName() string {
+	return "system_archive"
+}
+
+// Last rejected upload of a host, removed when the host is uploaded successfully
+type RejectedUpload struct {
+	RhAccountID   int       `gorm:"primaryKey"`
+	InventoryID   uuid.UUID `gorm:"primaryKey"`
+	DisplayName   *string
+	Reporter      string
+	HostType      *string
+	WorkspaceID   *uuid.UUID
+	Reason        string
+	Sample        []byte
+	Event         []byte
+	FirstRejected time.Time
+	LastRejected  time.Time
+	RejectedCount int
+	Replayed      *time.Time
+}
+
+func (RejectedUpload) TableName() string {
+	return "rejected_upload"
+}
+
+// VMaaS updates response shared by evaluator replicas, valid for VMaaS data exported at Exported
+type VmaasCache struct {
+	Checksum string `gorm:"primaryKey"`
+	Exported time.Time
+	Response []byte
+	Created  time.Time
+}
+
+func (VmaasCache) TableName() string {
+	return "vmaas_cache"
+}
+
 type AdvisorySeverity struct {
 	ID   int
 	Name string
@@ -340,6 +417,23 @@ func (SystemRepo) TableName() string {
 
 type SystemRepoSlice []SystemRepo
 
+const (
+	RepoClassificationTrusted    = "trusted"
+	RepoClassificationThirdParty = "third_party"
+	RepoClassificationIgnored    = "ignored"
+)
+
+// Per-org override of the global Repo.ThirdParty flag
+type RepoClassification struct {
+	RhAccountID    int   `gorm:"primaryKey"`
+	RepoID         int64 `gorm:"primaryKey"`
+	Classification string
+}
+
+func (RepoClassification) TableName() string {
+	return "repo_classification"
+}
+
 type TimestampKV struct {
 	Name  string `gorm:"unique"`
 	Value time.Time
@@ -360,3 +454,121 @@ type PackageAccountData struct {
 func (PackageAccountData) TableName() string {
 	return "package_account_data"
 }
+
+type PatchPlan struct {
+	ID          int64 `gorm:"primaryKey"`
+	RhAccountID int   `gorm:"primaryKey"`
+	Name        string
+	Description *string
+	WindowStart time.Time
+	WindowEnd   time.Time
+	Created     time.Time `gorm:"default:now()"`
+}
+
+func (PatchPlan) TableName() string {
+	return "patch_plan"
+}
+
+type Baseline struct {
+	ID                int64 `gorm:"primaryKey"`
+	RhAccountID       int   `gorm:"primaryKey"`
+	Name              string
+	Description       *string
+	ReferenceSystemID *int64
+	AdvisoryCutoff    *time.Time
+	Created           time.Time `gorm:"default:now()"`
+	LastEdited        time.Time `gorm:"default:now()"`
+}
+
+func (Baseline) TableName() string {
+	return "baseline"
+}
+
+type PatchPlanItem struct {
+	RhAccountID int   `gorm:"primaryKey"`
+	PlanID      int64 `gorm:"primaryKey"`
+	SystemID    int64 `gorm:"primaryKey"`
+	AdvisoryID  int64 `gorm:"primaryKey"`
+}
+
+func (PatchPlanItem) TableName() string {
+	return "patch_plan_item"
+}
+
+const (
+	AdvisoryExclusionAcceptedRisk = "accepted_risk"
+	AdvisoryExclusionExcluded     = "excluded"
+)
+
+type AdvisoryExclusion struct {
+	ID            int64 `gorm:"primaryKey"`
+	RhAccountID   int   `gorm:"primaryKey"`
+	AdvisoryID    int64
+	Kind          string
+	Justification string
+	Owner         string
+	Expires       *time.Time
+	Created       time.Time `gorm:"default:now()"`
+}
+
+func (AdvisoryExclusion) TableName() string {
+	return "advisory_exclusion"
+}
+
+type AdvisoryExclusionSystem struct {
+	RhAccountID int   `gorm:"primaryKey"`
+	ExclusionID int64 `gorm:"primaryKey"`
+	SystemID    int64 `gorm:"primaryKey"`
+	AdvisoryID  int64
+}
+
+func (AdvisoryExclusionSystem) TableName() string {
+	return "advisory_exclusion_system"
+}
+
+type PackageHold struct {
+	ID          int64 `gorm:"primaryKey"`
+	RhAccountID int   `gorm:"primaryKey"`
+	NameID      int64
+	EvraPattern string
+	Description *string
+	Created     time.Time `gorm:"default:now()"`
+}
+
+func (PackageHold) TableName() string {
+	return "package_hold"
+}
+
+type PackageHoldSystem struct {
+	RhAccountID int   `gorm:"primaryKey"`
+	HoldID      int64 `gorm:"primaryKey"`
+	SystemID    int64 `gorm:"primaryKey"`
+}
+
+func (PackageHoldSystem) TableName() string {
+	return "package_hold_system"
+}
+
+// Per-org override of inventory culling, culled systems are kept CullingDelayDays longer
+// and the org is notified NoticeDays before their deletion
+type CullingPolicy struct {
+	RhAccountID      int `gorm:"primaryKey"`
+	CullingDelayDays int
+	NoticeDays       int
+	LastEdited       time.Time
+}
+
+func (CullingPolicy) TableName() string {
+	return "culling_policy"
+}
+
+type SystemCullingNotice struct {
+	RhAccountID int   `gorm:"primaryKey"`
+	SystemID    int64 `gorm:"primaryKey"`
+	CullingDate time.Time
+	Notified    time.Time
+}
+
+func (SystemCullingNotice) TableName() string {
+	return "system_culling_notice"
+}
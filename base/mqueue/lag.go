@@ -0,0 +1,162 @@
+package mqueue
+
+import (
+	"app/base/utils"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+)
+
+var lagRefreshInterval = time.Duration(utils.PodConfig.GetInt("kafka_lag_interval_sec", 30)) * time.Second
+
+// groupOffsetsClient is the subset of kafka.Client used to compute consumer group lag, replaced in tests
+type groupOffsetsClient interface {
+	DescribeGroups(ctx context.Context, req *kafka.DescribeGroupsRequest) (*kafka.DescribeGroupsResponse, error)
+	Metadata(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error)
+	OffsetFetch(ctx context.Context, req *kafka.OffsetFetchRequest) (*kafka.OffsetFetchResponse, error)
+	ListOffsets(ctx context.Context, req *kafka.ListOffsetsRequest) (*kafka.ListOffsetsResponse, error)
+}
+
+func newKafkaClientFromEnv() *kafka.Client {
+	transport := &kafka.Transport{}
+	if dialer := tryCreateSecuredDialerFromEnv(); dialer != nil {
+		transport.TLS = dialer.TLS
+		transport.SASL = dialer.SASLMechanism
+	}
+	return &kafka.Client{Addr: kafka.TCP(utils.CoreCfg.KafkaServers...), Transport: transport}
+}
+
+// groupLag returns lag by partition of the consumer group computed by broker, i.e. high water mark minus committed
+// offset. Only partitions assigned to group members are returned, all topic partitions when the group has no members.
+func groupLag(ctx context.Context, client groupOffsetsClient, group, topic string) (map[int]int64, error) {
+	partitions, err := assignedPartitions(ctx, client, group, topic)
+	if err != nil {
+		return nil, err
+	}
+	if len(partitions) == 0 {
+		if partitions, err = topicPartitions(ctx, client, topic); err != nil {
+			return nil, err
+		}
+	}
+
+	committed, err := client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{GroupID: group,
+		Topics: map[string][]int{topic: partitions}})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to fetch committed offsets")
+	}
+	if committed.Error != nil {
+		return nil, errors.Wrap(committed.Error, "unable to fetch committed offsets")
+	}
+
+	requests := make([]kafka.OffsetRequest, 0, 2*len(partitions))
+	for _, p := range partitions {
+		requests = append(requests, kafka.FirstOffsetOf(p), kafka.LastOffsetOf(p))
+	}
+	offsets, err := client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{topic: requests}})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list partition offsets")
+	}
+	watermarks := map[int]kafka.PartitionOffsets{}
+	for _, o := range offsets.Topics[topic] {
+		if o.Error != nil {
+			return nil, errors.Wrapf(o.Error, "unable to list offsets of partition %d", o.Partition)
+		}
+		watermarks[o.Partition] = o
+	}
+
+	lag := make(map[int]int64, len(partitions))
+	for _, c := range committed.Topics[topic] {
+		if c.Error != nil {
+			return nil, errors.Wrapf(c.Error, "unable to fetch committed offset of partition %d", c.Partition)
+		}
+		w, ok := watermarks[c.Partition]
+		if !ok {
+			continue
+		}
+		// last offset is the high water mark, reader starts from the first offset when nothing is committed
+		from := c.CommittedOffset
+		if from < 0 {
+			from = w.FirstOffset
+		}
+		lag[c.Partition] = max(w.LastOffset-from, 0)
+	}
+	return lag, nil
+}
+
+func assignedPartitions(ctx context.Context, client groupOffsetsClient, group, topic string) ([]int, error) {
+	resp, err := client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: []string{group}})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to describe consumer group")
+	}
+	var partitions []int
+	for _, g := range resp.Groups {
+		if g.Error != nil {
+			return nil, errors.Wrap(g.Error, "unable to describe consumer group")
+		}
+		for _, m := range g.Members {
+			for _, t := range m.MemberAssignments.Topics {
+				if t.Topic == topic {
+					partitions = append(partitions, t.Partitions...)
+				}
+			}
+		}
+	}
+	return partitions, nil
+}
+
+func topicPartitions(ctx context.Context, client groupOffsetsClient, topic string) ([]int, error) {
+	resp, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load topic metadata")
+	}
+	var partitions []int
+	for _, t := range resp.Topics {
+		if t.Error != nil {
+			return nil, errors.Wrap(t.Error, "unable to load topic metadata")
+		}
+		for _, p := range t.Partitions {
+			partitions = append(partitions, p.ID)
+		}
+	}
+	return partitions, nil
+}
+
+// refreshLag periodically updates lag of the reader from broker until ctx is canceled
+func (t *kafkaGoReaderImpl) refreshLag(ctx context.Context, client groupOffsetsClient) {
+	ticker := time.NewTicker(lagRefreshInterval)
+	defer ticker.Stop()
+	for {
+		config := t.Config()
+		lag, err := groupLag(ctx, client, config.GroupID, config.Topic)
+		if err != nil {
+			utils.LogWarn("err", err, "topic", config.Topic, "Unable to refresh kafka consumer lag")
+		} else {
+			t.setLag(lag)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// setLag replaces lag of all partitions so partitions no longer assigned to the group are dropped
+func (t *kafkaGoReaderImpl) setLag(lag map[int]int64) {
+	topic := t.Config().Topic
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for partition := range t.lag {
+		if _, ok := lag[partition]; !ok {
+			kafkaReaderLag.DeleteLabelValues(topic, strconv.Itoa(partition))
+		}
+	}
+	for partition, l := range lag {
+		kafkaReaderLag.WithLabelValues(topic, strconv.Itoa(partition)).Set(float64(l))
+	}
+	t.lag = lag
+}
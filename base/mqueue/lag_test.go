@@ -0,0 +1,95 @@
+package mqueue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeOffsetsClient struct {
+	assigned  []int
+	topic     []int
+	committed map[int]int64
+	first     map[int]int64
+	last      map[int]int64
+}
+
+func (c *fakeOffsetsClient) DescribeGroups(_ context.Context, req *kafka.DescribeGroupsRequest,
+) (*kafka.DescribeGroupsResponse, error) {
+	group := kafka.DescribeGroupsResponseGroup{GroupID: req.GroupIDs[0]}
+	if len(c.assigned) > 0 {
+		group.Members = []kafka.DescribeGroupsResponseMember{{MemberAssignments: kafka.DescribeGroupsResponseAssignments{
+			Topics: []kafka.GroupMemberTopic{{Topic: "test", Partitions: c.assigned}, {Topic: "other", Partitions: []int{9}}},
+		}}}
+	}
+	return &kafka.DescribeGroupsResponse{Groups: []kafka.DescribeGroupsResponseGroup{group}}, nil
+}
+
+func (c *fakeOffsetsClient) Metadata(_ context.Context, _ *kafka.MetadataRequest) (*kafka.MetadataResponse, error) {
+	topic := kafka.Topic{Name: "test"}
+	for _, p := range c.topic {
+		topic.Partitions = append(topic.Partitions, kafka.Partition{Topic: "test", ID: p})
+	}
+	return &kafka.MetadataResponse{Topics: []kafka.Topic{topic}}, nil
+}
+
+func (c *fakeOffsetsClient) OffsetFetch(_ context.Context, req *kafka.OffsetFetchRequest,
+) (*kafka.OffsetFetchResponse, error) {
+	var partitions []kafka.OffsetFetchPartition
+	for _, p := range req.Topics["test"] {
+		committed, ok := c.committed[p]
+		if !ok {
+			committed = -1
+		}
+		partitions = append(partitions, kafka.OffsetFetchPartition{Partition: p, CommittedOffset: committed})
+	}
+	return &kafka.OffsetFetchResponse{Topics: map[string][]kafka.OffsetFetchPartition{"test": partitions}}, nil
+}
+
+func (c *fakeOffsetsClient) ListOffsets(_ context.Context, req *kafka.ListOffsetsRequest,
+) (*kafka.ListOffsetsResponse, error) {
+	var offsets []kafka.PartitionOffsets
+	for _, r := range req.Topics["test"] {
+		if r.Timestamp == kafka.LastOffset {
+			offsets = append(offsets, kafka.PartitionOffsets{Partition: r.Partition, FirstOffset: c.first[r.Partition],
+				LastOffset: c.last[r.Partition]})
+		}
+	}
+	return &kafka.ListOffsetsResponse{Topics: map[string][]kafka.PartitionOffsets{"test": offsets}}, nil
+}
+
+func TestGroupLag(t *testing.T) {
+	client := &fakeOffsetsClient{
+		assigned:  []int{0, 2},
+		topic:     []int{0, 1, 2},
+		committed: map[int]int64{0: 5, 1: 3},
+		first:     map[int]int64{0: 0, 1: 0, 2: 4},
+		last:      map[int]int64{0: 10, 1: 3, 2: 7},
+	}
+	// only partitions assigned to the group, not committed partition starts from first offset
+	lag, err := groupLag(context.Background(), client, "group", "test")
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int64{0: 5, 2: 3}, lag)
+
+	// all topic partitions when the group has no members
+	client.assigned = nil
+	lag, err = groupLag(context.Background(), client, "group", "test")
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int64{0: 5, 1: 0, 2: 3}, lag)
+}
+
+func TestSetLagDropsPartitions(t *testing.T) {
+	reader := &kafkaGoReaderImpl{Reader: *kafka.NewReader(kafka.ReaderConfig{Brokers: []string{"kafka:9092"},
+		Topic: "test-set-lag"})}
+	defer reader.Close()
+
+	reader.setLag(map[int]int64{0: 5, 1: 2})
+	assert.Equal(t, 2.0, testutil.ToFloat64(kafkaReaderLag.WithLabelValues("test-set-lag", "1")))
+	reader.setLag(map[int]int64{0: 1})
+	assert.Equal(t, int64(1), reader.status().Lag)
+	assert.False(t, kafkaReaderLag.DeleteLabelValues("test-set-lag", "1"))
+	assert.True(t, kafkaReaderLag.DeleteLabelValues("test-set-lag", "0"))
+}
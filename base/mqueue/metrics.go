@@ -1,5 +1,9 @@
 package mqueue
 
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
 type Counter interface {
 	Inc()
 }
@@ -20,3 +24,32 @@ func SetKafkaErrorWriteCnt(cnt Counter) {
 type emptyCnt struct{}
 
 func (t *emptyCnt) Inc() {}
+
+var (
+	kafkaReaderLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Help:      "Messages in partition not yet committed by the consumer group, refreshed from broker",
+		Namespace: "patchman_engine",
+		Subsystem: "kafka",
+		Name:      "reader_lag",
+	}, []string{"topic", "partition"})
+
+	kafkaMessageLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Help:      "Time from message production to completion of its handling",
+		Namespace: "patchman_engine",
+		Subsystem: "kafka",
+		Name:      "message_latency_seconds",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 1800, 3600, 7200},
+	}, []string{"topic"})
+
+	kafkaMessagesInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Help:      "Messages being handled by the reader",
+		Namespace: "patchman_engine",
+		Subsystem: "kafka",
+		Name:      "messages_in_flight",
+	}, []string{"topic"})
+)
+
+// ReaderCollectors returns metrics of kafka readers to be registered by components consuming messages
+func ReaderCollectors() []prometheus.Collector {
+	return []prometheus.Collector{kafkaReaderLag, kafkaMessageLatency, kafkaMessagesInFlight}
+}
@@ -52,6 +52,15 @@ type KafkaMessage struct {
 	Key     []byte
 	Value   []byte
 	Headers []kafka.Header
+	ctx     context.Context
+}
+
+// Context returns context carrying trace of the consumed message
+func (m KafkaMessage) Context() context.Context {
+	if m.ctx == nil {
+		return base.Context
+	}
+	return m.ctx
 }
 
 type MessageHandler func(message KafkaMessage) error
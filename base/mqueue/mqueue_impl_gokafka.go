@@ -1,6 +1,7 @@
 package mqueue
 
 import (
+	"app/base/tracing"
 	"app/base/utils"
 	"context"
 	"crypto/tls"
@@ -9,6 +10,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/segmentio/kafka-go"
@@ -16,13 +18,25 @@ import (
 	kafkaPlain "github.com/segmentio/kafka-go/sasl/plain"
 	kafkaScram "github.com/segmentio/kafka-go/sasl/scram"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type kafkaGoReaderImpl struct {
 	kafka.Reader
+	// lag by partition and in-flight messages, reported by ReadersStatus
+	lock     sync.Mutex
+	lag      map[int]int64
+	inFlight int64
 }
 
 func (t *kafkaGoReaderImpl) HandleMessages(ctx context.Context, handler MessageHandler) {
+	defer registerReader(t)()
+	if t.Config().GroupID != "" {
+		lagCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go t.refreshLag(lagCtx, newKafkaClientFromEnv())
+	}
 	for {
 		m, err := t.FetchMessage(ctx)
 		if err != nil {
@@ -39,9 +53,17 @@ func (t *kafkaGoReaderImpl) HandleMessages(ctx context.Context, handler MessageH
 				utils.LogTrace("key", h.Key, "value", string(h.Value), "kafka message header")
 			}
 		}
+		msgCtx, span := tracing.Start(extractTraceContext(ctx, m.Headers), "kafka consume "+m.Topic,
+			trace.WithSpanKind(trace.SpanKindConsumer), messagingAttributes(m.Topic),
+			trace.WithAttributes(attribute.Int("messaging.kafka.partition", m.Partition),
+				attribute.Int64("messaging.kafka.offset", m.Offset)))
 		// At this level, all errors are fatal
-		kafkaMessage := KafkaMessage{Key: m.Key, Value: m.Value, Headers: m.Headers}
-		if err = handler(kafkaMessage); err != nil {
+		kafkaMessage := KafkaMessage{Key: m.Key, Value: m.Value, Headers: m.Headers, ctx: msgCtx}
+		t.messageFetched(&m)
+		err = handler(kafkaMessage)
+		t.messageHandled(&m)
+		tracing.End(span, err)
+		if err != nil {
 			utils.LogPanic("err", err, "Handler failed")
 		}
 		err = t.CommitMessages(ctx, m)
@@ -60,11 +82,14 @@ type kafkaGoWriterImpl struct {
 }
 
 func (t *kafkaGoWriterImpl) WriteMessages(ctx context.Context, msgs ...KafkaMessage) error {
+	ctx, span := tracing.Start(ctx, "kafka produce "+t.Topic, trace.WithSpanKind(trace.SpanKindProducer),
+		messagingAttributes(t.Topic), trace.WithAttributes(attribute.Int("messaging.batch.message_count", len(msgs))))
 	kafkaGoMessages := make([]kafka.Message, len(msgs))
 	for i, m := range msgs {
-		kafkaGoMessages[i] = kafka.Message{Key: m.Key, Value: m.Value, Headers: m.Headers}
+		kafkaGoMessages[i] = kafka.Message{Key: m.Key, Value: m.Value, Headers: injectTraceContext(ctx, m.Headers)}
 	}
 	err := t.Writer.WriteMessages(ctx, kafkaGoMessages...)
+	tracing.End(span, err)
 	return err
 }
 
@@ -85,7 +110,7 @@ func NewKafkaReaderFromEnv(topic string) Reader {
 		MaxAttempts: maxAttempts,
 	}
 
-	reader := &kafkaGoReaderImpl{*kafka.NewReader(config)}
+	reader := &kafkaGoReaderImpl{Reader: *kafka.NewReader(config), lag: map[int]int64{}}
 	return reader
 }
 
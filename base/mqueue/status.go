@@ -0,0 +1,78 @@
+package mqueue
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// ReaderStatus is consumer lag of a reader running in the process
+type ReaderStatus struct {
+	Topic string `json:"topic"`
+	Group string `json:"group"`
+	// consumer group lag of partitions assigned to the group, refreshed from broker committed offsets
+	Lag      int64 `json:"lag"`
+	InFlight int64 `json:"in_flight"`
+}
+
+var (
+	runningReaders = map[*kafkaGoReaderImpl]struct{}{}
+	readersLock    sync.Mutex
+)
+
+func registerReader(r *kafkaGoReaderImpl) func() {
+	readersLock.Lock()
+	defer readersLock.Unlock()
+	runningReaders[r] = struct{}{}
+	return func() {
+		readersLock.Lock()
+		defer readersLock.Unlock()
+		delete(runningReaders, r)
+	}
+}
+
+// ReadersStatus returns lag of kafka readers handling messages in the process
+func ReadersStatus() []ReaderStatus {
+	readersLock.Lock()
+	defer readersLock.Unlock()
+
+	res := make([]ReaderStatus, 0, len(runningReaders))
+	for r := range runningReaders {
+		res = append(res, r.status())
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Topic < res[j].Topic })
+	return res
+}
+
+func (t *kafkaGoReaderImpl) status() ReaderStatus {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	config := t.Config()
+	status := ReaderStatus{Topic: config.Topic, Group: config.GroupID, InFlight: t.inFlight}
+	for _, lag := range t.lag {
+		status.Lag += lag
+	}
+	return status
+}
+
+func (t *kafkaGoReaderImpl) messageFetched(m *kafka.Message) {
+	kafkaMessagesInFlight.WithLabelValues(m.Topic).Inc()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.inFlight++
+}
+
+func (t *kafkaGoReaderImpl) messageHandled(m *kafka.Message) {
+	kafkaMessagesInFlight.WithLabelValues(m.Topic).Dec()
+	if !m.Time.IsZero() {
+		kafkaMessageLatency.WithLabelValues(m.Topic).Observe(time.Since(m.Time).Seconds())
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.inFlight--
+}
@@ -0,0 +1,31 @@
+package mqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadersStatus(t *testing.T) {
+	reader := &kafkaGoReaderImpl{Reader: *kafka.NewReader(kafka.ReaderConfig{Brokers: []string{"kafka:9092"},
+		Topic: "test"})}
+	defer reader.Close()
+
+	unregister := registerReader(reader)
+	assert.Equal(t, []ReaderStatus{{Topic: "test"}}, ReadersStatus())
+
+	m0 := kafka.Message{Topic: "test", Partition: 0, Offset: 5, HighWaterMark: 10, Time: time.Now()}
+	m1 := kafka.Message{Topic: "test", Partition: 1, Offset: 2, HighWaterMark: 3}
+	reader.messageFetched(&m0)
+	reader.messageFetched(&m1)
+	reader.setLag(map[int]int64{0: 4, 1: 0})
+	assert.Equal(t, []ReaderStatus{{Topic: "test", Lag: 4, InFlight: 2}}, ReadersStatus())
+	reader.messageHandled(&m0)
+	reader.messageHandled(&m1)
+	assert.Equal(t, []ReaderStatus{{Topic: "test", Lag: 4}}, ReadersStatus())
+
+	unregister()
+	assert.Empty(t, ReadersStatus())
+}
@@ -0,0 +1,59 @@
+package mqueue
+
+import (
+	"app/base/tracing"
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// headersCarrier reads and writes trace context in kafka message headers
+type headersCarrier struct {
+	headers *[]kafka.Header
+}
+
+func (c headersCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c headersCarrier) Set(key, value string) {
+	for i, h := range *c.headers {
+		if h.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c headersCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, h := range *c.headers {
+		keys[i] = h.Key
+	}
+	return keys
+}
+
+// injectTraceContext returns copy of headers with trace context of ctx
+func injectTraceContext(ctx context.Context, headers []kafka.Header) []kafka.Header {
+	res := make([]kafka.Header, len(headers), len(headers)+2)
+	copy(res, headers)
+	tracing.Inject(ctx, headersCarrier{&res})
+	return res
+}
+
+func extractTraceContext(ctx context.Context, headers []kafka.Header) context.Context {
+	return tracing.Extract(ctx, headersCarrier{&headers})
+}
+
+func messagingAttributes(topic string) trace.SpanStartOption {
+	return trace.WithAttributes(attribute.String("messaging.system", "kafka"),
+		attribute.String("messaging.destination.name", topic))
+}
@@ -0,0 +1,42 @@
+package mqueue
+
+import (
+	"app/base/tracing"
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceContextHeaders(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(prev)
+
+	ctx, span := tracing.Start(context.Background(), "produce")
+	defer span.End()
+
+	headers := []kafka.Header{{Key: "service", Value: []byte("listener")}, {Key: "traceparent", Value: []byte("old")}}
+	injected := injectTraceContext(ctx, headers)
+	// original headers are kept and stale trace context is replaced
+	assert.Len(t, injected, 2)
+	assert.Equal(t, "old", string(headers[1].Value))
+	assert.Equal(t, "listener", headersCarrier{&injected}.Get("service"))
+	assert.ElementsMatch(t, []string{"service", "traceparent"}, headersCarrier{&injected}.Keys())
+
+	extracted := extractTraceContext(context.Background(), injected)
+	assert.Equal(t, span.SpanContext().TraceID(), trace.SpanContextFromContext(extracted).TraceID())
+	assert.False(t, trace.SpanContextFromContext(extractTraceContext(context.Background(), nil)).IsValid())
+}
+
+func TestKafkaMessageContext(t *testing.T) {
+	assert.NotNil(t, KafkaMessage{}.Context())
+	ctx := context.WithValue(context.Background(), testKey{}, "value")
+	assert.Equal(t, "value", KafkaMessage{ctx: ctx}.Context().Value(testKey{}))
+}
+
+type testKey struct{}
@@ -15,6 +15,8 @@ const (
 	Bundle           = "rhel"
 	Application      = "patch"
 	NewAdvisoryEvent = "new-advisory"
+	// Systems deleted by culling within notice days of the org culling policy
+	SystemsCullingEvent = "systems-culling-scheduled"
 )
 
 // TODO: Remove Context, MakeNotification and *Context field on Notification after fully migrating to the aggregator
@@ -80,6 +82,12 @@ type Advisory struct {
 	Synopsis     string `json:"synopsis"`
 }
 
+type CulledSystem struct {
+	InventoryID uuid.UUID `json:"inventory_id"`
+	DisplayName string    `json:"display_name"`
+	CullingDate string    `json:"culling_date"`
+}
+
 type SystemTag struct {
 	Key       string `json:"key,omitempty"`
 	Namespace string `json:"namespace,omitempty"`
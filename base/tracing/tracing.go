@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"app/base/utils"
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "app"
+
+var provider *sdktrace.TracerProvider
+
+func init() {
+	// propagate incoming trace context even when spans are not exported by this component
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+// Configure sets up span exporter selected by OTEL_TRACES_EXPORTER (otlp, console, none).
+// OTLP endpoint, headers and sampler are read by the SDK from standard OTEL_* variables.
+func Configure() {
+	if provider != nil {
+		return
+	}
+	exporterName := strings.ToLower(utils.Getenv("OTEL_TRACES_EXPORTER", "none"))
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch exporterName {
+	case "otlp":
+		exporter, err = otlptracehttp.New(context.Background())
+	case "console", "stdout":
+		exporter, err = stdouttrace.New()
+	case "none", "":
+		return
+	default:
+		utils.LogWarn("exporter", exporterName, "Unknown OTEL_TRACES_EXPORTER, tracing disabled")
+		return
+	}
+	if err != nil {
+		utils.LogError("err", err, "exporter", exporterName, "Unable to create trace exporter")
+		return
+	}
+
+	res, err := resource.New(context.Background(),
+		resource.WithAttributes(attribute.String("service.name", "patchman-"+component())),
+		resource.WithFromEnv(), resource.WithTelemetrySDK())
+	if err != nil {
+		utils.LogWarn("err", err, "Unable to detect tracing resource")
+	}
+	provider = sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	utils.LogInfo("exporter", exporterName, "Tracing configured")
+}
+
+// Shutdown flushes spans buffered by the exporter
+func Shutdown() {
+	if provider == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := provider.Shutdown(ctx); err != nil {
+		utils.LogWarn("err", err, "Unable to flush traces")
+	}
+}
+
+func component() string {
+	if len(os.Args) > 2 && os.Args[1] == "job" {
+		return os.Args[2]
+	}
+	if len(os.Args) > 1 {
+		return os.Args[1]
+	}
+	return "engine"
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start creates span as a child of span in ctx
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// End records err in the span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Link returns link to span in ctx, used by spans processing batches of traced events
+func Link(ctx context.Context) (trace.Link, bool) {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	return trace.Link{SpanContext: spanCtx}, spanCtx.IsValid()
+}
+
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+}
+
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
@@ -69,9 +69,11 @@ type coreConfig struct {
 	NotificationsTopic     string
 	TemplateTopic          string
 	InventoryViewsTopic    string
+	ReplayTopic            string
 
 	// services
 	VmaasAddress                  string
+	VmaasDumpPath                 string
 	RbacAddress                   string
 	CandlepinAddress              string
 	CandlepinCert                 string
@@ -183,10 +185,13 @@ func initTopicsFromEnv() {
 	CoreCfg.NotificationsTopic = Getenv("NOTIFICATIONS_TOPIC", "")
 	CoreCfg.TemplateTopic = Getenv("TEMPLATE_TOPIC", "")
 	CoreCfg.InventoryViewsTopic = Getenv("INVENTORY_VIEWS_TOPIC", "")
+	CoreCfg.ReplayTopic = Getenv("REPLAY_TOPIC", "")
 }
 
 func initServicesFromEnv() {
 	CoreCfg.VmaasAddress = Getenv("VMAAS_ADDRESS", CoreCfg.VmaasAddress)
+	// offline vmaas data used instead of VMAAS_ADDRESS in disconnected deployments
+	CoreCfg.VmaasDumpPath = Getenv("VMAAS_DUMP_PATH", "")
 	CoreCfg.RbacAddress = Getenv("RBAC_ADDRESS", CoreCfg.RbacAddress)
 	CoreCfg.CandlepinAddress = Getenv("CANDLEPIN_ADDRESS", CoreCfg.CandlepinAddress)
 	CoreCfg.CandlepinCert = Getenv("CANDLEPIN_CERT", CoreCfg.CandlepinCert)
@@ -268,6 +273,7 @@ func initKafkaFromClowder() {
 		translateTopic(&CoreCfg.TemplateTopic)
 		translateTopic(&CoreCfg.InventoryViewsTopic)
 		translateTopic(&CoreCfg.AdvisoryUpdateTopic)
+		translateTopic(&CoreCfg.ReplayTopic)
 	}
 }
 
@@ -424,10 +430,12 @@ func printKafkaParams() {
 	fmt.Printf("TEMPLATE_TOPIC=%s\n", CoreCfg.TemplateTopic)
 	fmt.Printf("INVENTORY_VIEWS_TOPIC=%s\n", CoreCfg.InventoryViewsTopic)
 	fmt.Printf("ADVISORY_UPDATE_TOPIC=%s\n", CoreCfg.AdvisoryUpdateTopic)
+	fmt.Printf("REPLAY_TOPIC=%s\n", CoreCfg.ReplayTopic)
 }
 
 func printServicesParams() {
 	fmt.Printf("VMAAS_ADDRESS=http://%s\n", CoreCfg.VmaasAddress)
+	fmt.Printf("VMAAS_DUMP_PATH=%s\n", CoreCfg.VmaasDumpPath)
 	fmt.Printf("RBAC_ADDRESS=http://%s\n", CoreCfg.RbacAddress)
 	fmt.Printf("CONTENT_SOURCES_ADDRESS=http://%s\n", CoreCfg.ContentSourcesAddress)
 }
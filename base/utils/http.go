@@ -15,6 +15,9 @@ import (
 	_ "net/http/pprof" //nolint:gosec
 )
 
+// ErrNoRetry wrapped in the error returned by the http call stops further retries
+var ErrNoRetry = errors.New("no retry")
+
 func HTTPCallRetry(httpCallFun func() (outputDataPtr interface{}, resp *http.Response, err error),
 	exponentialRetry bool, maxRetries int, codesToRetry ...int) (outputDataPtr interface{}, err error) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -24,6 +27,10 @@ func HTTPCallRetry(httpCallFun func() (outputDataPtr interface{}, resp *http.Res
 	for backoff.Continue(backoffState) {
 		attempt++
 		outDataPtr, resp, callErr := httpCallFun()
+		if errors.Is(callErr, ErrNoRetry) {
+			closeHTTPResponse(resp)
+			return nil, errors.Wrap(callErr, "HTTP call failed")
+		}
 		if statusCodeFound(resp, codesToRetry) {
 			closeHTTPResponse(resp)
 			LogWarn("attempt", attempt, "status_code", TryGetStatusCode(resp),
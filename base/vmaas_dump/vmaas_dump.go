@@ -0,0 +1,359 @@
+package vmaas_dump
+
+import (
+	"app/base/types"
+	"app/base/utils"
+	"app/base/vmaas"
+	"compress/gzip"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+)
+
+// Dump is an offline copy of VMaaS data for disconnected deployments.
+// It is a JSON document (gzip compressed when the file name ends with `.gz`) holding the same
+// errata, pkglist and repos data as VMaaS API returns, and an updates tree
+// mapping package name to all builds of the package available in repositories.
+type Dump struct {
+	Exported       *types.Rfc3339Timestamp                              `json:"exported"`
+	ErrataList     map[string]vmaas.ErrataResponseErrataList            `json:"errata_list"`
+	PackageList    []vmaas.PkgListItem                                  `json:"package_list"`
+	RepositoryList map[string][]map[string]interface{}                  `json:"repository_list"`
+	UpdatesTree    map[string][]vmaas.UpdatesV3ResponseAvailableUpdates `json:"updates_tree"`
+
+	builds map[string][]build
+}
+
+type build struct {
+	nevra  *utils.Nevra
+	update vmaas.UpdatesV3ResponseAvailableUpdates
+}
+
+// Source loads the dump from disk and reloads it whenever the file is modified
+type Source struct {
+	path     string
+	lock     sync.Mutex
+	modified time.Time
+	dump     *Dump
+}
+
+func NewSource(path string) *Source {
+	return &Source{path: path}
+}
+
+func (s *Source) Load() (*Dump, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to stat vmaas dump")
+	}
+	if s.dump != nil && !info.ModTime().After(s.modified) {
+		return s.dump, nil
+	}
+
+	dump, err := Read(s.path)
+	if err != nil {
+		return nil, err
+	}
+	utils.LogInfo("path", s.path, "exported", dump.Exported, "errata", len(dump.ErrataList),
+		"packages", len(dump.PackageList), "repos", len(dump.RepositoryList), "Loaded vmaas dump")
+	s.dump = dump
+	s.modified = info.ModTime()
+	return s.dump, nil
+}
+
+func Read(path string) (*Dump, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open vmaas dump")
+	}
+	defer f.Close()
+
+	var reader io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to decompress vmaas dump")
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read vmaas dump")
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) (*Dump, error) {
+	var dump Dump
+	if err := sonic.Unmarshal(data, &dump); err != nil {
+		return nil, errors.Wrap(err, "unable to parse vmaas dump")
+	}
+
+	dump.builds = make(map[string][]build, len(dump.UpdatesTree))
+	for name, updates := range dump.UpdatesTree {
+		for _, u := range updates {
+			nevra, err := utils.ParseNameEVRA(name, u.GetEVRA())
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid package %s in vmaas dump", name)
+			}
+			if u.PackageName == nil {
+				u.PackageName = utils.PtrString(name)
+			}
+			if u.Package == nil {
+				u.Package = utils.PtrString(nevra.String())
+			}
+			dump.builds[name] = append(dump.builds[name], build{nevra: nevra, update: u})
+		}
+	}
+	return &dump, nil
+}
+
+// DBChange returns the same data as VMaaS /dbchange
+func (d *Dump) DBChange() *vmaas.DBChangeResponse {
+	return &vmaas.DBChangeResponse{
+		ErrataChanges:     d.Exported,
+		RepositoryChanges: d.Exported,
+		LastChange:        d.Exported,
+		Exported:          d.Exported,
+	}
+}
+
+// Updates answers VMaaS /updates request, modules streams are not taken into account
+func (d *Dump) Updates(req *vmaas.UpdatesV3Request) *vmaas.UpdatesV3Response {
+	repos := make(map[string]bool, len(req.RepositoryList))
+	for _, r := range req.RepositoryList {
+		repos[r] = true
+	}
+
+	updateList := make(map[string]*vmaas.UpdatesV3ResponseUpdateList, len(req.PackageList))
+	for _, pkg := range req.PackageList {
+		installed, err := utils.ParseNevra(pkg)
+		if err != nil {
+			utils.LogWarn("nevra", pkg, "Unable to parse package, skipping")
+			continue
+		}
+		candidates := make([]*build, 0)
+		for i := range d.builds[installed.Name] {
+			if d.isUpdate(req, repos, installed, &d.builds[installed.Name][i]) {
+				candidates = append(candidates, &d.builds[installed.Name][i])
+			}
+		}
+		if req.LatestOnly != nil && *req.LatestOnly {
+			candidates = latestBuilds(candidates)
+		}
+		available := make([]vmaas.UpdatesV3ResponseAvailableUpdates, 0, len(candidates))
+		for _, b := range candidates {
+			available = append(available, b.update)
+		}
+		sort.SliceStable(available, func(i, j int) bool {
+			return available[i].Cmp(&available[j]) < 0
+		})
+		updateList[pkg] = &vmaas.UpdatesV3ResponseUpdateList{AvailableUpdates: &available}
+	}
+
+	return &vmaas.UpdatesV3Response{
+		UpdateList:     &updateList,
+		RepositoryList: &req.RepositoryList,
+		ModulesList:    req.ModulesList,
+		Releasever:     req.Releasever,
+		Basearch:       req.Basearch,
+		LastChange:     utils.PtrString(d.lastChange()),
+	}
+}
+
+func (d *Dump) isUpdate(req *vmaas.UpdatesV3Request, repos map[string]bool, installed *utils.Nevra, b *build) bool {
+	if b.nevra.Arch != installed.Arch && b.nevra.Arch != "noarch" && installed.Arch != "noarch" {
+		return false
+	}
+	candidate := *b.nevra
+	candidate.Arch = installed.Arch
+	if candidate.EVRACmp(installed) <= 0 {
+		return false
+	}
+	if len(repos) > 0 && !repos[b.update.GetRepository()] {
+		return false
+	}
+	if !matches(req.Releasever, b.update.Releasever) || !matches(req.Basearch, b.update.Basearch) {
+		return false
+	}
+	erratum := d.ErrataList[b.update.GetErratum()]
+	if req.SecurityOnly != nil && *req.SecurityOnly && erratum.Type != "security" {
+		return false
+	}
+	if isTrue(erratum.ThirdParty) && !isTrue(req.ThirdParty) {
+		return false
+	}
+	return true
+}
+
+func latestBuilds(builds []*build) []*build {
+	latest := make([]*build, 0, len(builds))
+	for _, b := range builds {
+		if len(latest) > 0 {
+			cmp := b.nevra.EVRACmp(latest[0].nevra)
+			if cmp < 0 {
+				continue
+			}
+			if cmp > 0 {
+				latest = latest[:0]
+			}
+		}
+		latest = append(latest, b)
+	}
+	return latest
+}
+
+// Errata answers VMaaS /errata request for all errata modified since `modified_since`
+func (d *Dump) Errata(req *vmaas.ErrataRequest) *vmaas.ErrataResponse {
+	names := make([]string, 0, len(d.ErrataList))
+	for name, erratum := range d.ErrataList {
+		if isTrue(erratum.ThirdParty) && !isTrue(req.ThirdParty) {
+			continue
+		}
+		if !modifiedSince(erratum.Updated, req.ModifiedSince) {
+			continue
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	pageNames, page, pageSize, pages := paginate(names, req.Page, req.PageSize)
+	errataList := make(map[string]vmaas.ErrataResponseErrataList, len(pageNames))
+	for _, name := range pageNames {
+		errataList[name] = d.ErrataList[name]
+	}
+	return &vmaas.ErrataResponse{
+		Page:       page,
+		PageSize:   pageSize,
+		Pages:      pages,
+		ErrataList: errataList,
+		LastChange: d.lastChange(),
+	}
+}
+
+// PkgList answers VMaaS /pkglist request for all packages modified since `modified_since`
+func (d *Dump) PkgList(req *vmaas.PkgListRequest) *vmaas.PkgListResponse {
+	packages := make([]vmaas.PkgListItem, 0, len(d.PackageList))
+	for _, pkg := range d.PackageList {
+		if modifiedSince(pkg.Modified, req.ModifiedSince) {
+			packages = append(packages, pkg)
+		}
+	}
+
+	pagePackages, page, pageSize, pages := paginate(packages, req.Page, req.PageSize)
+	return &vmaas.PkgListResponse{
+		Page:        page,
+		PageSize:    pageSize,
+		Pages:       pages,
+		LastChange:  utils.PtrString(d.lastChange()),
+		PackageList: pagePackages,
+		Total:       len(packages),
+	}
+}
+
+// Repos answers VMaaS /repos request for all repos modified since `modified_since`
+func (d *Dump) Repos(req *vmaas.ReposRequest) *vmaas.ReposResponse {
+	names := make([]string, 0, len(d.RepositoryList))
+	var latestRepoChange *time.Time
+	for name, contentSet := range d.RepositoryList {
+		include := false
+		for _, repo := range contentSet {
+			if repo["third_party"] == (interface{})(true) && !isTrue(req.ThirdParty) {
+				continue
+			}
+			lastChange, _ := repo["last_change"].(string)
+			if !modifiedSince(lastChange, req.ModifiedSince) {
+				continue
+			}
+			include = true
+			if ts, err := time.Parse(time.RFC3339Nano, lastChange); err == nil {
+				if latestRepoChange == nil || latestRepoChange.Before(ts) {
+					latestRepoChange = &ts
+				}
+			}
+		}
+		if include {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	pageNames, page, pageSize, pages := paginate(names, req.Page, req.PageSize)
+	repoList := make(map[string][]map[string]interface{}, len(pageNames))
+	for _, name := range pageNames {
+		repoList[name] = d.RepositoryList[name]
+	}
+	res := vmaas.ReposResponse{
+		Page:           page,
+		PageSize:       pageSize,
+		Pages:          pages,
+		RepositoryList: repoList,
+		LastChange:     utils.PtrString(d.lastChange()),
+	}
+	switch {
+	case latestRepoChange != nil:
+		ts := types.Rfc3339Timestamp(*latestRepoChange)
+		res.LatestRepoChange = &ts
+	case len(names) > 0:
+		res.LatestRepoChange = d.Exported
+	}
+	return &res
+}
+
+func (d *Dump) lastChange() string {
+	if d.Exported == nil {
+		return ""
+	}
+	return d.Exported.Time().Format(time.RFC3339)
+}
+
+// paginate the same way as VMaaS does, pages are numbered from 1
+func paginate[T any](items []T, page, pageSize int) ([]T, int, int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = len(items)
+	}
+	if len(items) == 0 {
+		return items, page, pageSize, 0
+	}
+	pages := (len(items) + pageSize - 1) / pageSize
+	from := min((page-1)*pageSize, len(items))
+	to := min(from+pageSize, len(items))
+	return items[from:to], page, pageSize, pages
+}
+
+func modifiedSince(modified string, since *string) bool {
+	if since == nil || modified == "" {
+		return true
+	}
+	sinceTS, err := time.Parse(time.RFC3339Nano, *since)
+	if err != nil {
+		return true
+	}
+	modifiedTS, err := time.Parse(time.RFC3339Nano, modified)
+	if err != nil {
+		return true
+	}
+	return modifiedTS.After(sinceTS)
+}
+
+func matches(requested, available *string) bool {
+	return requested == nil || available == nil || *requested == "" || *requested == *available
+}
+
+func isTrue(value *bool) bool {
+	return value != nil && *value
+}
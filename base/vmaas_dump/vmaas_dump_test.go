@@ -0,0 +1,141 @@
+package vmaas_dump
+
+import (
+	"app/base/utils"
+	"app/base/vmaas"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testDump = []byte(`{
+	"exported": "2024-05-01T10:00:00Z",
+	"errata_list": {
+		"RHSA-2024:0001": {"type": "security", "updated": "2024-04-01T00:00:00Z"},
+		"RHBA-2024:0002": {"type": "bugfix", "updated": "2024-04-20T00:00:00Z"},
+		"EPEL-2024:0003": {"type": "bugfix", "updated": "2024-04-20T00:00:00Z", "third_party": true}
+	},
+	"package_list": [
+		{"nevra": "kernel-5.14.0-1.el9.x86_64", "modified": "2024-04-01T00:00:00Z"},
+		{"nevra": "kernel-5.14.0-2.el9.x86_64", "modified": "2024-04-20T00:00:00Z"}
+	],
+	"repository_list": {
+		"rhel-9-baseos": [{"last_change": "2024-04-20T00:00:00Z", "updated_package_names": ["kernel"]}],
+		"epel-9": [{"last_change": "2024-04-20T00:00:00Z", "third_party": true}]
+	},
+	"updates_tree": {
+		"kernel": [
+			{"evra": "5.14.0-1.el9.x86_64", "erratum": "RHSA-2024:0001", "repository": "rhel-9-baseos",
+			 "releasever": "9", "basearch": "x86_64"},
+			{"evra": "5.14.0-2.el9.x86_64", "erratum": "RHBA-2024:0002", "repository": "rhel-9-baseos",
+			 "releasever": "9", "basearch": "x86_64"},
+			{"evra": "5.14.0-2.el9.aarch64", "erratum": "RHBA-2024:0002", "repository": "rhel-9-baseos",
+			 "releasever": "9", "basearch": "aarch64"},
+			{"evra": "5.14.0-3.el9.x86_64", "erratum": "EPEL-2024:0003", "repository": "epel-9"}
+		]
+	}
+}`)
+
+func updatesEVRAs(res *vmaas.UpdatesV3Response, pkg string) []string {
+	evras := []string{}
+	for _, u := range res.GetUpdateList()[pkg].GetAvailableUpdates() {
+		evras = append(evras, u.GetEVRA())
+	}
+	return evras
+}
+
+func TestUpdates(t *testing.T) {
+	dump, err := Parse(testDump)
+	assert.NoError(t, err)
+
+	pkg := "kernel-5.14.0-0.el9.x86_64"
+	req := vmaas.UpdatesV3Request{PackageList: []string{pkg, "unknown-1-1.noarch", "invalid"}}
+	res := dump.Updates(&req)
+	assert.Equal(t, []string{"5.14.0-1.el9.x86_64", "5.14.0-2.el9.x86_64"}, updatesEVRAs(res, pkg))
+	assert.Empty(t, updatesEVRAs(res, "unknown-1-1.noarch"))
+	assert.NotContains(t, res.GetUpdateList(), "invalid")
+	assert.Equal(t, "2024-05-01T10:00:00Z", *res.LastChange)
+	// package name and nevra are filled from the updates tree
+	update := res.GetUpdateList()[pkg].GetAvailableUpdates()[0]
+	assert.Equal(t, "kernel-5.14.0-1.el9.x86_64", update.GetPackage())
+
+	req.ThirdParty = utils.PtrBool(true)
+	res = dump.Updates(&req)
+	assert.Equal(t, []string{"5.14.0-1.el9.x86_64", "5.14.0-2.el9.x86_64", "5.14.0-3.el9.x86_64"},
+		updatesEVRAs(res, pkg))
+
+	req.LatestOnly = utils.PtrBool(true)
+	res = dump.Updates(&req)
+	assert.Equal(t, []string{"5.14.0-3.el9.x86_64"}, updatesEVRAs(res, pkg))
+
+	req = vmaas.UpdatesV3Request{PackageList: []string{pkg}, SecurityOnly: utils.PtrBool(true)}
+	res = dump.Updates(&req)
+	assert.Equal(t, []string{"5.14.0-1.el9.x86_64"}, updatesEVRAs(res, pkg))
+
+	req = vmaas.UpdatesV3Request{PackageList: []string{pkg}, RepositoryList: []string{"epel-9"}}
+	res = dump.Updates(&req)
+	assert.Empty(t, updatesEVRAs(res, pkg))
+}
+
+func TestErrataPkgListRepos(t *testing.T) {
+	dump, err := Parse(testDump)
+	assert.NoError(t, err)
+
+	errata := dump.Errata(&vmaas.ErrataRequest{Page: 0, PageSize: 1})
+	assert.Equal(t, 1, errata.Page)
+	assert.Equal(t, 2, errata.Pages)
+	assert.Contains(t, errata.ErrataList, "RHBA-2024:0002")
+	errata = dump.Errata(&vmaas.ErrataRequest{Page: 2, PageSize: 1})
+	assert.Contains(t, errata.ErrataList, "RHSA-2024:0001")
+
+	errata = dump.Errata(&vmaas.ErrataRequest{ThirdParty: utils.PtrBool(true),
+		ModifiedSince: utils.PtrString("2024-04-10T00:00:00Z")})
+	assert.Len(t, errata.ErrataList, 2)
+	assert.NotContains(t, errata.ErrataList, "RHSA-2024:0001")
+
+	pkgList := dump.PkgList(&vmaas.PkgListRequest{ModifiedSince: utils.PtrString("2024-04-10T00:00:00Z")})
+	assert.Equal(t, 1, pkgList.Total)
+	assert.Equal(t, "kernel-5.14.0-2.el9.x86_64", pkgList.PackageList[0].Nevra)
+
+	repos := dump.Repos(&vmaas.ReposRequest{Page: 1, PageSize: 10})
+	assert.Equal(t, 1, repos.Pages)
+	assert.Len(t, repos.RepositoryList, 1)
+	assert.Contains(t, repos.RepositoryList, "rhel-9-baseos")
+	assert.Equal(t, time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC), repos.LatestRepoChange.Time().UTC())
+	repos = dump.Repos(&vmaas.ReposRequest{ModifiedSince: utils.PtrString("2024-05-01T00:00:00Z")})
+	assert.Equal(t, 0, repos.Pages)
+	assert.Nil(t, repos.LatestRepoChange)
+
+	assert.Equal(t, dump.Exported, dump.DBChange().GetExported())
+}
+
+func TestSourceReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vmaas_dump.json.gz")
+	writeDump := func(data []byte, modified time.Time) {
+		f, err := os.Create(path)
+		assert.NoError(t, err)
+		gz := gzip.NewWriter(f)
+		_, err = gz.Write(data)
+		assert.NoError(t, err)
+		assert.NoError(t, gz.Close())
+		assert.NoError(t, f.Close())
+		assert.NoError(t, os.Chtimes(path, modified, modified))
+	}
+
+	now := time.Now()
+	writeDump(testDump, now.Add(-time.Hour))
+	source := NewSource(path)
+	dump, err := source.Load()
+	assert.NoError(t, err)
+	assert.Len(t, dump.ErrataList, 3)
+
+	writeDump([]byte(`{"exported": "2024-05-02T10:00:00Z"}`), now)
+	dump, err = source.Load()
+	assert.NoError(t, err)
+	assert.Empty(t, dump.ErrataList)
+	assert.Equal(t, time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC), dump.Exported.Time().UTC())
+}
@@ -19,6 +19,13 @@ var (
 	updateDBConfig = utils.PodConfig.GetBool("update_db_config", false)
 	// Terminate lockUsers sessions after NOLOGIN (for major DDL migrations)
 	terminateDBSessions = utils.PodConfig.GetBool("terminate_db_sessions", false)
+	// Run migrations without blocking app users when all of them are classified as online
+	onlineMigrations = utils.PodConfig.GetBool("online_migrations", false)
+	// Lock wait limit of online migration statements, the migration is retried after timeout
+	onlineMigrationLockTimeoutMs = utils.PodConfig.GetInt("online_migration_lock_timeout_ms", 5000)
+	onlineMigrationRetries       = utils.PodConfig.GetInt("online_migration_retries", 5)
+	// Print migration plan and exit
+	migrationDryRun = utils.PodConfig.GetBool("migration_dry_run", false)
 	// One-off: truncate corrupt system_advisories_0 and clear bucket-0 advisory caches
 	repairSystemAdvisories0 = utils.PodConfig.GetBool("repair_system_advisories_0", false)
 )
@@ -0,0 +1,333 @@
+package database_admin
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// LockLevel is the strongest table lock taken by a statement
+type LockLevel int
+
+const (
+	LockNone LockLevel = iota
+	// allows reads and writes, e.g. CREATE INDEX CONCURRENTLY, VALIDATE CONSTRAINT
+	LockShareUpdateExclusive
+	// blocks writes, e.g. CREATE INDEX
+	LockShare
+	// blocks writes, e.g. ADD FOREIGN KEY, CREATE TRIGGER
+	LockShareRowExclusive
+	// blocks reads and writes, e.g. most of ALTER TABLE, DROP TABLE
+	LockAccessExclusive
+)
+
+func (l LockLevel) String() string {
+	switch l {
+	case LockShareUpdateExclusive:
+		return "SHARE UPDATE EXCLUSIVE"
+	case LockShare:
+		return "SHARE"
+	case LockShareRowExclusive:
+		return "SHARE ROW EXCLUSIVE"
+	case LockAccessExclusive:
+		return "ACCESS EXCLUSIVE"
+	default:
+		return "NONE"
+	}
+}
+
+// StatementCheck is classification of a single migration statement.
+// Statements holding lock only briefly (metadata changes) are not blocking.
+type StatementCheck struct {
+	SQL      string
+	Table    string
+	Lock     LockLevel
+	Rewrite  bool
+	Blocking bool
+	Issue    string
+}
+
+// MigrationCheck is classification of a migration file, online migration can run without stopping app components
+type MigrationCheck struct {
+	Version    int
+	Name       string
+	Statements []StatementCheck
+	// declared by `-- offline` comment in the migration file
+	Offline bool
+	Issues  []string
+}
+
+func (m *MigrationCheck) Online() bool {
+	return !m.Offline && len(m.Issues) == 0
+}
+
+func (m *MigrationCheck) Lock() LockLevel {
+	lock := LockNone
+	for _, s := range m.Statements {
+		lock = max(lock, s.Lock)
+	}
+	return lock
+}
+
+func (m *MigrationCheck) Rewrite() bool {
+	for _, s := range m.Statements {
+		if s.Rewrite {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MigrationCheck) String() string {
+	mode := "online"
+	if !m.Online() {
+		mode = "offline"
+	}
+	res := fmt.Sprintf("%s: %s, lock %s, rewrite %t", m.Name, mode, m.Lock(), m.Rewrite())
+	for _, issue := range m.Issues {
+		res += "\n  - " + issue
+	}
+	return res
+}
+
+var (
+	offlineMarkerRe = regexp.MustCompile(`(?m)^--\s*offline\b`)
+	dollarQuoteRe   = regexp.MustCompile(`^\$(?:[A-Za-z_]\w*)?\$`)
+	whitespaceRe    = regexp.MustCompile(`\s+`)
+
+	alterTableRe  = regexp.MustCompile(`^ALTER TABLE (?:IF EXISTS )?(?:ONLY )?([\w."]+)`)
+	createTableRe = regexp.MustCompile(`^CREATE (?:UNLOGGED |TEMP |TEMPORARY )?TABLE (?:IF NOT EXISTS )?([\w."]+)`)
+	createIndexRe = regexp.MustCompile(`^CREATE (?:UNIQUE )?INDEX (CONCURRENTLY )?(?:.*? )?ON (?:ONLY )?([\w."]+)`)
+	tableStmtRe   = regexp.MustCompile(
+		`^(?:UPDATE |DELETE FROM |TRUNCATE (?:TABLE )?|DROP TABLE (?:IF EXISTS )?)(?:ONLY )?([\w."]+)`)
+	partitionOfRe = regexp.MustCompile(` PARTITION OF ([\w."]+)`)
+
+	volatileDefaultRe = regexp.MustCompile(`DEFAULT [^,]*(RANDOM|GEN_RANDOM_UUID|UUID_GENERATE|CLOCK_TIMESTAMP|NEXTVAL)\(`)
+	addConstraintRe   = regexp.MustCompile(`ADD (?:CONSTRAINT [\w"]+ )?(FOREIGN KEY|CHECK|PRIMARY KEY|UNIQUE)`)
+)
+
+// statement prefixes changing no table data or holding locks only for metadata update
+var safePrefixes = []string{
+	"GRANT ", "REVOKE ", "COMMENT ON ", "SET ", "RESET ", "BEGIN", "COMMIT", "START TRANSACTION", "END",
+	"CREATE FUNCTION ", "CREATE OR REPLACE FUNCTION ", "CREATE PROCEDURE ", "CREATE OR REPLACE PROCEDURE ",
+	"CREATE VIEW ", "CREATE OR REPLACE VIEW ", "CREATE TYPE ", "CREATE SEQUENCE ", "CREATE SCHEMA ",
+	"CREATE EXTENSION ", "CREATE MATERIALIZED VIEW ", "CREATE ROLE ", "CREATE USER ",
+	"DROP FUNCTION ", "DROP PROCEDURE ", "DROP VIEW ", "DROP TYPE ", "DROP SEQUENCE ", "DROP ROLE ", "DROP USER ",
+	"ALTER FUNCTION ", "ALTER PROCEDURE ", "ALTER TYPE ", "ALTER SEQUENCE ", "ALTER ROLE ", "ALTER USER ",
+	"ALTER DEFAULT PRIVILEGES ", "ALTER VIEW ", "ANALYZE", "INSERT INTO ",
+	// partitioning helpers of the schema, creating and granting new tables only
+	"SELECT CREATE_TABLE_PARTITIONS(", "SELECT GRANT_TABLE_PARTITIONS(",
+}
+
+// splitStatements splits SQL script to statements without comments
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(script); {
+		rest := script[i:]
+		end := 1
+		switch {
+		case strings.HasPrefix(rest, "--"):
+			if end = strings.IndexByte(rest, '\n'); end < 0 {
+				end = len(rest)
+			}
+			current.WriteByte(' ')
+		case strings.HasPrefix(rest, "/*"):
+			if end = strings.Index(rest, "*/") + 2; end < 2 {
+				end = len(rest)
+			}
+			current.WriteByte(' ')
+		case rest[0] == '\'':
+			for end < len(rest) {
+				if rest[end] == '\'' && (end+1 == len(rest) || rest[end+1] != '\'') {
+					end++
+					break
+				}
+				if rest[end] == '\'' {
+					// escaped quote
+					end++
+				}
+				end++
+			}
+			current.WriteString(rest[:end])
+		case rest[0] == '$' && dollarQuoteRe.MatchString(rest):
+			tag := dollarQuoteRe.FindString(rest)
+			if end = strings.Index(rest[len(tag):], tag) + 2*len(tag); end < 2*len(tag) {
+				end = len(rest)
+			}
+			current.WriteString(rest[:end])
+		case rest[0] == ';':
+			flush()
+		default:
+			current.WriteByte(rest[0])
+		}
+		i += min(end, len(rest))
+	}
+	flush()
+	return statements
+}
+
+func normalizeStatement(stmt string) string {
+	return strings.ToUpper(whitespaceRe.ReplaceAllString(strings.TrimSpace(stmt), " "))
+}
+
+func tableName(name string) string {
+	return strings.ToLower(strings.Trim(name, `"`))
+}
+
+func abbreviate(stmt string) string {
+	stmt = whitespaceRe.ReplaceAllString(strings.TrimSpace(stmt), " ")
+	if len(stmt) > 80 {
+		return stmt[:77] + "..."
+	}
+	return stmt
+}
+
+// classifyStatement returns lock and blocking risk of the statement,
+// `created` are tables created earlier in the same migration which are not used by app yet
+func classifyStatement(stmt string, created map[string]bool) StatementCheck {
+	norm := normalizeStatement(stmt)
+	check := StatementCheck{SQL: abbreviate(stmt)}
+
+	for _, prefix := range safePrefixes {
+		if strings.HasPrefix(norm, prefix) {
+			return check
+		}
+	}
+
+	switch {
+	case createTableRe.MatchString(norm):
+		check.Table = tableName(createTableRe.FindStringSubmatch(norm)[1])
+		created[check.Table] = true
+		if m := partitionOfRe.FindStringSubmatch(norm); m != nil && !created[tableName(m[1])] {
+			// new partition briefly locks its parent
+			check.Lock = LockAccessExclusive
+		}
+	case createIndexRe.MatchString(norm):
+		m := createIndexRe.FindStringSubmatch(norm)
+		check.Table = tableName(m[2])
+		switch {
+		case m[1] != "":
+			check.Lock = LockShareUpdateExclusive
+		case !created[check.Table]:
+			check.Lock = LockShare
+			check.Blocking = true
+			check.Issue = "CREATE INDEX without CONCURRENTLY blocks writes to " + check.Table
+		}
+	case strings.HasPrefix(norm, "DROP INDEX CONCURRENTLY "):
+		check.Lock = LockShareUpdateExclusive
+	case strings.HasPrefix(norm, "DROP INDEX "), strings.HasPrefix(norm, "ALTER INDEX "),
+		strings.HasPrefix(norm, "CREATE TRIGGER "), strings.HasPrefix(norm, "CREATE OR REPLACE TRIGGER "),
+		strings.HasPrefix(norm, "DROP TRIGGER "):
+		check.Lock = LockAccessExclusive
+	case alterTableRe.MatchString(norm):
+		check.Table = tableName(alterTableRe.FindStringSubmatch(norm)[1])
+		check.Lock = LockAccessExclusive
+		if !created[check.Table] {
+			classifyAlterTable(norm, &check)
+		}
+	case strings.HasPrefix(norm, "REFRESH MATERIALIZED VIEW CONCURRENTLY "):
+		check.Lock = LockShareUpdateExclusive
+	case strings.HasPrefix(norm, "REFRESH MATERIALIZED VIEW "):
+		check.Lock = LockAccessExclusive
+		check.Blocking = true
+		check.Issue = "REFRESH MATERIALIZED VIEW without CONCURRENTLY blocks reads"
+	case strings.HasPrefix(norm, "VACUUM FULL"), strings.HasPrefix(norm, "CLUSTER"):
+		check.Lock = LockAccessExclusive
+		check.Rewrite = true
+		check.Blocking = true
+		check.Issue = "table rewrite blocks reads and writes"
+	case tableStmtRe.MatchString(norm):
+		check.Table = tableName(tableStmtRe.FindStringSubmatch(norm)[1])
+		switch {
+		case created[check.Table]:
+		case strings.HasPrefix(norm, "DROP TABLE "):
+			check.Lock = LockAccessExclusive
+		case strings.HasPrefix(norm, "TRUNCATE"):
+			check.Lock = LockAccessExclusive
+			check.Blocking = true
+			check.Issue = "TRUNCATE of " + check.Table + " removes data used by running components"
+		case !strings.Contains(norm, " WHERE "):
+			check.Blocking = true
+			check.Issue = "UPDATE/DELETE of all rows of " + check.Table + " locks them for the whole migration"
+		}
+	default:
+		check.Blocking = true
+		check.Issue = "unknown effect of statement, review manually: " + check.SQL
+	}
+	return check
+}
+
+func classifyAlterTable(norm string, check *StatementCheck) {
+	switch {
+	case strings.Contains(norm, " TYPE ") && strings.Contains(norm, "ALTER COLUMN "):
+		check.Rewrite = true
+		check.Issue = "column type change rewrites " + check.Table
+	case strings.Contains(norm, "SET NOT NULL"):
+		check.Issue = "SET NOT NULL scans " + check.Table + " under ACCESS EXCLUSIVE lock, " +
+			"add CHECK (... IS NOT NULL) NOT VALID constraint and validate it first"
+	case strings.Contains(norm, "SET TABLESPACE"), strings.Contains(norm, "SET LOGGED"),
+		strings.Contains(norm, "SET UNLOGGED"):
+		check.Rewrite = true
+		check.Issue = "table rewrite of " + check.Table
+	case strings.Contains(norm, "ADD COLUMN") && volatileDefaultRe.MatchString(norm),
+		strings.Contains(norm, "ADD COLUMN") && strings.Contains(norm, " STORED"):
+		check.Rewrite = true
+		check.Issue = "new column with volatile default or stored generated value rewrites " + check.Table
+	case strings.Contains(norm, " REFERENCES ") && !strings.Contains(norm, "NOT VALID"):
+		check.Lock = LockShareRowExclusive
+		check.Issue = "foreign key on " + check.Table + " is validated under lock, add it NOT VALID " +
+			"and VALIDATE CONSTRAINT in separate migration"
+	case addConstraintRe.MatchString(norm):
+		kind := addConstraintRe.FindStringSubmatch(norm)[1]
+		switch {
+		case (kind == "PRIMARY KEY" || kind == "UNIQUE") && !strings.Contains(norm, "USING INDEX"):
+			check.Issue = kind + " constraint builds index on " + check.Table + " under lock, " +
+				"create the index CONCURRENTLY and add the constraint USING INDEX"
+		case kind == "FOREIGN KEY" && !strings.Contains(norm, "NOT VALID"):
+			check.Lock = LockShareRowExclusive
+			check.Issue = "foreign key on " + check.Table + " is validated under lock, add it NOT VALID"
+		case kind == "CHECK" && !strings.Contains(norm, "NOT VALID"):
+			check.Issue = "CHECK constraint on " + check.Table + " is validated under lock, add it NOT VALID"
+		}
+	case strings.Contains(norm, "VALIDATE CONSTRAINT"), strings.Contains(norm, "ATTACH PARTITION"),
+		strings.Contains(norm, "DETACH PARTITION") && strings.Contains(norm, "CONCURRENTLY"),
+		strings.Contains(norm, " SET ("), strings.Contains(norm, " RESET ("):
+		check.Lock = LockShareUpdateExclusive
+	}
+	check.Blocking = check.Issue != ""
+}
+
+// LintMigration classifies statements of migration file
+func LintMigration(path string) (MigrationCheck, error) {
+	script, err := os.ReadFile(path)
+	if err != nil {
+		return MigrationCheck{}, err
+	}
+	name := path[strings.LastIndexByte(path, '/')+1:]
+	check := MigrationCheck{Name: name, Offline: offlineMarkerRe.Match(script)}
+	fmt.Sscanf(name, "%d_", &check.Version) //nolint:errcheck
+
+	statements := splitStatements(string(script))
+	created := map[string]bool{}
+	for _, stmt := range statements {
+		stmtCheck := classifyStatement(stmt, created)
+		check.Statements = append(check.Statements, stmtCheck)
+		if stmtCheck.Issue != "" {
+			check.Issues = append(check.Issues, stmtCheck.Issue)
+		}
+		if strings.Contains(normalizeStatement(stmt), " CONCURRENTLY ") && len(statements) > 1 {
+			check.Issues = append(check.Issues,
+				"CONCURRENTLY can't run in transaction, it has to be the only statement of the migration")
+		}
+	}
+	return check, nil
+}
@@ -0,0 +1,106 @@
+package database_admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- comment; with semicolon
+CREATE OR REPLACE FUNCTION f() RETURNS INT AS
+$$
+BEGIN
+    RETURN 1; -- inside function
+END;
+$$ LANGUAGE plpgsql;
+/* block; comment */
+INSERT INTO t (v) VALUES ('a;b');
+`
+	statements := splitStatements(script)
+	require.Len(t, statements, 2)
+	assert.Contains(t, statements[0], "RETURN 1;")
+	assert.Contains(t, statements[1], "'a;b'")
+}
+
+func TestClassifyStatement(t *testing.T) {
+	tests := []struct {
+		stmt     string
+		lock     LockLevel
+		rewrite  bool
+		blocking bool
+	}{
+		{"GRANT SELECT ON system_platform TO manager", LockNone, false, false},
+		{"CREATE INDEX ON system_platform (stale)", LockShare, false, true},
+		{"CREATE INDEX CONCURRENTLY IF NOT EXISTS x ON system_platform (stale)", LockShareUpdateExclusive, false, false},
+		{"ALTER TABLE system_platform ADD COLUMN x INT", LockAccessExclusive, false, false},
+		{"ALTER TABLE system_platform ADD COLUMN x UUID DEFAULT gen_random_uuid()", LockAccessExclusive, true, true},
+		{"ALTER TABLE system_platform ALTER COLUMN x TYPE BIGINT", LockAccessExclusive, true, true},
+		{"ALTER TABLE system_platform ALTER COLUMN x SET NOT NULL", LockAccessExclusive, false, true},
+		{"ALTER TABLE system_platform ADD FOREIGN KEY (x) REFERENCES rh_account (id) NOT VALID",
+			LockAccessExclusive, false, false},
+		{"ALTER TABLE system_platform ADD FOREIGN KEY (x) REFERENCES rh_account (id)",
+			LockShareRowExclusive, false, true},
+		{"ALTER TABLE system_platform VALIDATE CONSTRAINT x_fkey", LockShareUpdateExclusive, false, false},
+		{"UPDATE system_platform SET x = 1", LockNone, false, true},
+		{"UPDATE system_platform SET x = 1 WHERE id < 1000", LockNone, false, false},
+		{"TRUNCATE system_platform", LockAccessExclusive, false, true},
+		{"REFRESH MATERIALIZED VIEW CONCURRENTLY mv", LockShareUpdateExclusive, false, false},
+		{"DO $$ BEGIN PERFORM 1; END $$", LockNone, false, true},
+	}
+	for _, tc := range tests {
+		check := classifyStatement(tc.stmt, map[string]bool{})
+		assert.Equal(t, tc.lock, check.Lock, tc.stmt)
+		assert.Equal(t, tc.rewrite, check.Rewrite, tc.stmt)
+		assert.Equal(t, tc.blocking, check.Blocking, tc.stmt)
+	}
+}
+
+func TestClassifyStatementNewTable(t *testing.T) {
+	created := map[string]bool{}
+	check := classifyStatement("CREATE TABLE IF NOT EXISTS new_table (id INT)", created)
+	assert.False(t, check.Blocking)
+	// indexes and constraints of table created in the same migration don't block anything
+	check = classifyStatement("CREATE UNIQUE INDEX ON new_table (id)", created)
+	assert.False(t, check.Blocking)
+	check = classifyStatement("ALTER TABLE new_table ADD FOREIGN KEY (id) REFERENCES rh_account (id)", created)
+	assert.False(t, check.Blocking)
+}
+
+func TestLintMigrations(t *testing.T) {
+	latest, err := latestSchemaMigrationFileVersion("file://migrations")
+	require.NoError(t, err)
+	plan, err := planMigrations("file://migrations", 0, latest)
+	require.NoError(t, err)
+	require.NotEmpty(t, plan.Migrations)
+	assert.False(t, plan.Online())
+
+	checks := map[int]MigrationCheck{}
+	for _, m := range plan.Migrations {
+		checks[m.Version] = m
+		if m.Version > lintBaselineVersion {
+			assert.True(t, m.Online() || m.Offline, "%s is neither online nor marked `-- offline`", m.Name)
+		}
+	}
+	vmaasCache, packageHold := checks[176], checks[172]
+	assert.True(t, vmaasCache.Online(), vmaasCache.String())
+	assert.False(t, packageHold.Online(), packageHold.String())
+}
+
+func TestPlanMigrations(t *testing.T) {
+	plan, err := planMigrations("file://migrations", 173, 176)
+	require.NoError(t, err)
+	require.Len(t, plan.Migrations, 3)
+	assert.Equal(t, 174, plan.Migrations[0].Version)
+	assert.Equal(t, 176, plan.Migrations[2].Version)
+	assert.True(t, plan.Online())
+	assert.Equal(t, 174, plan.previousVersion(175))
+	assert.Equal(t, 173, plan.previousVersion(174))
+
+	plan, err = planMigrations("file://migrations", 176, 174)
+	require.NoError(t, err)
+	require.Len(t, plan.Migrations, 2)
+	assert.Equal(t, "176_vmaas_cache.down.sql", plan.Migrations[0].Name)
+	assert.False(t, plan.Online())
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS patch_plan_item;
+DROP TABLE IF EXISTS patch_plan;
@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS patch_plan
+(
+    id            BIGINT      GENERATED BY DEFAULT AS IDENTITY,
+    rh_account_id INT         NOT NULL REFERENCES rh_account (id),
+    name          TEXT        NOT NULL CHECK (not empty(name)),
+    description   TEXT        CHECK (NOT empty(description)),
+    window_start  TIMESTAMPTZ NOT NULL,
+    window_end    TIMESTAMPTZ NOT NULL,
+    created       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (rh_account_id, id),
+    UNIQUE (rh_account_id, name),
+    CHECK (window_start < window_end)
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('patch_plan', 16,
+                               $$WITH (fillfactor = '70', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+SELECT grant_table_partitions('SELECT, INSERT, UPDATE, DELETE', 'patch_plan', 'manager');
+SELECT grant_table_partitions('SELECT', 'patch_plan', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'patch_plan', 'listener');
+SELECT grant_table_partitions('SELECT', 'patch_plan', 'vmaas_sync');
+
+-- system/advisory pairs selected by a patch plan at the time of its creation,
+-- pairs which disappear from system_advisories are considered remediated
+CREATE TABLE IF NOT EXISTS patch_plan_item
+(
+    rh_account_id INT    NOT NULL,
+    plan_id       BIGINT NOT NULL,
+    system_id     BIGINT NOT NULL,
+    advisory_id   BIGINT NOT NULL,
+    PRIMARY KEY (rh_account_id, plan_id, system_id, advisory_id),
+    CONSTRAINT patch_plan_item_plan_id
+        FOREIGN KEY (rh_account_id, plan_id)
+            REFERENCES patch_plan (rh_account_id, id) ON DELETE CASCADE,
+    CONSTRAINT patch_plan_item_system_id
+        FOREIGN KEY (rh_account_id, system_id)
+            REFERENCES system_inventory (rh_account_id, id) ON DELETE CASCADE,
+    CONSTRAINT patch_plan_item_advisory_id
+        FOREIGN KEY (advisory_id)
+            REFERENCES advisory_metadata (id) ON DELETE CASCADE
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('patch_plan_item', 16,
+                               $$WITH (fillfactor = '70', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+CREATE INDEX ON patch_plan_item (rh_account_id, system_id);
+
+SELECT grant_table_partitions('SELECT, INSERT, UPDATE, DELETE', 'patch_plan_item', 'manager');
+SELECT grant_table_partitions('SELECT', 'patch_plan_item', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'patch_plan_item', 'listener');
+SELECT grant_table_partitions('SELECT', 'patch_plan_item', 'vmaas_sync');
@@ -0,0 +1,13 @@
+-- per-org override of the global repo.third_party flag
+CREATE TABLE IF NOT EXISTS repo_classification
+(
+    rh_account_id  INT    NOT NULL REFERENCES rh_account (id),
+    repo_id        BIGINT NOT NULL REFERENCES repo (id) ON DELETE CASCADE,
+    classification TEXT   NOT NULL CHECK (classification IN ('trusted', 'third_party', 'ignored')),
+    PRIMARY KEY (rh_account_id, repo_id)
+) TABLESPACE pg_default;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON repo_classification TO manager;
+GRANT SELECT ON repo_classification TO evaluator;
+GRANT SELECT ON repo_classification TO listener;
+GRANT SELECT ON repo_classification TO vmaas_sync;
@@ -0,0 +1,26 @@
+-- append-only journal of package changes detected by evaluator
+CREATE TABLE IF NOT EXISTS system_package_change
+(
+    id            BIGINT      GENERATED BY DEFAULT AS IDENTITY,
+    rh_account_id INT         NOT NULL,
+    system_id     BIGINT      NOT NULL,
+    name_id       BIGINT      NOT NULL REFERENCES package_name (id),
+    change        TEXT        NOT NULL CHECK (change IN ('installed', 'removed', 'upgraded', 'downgraded')),
+    old_evra      TEXT        CHECK (NOT empty(old_evra)),
+    new_evra      TEXT        CHECK (NOT empty(new_evra)),
+    changed       TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (rh_account_id, system_id, id),
+    CONSTRAINT system_package_change_system_id
+        FOREIGN KEY (rh_account_id, system_id)
+            REFERENCES system_inventory (rh_account_id, id) ON DELETE CASCADE
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('system_package_change', 16,
+                               $$WITH (fillfactor = '100', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+CREATE INDEX ON system_package_change (rh_account_id, name_id, changed);
+
+SELECT grant_table_partitions('SELECT', 'system_package_change', 'manager');
+SELECT grant_table_partitions('SELECT, INSERT', 'system_package_change', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'system_package_change', 'listener');
+SELECT grant_table_partitions('SELECT', 'system_package_change', 'vmaas_sync');
@@ -0,0 +1,7 @@
+REVOKE UPDATE (baseline_id) ON system_patch FROM manager;
+
+ALTER TABLE system_patch DROP CONSTRAINT IF EXISTS system_patch_baseline_id;
+ALTER TABLE system_patch DROP COLUMN IF EXISTS baseline_id,
+                         DROP COLUMN IF EXISTS baseline_advisory_count_cache;
+
+DROP TABLE IF EXISTS baseline;
@@ -0,0 +1,33 @@
+-- offline: foreign key on system_patch is validated under ACCESS EXCLUSIVE lock
+-- baseline limits advisories evaluated for assigned systems either to updates
+-- which are already installed on a reference system or to advisories released before a cutoff date,
+-- reference system is not a foreign key, baseline of a deleted reference system does not limit packages
+CREATE TABLE IF NOT EXISTS baseline
+(
+    id                  BIGINT      GENERATED BY DEFAULT AS IDENTITY,
+    rh_account_id       INT         NOT NULL REFERENCES rh_account (id),
+    name                TEXT        NOT NULL CHECK (not empty(name)),
+    description         TEXT        CHECK (NOT empty(description)),
+    reference_system_id BIGINT,
+    advisory_cutoff     TIMESTAMPTZ,
+    created             TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_edited         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (rh_account_id, id),
+    UNIQUE (rh_account_id, name),
+    CHECK (reference_system_id IS NOT NULL OR advisory_cutoff IS NOT NULL)
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('baseline', 16,
+                               $$WITH (fillfactor = '70', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+SELECT grant_table_partitions('SELECT, INSERT, UPDATE, DELETE', 'baseline', 'manager');
+SELECT grant_table_partitions('SELECT', 'baseline', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'baseline', 'listener');
+SELECT grant_table_partitions('SELECT', 'baseline', 'vmaas_sync');
+
+ALTER TABLE system_patch ADD COLUMN IF NOT EXISTS baseline_id BIGINT,
+                         ADD COLUMN IF NOT EXISTS baseline_advisory_count_cache INT NOT NULL DEFAULT 0;
+ALTER TABLE system_patch ADD CONSTRAINT system_patch_baseline_id
+    FOREIGN KEY (rh_account_id, baseline_id) REFERENCES baseline (rh_account_id, id);
+
+GRANT UPDATE (baseline_id) ON system_patch TO manager;
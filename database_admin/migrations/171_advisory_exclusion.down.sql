@@ -0,0 +1,90 @@
+CREATE OR REPLACE FUNCTION refresh_account_advisory_caches_multi(advisory_ids_in INTEGER[] DEFAULT NULL,
+                                                                  rh_account_id_in INTEGER DEFAULT NULL)
+    RETURNS VOID AS
+$refresh_account_advisory$
+BEGIN
+    PERFORM aa.rh_account_id, aa.workspace_id, aa.advisory_id
+    FROM account_advisory aa
+    WHERE (aa.advisory_id = ANY (advisory_ids_in) OR advisory_ids_in IS NULL)
+      AND (aa.rh_account_id = rh_account_id_in OR rh_account_id_in IS NULL)
+        FOR UPDATE OF aa;
+
+    WITH current_counts AS (
+        SELECT sa.advisory_id, sa.rh_account_id, si.workspace_id,
+               count(sa.*) FILTER (WHERE sa.status_id = 0) AS systems_installable,
+               count(sa.*) AS systems_applicable
+          FROM system_advisories sa
+          JOIN system_inventory si
+            ON sa.rh_account_id = si.rh_account_id AND sa.system_id = si.id
+          JOIN system_patch sp
+            ON si.id = sp.system_id AND sp.rh_account_id = si.rh_account_id
+         WHERE sp.last_evaluation IS NOT NULL
+           AND si.stale = FALSE
+           AND si.workspace_id IS NOT NULL
+           AND (sa.advisory_id = ANY (advisory_ids_in) OR advisory_ids_in IS NULL)
+           AND (si.rh_account_id = rh_account_id_in OR rh_account_id_in IS NULL)
+         GROUP BY sa.advisory_id, sa.rh_account_id, si.workspace_id
+    ),
+        upserted AS (
+            INSERT INTO account_advisory (advisory_id, rh_account_id, workspace_id, systems_installable, systems_applicable)
+                 SELECT advisory_id, rh_account_id, workspace_id, systems_installable, systems_applicable
+                   FROM current_counts
+            ON CONFLICT (rh_account_id, workspace_id, advisory_id) DO UPDATE SET
+                     systems_installable = EXCLUDED.systems_installable,
+                     systems_applicable = EXCLUDED.systems_applicable
+         )
+    DELETE FROM account_advisory
+     WHERE (advisory_id, rh_account_id, workspace_id) NOT IN (SELECT advisory_id, rh_account_id, workspace_id FROM current_counts)
+       AND (advisory_id = ANY (advisory_ids_in) OR advisory_ids_in IS NULL)
+       AND (rh_account_id = rh_account_id_in OR rh_account_id_in IS NULL);
+END;
+$refresh_account_advisory$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION refresh_system_caches(system_id_in BIGINT DEFAULT NULL,
+                                                 rh_account_id_in INTEGER DEFAULT NULL)
+    RETURNS INTEGER AS
+$refresh_system$
+DECLARE
+    COUNT INTEGER;
+BEGIN
+    WITH system_advisories_count AS (
+        SELECT si.rh_account_id, si.id,
+               COUNT(advisory_id) FILTER (WHERE sa.status_id = 0) as installable_total,
+               COUNT(advisory_id) FILTER (WHERE am.advisory_type_id = 1 AND sa.status_id = 0) AS installable_enhancement,
+               COUNT(advisory_id) FILTER (WHERE am.advisory_type_id = 2 AND sa.status_id = 0) AS installable_bugfix,
+               COUNT(advisory_id) FILTER (WHERE am.advisory_type_id = 3 AND sa.status_id = 0) as installable_security,
+               COUNT(advisory_id) as applicable_total,
+               COUNT(advisory_id) FILTER (WHERE am.advisory_type_id = 1) AS applicable_enhancement,
+               COUNT(advisory_id) FILTER (WHERE am.advisory_type_id = 2) AS applicable_bugfix,
+               COUNT(advisory_id) FILTER (WHERE am.advisory_type_id = 3) as applicable_security
+          FROM system_inventory si  -- this table ensures even systems without any system_advisories are in results
+          LEFT JOIN system_advisories sa
+            ON si.rh_account_id = sa.rh_account_id AND si.id = sa.system_id
+          LEFT JOIN advisory_metadata am
+            ON sa.advisory_id = am.id
+         WHERE (si.id = system_id_in OR system_id_in IS NULL)
+           AND (si.rh_account_id = rh_account_id_in OR rh_account_id_in IS NULL)
+         GROUP BY si.rh_account_id, si.id
+         ORDER BY si.rh_account_id, si.id
+    )
+        UPDATE system_patch sp
+           SET installable_advisory_count_cache = sc.installable_total,
+               installable_advisory_enh_count_cache = sc.installable_enhancement,
+               installable_advisory_bug_count_cache = sc.installable_bugfix,
+               installable_advisory_sec_count_cache = sc.installable_security,
+               applicable_advisory_count_cache = sc.applicable_total,
+               applicable_advisory_enh_count_cache = sc.applicable_enhancement,
+               applicable_advisory_bug_count_cache = sc.applicable_bugfix,
+               applicable_advisory_sec_count_cache = sc.applicable_security
+          FROM system_advisories_count sc
+         WHERE sp.rh_account_id = sc.rh_account_id AND sp.system_id = sc.id
+           AND (sp.system_id = system_id_in OR system_id_in IS NULL)
+           AND (sp.rh_account_id = rh_account_id_in OR rh_account_id_in IS NULL);
+
+    GET DIAGNOSTICS COUNT = ROW_COUNT;
+    RETURN COUNT;
+END;
+$refresh_system$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS advisory_exclusion_system;
+DROP TABLE IF EXISTS advisory_exclusion;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS package_hold_system;
+DROP TABLE IF EXISTS package_hold;
+
+ALTER TABLE system_patch DROP COLUMN IF EXISTS packages_held;
+
+ALTER TABLE system_package2 DROP COLUMN IF EXISTS held_id;
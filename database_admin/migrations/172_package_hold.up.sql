@@ -0,0 +1,50 @@
+-- offline: foreign key on system_package2 is validated under ACCESS EXCLUSIVE lock
+ALTER TABLE system_package2 ADD COLUMN IF NOT EXISTS held_id BIGINT REFERENCES package (id);
+
+ALTER TABLE system_patch ADD COLUMN IF NOT EXISTS packages_held INT NOT NULL DEFAULT 0;
+
+-- package_hold
+-- pins package versions of a set of systems, updates not matching evra_pattern are held
+CREATE TABLE IF NOT EXISTS package_hold
+(
+    id            BIGINT      GENERATED BY DEFAULT AS IDENTITY,
+    rh_account_id INT         NOT NULL REFERENCES rh_account (id),
+    name_id       BIGINT      NOT NULL REFERENCES package_name (id),
+    evra_pattern  TEXT        NOT NULL CHECK (not empty(evra_pattern)),
+    description   TEXT,
+    created       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (rh_account_id, id)
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('package_hold', 16,
+                               $$WITH (fillfactor = '70', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+SELECT grant_table_partitions('SELECT, INSERT, UPDATE, DELETE', 'package_hold', 'manager');
+SELECT grant_table_partitions('SELECT', 'package_hold', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'package_hold', 'listener');
+SELECT grant_table_partitions('SELECT', 'package_hold', 'vmaas_sync');
+
+-- systems covered by a package hold
+CREATE TABLE IF NOT EXISTS package_hold_system
+(
+    rh_account_id INT    NOT NULL,
+    hold_id       BIGINT NOT NULL,
+    system_id     BIGINT NOT NULL,
+    PRIMARY KEY (rh_account_id, hold_id, system_id),
+    CONSTRAINT package_hold_system_hold_id
+        FOREIGN KEY (rh_account_id, hold_id)
+            REFERENCES package_hold (rh_account_id, id) ON DELETE CASCADE,
+    CONSTRAINT package_hold_system_system_id
+        FOREIGN KEY (rh_account_id, system_id)
+            REFERENCES system_inventory (rh_account_id, id) ON DELETE CASCADE
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('package_hold_system', 16,
+                               $$WITH (fillfactor = '70', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+CREATE INDEX ON package_hold_system (rh_account_id, system_id);
+
+SELECT grant_table_partitions('SELECT, INSERT, UPDATE, DELETE', 'package_hold_system', 'manager');
+SELECT grant_table_partitions('SELECT', 'package_hold_system', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'package_hold_system', 'listener');
+SELECT grant_table_partitions('SELECT', 'package_hold_system', 'vmaas_sync');
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS system_culling_notice;
+DROP TABLE IF EXISTS culling_policy;
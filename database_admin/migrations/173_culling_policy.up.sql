@@ -0,0 +1,35 @@
+-- culling_policy
+-- per-org override of inventory culling, culled systems are deleted culling_delay_days later
+-- and org is notified notice_days before the deletion
+CREATE TABLE IF NOT EXISTS culling_policy
+(
+    rh_account_id      INT         NOT NULL REFERENCES rh_account (id),
+    culling_delay_days INT         NOT NULL DEFAULT 0 CHECK (culling_delay_days >= 0),
+    notice_days        INT         NOT NULL DEFAULT 0 CHECK (notice_days >= 0),
+    last_edited        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (rh_account_id)
+) TABLESPACE pg_default;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON culling_policy TO manager;
+GRANT SELECT ON culling_policy TO evaluator;
+GRANT SELECT ON culling_policy TO listener;
+GRANT SELECT ON culling_policy TO vmaas_sync;
+
+-- system_culling_notice
+-- systems whose org has been notified about the upcoming deletion on culling_date
+CREATE TABLE IF NOT EXISTS system_culling_notice
+(
+    rh_account_id INT         NOT NULL,
+    system_id     BIGINT      NOT NULL,
+    culling_date  TIMESTAMPTZ NOT NULL,
+    notified      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (rh_account_id, system_id),
+    CONSTRAINT system_culling_notice_system_id
+        FOREIGN KEY (rh_account_id, system_id)
+            REFERENCES system_inventory (rh_account_id, id) ON DELETE CASCADE
+) TABLESPACE pg_default;
+
+GRANT SELECT ON system_culling_notice TO manager;
+GRANT SELECT ON system_culling_notice TO evaluator;
+GRANT SELECT ON system_culling_notice TO listener;
+GRANT SELECT, INSERT, UPDATE, DELETE ON system_culling_notice TO vmaas_sync;
@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS restore_system(uuid);
+DROP FUNCTION IF EXISTS archive_system(uuid);
+DROP TABLE IF EXISTS system_archive;
@@ -0,0 +1,123 @@
+-- system_archive
+-- rows of soft deleted systems kept as jsonb until they are restored or pruned after retention
+CREATE TABLE IF NOT EXISTS system_archive
+(
+    inventory_id  UUID        NOT NULL,
+    rh_account_id INT         NOT NULL REFERENCES rh_account (id),
+    system_id     BIGINT      NOT NULL,
+    archived      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    inventory     JSONB       NOT NULL,
+    patch         JSONB,
+    advisories    JSONB       NOT NULL DEFAULT '[]',
+    packages      JSONB       NOT NULL DEFAULT '[]',
+    repos         JSONB       NOT NULL DEFAULT '[]',
+    PRIMARY KEY (inventory_id)
+) TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS system_archive_archived_idx ON system_archive (archived);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON system_archive TO vmaas_sync;
+GRANT SELECT ON system_archive TO manager;
+
+CREATE OR REPLACE FUNCTION archive_system(inventory_id_in uuid)
+    RETURNS uuid
+AS
+$archive_system$
+DECLARE
+    v_system_id  BIGINT;
+    v_account_id INT;
+BEGIN
+    SELECT id, rh_account_id
+    FROM system_inventory
+    WHERE inventory_id = inventory_id_in
+    LIMIT 1
+        FOR UPDATE OF system_inventory
+    INTO v_system_id, v_account_id;
+
+    IF v_system_id IS NULL OR v_account_id IS NULL THEN
+        RAISE NOTICE 'Not found';
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO system_archive (inventory_id, rh_account_id, system_id, archived,
+                                inventory, patch, advisories, packages, repos)
+    SELECT inventory_id_in, v_account_id, v_system_id, now(),
+           (SELECT to_jsonb(si) FROM system_inventory si
+             WHERE si.rh_account_id = v_account_id AND si.id = v_system_id),
+           (SELECT to_jsonb(sp) FROM system_patch sp
+             WHERE sp.rh_account_id = v_account_id AND sp.system_id = v_system_id),
+           (SELECT coalesce(jsonb_agg(sa), '[]') FROM system_advisories sa
+             WHERE sa.rh_account_id = v_account_id AND sa.system_id = v_system_id),
+           (SELECT coalesce(jsonb_agg(spkg), '[]') FROM system_package2 spkg
+             WHERE spkg.rh_account_id = v_account_id AND spkg.system_id = v_system_id),
+           (SELECT coalesce(jsonb_agg(sr), '[]') FROM system_repo sr
+             WHERE sr.rh_account_id = v_account_id AND sr.system_id = v_system_id)
+    ON CONFLICT (inventory_id) DO UPDATE
+        SET rh_account_id = EXCLUDED.rh_account_id,
+            system_id     = EXCLUDED.system_id,
+            archived      = EXCLUDED.archived,
+            inventory     = EXCLUDED.inventory,
+            patch         = EXCLUDED.patch,
+            advisories    = EXCLUDED.advisories,
+            packages      = EXCLUDED.packages,
+            repos         = EXCLUDED.repos;
+
+    RETURN delete_system(inventory_id_in);
+END;
+$archive_system$ LANGUAGE 'plpgsql';
+
+CREATE OR REPLACE FUNCTION restore_system(inventory_id_in uuid)
+    RETURNS uuid
+AS
+$restore_system$
+DECLARE
+    v_archive system_archive%ROWTYPE;
+BEGIN
+    SELECT *
+    FROM system_archive
+    WHERE inventory_id = inventory_id_in
+        FOR UPDATE
+    INTO v_archive;
+
+    IF NOT FOUND THEN
+        RAISE NOTICE 'Not found';
+        RETURN NULL;
+    END IF;
+
+    -- insert system as stale and un-stale it at the end so on_system_update trigger counts it in caches again
+    INSERT INTO system_inventory
+    SELECT *
+    FROM jsonb_populate_record(NULL::system_inventory,
+                               v_archive.inventory || jsonb_build_object('stale', true, 'culled_timestamp', NULL));
+
+    IF v_archive.patch IS NOT NULL THEN
+        INSERT INTO system_patch
+        SELECT * FROM jsonb_populate_record(NULL::system_patch, v_archive.patch);
+    END IF;
+
+    INSERT INTO system_repo
+    SELECT r.*
+    FROM jsonb_populate_recordset(NULL::system_repo, v_archive.repos) r
+    WHERE EXISTS (SELECT 1 FROM repo WHERE repo.id = r.repo_id);
+
+    -- packages and advisories removed by cleaning jobs in the meantime are fixed by the next evaluation
+    INSERT INTO system_package2
+    SELECT r.*
+    FROM jsonb_populate_recordset(NULL::system_package2, v_archive.packages) r
+    WHERE EXISTS (SELECT 1 FROM package p WHERE p.id = r.package_id);
+
+    INSERT INTO system_advisories
+    SELECT r.*
+    FROM jsonb_populate_recordset(NULL::system_advisories, v_archive.advisories) r
+    WHERE EXISTS (SELECT 1 FROM advisory_metadata am WHERE am.id = r.advisory_id);
+
+    UPDATE system_inventory
+    SET stale = (v_archive.inventory ->> 'stale')::boolean
+    WHERE rh_account_id = v_archive.rh_account_id
+      AND id = v_archive.system_id;
+
+    DELETE FROM system_archive WHERE inventory_id = inventory_id_in;
+
+    RETURN inventory_id_in;
+END;
+$restore_system$ LANGUAGE 'plpgsql';
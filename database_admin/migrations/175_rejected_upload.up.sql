@@ -0,0 +1,26 @@
+-- rejected_upload
+-- last rejected upload of a host with reason, sample of offending data and the event for replay
+CREATE TABLE IF NOT EXISTS rejected_upload
+(
+    rh_account_id  INT         NOT NULL REFERENCES rh_account (id),
+    inventory_id   UUID        NOT NULL,
+    display_name   TEXT,
+    reporter       TEXT        NOT NULL,
+    host_type      TEXT,
+    workspace_id   UUID,
+    reason         TEXT        NOT NULL CHECK (NOT empty(reason)),
+    sample         JSONB,
+    event          JSONB       NOT NULL,
+    first_rejected TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_rejected  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    rejected_count INT         NOT NULL DEFAULT 1,
+    replayed       TIMESTAMPTZ,
+    PRIMARY KEY (rh_account_id, inventory_id)
+) TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS rejected_upload_inventory_id_idx ON rejected_upload (inventory_id);
+CREATE INDEX IF NOT EXISTS rejected_upload_last_rejected_idx ON rejected_upload (last_rejected);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON rejected_upload TO listener;
+GRANT SELECT ON rejected_upload TO manager;
+GRANT SELECT, DELETE ON rejected_upload TO vmaas_sync;
@@ -0,0 +1,14 @@
+-- vmaas_cache
+-- vmaas updates responses shared by evaluator replicas, rows are valid for vmaas data exported at `exported`
+CREATE UNLOGGED TABLE IF NOT EXISTS vmaas_cache
+(
+    checksum TEXT        NOT NULL CHECK (NOT empty(checksum)),
+    exported TIMESTAMPTZ NOT NULL,
+    response BYTEA       NOT NULL,
+    created  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (checksum)
+) TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS vmaas_cache_exported_idx ON vmaas_cache (exported);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON vmaas_cache TO evaluator;
@@ -0,0 +1,111 @@
+-- excluded system/advisory pairs are not counted in advisory_account_data caches either,
+-- the same way as in account_advisory and system caches
+CREATE OR REPLACE FUNCTION on_system_update()
+-- this trigger updates advisory_account_data when server changes its stale flag
+    RETURNS TRIGGER
+AS
+$system_update$
+DECLARE
+    was_counted  BOOLEAN;
+    should_count BOOLEAN;
+    change       INT;
+BEGIN
+    -- Ignore not yet evaluated systems
+    IF TG_OP != 'UPDATE' OR NOT EXISTS (
+        SELECT 1
+        FROM system_patch
+        WHERE system_id = NEW.id 
+          AND rh_account_id = NEW.rh_account_id
+          AND last_evaluation IS NOT NULL
+    ) THEN
+        RETURN NEW;
+    END IF;
+
+    was_counted := OLD.stale = FALSE;
+    should_count := NEW.stale = FALSE;
+
+    -- Determine what change we are performing
+    IF was_counted and NOT should_count THEN
+        change := -1;
+    ELSIF NOT was_counted AND should_count THEN
+        change := 1;
+    ELSE
+        -- No change
+        RETURN NEW;
+    END IF;
+
+    -- insert/update advisories linked to the server
+    INSERT
+      INTO advisory_account_data (advisory_id, rh_account_id, systems_installable, systems_applicable)
+    SELECT sa.advisory_id, NEW.rh_account_id,
+           case when sa.status_id = 0 then change else 0 end as systems_installable,
+           change as systems_applicable
+      FROM system_advisories sa
+     WHERE sa.system_id = NEW.id AND sa.rh_account_id = NEW.rh_account_id
+       AND sa.status_id <> 2
+       AND NOT EXISTS (SELECT 1
+                         FROM advisory_exclusion_system aes
+                         JOIN advisory_exclusion ae
+                           ON ae.rh_account_id = aes.rh_account_id AND ae.id = aes.exclusion_id
+                        WHERE aes.rh_account_id = sa.rh_account_id
+                          AND aes.system_id = sa.system_id
+                          AND aes.advisory_id = sa.advisory_id
+                          AND (ae.expires IS NULL OR ae.expires > now()))
+     ORDER BY sa.advisory_id
+        ON CONFLICT (advisory_id, rh_account_id) DO UPDATE
+           SET systems_installable = advisory_account_data.systems_installable + EXCLUDED.systems_installable,
+               systems_applicable = advisory_account_data.systems_applicable + EXCLUDED.systems_applicable;
+    RETURN NEW;
+END;
+$system_update$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION refresh_advisory_caches_multi(advisory_ids_in INTEGER[] DEFAULT NULL,
+                                                         rh_account_id_in INTEGER DEFAULT NULL)
+    RETURNS VOID AS
+$refresh_advisory$
+BEGIN
+    -- Lock rows
+    PERFORM aad.rh_account_id, aad.advisory_id
+    FROM advisory_account_data aad
+    WHERE (aad.advisory_id = ANY (advisory_ids_in) OR advisory_ids_in IS NULL)
+      AND (aad.rh_account_id = rh_account_id_in OR rh_account_id_in IS NULL)
+        FOR UPDATE OF aad;
+
+    WITH current_counts AS (
+        SELECT sa.advisory_id, sa.rh_account_id,
+               count(sa.*) filter (where sa.status_id = 0) as systems_installable,
+               count(sa.*) as systems_applicable
+          FROM system_advisories sa
+          JOIN system_inventory si
+            ON sa.rh_account_id = si.rh_account_id AND sa.system_id = si.id
+          JOIN system_patch sp
+            ON si.id = sp.system_id AND sp.rh_account_id = si.rh_account_id
+         WHERE sp.last_evaluation IS NOT NULL
+           AND si.stale = FALSE
+           AND sa.status_id <> 2
+           AND NOT EXISTS (SELECT 1
+                             FROM advisory_exclusion_system aes
+                             JOIN advisory_exclusion ae
+                               ON ae.rh_account_id = aes.rh_account_id AND ae.id = aes.exclusion_id
+                            WHERE aes.rh_account_id = sa.rh_account_id
+                              AND aes.system_id = sa.system_id
+                              AND aes.advisory_id = sa.advisory_id
+                              AND (ae.expires IS NULL OR ae.expires > now()))
+           AND (sa.advisory_id = ANY (advisory_ids_in) OR advisory_ids_in IS NULL)
+           AND (si.rh_account_id = rh_account_id_in OR rh_account_id_in IS NULL)
+         GROUP BY sa.advisory_id, sa.rh_account_id
+    ),
+        upserted AS (
+            INSERT INTO advisory_account_data (advisory_id, rh_account_id, systems_installable, systems_applicable)
+                 SELECT advisory_id, rh_account_id, systems_installable, systems_applicable
+                   FROM current_counts
+            ON CONFLICT (advisory_id, rh_account_id) DO UPDATE SET
+                     systems_installable = EXCLUDED.systems_installable,
+                     systems_applicable = EXCLUDED.systems_applicable
+         )
+    DELETE FROM advisory_account_data
+     WHERE (advisory_id, rh_account_id) NOT IN (SELECT advisory_id, rh_account_id FROM current_counts)
+       AND (advisory_id = ANY (advisory_ids_in) OR advisory_ids_in IS NULL)
+       AND (rh_account_id = rh_account_id_in OR rh_account_id_in IS NULL);
+END;
+$refresh_advisory$ language plpgsql;
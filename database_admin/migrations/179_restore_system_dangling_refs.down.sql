@@ -0,0 +1,55 @@
+CREATE OR REPLACE FUNCTION restore_system(inventory_id_in uuid)
+    RETURNS uuid
+AS
+$restore_system$
+DECLARE
+    v_archive system_archive%ROWTYPE;
+BEGIN
+    SELECT *
+    FROM system_archive
+    WHERE inventory_id = inventory_id_in
+        FOR UPDATE
+    INTO v_archive;
+
+    IF NOT FOUND THEN
+        RAISE NOTICE 'Not found';
+        RETURN NULL;
+    END IF;
+
+    -- insert system as stale and un-stale it at the end so on_system_update trigger counts it in caches again
+    INSERT INTO system_inventory
+    SELECT *
+    FROM jsonb_populate_record(NULL::system_inventory,
+                               v_archive.inventory || jsonb_build_object('stale', true, 'culled_timestamp', NULL));
+
+    IF v_archive.patch IS NOT NULL THEN
+        INSERT INTO system_patch
+        SELECT * FROM jsonb_populate_record(NULL::system_patch, v_archive.patch);
+    END IF;
+
+    INSERT INTO system_repo
+    SELECT r.*
+    FROM jsonb_populate_recordset(NULL::system_repo, v_archive.repos) r
+    WHERE EXISTS (SELECT 1 FROM repo WHERE repo.id = r.repo_id);
+
+    -- packages and advisories removed by cleaning jobs in the meantime are fixed by the next evaluation
+    INSERT INTO system_package2
+    SELECT r.*
+    FROM jsonb_populate_recordset(NULL::system_package2, v_archive.packages) r
+    WHERE EXISTS (SELECT 1 FROM package p WHERE p.id = r.package_id);
+
+    INSERT INTO system_advisories
+    SELECT r.*
+    FROM jsonb_populate_recordset(NULL::system_advisories, v_archive.advisories) r
+    WHERE EXISTS (SELECT 1 FROM advisory_metadata am WHERE am.id = r.advisory_id);
+
+    UPDATE system_inventory
+    SET stale = (v_archive.inventory ->> 'stale')::boolean
+    WHERE rh_account_id = v_archive.rh_account_id
+      AND id = v_archive.system_id;
+
+    DELETE FROM system_archive WHERE inventory_id = inventory_id_in;
+
+    RETURN inventory_id_in;
+END;
+$restore_system$ LANGUAGE 'plpgsql';
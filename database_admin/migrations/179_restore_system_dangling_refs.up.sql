@@ -0,0 +1,75 @@
+CREATE OR REPLACE FUNCTION restore_system(inventory_id_in uuid)
+    RETURNS uuid
+AS
+$restore_system$
+DECLARE
+    v_archive system_archive%ROWTYPE;
+BEGIN
+    SELECT *
+    FROM system_archive
+    WHERE inventory_id = inventory_id_in
+        FOR UPDATE
+    INTO v_archive;
+
+    IF NOT FOUND THEN
+        RAISE NOTICE 'Not found';
+        RETURN NULL;
+    END IF;
+
+    -- insert system as stale and un-stale it at the end so on_system_update trigger counts it in caches again
+    -- references to rows deleted in the meantime are cleared
+    INSERT INTO system_inventory
+    SELECT *
+    FROM jsonb_populate_record(NULL::system_inventory,
+                               v_archive.inventory || jsonb_build_object(
+                                   'stale', true,
+                                   'culled_timestamp', NULL,
+                                   'reporter_id', (SELECT r.id
+                                                   FROM reporter r
+                                                   WHERE r.id = (v_archive.inventory ->> 'reporter_id')::int)));
+
+    IF v_archive.patch IS NOT NULL THEN
+        INSERT INTO system_patch
+        SELECT *
+        FROM jsonb_populate_record(NULL::system_patch,
+                                   v_archive.patch || jsonb_build_object(
+                                       'template_id', (SELECT t.id
+                                                       FROM template t
+                                                       WHERE t.rh_account_id = v_archive.rh_account_id
+                                                         AND t.id = (v_archive.patch ->> 'template_id')::bigint),
+                                       'baseline_id', (SELECT b.id
+                                                       FROM baseline b
+                                                       WHERE b.rh_account_id = v_archive.rh_account_id
+                                                         AND b.id = (v_archive.patch ->> 'baseline_id')::bigint)));
+    END IF;
+
+    INSERT INTO system_repo
+    SELECT r.*
+    FROM jsonb_populate_recordset(NULL::system_repo, v_archive.repos) r
+    WHERE EXISTS (SELECT 1 FROM repo WHERE repo.id = r.repo_id);
+
+    -- packages and advisories removed by cleaning jobs in the meantime are fixed by the next evaluation
+    INSERT INTO system_package2 (rh_account_id, system_id, name_id, package_id,
+                                 installable_id, applicable_id, held_id)
+    SELECT r.rh_account_id, r.system_id, r.name_id, r.package_id,
+           CASE WHEN EXISTS (SELECT 1 FROM package p WHERE p.id = r.installable_id) THEN r.installable_id END,
+           CASE WHEN EXISTS (SELECT 1 FROM package p WHERE p.id = r.applicable_id) THEN r.applicable_id END,
+           CASE WHEN EXISTS (SELECT 1 FROM package p WHERE p.id = r.held_id) THEN r.held_id END
+    FROM jsonb_populate_recordset(NULL::system_package2, v_archive.packages) r
+    WHERE EXISTS (SELECT 1 FROM package p WHERE p.id = r.package_id);
+
+    INSERT INTO system_advisories
+    SELECT r.*
+    FROM jsonb_populate_recordset(NULL::system_advisories, v_archive.advisories) r
+    WHERE EXISTS (SELECT 1 FROM advisory_metadata am WHERE am.id = r.advisory_id);
+
+    UPDATE system_inventory
+    SET stale = (v_archive.inventory ->> 'stale')::boolean
+    WHERE rh_account_id = v_archive.rh_account_id
+      AND id = v_archive.system_id;
+
+    DELETE FROM system_archive WHERE inventory_id = inventory_id_in;
+
+    RETURN inventory_id_in;
+END;
+$restore_system$ LANGUAGE 'plpgsql';
@@ -0,0 +1,184 @@
+package database_admin
+
+import (
+	"app/base/utils"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// migrations up to this version predate the linter and are not required to be online or marked `-- offline`
+const lintBaselineVersion = 169
+
+const pgLockNotAvailable = "55P03"
+
+// MigrationPlan lists migrations run from current to target schema version
+type MigrationPlan struct {
+	Current    int
+	Target     int
+	Migrations []MigrationCheck
+}
+
+// Online returns true if the plan can run without stopping app components, only upgrades can run online
+func (p *MigrationPlan) Online() bool {
+	if p.Current < 0 || p.Target < p.Current {
+		return false
+	}
+	for i := range p.Migrations {
+		if !p.Migrations[i].Online() {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *MigrationPlan) Log() {
+	log.Infof("Migration plan from version %d to %d, online: %t", p.Current, p.Target, p.Online())
+	for i := range p.Migrations {
+		log.Info(p.Migrations[i].String())
+	}
+}
+
+// previousVersion returns version applied before the migration `version` of upgrade plan
+func (p *MigrationPlan) previousVersion(version int) int {
+	prev := p.Current
+	for _, m := range p.Migrations {
+		if m.Version >= version {
+			break
+		}
+		prev = m.Version
+	}
+	return prev
+}
+
+// planMigrations lints migration files run when migrating from `current` to `target` version
+func planMigrations(sourceURL string, current, target int) (MigrationPlan, error) {
+	plan := MigrationPlan{Current: current, Target: target}
+	dir := filepath.Clean(sourceURL[len("file://"):])
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return plan, errors.Wrap(err, "Error reading migration files")
+	}
+
+	suffix, from, to := ".up.sql", current, target
+	if target < current {
+		suffix, from, to = ".down.sql", target, current
+	}
+	for _, f := range files {
+		ver, _, _ := cut(f.Name(), '_')
+		version, err := strconv.Atoi(ver)
+		if err != nil || !strings.HasSuffix(f.Name(), suffix) || version <= from || version > to {
+			continue
+		}
+		check, err := LintMigration(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return plan, errors.Wrap(err, "Error reading migration file")
+		}
+		plan.Migrations = append(plan.Migrations, check)
+	}
+	sort.Slice(plan.Migrations, func(i, j int) bool {
+		if target < current {
+			return plan.Migrations[i].Version > plan.Migrations[j].Version
+		}
+		return plan.Migrations[i].Version < plan.Migrations[j].Version
+	})
+	return plan, nil
+}
+
+func migrationPlan(conn database.Driver, sourceURL string) MigrationPlan {
+	target := migrationTargetVersion(sourceURL)
+	current, err := dbSchemaVersion(conn, sourceURL)
+	if err != nil {
+		// e.g. dirty schema fixed by force_migration_version, has to run offline
+		log.Warnf("Unable to plan migration: %v", err)
+		return MigrationPlan{Current: -1, Target: target}
+	}
+	plan, err := planMigrations(sourceURL, current, target)
+	if err != nil {
+		panic(err)
+	}
+	return plan
+}
+
+// startOnlineMigration migrates without blocking app users, statements waiting for a lock longer than
+// online_migration_lock_timeout_ms are canceled and the migration is retried
+func startOnlineMigration(conn database.Driver, sourceURL string, plan *MigrationPlan) {
+	log.Infof("Starting online schema migration to version %d (current version %d)", plan.Target, plan.Current)
+	setLockTimeout := fmt.Sprintf("SET lock_timeout = %d", onlineMigrationLockTimeoutMs)
+	if err := conn.Run(strings.NewReader(setLockTimeout)); err != nil {
+		panic(err)
+	}
+	defer func() {
+		if err := conn.Run(strings.NewReader("RESET lock_timeout")); err != nil {
+			log.Warnf("Unable to reset lock_timeout: %v", err)
+		}
+	}()
+
+	m := createMigrate(conn, sourceURL)
+	for attempt := 1; ; attempt++ {
+		err := m.Migrate(uint(plan.Target)) //nolint:gosec
+		if err == nil || errors.Is(err, migrate.ErrNoChange) {
+			return
+		}
+		if !isLockTimeout(err) || attempt >= onlineMigrationRetries {
+			utils.LogError("err", err, "error upgrading the database")
+			panic(err)
+		}
+		// the failed migration was rolled back, clear dirty flag of its version and retry
+		version, _, verErr := m.Version()
+		if verErr != nil {
+			panic(verErr)
+		}
+		prev := plan.previousVersion(int(version)) //nolint:gosec
+		log.Warnf("Migration %d timed out waiting for lock, attempt %d, retrying from version %d",
+			version, attempt, prev)
+		if err = m.Force(prev); err != nil {
+			panic(err)
+		}
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+}
+
+func isLockTimeout(err error) bool {
+	var dbErr database.Error
+	if !errors.As(err, &dbErr) {
+		return false
+	}
+	var pqErr *pq.Error
+	return errors.As(dbErr.OrigErr, &pqErr) && pqErr.Code == pgLockNotAvailable
+}
+
+// LintMigrations prints classification of all migrations, fails when a new migration is neither online
+// nor marked `-- offline`
+func LintMigrations(sourceURL string) {
+	latest, err := latestSchemaMigrationFileVersion(sourceURL)
+	if err != nil {
+		panic(err)
+	}
+	plan, err := planMigrations(sourceURL, 0, latest)
+	if err != nil {
+		panic(err)
+	}
+	failed := false
+	for i := range plan.Migrations {
+		m := &plan.Migrations[i]
+		fmt.Println(m.String())
+		if m.Version > lintBaselineVersion && !m.Offline && !m.Online() {
+			fmt.Printf("  ! %s blocks app components, fix it or mark it `-- offline`\n", m.Name)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
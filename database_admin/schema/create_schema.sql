@@ -7,7 +7,7 @@ CREATE TABLE IF NOT EXISTS schema_migrations
 
 
 INSERT INTO schema_migrations
-VALUES (166, false);
+VALUES (179, false);
 
 -- ---------------------------------------------------------------------------
 -- Functions
@@ -107,6 +107,15 @@ BEGIN
            change as systems_applicable
       FROM system_advisories sa
      WHERE sa.system_id = NEW.id AND sa.rh_account_id = NEW.rh_account_id
+       AND sa.status_id <> 2
+       AND NOT EXISTS (SELECT 1
+                         FROM advisory_exclusion_system aes
+                         JOIN advisory_exclusion ae
+                           ON ae.rh_account_id = aes.rh_account_id AND ae.id = aes.exclusion_id
+                        WHERE aes.rh_account_id = sa.rh_account_id
+                          AND aes.system_id = sa.system_id
+                          AND aes.advisory_id = sa.advisory_id
+                          AND (ae.expires IS NULL OR ae.expires > now()))
      ORDER BY sa.advisory_id
         ON CONFLICT (advisory_id, rh_account_id) DO UPDATE
            SET systems_installable = advisory_account_data.systems_installable + EXCLUDED.systems_installable,
@@ -138,6 +147,15 @@ BEGIN
             ON si.id = sp.system_id AND sp.rh_account_id = si.rh_account_id
          WHERE sp.last_evaluation IS NOT NULL
            AND si.stale = FALSE
+           AND sa.status_id <> 2
+           AND NOT EXISTS (SELECT 1
+                             FROM advisory_exclusion_system aes
+                             JOIN advisory_exclusion ae
+                               ON ae.rh_account_id = aes.rh_account_id AND ae.id = aes.exclusion_id
+                            WHERE aes.rh_account_id = sa.rh_account_id
+                              AND aes.system_id = sa.system_id
+                              AND aes.advisory_id = sa.advisory_id
+                              AND (ae.expires IS NULL OR ae.expires > now()))
            AND (sa.advisory_id = ANY (advisory_ids_in) OR advisory_ids_in IS NULL)
            AND (si.rh_account_id = rh_account_id_in OR rh_account_id_in IS NULL)
          GROUP BY sa.advisory_id, sa.rh_account_id
@@ -193,6 +211,15 @@ BEGIN
          WHERE sp.last_evaluation IS NOT NULL
            AND si.stale = FALSE
            AND si.workspace_id IS NOT NULL
+           AND sa.status_id <> 2
+           AND NOT EXISTS (SELECT 1
+                             FROM advisory_exclusion_system aes
+                             JOIN advisory_exclusion ae
+                               ON ae.rh_account_id = aes.rh_account_id AND ae.id = aes.exclusion_id
+                            WHERE aes.rh_account_id = sa.rh_account_id
+                              AND aes.system_id = sa.system_id
+                              AND aes.advisory_id = sa.advisory_id
+                              AND (ae.expires IS NULL OR ae.expires > now()))
            AND (sa.advisory_id = ANY (advisory_ids_in) OR advisory_ids_in IS NULL)
            AND (si.rh_account_id = rh_account_id_in OR rh_account_id_in IS NULL)
          GROUP BY sa.advisory_id, sa.rh_account_id, si.workspace_id
@@ -253,6 +280,15 @@ BEGIN
           FROM system_inventory si  -- this table ensures even systems without any system_advisories are in results
           LEFT JOIN system_advisories sa
             ON si.rh_account_id = sa.rh_account_id AND si.id = sa.system_id
+           AND sa.status_id <> 2
+           AND NOT EXISTS (SELECT 1
+                             FROM advisory_exclusion_system aes
+                             JOIN advisory_exclusion ae
+                               ON ae.rh_account_id = aes.rh_account_id AND ae.id = aes.exclusion_id
+                            WHERE aes.rh_account_id = sa.rh_account_id
+                              AND aes.system_id = sa.system_id
+                              AND aes.advisory_id = sa.advisory_id
+                              AND (ae.expires IS NULL OR ae.expires > now()))
           LEFT JOIN advisory_metadata am
             ON sa.advisory_id = am.id
          WHERE (si.id = system_id_in OR system_id_in IS NULL)
@@ -418,6 +454,128 @@ BEGIN
 END;
 $delete_system$ LANGUAGE 'plpgsql';
 
+CREATE OR REPLACE FUNCTION archive_system(inventory_id_in uuid)
+    RETURNS uuid
+AS
+$archive_system$
+DECLARE
+    v_system_id  BIGINT;
+    v_account_id INT;
+BEGIN
+    SELECT id, rh_account_id
+    FROM system_inventory
+    WHERE inventory_id = inventory_id_in
+    LIMIT 1
+        FOR UPDATE OF system_inventory
+    INTO v_system_id, v_account_id;
+
+    IF v_system_id IS NULL OR v_account_id IS NULL THEN
+        RAISE NOTICE 'Not found';
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO system_archive (inventory_id, rh_account_id, system_id, archived,
+                                inventory, patch, advisories, packages, repos)
+    SELECT inventory_id_in, v_account_id, v_system_id, now(),
+           (SELECT to_jsonb(si) FROM system_inventory si
+             WHERE si.rh_account_id = v_account_id AND si.id = v_system_id),
+           (SELECT to_jsonb(sp) FROM system_patch sp
+             WHERE sp.rh_account_id = v_account_id AND sp.system_id = v_system_id),
+           (SELECT coalesce(jsonb_agg(sa), '[]') FROM system_advisories sa
+             WHERE sa.rh_account_id = v_account_id AND sa.system_id = v_system_id),
+           (SELECT coalesce(jsonb_agg(spkg), '[]') FROM system_package2 spkg
+             WHERE spkg.rh_account_id = v_account_id AND spkg.system_id = v_system_id),
+           (SELECT coalesce(jsonb_agg(sr), '[]') FROM system_repo sr
+             WHERE sr.rh_account_id = v_account_id AND sr.system_id = v_system_id)
+    ON CONFLICT (inventory_id) DO UPDATE
+        SET rh_account_id = EXCLUDED.rh_account_id,
+            system_id     = EXCLUDED.system_id,
+            archived      = EXCLUDED.archived,
+            inventory     = EXCLUDED.inventory,
+            patch         = EXCLUDED.patch,
+            advisories    = EXCLUDED.advisories,
+            packages      = EXCLUDED.packages,
+            repos         = EXCLUDED.repos;
+
+    RETURN delete_system(inventory_id_in);
+END;
+$archive_system$ LANGUAGE 'plpgsql';
+
+CREATE OR REPLACE FUNCTION restore_system(inventory_id_in uuid)
+    RETURNS uuid
+AS
+$restore_system$
+DECLARE
+    v_archive system_archive%ROWTYPE;
+BEGIN
+    SELECT *
+    FROM system_archive
+    WHERE inventory_id = inventory_id_in
+        FOR UPDATE
+    INTO v_archive;
+
+    IF NOT FOUND THEN
+        RAISE NOTICE 'Not found';
+        RETURN NULL;
+    END IF;
+
+    -- insert system as stale and un-stale it at the end so on_system_update trigger counts it in caches again
+    -- references to rows deleted in the meantime are cleared
+    INSERT INTO system_inventory
+    SELECT *
+    FROM jsonb_populate_record(NULL::system_inventory,
+                               v_archive.inventory || jsonb_build_object(
+                                   'stale', true,
+                                   'culled_timestamp', NULL,
+                                   'reporter_id', (SELECT r.id
+                                                   FROM reporter r
+                                                   WHERE r.id = (v_archive.inventory ->> 'reporter_id')::int)));
+
+    IF v_archive.patch IS NOT NULL THEN
+        INSERT INTO system_patch
+        SELECT *
+        FROM jsonb_populate_record(NULL::system_patch,
+                                   v_archive.patch || jsonb_build_object(
+                                       'template_id', (SELECT t.id
+                                                       FROM template t
+                                                       WHERE t.rh_account_id = v_archive.rh_account_id
+                                                         AND t.id = (v_archive.patch ->> 'template_id')::bigint),
+                                       'baseline_id', (SELECT b.id
+                                                       FROM baseline b
+                                                       WHERE b.rh_account_id = v_archive.rh_account_id
+                                                         AND b.id = (v_archive.patch ->> 'baseline_id')::bigint)));
+    END IF;
+
+    INSERT INTO system_repo
+    SELECT r.*
+    FROM jsonb_populate_recordset(NULL::system_repo, v_archive.repos) r
+    WHERE EXISTS (SELECT 1 FROM repo WHERE repo.id = r.repo_id);
+
+    -- packages and advisories removed by cleaning jobs in the meantime are fixed by the next evaluation
+    INSERT INTO system_package2 (rh_account_id, system_id, name_id, package_id,
+                                 installable_id, applicable_id, held_id)
+    SELECT r.rh_account_id, r.system_id, r.name_id, r.package_id,
+           CASE WHEN EXISTS (SELECT 1 FROM package p WHERE p.id = r.installable_id) THEN r.installable_id END,
+           CASE WHEN EXISTS (SELECT 1 FROM package p WHERE p.id = r.applicable_id) THEN r.applicable_id END,
+           CASE WHEN EXISTS (SELECT 1 FROM package p WHERE p.id = r.held_id) THEN r.held_id END
+    FROM jsonb_populate_recordset(NULL::system_package2, v_archive.packages) r
+    WHERE EXISTS (SELECT 1 FROM package p WHERE p.id = r.package_id);
+
+    INSERT INTO system_advisories
+    SELECT r.*
+    FROM jsonb_populate_recordset(NULL::system_advisories, v_archive.advisories) r
+    WHERE EXISTS (SELECT 1 FROM advisory_metadata am WHERE am.id = r.advisory_id);
+
+    UPDATE system_inventory
+    SET stale = (v_archive.inventory ->> 'stale')::boolean
+    WHERE rh_account_id = v_archive.rh_account_id
+      AND id = v_archive.system_id;
+
+    DELETE FROM system_archive WHERE inventory_id = inventory_id_in;
+
+    RETURN inventory_id_in;
+END;
+$restore_system$ LANGUAGE 'plpgsql';
 CREATE OR REPLACE FUNCTION hash_partition_id(id int, parts int)
     RETURNS int AS
 $$
@@ -789,7 +947,8 @@ CREATE TABLE IF NOT EXISTS status
 
 INSERT INTO status (id, name)
 VALUES (0, 'Installable'),
-       (1, 'Applicable')
+       (1, 'Applicable'),
+       (2, 'Not applicable by policy')
 ON CONFLICT DO NOTHING;
 
 
@@ -942,6 +1101,120 @@ GRANT DELETE ON system_repo TO manager;
 GRANT SELECT, INSERT, UPDATE, DELETE ON system_repo TO evaluator;
 GRANT SELECT, DELETE on system_repo to vmaas_sync;
 
+-- repo_classification
+-- per-org override of the global repo.third_party flag
+CREATE TABLE IF NOT EXISTS repo_classification
+(
+    rh_account_id  INT    NOT NULL REFERENCES rh_account (id),
+    repo_id        BIGINT NOT NULL REFERENCES repo (id) ON DELETE CASCADE,
+    classification TEXT   NOT NULL CHECK (classification IN ('trusted', 'third_party', 'ignored')),
+    PRIMARY KEY (rh_account_id, repo_id)
+) TABLESPACE pg_default;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON repo_classification TO manager;
+GRANT SELECT ON repo_classification TO evaluator;
+GRANT SELECT ON repo_classification TO listener;
+GRANT SELECT ON repo_classification TO vmaas_sync;
+
+-- culling_policy
+-- per-org override of inventory culling, culled systems are deleted culling_delay_days later
+-- and org is notified notice_days before the deletion
+CREATE TABLE IF NOT EXISTS culling_policy
+(
+    rh_account_id      INT         NOT NULL REFERENCES rh_account (id),
+    culling_delay_days INT         NOT NULL DEFAULT 0 CHECK (culling_delay_days >= 0),
+    notice_days        INT         NOT NULL DEFAULT 0 CHECK (notice_days >= 0),
+    last_edited        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (rh_account_id)
+) TABLESPACE pg_default;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON culling_policy TO manager;
+GRANT SELECT ON culling_policy TO evaluator;
+GRANT SELECT ON culling_policy TO listener;
+GRANT SELECT ON culling_policy TO vmaas_sync;
+
+-- system_culling_notice
+-- systems whose org has been notified about the upcoming deletion on culling_date
+CREATE TABLE IF NOT EXISTS system_culling_notice
+(
+    rh_account_id INT         NOT NULL,
+    system_id     BIGINT      NOT NULL,
+    culling_date  TIMESTAMPTZ NOT NULL,
+    notified      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (rh_account_id, system_id),
+    CONSTRAINT system_culling_notice_system_id
+        FOREIGN KEY (rh_account_id, system_id)
+            REFERENCES system_inventory (rh_account_id, id) ON DELETE CASCADE
+) TABLESPACE pg_default;
+
+GRANT SELECT ON system_culling_notice TO manager;
+GRANT SELECT ON system_culling_notice TO evaluator;
+GRANT SELECT ON system_culling_notice TO listener;
+GRANT SELECT, INSERT, UPDATE, DELETE ON system_culling_notice TO vmaas_sync;
+
+-- system_archive
+-- rows of soft deleted systems kept as jsonb until they are restored or pruned after retention
+CREATE TABLE IF NOT EXISTS system_archive
+(
+    inventory_id  UUID        NOT NULL,
+    rh_account_id INT         NOT NULL REFERENCES rh_account (id),
+    system_id     BIGINT      NOT NULL,
+    archived      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    inventory     JSONB       NOT NULL,
+    patch         JSONB,
+    advisories    JSONB       NOT NULL DEFAULT '[]',
+    packages      JSONB       NOT NULL DEFAULT '[]',
+    repos         JSONB       NOT NULL DEFAULT '[]',
+    PRIMARY KEY (inventory_id)
+) TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS system_archive_archived_idx ON system_archive (archived);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON system_archive TO vmaas_sync;
+GRANT SELECT ON system_archive TO manager;
+
+-- rejected_upload
+-- last rejected upload of a host with reason, sample of offending data and the event for replay
+CREATE TABLE IF NOT EXISTS rejected_upload
+(
+    rh_account_id  INT         NOT NULL REFERENCES rh_account (id),
+    inventory_id   UUID        NOT NULL,
+    display_name   TEXT,
+    reporter       TEXT        NOT NULL,
+    host_type      TEXT,
+    workspace_id   UUID,
+    reason         TEXT        NOT NULL CHECK (NOT empty(reason)),
+    sample         JSONB,
+    event          JSONB       NOT NULL,
+    first_rejected TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_rejected  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    rejected_count INT         NOT NULL DEFAULT 1,
+    replayed       TIMESTAMPTZ,
+    PRIMARY KEY (rh_account_id, inventory_id)
+) TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS rejected_upload_inventory_id_idx ON rejected_upload (inventory_id);
+CREATE INDEX IF NOT EXISTS rejected_upload_last_rejected_idx ON rejected_upload (last_rejected);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON rejected_upload TO listener;
+GRANT SELECT ON rejected_upload TO manager;
+GRANT SELECT, DELETE ON rejected_upload TO vmaas_sync;
+
+-- vmaas_cache
+-- vmaas updates responses shared by evaluator replicas, rows are valid for vmaas data exported at `exported`
+CREATE UNLOGGED TABLE IF NOT EXISTS vmaas_cache
+(
+    checksum TEXT        NOT NULL CHECK (NOT empty(checksum)),
+    exported TIMESTAMPTZ NOT NULL,
+    response BYTEA       NOT NULL,
+    created  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (checksum)
+) TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS vmaas_cache_exported_idx ON vmaas_cache (exported);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON vmaas_cache TO evaluator;
+
 -- the following constraints are enabled here not directly in the table definitions
 -- to make new schema equal to the migrated schema
 ALTER TABLE system_advisories
@@ -999,6 +1272,8 @@ CREATE TABLE IF NOT EXISTS system_package2
     -- Use null to represent up-to-date packages
     installable_id BIGINT REFERENCES package (id),
     applicable_id  BIGINT REFERENCES package (id),
+    -- latest update violating a package hold
+    held_id        BIGINT REFERENCES package (id),
 
     PRIMARY KEY (rh_account_id, system_id, package_id),
     CONSTRAINT system_inventory_id
@@ -1055,6 +1330,33 @@ GRANT SELECT, INSERT, UPDATE, DELETE ON timestamp_kv TO vmaas_sync;
 GRANT DELETE ON system_advisories TO vmaas_sync;
 GRANT DELETE ON advisory_account_data TO vmaas_sync;
 
+-- baseline
+-- baseline limits advisories evaluated for assigned systems either to updates
+-- which are already installed on a reference system or to advisories released before a cutoff date,
+-- reference system is not a foreign key, baseline of a deleted reference system does not limit packages
+CREATE TABLE IF NOT EXISTS baseline
+(
+    id                  BIGINT      GENERATED BY DEFAULT AS IDENTITY,
+    rh_account_id       INT         NOT NULL REFERENCES rh_account (id),
+    name                TEXT        NOT NULL CHECK (not empty(name)),
+    description         TEXT        CHECK (NOT empty(description)),
+    reference_system_id BIGINT,
+    advisory_cutoff     TIMESTAMPTZ,
+    created             TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_edited         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (rh_account_id, id),
+    UNIQUE (rh_account_id, name),
+    CHECK (reference_system_id IS NOT NULL OR advisory_cutoff IS NOT NULL)
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('baseline', 16,
+                               $$WITH (fillfactor = '70', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+SELECT grant_table_partitions('SELECT, INSERT, UPDATE, DELETE', 'baseline', 'manager');
+SELECT grant_table_partitions('SELECT', 'baseline', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'baseline', 'listener');
+SELECT grant_table_partitions('SELECT', 'baseline', 'vmaas_sync');
+
 -- system_patch
 CREATE TABLE IF NOT EXISTS system_patch
 (
@@ -1074,8 +1376,13 @@ CREATE TABLE IF NOT EXISTS system_patch
     applicable_advisory_bug_count_cache  INT         NOT NULL DEFAULT 0,
     applicable_advisory_sec_count_cache  INT         NOT NULL DEFAULT 0,
     template_id                          BIGINT,
+    baseline_id                          BIGINT,
+    baseline_advisory_count_cache        INT         NOT NULL DEFAULT 0,
+    packages_held                        INT         NOT NULL DEFAULT 0,
     PRIMARY KEY (rh_account_id, system_id),
     FOREIGN KEY (rh_account_id, template_id) REFERENCES template (rh_account_id, id),
+    CONSTRAINT system_patch_baseline_id
+        FOREIGN KEY (rh_account_id, baseline_id) REFERENCES baseline (rh_account_id, id),
     FOREIGN KEY (rh_account_id, system_id) REFERENCES system_inventory (rh_account_id, id)
 ) PARTITION BY HASH (rh_account_id);
 
@@ -1093,9 +1400,193 @@ GRANT SELECT, UPDATE (installable_advisory_count_cache,
               applicable_advisory_enh_count_cache,
               applicable_advisory_bug_count_cache,
               applicable_advisory_sec_count_cache,
-              template_id) ON system_patch TO manager;
+              template_id,
+              baseline_id) ON system_patch TO manager;
 GRANT SELECT, UPDATE, DELETE ON system_patch to vmaas_sync; -- vmaas_sync performs system culling
 
+-- system_package_change
+-- append-only journal of package changes detected by evaluator
+CREATE TABLE IF NOT EXISTS system_package_change
+(
+    id            BIGINT      GENERATED BY DEFAULT AS IDENTITY,
+    rh_account_id INT         NOT NULL,
+    system_id     BIGINT      NOT NULL,
+    name_id       BIGINT      NOT NULL REFERENCES package_name (id),
+    change        TEXT        NOT NULL CHECK (change IN ('installed', 'removed', 'upgraded', 'downgraded')),
+    old_evra      TEXT        CHECK (NOT empty(old_evra)),
+    new_evra      TEXT        CHECK (NOT empty(new_evra)),
+    changed       TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (rh_account_id, system_id, id),
+    CONSTRAINT system_package_change_system_id
+        FOREIGN KEY (rh_account_id, system_id)
+            REFERENCES system_inventory (rh_account_id, id) ON DELETE CASCADE
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('system_package_change', 16,
+                               $$WITH (fillfactor = '100', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+CREATE INDEX ON system_package_change (rh_account_id, name_id, changed);
+
+SELECT grant_table_partitions('SELECT', 'system_package_change', 'manager');
+SELECT grant_table_partitions('SELECT, INSERT', 'system_package_change', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'system_package_change', 'listener');
+SELECT grant_table_partitions('SELECT', 'system_package_change', 'vmaas_sync');
+
+-- patch_plan
+CREATE TABLE IF NOT EXISTS patch_plan
+(
+    id            BIGINT      GENERATED BY DEFAULT AS IDENTITY,
+    rh_account_id INT         NOT NULL REFERENCES rh_account (id),
+    name          TEXT        NOT NULL CHECK (not empty(name)),
+    description   TEXT        CHECK (NOT empty(description)),
+    window_start  TIMESTAMPTZ NOT NULL,
+    window_end    TIMESTAMPTZ NOT NULL,
+    created       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (rh_account_id, id),
+    UNIQUE (rh_account_id, name),
+    CHECK (window_start < window_end)
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('patch_plan', 16,
+                               $$WITH (fillfactor = '70', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+SELECT grant_table_partitions('SELECT, INSERT, UPDATE, DELETE', 'patch_plan', 'manager');
+SELECT grant_table_partitions('SELECT', 'patch_plan', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'patch_plan', 'listener');
+SELECT grant_table_partitions('SELECT', 'patch_plan', 'vmaas_sync');
+
+-- system/advisory pairs selected by a patch plan at the time of its creation,
+-- pairs which disappear from system_advisories are considered remediated
+CREATE TABLE IF NOT EXISTS patch_plan_item
+(
+    rh_account_id INT    NOT NULL,
+    plan_id       BIGINT NOT NULL,
+    system_id     BIGINT NOT NULL,
+    advisory_id   BIGINT NOT NULL,
+    PRIMARY KEY (rh_account_id, plan_id, system_id, advisory_id),
+    CONSTRAINT patch_plan_item_plan_id
+        FOREIGN KEY (rh_account_id, plan_id)
+            REFERENCES patch_plan (rh_account_id, id) ON DELETE CASCADE,
+    CONSTRAINT patch_plan_item_system_id
+        FOREIGN KEY (rh_account_id, system_id)
+            REFERENCES system_inventory (rh_account_id, id) ON DELETE CASCADE,
+    CONSTRAINT patch_plan_item_advisory_id
+        FOREIGN KEY (advisory_id)
+            REFERENCES advisory_metadata (id) ON DELETE CASCADE
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('patch_plan_item', 16,
+                               $$WITH (fillfactor = '70', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+CREATE INDEX ON patch_plan_item (rh_account_id, system_id);
+
+SELECT grant_table_partitions('SELECT, INSERT, UPDATE, DELETE', 'patch_plan_item', 'manager');
+SELECT grant_table_partitions('SELECT', 'patch_plan_item', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'patch_plan_item', 'listener');
+SELECT grant_table_partitions('SELECT', 'patch_plan_item', 'vmaas_sync');
+
+-- advisory_exclusion
+-- advisory accepted as a risk or excluded for a set of systems,
+-- excluded system/advisory pairs are not counted in system and account advisory caches
+CREATE TABLE IF NOT EXISTS advisory_exclusion
+(
+    id            BIGINT      GENERATED BY DEFAULT AS IDENTITY,
+    rh_account_id INT         NOT NULL REFERENCES rh_account (id),
+    advisory_id   BIGINT      NOT NULL,
+    kind          TEXT        NOT NULL CHECK (kind IN ('accepted_risk', 'excluded')),
+    justification TEXT        NOT NULL CHECK (not empty(justification)),
+    owner         TEXT        NOT NULL CHECK (not empty(owner)),
+    expires       TIMESTAMPTZ,
+    created       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (rh_account_id, id),
+    CONSTRAINT advisory_exclusion_advisory_id
+        FOREIGN KEY (advisory_id)
+            REFERENCES advisory_metadata (id) ON DELETE CASCADE
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('advisory_exclusion', 16,
+                               $$WITH (fillfactor = '70', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+CREATE INDEX ON advisory_exclusion (expires);
+
+SELECT grant_table_partitions('SELECT, INSERT, UPDATE, DELETE', 'advisory_exclusion', 'manager');
+SELECT grant_table_partitions('SELECT', 'advisory_exclusion', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'advisory_exclusion', 'listener');
+SELECT grant_table_partitions('SELECT, DELETE', 'advisory_exclusion', 'vmaas_sync');
+
+-- systems covered by an advisory exclusion, stored separately from system_advisories
+-- so the exclusion applies also when the advisory becomes applicable later
+CREATE TABLE IF NOT EXISTS advisory_exclusion_system
+(
+    rh_account_id INT    NOT NULL,
+    exclusion_id  BIGINT NOT NULL,
+    system_id     BIGINT NOT NULL,
+    advisory_id   BIGINT NOT NULL,
+    PRIMARY KEY (rh_account_id, exclusion_id, system_id),
+    CONSTRAINT advisory_exclusion_system_exclusion_id
+        FOREIGN KEY (rh_account_id, exclusion_id)
+            REFERENCES advisory_exclusion (rh_account_id, id) ON DELETE CASCADE,
+    CONSTRAINT advisory_exclusion_system_system_id
+        FOREIGN KEY (rh_account_id, system_id)
+            REFERENCES system_inventory (rh_account_id, id) ON DELETE CASCADE
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('advisory_exclusion_system', 16,
+                               $$WITH (fillfactor = '70', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+CREATE INDEX ON advisory_exclusion_system (rh_account_id, system_id, advisory_id);
+
+SELECT grant_table_partitions('SELECT, INSERT, UPDATE, DELETE', 'advisory_exclusion_system', 'manager');
+SELECT grant_table_partitions('SELECT', 'advisory_exclusion_system', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'advisory_exclusion_system', 'listener');
+SELECT grant_table_partitions('SELECT, DELETE', 'advisory_exclusion_system', 'vmaas_sync');
+
+-- package_hold
+-- pins package versions of a set of systems, updates not matching evra_pattern are held
+CREATE TABLE IF NOT EXISTS package_hold
+(
+    id            BIGINT      GENERATED BY DEFAULT AS IDENTITY,
+    rh_account_id INT         NOT NULL REFERENCES rh_account (id),
+    name_id       BIGINT      NOT NULL REFERENCES package_name (id),
+    evra_pattern  TEXT        NOT NULL CHECK (not empty(evra_pattern)),
+    description   TEXT,
+    created       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (rh_account_id, id)
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('package_hold', 16,
+                               $$WITH (fillfactor = '70', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+SELECT grant_table_partitions('SELECT, INSERT, UPDATE, DELETE', 'package_hold', 'manager');
+SELECT grant_table_partitions('SELECT', 'package_hold', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'package_hold', 'listener');
+SELECT grant_table_partitions('SELECT', 'package_hold', 'vmaas_sync');
+
+-- systems covered by a package hold
+CREATE TABLE IF NOT EXISTS package_hold_system
+(
+    rh_account_id INT    NOT NULL,
+    hold_id       BIGINT NOT NULL,
+    system_id     BIGINT NOT NULL,
+    PRIMARY KEY (rh_account_id, hold_id, system_id),
+    CONSTRAINT package_hold_system_hold_id
+        FOREIGN KEY (rh_account_id, hold_id)
+            REFERENCES package_hold (rh_account_id, id) ON DELETE CASCADE,
+    CONSTRAINT package_hold_system_system_id
+        FOREIGN KEY (rh_account_id, system_id)
+            REFERENCES system_inventory (rh_account_id, id) ON DELETE CASCADE
+) PARTITION BY HASH (rh_account_id);
+
+SELECT create_table_partitions('package_hold_system', 16,
+                               $$WITH (fillfactor = '70', autovacuum_vacuum_scale_factor = '0.05')$$);
+
+CREATE INDEX ON package_hold_system (rh_account_id, system_id);
+
+SELECT grant_table_partitions('SELECT, INSERT, UPDATE, DELETE', 'package_hold_system', 'manager');
+SELECT grant_table_partitions('SELECT', 'package_hold_system', 'evaluator');
+SELECT grant_table_partitions('SELECT', 'package_hold_system', 'listener');
+SELECT grant_table_partitions('SELECT', 'package_hold_system', 'vmaas_sync');
+
 -- ----------------------------------------------------------------------------
 -- Read access for all users
 -- ----------------------------------------------------------------------------
@@ -213,6 +213,12 @@ func UpdateDB(migrationFilesURL string) {
 	utils.ConfigureLogging()
 	conn, db := dbConn()
 
+	if migrationDryRun {
+		plan := migrationPlan(conn, migrationFilesURL)
+		plan.Log()
+		return
+	}
+
 	getAdvisoryLock(db)
 	defer releaseAdvisoryLock(db)
 
@@ -242,7 +248,13 @@ func UpdateDB(migrationFilesURL string) {
 		log.Info("Skipping migration")
 	case MIGRATE:
 		log.Info("Migrating the database")
-		startMigration(conn, db, migrationFilesURL)
+		plan := migrationPlan(conn, migrationFilesURL)
+		plan.Log()
+		if onlineMigrations && plan.Online() {
+			startOnlineMigration(conn, migrationFilesURL, &plan)
+		} else {
+			startMigration(conn, db, migrationFilesURL)
+		}
 	}
 
 	if repairSystemAdvisories0 {
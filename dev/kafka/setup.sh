@@ -9,6 +9,7 @@ for topic in \
             "patchman.evaluator.upload" \
             "patchman.evaluator.user-evaluation" \
             "patchman.advisory.update" \
+            "patchman.listener.replay" \
             "platform.content-sources.template" \
             "platform.inventory.events" \
             "platform.inventory.host-apps" \
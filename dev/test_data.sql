@@ -1,7 +1,21 @@
+DELETE FROM vmaas_cache;
+DELETE FROM rejected_upload;
+DELETE FROM system_archive;
+DELETE FROM system_culling_notice;
+DELETE FROM culling_policy;
+DELETE FROM package_hold_system;
+DELETE FROM package_hold;
+DELETE FROM advisory_exclusion_system;
+DELETE FROM advisory_exclusion;
+DELETE FROM patch_plan_item;
+DELETE FROM patch_plan;
 DELETE FROM system_advisories;
 DELETE FROM system_repo;
+DELETE FROM repo_classification;
 DELETE FROM system_package2;
+DELETE FROM system_package_change;
 DELETE FROM system_patch;
+DELETE FROM baseline;
 DELETE FROM system_inventory;
 DELETE FROM deleted_system;
 DELETE FROM repo;
@@ -32,13 +46,18 @@ INSERT INTO system_inventory (id, inventory_id, rh_account_id, vmaas_json, json_
 (4, '00000000-0000-0000-0000-000000000004', 1, '{ "package_list": [ "kernel-2.6.32-696.20.1.el6.x86_64" ], "repository_list": [ "rhel-6-server-rpms" ] }', '1', '2018-09-18 12:00:00-04', '00000000-0000-0000-0000-000000000004', 1, 'x86_64', '[{"key": "k3", "value": "val4", "namespace": "ns1"}]',                                                                                                       '2018-08-26 12:00:00-04', '2018-08-26 12:00:00-04', '2018-09-02 12:00:00-04', '00000000-0000-0000-0000-000000000001', 'group1', 'RHEL', 8,  2, '8.3',  'cccccccc-0000-0000-0001-000000000004', true, NULL,                       false, NULL, false),
 (5, '00000000-0000-0000-0000-000000000005', 1, '{ "package_list": [ "kernel-2.6.32-696.20.1.el6.x86_64" ], "repository_list": [ "rhel-6-server-rpms" ] }', '1', '2018-09-18 12:00:00-04', '00000000-0000-0000-0000-000000000005', 1, 'x86_64', '[{"key": "k1", "value": "val1", "namespace": "ns1"}]',                                                                                                       '2018-08-26 12:00:00-04', '2018-08-26 12:00:00-04', '2018-09-02 12:00:00-04', '00000000-0000-0000-0000-000000000001', 'group1', 'RHEL', 8,  3, '8.3',  'cccccccc-0000-0000-0001-000000000005', true, NULL,                       false, NULL, false),
 (6, '00000000-0000-0000-0000-000000000006', 1, '{ "package_list": [ "kernel-2.6.32-696.20.1.el6.x86_64" ], "repository_list": [ "rhel-6-server-rpms" ] }', '1', '2018-08-26 12:00:00-04', '00000000-0000-0000-0000-000000000006', 1, 'x86_64', '[{"key": "k1", "value": "val1", "namespace": "ns1"}]',                                                                                                       '2018-08-26 12:00:00-04', '2018-08-26 12:00:00-04', '2018-09-02 12:00:00-04', '00000000-0000-0000-0000-000000000001', 'group1', 'RHEL', 7,  3, '7.3',  NULL,                                   true, NULL,                        true, '15.3.0', false);
-INSERT INTO system_patch (system_id, rh_account_id, last_evaluation, third_party, template_id) VALUES
-(1, 1, '2018-09-22 12:00:00-04', true , 1),
-(2, 1, '2018-09-22 12:00:00-04', false, 1),
-(3, 1, '2018-09-22 12:00:00-04', false, 2),
-(4, 1, '2018-09-22 12:00:00-04', false, NULL),
-(5, 1, '2018-09-22 12:00:00-04', false, NULL),
-(6, 1, '2018-09-22 12:00:00-04', false, NULL);
+INSERT INTO baseline (id, rh_account_id, name, description, reference_system_id, advisory_cutoff) VALUES
+(1, 1, 'baseline1-1', 'desc1', 2, NULL),
+(2, 1, 'baseline2-1', NULL, NULL, '2016-10-01 00:00:00-04'),
+(3, 3, 'baseline3-3', NULL, 12, NULL);
+
+INSERT INTO system_patch (system_id, rh_account_id, last_evaluation, third_party, template_id, baseline_id, baseline_advisory_count_cache) VALUES
+(1, 1, '2018-09-22 12:00:00-04', true , 1, NULL, 0),
+(2, 1, '2018-09-22 12:00:00-04', false, 1, NULL, 0),
+(3, 1, '2018-09-22 12:00:00-04', false, 2, NULL, 0),
+(4, 1, '2018-09-22 12:00:00-04', false, NULL, NULL, 0),
+(5, 1, '2018-09-22 12:00:00-04', false, NULL, NULL, 0),
+(6, 1, '2018-09-22 12:00:00-04', false, NULL, 1, 1);
 
 INSERT INTO system_inventory (id, inventory_id, rh_account_id, vmaas_json, json_checksum, last_updated, unchanged_since, last_upload, display_name, arch, tags, created, stale_timestamp, stale_warning_timestamp, workspace_id, workspace_name, os_name, os_major, rhsm_version, subscription_manager_id, sap_workload, ansible_workload, ansible_workload_controller_version) VALUES
 (7, '00000000-0000-0000-0000-000000000007', 1, '{ "package_list": [ "kernel-2.6.32-696.20.1.el6.x86_64" ], "repository_list": [ "rhel-6-server-rpms" ] }', '1', '2018-10-04 14:13:12-04', '2018-09-22 12:00:00-04', '2018-08-26 12:00:00-04', '00000000-0000-0000-0000-000000000007', 'x86_64', '[{"key": "k1", "value": "val1", "namespace": "ns1"}]', '2018-08-26 12:00:00-04', '2018-08-26 12:00:00-04', '2018-09-02 12:00:00-04', '00000000-0000-0000-0000-000000000002', 'group2', 'RHEL', 8, '8.x', 'cccccccc-0000-0000-0001-000000000007', true, true, '1.0');
@@ -200,6 +219,13 @@ INSERT INTO system_package2 (rh_account_id, system_id, name_id, package_id, inst
 (1, 17, 101, 1, 11, null),
 (1, 17, 102, 2, 12, null);
 
+INSERT INTO system_package_change (id, rh_account_id, system_id, name_id, change, old_evra, new_evra, changed) VALUES
+(1, 1, 2, 101, 'upgraded', '5.6.12-200.fc31.x86_64', '5.6.13-200.fc31.x86_64', '2018-09-22 12:00:00-04'),
+(2, 1, 2, 102, 'installed', NULL, '76.0.1-1.fc31.x86_64', '2018-09-22 12:00:00-04'),
+(3, 1, 3, 101, 'upgraded', '5.6.12-200.fc31.x86_64', '5.6.13-200.fc31.x86_64', '2018-09-18 12:00:00-04'),
+(4, 1, 3, 103, 'removed', '4.4.19-8.el8_0.x86_64', NULL, '2018-09-18 12:00:00-04'),
+(5, 3, 12, 101, 'downgraded', '5.6.13-201.fc31.x86_64', '5.6.13-200.fc31.x86_64', '2018-01-22 12:00:00-04');
+
 INSERT INTO timestamp_kv (name, value) VALUES
 ('last_eval_repo_based', '2018-04-05T01:23:45+02:00');
 
@@ -213,3 +239,7 @@ ALTER TABLE repo ALTER COLUMN id RESTART WITH 100;
 ALTER TABLE package ALTER COLUMN id RESTART WITH 100;
 ALTER TABLE package_name ALTER COLUMN id RESTART WITH 150;
 ALTER TABLE template ALTER COLUMN id RESTART WITH 100;
+ALTER TABLE baseline ALTER COLUMN id RESTART WITH 100;
+ALTER TABLE advisory_exclusion ALTER COLUMN id RESTART WITH 100;
+ALTER TABLE package_hold ALTER COLUMN id RESTART WITH 100;
+ALTER TABLE system_package_change ALTER COLUMN id RESTART WITH 100;
@@ -0,0 +1,30 @@
+package evaluator
+
+import (
+	"app/base/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Advisories excluded (or accepted as a risk) for the system by non-expired advisory exclusions,
+// excluded advisories stay in system_advisories but are not counted in system caches
+func loadExcludedAdvisories(tx *gorm.DB, accountID int, systemID int64) (map[int64]bool, error) {
+	defer utils.ObserveSecondsSince(time.Now(), evaluationPartDuration.WithLabelValues("advisory-exclusions-load"))
+	var advisoryIDs []int64
+	err := tx.Table("advisory_exclusion_system aes").
+		Joins("JOIN advisory_exclusion ae ON ae.rh_account_id = aes.rh_account_id AND ae.id = aes.exclusion_id").
+		Where("aes.rh_account_id = ? AND aes.system_id = ?", accountID, systemID).
+		Where("ae.expires IS NULL OR ae.expires > now()").
+		Distinct().
+		Pluck("aes.advisory_id", &advisoryIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[int64]bool, len(advisoryIDs))
+	for _, id := range advisoryIDs {
+		excluded[id] = true
+	}
+	return excluded, nil
+}
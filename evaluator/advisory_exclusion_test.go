@@ -0,0 +1,29 @@
+package evaluator
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadExcludedAdvisories(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	expired := time.Now().Add(-time.Hour)
+	active := time.Now().Add(time.Hour)
+	id1 := database.CreateAdvisoryExclusion(t, 1, 1, &active, []int64{1})
+	defer database.DeleteAdvisoryExclusion(t, 1, id1)
+	id2 := database.CreateAdvisoryExclusion(t, 1, 3, &expired, []int64{1})
+	defer database.DeleteAdvisoryExclusion(t, 1, id2)
+	id3 := database.CreateAdvisoryExclusion(t, 1, 5, nil, []int64{2})
+	defer database.DeleteAdvisoryExclusion(t, 1, id3)
+
+	excluded, err := loadExcludedAdvisories(database.DB, 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int64]bool{1: true}, excluded)
+}
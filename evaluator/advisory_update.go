@@ -1,11 +1,11 @@
 package evaluator
 
 import (
-	"app/base"
 	"app/base/models"
 	"app/base/mqueue"
 	"app/base/types"
 	"app/base/utils"
+	"context"
 	"time"
 
 	"github.com/google/uuid"
@@ -46,7 +46,8 @@ func createAdvisoryUpdateEvent(system *models.SystemPlatformV2, advisoryIDs []in
 	}
 }
 
-func publishAdvisoryUpdates(system *models.SystemPlatformV2, advisoriesByName extendedAdvisoryMap) error {
+func publishAdvisoryUpdates(ctx context.Context, system *models.SystemPlatformV2,
+	advisoriesByName extendedAdvisoryMap) error {
 	if advisoryUpdatePublisher == nil {
 		return nil
 	}
@@ -64,7 +65,7 @@ func publishAdvisoryUpdates(system *models.SystemPlatformV2, advisoriesByName ex
 	}
 
 	event := createAdvisoryUpdateEvent(system, advisoryIDs)
-	if err := mqueue.SendMessages(base.Context, advisoryUpdatePublisher, &mqueue.AdvisoryUpdateEvents{event}); err != nil {
+	if err := mqueue.SendMessages(ctx, advisoryUpdatePublisher, &mqueue.AdvisoryUpdateEvents{event}); err != nil {
 		return errors.Wrap(err, "writing advisory update events")
 	}
 
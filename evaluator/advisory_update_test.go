@@ -72,7 +72,7 @@ func TestPublishAdvisoryUpdates(t *testing.T) {
 		"RH-3": {change: Remove, SystemAdvisories: models.SystemAdvisories{AdvisoryID: 3}},
 	}
 
-	err := publishAdvisoryUpdates(system, advisories)
+	err := publishAdvisoryUpdates(t.Context(), system, advisories)
 	assert.NoError(t, err)
 	assert.Len(t, mockWriter.Messages, 1)
 
@@ -102,7 +102,7 @@ func TestPublishAdvisoryUpdatesNoDelta(t *testing.T) {
 		"RH-2": {change: Keep, SystemAdvisories: models.SystemAdvisories{AdvisoryID: 2}},
 	}
 
-	err := publishAdvisoryUpdates(system, advisories)
+	err := publishAdvisoryUpdates(t.Context(), system, advisories)
 	assert.NoError(t, err)
 	assert.Empty(t, mockWriter.Messages)
 }
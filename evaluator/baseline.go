@@ -0,0 +1,152 @@
+package evaluator
+
+import (
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/base/vmaas"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// applyBaseline marks updates beyond the baseline assigned to the system in vmaasData as not applicable
+// by policy and returns number of such advisories.
+func applyBaseline(system *models.SystemPlatformV2, vmaasData *vmaas.UpdatesV3Response) (int, error) {
+	if system.Patch.BaselineID == nil || vmaasData == nil {
+		return 0, nil
+	}
+
+	defer utils.ObserveSecondsSince(time.Now(), evaluationPartDuration.WithLabelValues("baseline"))
+
+	var baseline models.Baseline
+	err := database.DB.Where("rh_account_id = ? AND id = ?", system.Inventory.RhAccountID, *system.Patch.BaselineID).
+		Find(&baseline).Error
+	if err != nil {
+		return 0, errors.Wrap(err, "loading baseline")
+	}
+	if baseline.ID == 0 {
+		return 0, nil
+	}
+
+	var referencePackages map[string][]*utils.Nevra
+	if baseline.ReferenceSystemID != nil {
+		referencePackages, err = loadReferencePackages(database.DB, baseline.RhAccountID, *baseline.ReferenceSystemID)
+		if err != nil {
+			return 0, errors.Wrap(err, "loading baseline reference packages")
+		}
+	}
+
+	var cutoffErrata map[string]bool
+	if baseline.AdvisoryCutoff != nil {
+		cutoffErrata, err = loadErrataAfterCutoff(database.DB, vmaasData, *baseline.AdvisoryCutoff)
+		if err != nil {
+			return 0, errors.Wrap(err, "loading advisories after baseline cutoff")
+		}
+	}
+
+	beyond := map[string]bool{}
+	updateList := vmaasData.GetUpdateList()
+	for _, updates := range updateList {
+		availableUpdates := updates.GetAvailableUpdates()
+		for i := range availableUpdates {
+			erratum := availableUpdates[i].GetErratum()
+			if len(erratum) == 0 {
+				continue
+			}
+			if cutoffErrata[erratum] ||
+				(referencePackages != nil && !installedOnReference(referencePackages, &availableUpdates[i])) {
+				beyond[erratum] = true
+			}
+		}
+	}
+	if len(beyond) == 0 {
+		return 0, nil
+	}
+
+	for _, updates := range updateList {
+		if updates == nil || updates.AvailableUpdates == nil {
+			continue
+		}
+		for i := range *updates.AvailableUpdates {
+			u := &(*updates.AvailableUpdates)[i]
+			if beyond[u.GetErratum()] {
+				u.SetInstallability(NOTAPPLICABLE)
+			}
+		}
+	}
+	return len(beyond), nil
+}
+
+// Installed packages of the reference system by package name
+func loadReferencePackages(tx *gorm.DB, accountID int, systemID int64) (map[string][]*utils.Nevra, error) {
+	var rows []struct {
+		Name string
+		Evra string
+	}
+	err := database.SystemPackagesShort(tx, accountID).
+		Joins("JOIN package p ON p.id = spkg.package_id").
+		Joins("JOIN package_name pn ON pn.id = spkg.name_id").
+		Select("pn.name, p.evra").
+		Where("spkg.system_id = ?", systemID).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		// reference system was deleted (or not evaluated yet), do not limit packages
+		return nil, nil
+	}
+
+	packages := make(map[string][]*utils.Nevra, len(rows))
+	for _, r := range rows {
+		nevra, err := utils.ParseNameEVRA(r.Name, r.Evra)
+		if err != nil {
+			utils.LogWarn("name", r.Name, "evra", r.Evra, "err", err, "unable to parse baseline reference package")
+			continue
+		}
+		packages[r.Name] = append(packages[r.Name], nevra)
+	}
+	return packages, nil
+}
+
+// Update is within the baseline when the reference system has the package installed in the same or newer version
+func installedOnReference(referencePackages map[string][]*utils.Nevra,
+	u *vmaas.UpdatesV3ResponseAvailableUpdates) bool {
+	update, err := utils.ParseNevra(u.GetPackage())
+	if err != nil {
+		return false
+	}
+	for _, installed := range referencePackages[update.Name] {
+		if installed.EVRACmp(update) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func loadErrataAfterCutoff(tx *gorm.DB, vmaasData *vmaas.UpdatesV3Response, cutoff time.Time) (
+	map[string]bool, error) {
+	reported := getReportedAdvisories(vmaasData)
+	if len(reported) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(reported))
+	for name := range reported {
+		names = append(names, name)
+	}
+
+	var after []string
+	err := tx.Table("advisory_metadata").
+		Where("name IN (?) AND public_date > ?", names, cutoff).
+		Pluck("name", &after).Error
+	if err != nil {
+		return nil, err
+	}
+	errata := make(map[string]bool, len(after))
+	for _, name := range after {
+		errata[name] = true
+	}
+	return errata, nil
+}
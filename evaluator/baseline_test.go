@@ -0,0 +1,113 @@
+package evaluator
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/base/vmaas"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+)
+
+var baselineVmaasData = `
+{
+	"update_list": {
+		"kernel-5.6.12-200.fc31.x86_64": {
+			"available_updates": [
+				{"erratum": "RH-1", "package": "kernel-5.6.13-200.fc31.x86_64"},
+				{"erratum": "RH-7", "package": "kernel-5.6.13-201.fc31.x86_64"}
+			]
+		},
+		"firefox-76.0.1-1.fc31.x86_64": {
+			"available_updates": [
+				{"erratum": "RH-3", "package": "firefox-77.0.1-1.fc31.x86_64"}
+			]
+		}
+	}
+}
+`
+
+func testBaselineVmaasData(t *testing.T) *vmaas.UpdatesV3Response {
+	var vmaasData vmaas.UpdatesV3Response
+	assert.Nil(t, sonic.Unmarshal([]byte(baselineVmaasData), &vmaasData))
+	return &vmaasData
+}
+
+func baselineErrata(vmaasData *vmaas.UpdatesV3Response, status int) []string {
+	errata := []string{}
+	for _, nevra := range []string{"kernel-5.6.12-200.fc31.x86_64", "firefox-76.0.1-1.fc31.x86_64"} {
+		for _, u := range vmaasData.GetUpdateList()[nevra].GetAvailableUpdates() {
+			if u.StatusID == status {
+				errata = append(errata, u.GetErratum())
+			}
+		}
+	}
+	return errata
+}
+
+func TestInstalledOnReference(t *testing.T) {
+	installed, err := utils.ParseNevra("kernel-5.6.13-200.fc31.x86_64")
+	assert.Nil(t, err)
+	reference := map[string][]*utils.Nevra{"kernel": {installed}}
+
+	older := "kernel-5.6.12-200.fc31.x86_64"
+	newer := "kernel-5.6.13-201.fc31.x86_64"
+	other := "firefox-77.0.1-1.fc31.x86_64"
+	assert.True(t, installedOnReference(reference, &vmaas.UpdatesV3ResponseAvailableUpdates{Package: &older}))
+	assert.False(t, installedOnReference(reference, &vmaas.UpdatesV3ResponseAvailableUpdates{Package: &newer}))
+	assert.False(t, installedOnReference(reference, &vmaas.UpdatesV3ResponseAvailableUpdates{Package: &other}))
+}
+
+func TestApplyBaselineNoBaseline(t *testing.T) {
+	vmaasData := testBaselineVmaasData(t)
+	withheld, err := applyBaseline(&models.SystemPlatformV2{}, vmaasData)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, withheld)
+	assert.Equal(t, []string{"RH-1", "RH-7", "RH-3"}, baselineErrata(vmaasData, INSTALLABLE))
+}
+
+func TestApplyBaselineReferenceSystem(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	// reference system has kernel-5.6.13-200 and firefox-76.0.1-1 installed
+	referenceID := int64(12)
+	baselineID := database.CreateBaseline(t, rhAccountID, "baseline-reference", &referenceID, nil, nil)
+	defer database.DeleteBaseline(t, rhAccountID, "baseline-reference")
+
+	system := models.SystemPlatformV2{
+		Inventory: models.SystemInventory{RhAccountID: rhAccountID},
+		Patch:     models.SystemPatch{RhAccountID: rhAccountID, BaselineID: &baselineID},
+	}
+	vmaasData := testBaselineVmaasData(t)
+	withheld, err := applyBaseline(&system, vmaasData)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, withheld)
+	assert.Equal(t, []string{"RH-1"}, baselineErrata(vmaasData, INSTALLABLE))
+	assert.Equal(t, []string{"RH-7", "RH-3"}, baselineErrata(vmaasData, NOTAPPLICABLE))
+}
+
+func TestApplyBaselineCutoff(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	// RH-7 is the only advisory published in 2017
+	cutoff := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	baselineID := database.CreateBaseline(t, rhAccountID, "baseline-cutoff", nil, &cutoff, nil)
+	defer database.DeleteBaseline(t, rhAccountID, "baseline-cutoff")
+
+	system := models.SystemPlatformV2{
+		Inventory: models.SystemInventory{RhAccountID: rhAccountID},
+		Patch:     models.SystemPatch{RhAccountID: rhAccountID, BaselineID: &baselineID},
+	}
+	vmaasData := testBaselineVmaasData(t)
+	withheld, err := applyBaseline(&system, vmaasData)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, withheld)
+	assert.Equal(t, []string{"RH-1", "RH-3"}, baselineErrata(vmaasData, INSTALLABLE))
+	assert.Equal(t, []string{"RH-7"}, baselineErrata(vmaasData, NOTAPPLICABLE))
+}
@@ -7,11 +7,14 @@ import (
 	"app/base/database"
 	"app/base/models"
 	"app/base/mqueue"
+	"app/base/tracing"
 	"app/base/types"
 	"app/base/utils"
 	"app/base/vmaas"
+	"app/base/vmaas_dump"
 	"context"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
@@ -19,6 +22,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/jinzhu/copier"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
@@ -36,12 +41,14 @@ var (
 	consumerCount                 int
 	vmaasClient                   *api.Client
 	vmaasUpdatesURL               string
+	vmaasDump                     *vmaas_dump.Source
 	evalTopic                     string
 	evalLabel                     string
 	ptTopic                       string
 	ptWriter                      mqueue.Writer
 	enableAdvisoryAnalysis        bool
 	enablePackageAnalysis         bool
+	enablePackageHistory          bool
 	enableRepoAnalysis            bool
 	enableBypass                  bool
 	enableStaleSysEval            bool
@@ -52,6 +59,7 @@ var (
 	packageNameCacheSize          int
 	enableVmaasCache              bool
 	vmaasCacheSize                int
+	vmaasCacheSharedBackend       string
 	vmaasCacheCheckDuration       time.Duration
 	vmaasCallMaxRetries           int
 	vmaasCallUseExpRetry          bool
@@ -75,17 +83,28 @@ func configure() {
 	evalTopic = utils.FailIfEmpty(utils.CoreCfg.EvalTopic, "EVAL_TOPIC")
 	ptTopic = utils.FailIfEmpty(utils.CoreCfg.PayloadTrackerTopic, "PAYLOAD_TRACKER_TOPIC")
 	ptWriter = mqueue.NewKafkaWriterFromEnv(ptTopic)
+	configureVmaas()
+	configureRemediations()
+	configureNotifications()
+	configureInventoryViews()
+	configureAdvisoryUpdates()
+	configureStatus()
+}
+
+func configureVmaas() {
+	if utils.CoreCfg.VmaasDumpPath != "" {
+		// disconnected deployment, answer updates requests from offline vmaas dump
+		utils.LogInfo("path", utils.CoreCfg.VmaasDumpPath, "Evaluating from offline vmaas dump")
+		vmaasDump = vmaas_dump.NewSource(utils.CoreCfg.VmaasDumpPath)
+		return
+	}
 	useTraceLevel := log.IsLevelEnabled(log.TraceLevel)
 	vmaasClient = &api.Client{
 		HTTPClient: &http.Client{Transport: &http.Transport{DisableCompression: disableCompression}},
 		Debug:      useTraceLevel,
+		Upstream:   api.GetUpstream(api.UpstreamVmaas),
 	}
 	vmaasUpdatesURL = utils.FailIfEmpty(utils.CoreCfg.VmaasAddress, "VMAAS_ADDRESS") + base.VMaaSAPIPrefix + "/updates"
-	configureRemediations()
-	configureNotifications()
-	configureInventoryViews()
-	configureAdvisoryUpdates()
-	configureStatus()
 }
 
 func configureEvaluator() {
@@ -98,6 +117,8 @@ func configureEvaluator() {
 	enableAdvisoryAnalysis = utils.PodConfig.GetBool("advisory_analysis", true)
 	// evaluate packages
 	enablePackageAnalysis = utils.PodConfig.GetBool("package_analysis", true)
+	// Store package changes to system package journal
+	enablePackageHistory = utils.PodConfig.GetBool("package_history", true)
 	// Look for third party repos
 	enableRepoAnalysis = utils.PodConfig.GetBool("repo_analysis", true)
 	// Evaluate stale systems
@@ -120,6 +141,8 @@ func configureEvaluator() {
 	vmaasCacheSize = utils.PodConfig.GetInt("vmaas_cache_size", 10000)
 	// Interval to check vmaas API if there was a data change thus if cache is still valid
 	vmaasCacheCheckDuration = time.Duration(utils.PodConfig.GetInt("vmaas_cache_check_duration_sec", 60)) * time.Second
+	// Optional vmaas response cache shared by all evaluator replicas ("" = disabled, "postgresql")
+	vmaasCacheSharedBackend = utils.PodConfig.GetString("vmaas_cache_shared_backend", "")
 	// How many tries before we consider vmaad API call failed
 	vmaasCallMaxRetries = utils.PodConfig.GetInt("vmaas_call_max_retries", 8)
 	// Use exponential delay between two vmaas calls
@@ -142,8 +165,13 @@ func configureEvaluator() {
 	enableTemplateAdvisoryEval = utils.PodConfig.GetBool("template_advisory_eval", false)
 }
 
-func Evaluate(ctx context.Context, event *mqueue.PlatformEvent, inventoryID uuid.UUID, evaluationType string) error {
+func Evaluate(ctx context.Context, event *mqueue.PlatformEvent, inventoryID uuid.UUID,
+	evaluationType string) (err error) {
 	defer utils.ObserveSecondsSince(time.Now(), evaluationDuration.WithLabelValues(evaluationType))
+	ctx, span := tracing.Start(ctx, "evaluator evaluate", trace.WithAttributes(
+		attribute.String("inventory_id", inventoryID.String()), attribute.String("org_id", event.GetOrgID()),
+		attribute.String("evaluation_type", evaluationType)))
+	defer func() { tracing.End(span, err) }()
 
 	utils.LogInfo("inventoryID", inventoryID, "Evaluating system")
 	if enableBypass {
@@ -236,7 +264,14 @@ func evaluateInDatabase(ctx context.Context, event *mqueue.PlatformEvent, invent
 		return nil, nil, nil
 	}
 
-	vmaasData, err := evaluateWithVmaas(updatesData, system, event)
+	baselineAdvisories, err := applyBaseline(system, updatesData)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "baseline evaluation failed")
+	}
+	// persisted to system_patch.baseline_advisory_count_cache in updateSystemPlatform
+	system.Patch.BaselineAdvisoryCountCache = baselineAdvisories
+
+	vmaasData, err := evaluateWithVmaas(ctx, updatesData, system, event)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "evaluation with vmaas failed")
 	}
@@ -287,11 +322,11 @@ func tryGetYumUpdates(system *models.SystemPlatformV2) (*vmaas.UpdatesV3Response
 	return &resp, nil
 }
 
-func evaluateWithVmaas(updatesData *vmaas.UpdatesV3Response,
+func evaluateWithVmaas(ctx context.Context, updatesData *vmaas.UpdatesV3Response,
 	system *models.SystemPlatformV2, event *mqueue.PlatformEvent) (*vmaas.UpdatesV3Response, error) {
 	defer utils.ObserveSecondsSince(time.Now(), evaluationPartDuration.WithLabelValues("evaluate-with-vmaas-full"))
 
-	err := evaluateAndStore(system, updatesData, event)
+	err := evaluateAndStore(ctx, system, updatesData, event)
 	if err != nil {
 		return nil, errors.Wrap(err, "Unable to evaluate and store results")
 	}
@@ -355,8 +390,16 @@ func getVmaasUpdates(ctx context.Context, system *models.SystemPlatformV2) (*vma
 	defer utils.ObserveSecondsSince(time.Now(), evaluationPartDuration.WithLabelValues("vmaas-updates-prepare"))
 
 	var vmaasDataCopy vmaas.UpdatesV3Response
+	// repos are classified per org, the response differs for the same package profile
+	thirdParty := system.Patch.ThirdParty
+	useOptimisticUpdates := thirdParty || vmaasCallUseOptimisticUpdates
+	ignoredRepos, err := loadIgnoredRepos(system)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load ignored repos")
+	}
+	cacheKey := vmaasCacheKey(system.Inventory.JSONChecksum, thirdParty, useOptimisticUpdates, ignoredRepos)
 	// first check if we have data in cache
-	vmaasData, ok := memoryVmaasCache.Get(system.Inventory.JSONChecksum)
+	vmaasData, ok := vmaasCache.Get(cacheKey)
 	if ok {
 		// return copy of vmaasData to avoid modification of cached data e.g. by templates
 		err := copier.CopyWithOption(&vmaasDataCopy, vmaasData, copier.Option{DeepCopy: true})
@@ -365,7 +408,7 @@ func getVmaasUpdates(ctx context.Context, system *models.SystemPlatformV2) (*vma
 		}
 		return &vmaasDataCopy, nil
 	}
-	updatesReq, err := tryGetVmaasRequest(system)
+	updatesReq, err := tryGetVmaasRequest(system, ignoredRepos)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to get vmaas request")
 	}
@@ -375,8 +418,7 @@ func getVmaasUpdates(ctx context.Context, system *models.SystemPlatformV2) (*vma
 		return nil, nil
 	}
 
-	updatesReq.ThirdParty = utils.PtrBool(system.Patch.ThirdParty) // enable "third_party" updates in VMaaS if needed
-	useOptimisticUpdates := system.Patch.ThirdParty || vmaasCallUseOptimisticUpdates
+	updatesReq.ThirdParty = utils.PtrBool(thirdParty) // enable "third_party" updates in VMaaS if needed
 	updatesReq.OptimisticUpdates = utils.PtrBool(useOptimisticUpdates)
 	updatesReq.EpochRequired = utils.PtrBool(true)
 
@@ -386,18 +428,18 @@ func getVmaasUpdates(ctx context.Context, system *models.SystemPlatformV2) (*vma
 		return nil, errors.Wrap(err, "vmaas API call failed")
 	}
 
-	if memoryVmaasCache.enabled {
+	if vmaasCache.enabled {
 		// store copy of vmaasData to cache to avoid modification of cached data e.g. by templates
 		err := copier.CopyWithOption(&vmaasDataCopy, vmaasData, copier.Option{DeepCopy: true})
 		if err != nil {
 			return nil, err
 		}
-		memoryVmaasCache.Add(system.Inventory.JSONChecksum, &vmaasDataCopy)
+		vmaasCache.Add(cacheKey, &vmaasDataCopy)
 	}
 	return vmaasData, nil
 }
 
-func tryGetVmaasRequest(system *models.SystemPlatformV2) (*vmaas.UpdatesV3Request, error) {
+func tryGetVmaasRequest(system *models.SystemPlatformV2, ignoredRepos []string) (*vmaas.UpdatesV3Request, error) {
 	if system == nil || system.Inventory.VmaasJSON == nil {
 		evaluationCnt.WithLabelValues("error-parse-vmaas-json").Inc()
 		invID := uuid.Nil
@@ -426,6 +468,9 @@ func tryGetVmaasRequest(system *models.SystemPlatformV2) (*vmaas.UpdatesV3Reques
 		return nil, nil
 	}
 
+	updatesReq.RepositoryList = slices.DeleteFunc(updatesReq.RepositoryList, func(repo string) bool {
+		return slices.Contains(ignoredRepos, repo)
+	})
 	if len(updatesReq.RepositoryList) == 0 {
 		// system without any repositories won't have any advisories evaluated by vmaas
 		evaluationCnt.WithLabelValues("error-no-repositories").Inc()
@@ -477,7 +522,7 @@ func commitWithObserve(tx *gorm.DB) error {
 // and then executes all deletions, updates, and insertions in a single transaction.
 
 //nolint:funlen
-func evaluateAndStore(system *models.SystemPlatformV2,
+func evaluateAndStore(ctx context.Context, system *models.SystemPlatformV2,
 	vmaasData *vmaas.UpdatesV3Response, event *mqueue.PlatformEvent) error {
 	advisoriesByName, err := lazySaveAndLoadAdvisories(system, vmaasData)
 	if err != nil {
@@ -489,7 +534,7 @@ func evaluateAndStore(system *models.SystemPlatformV2,
 		return errors.Wrap(err, "Package loading failed")
 	}
 
-	tx := database.DB.WithContext(base.Context).Begin()
+	tx := database.DB.WithContext(ctx).Begin()
 	// Don't allow requested TX to hang around locking the rows
 	defer tx.Rollback()
 
@@ -505,7 +550,8 @@ func evaluateAndStore(system *models.SystemPlatformV2,
 		return errors.Wrap(err, "Unable to update system packages")
 	}
 
-	err = updateSystemPlatform(tx, system, systemAdvisoriesNew, installed, installable, applicable)
+	held := heldPackagesCount(pkgByName)
+	err = updateSystemPlatform(tx, system, systemAdvisoriesNew, installed, installable, applicable, held)
 	if err != nil {
 		evaluationCnt.WithLabelValues("error-update-system").Inc()
 		return errors.Wrap(err, "Unable to update system")
@@ -533,7 +579,7 @@ func evaluateAndStore(system *models.SystemPlatformV2,
 	}
 
 	if enableAdvisoryUpdates {
-		err = publishAdvisoryUpdates(system, advisoriesByName)
+		err = publishAdvisoryUpdates(ctx, system, advisoriesByName)
 		if err != nil {
 			evaluationCnt.WithLabelValues("error-advisory-update-publish").Inc()
 			utils.LogError("orgID", event.GetOrgID(), "inventoryID", system.GetInventoryID(), "err", err,
@@ -559,13 +605,16 @@ func analyzeRepos(system *models.SystemPlatformV2) (thirdParty bool, err error)
 	defer utils.ObserveSecondsSince(time.Now(), evaluationPartDuration.WithLabelValues("repo-analysis"))
 
 	// if system has associated at least one third party repo
-	// it's marked as third party system
+	// it's marked as third party system,
+	// org classification of the repo (trusted, third_party) overrides the global flag,
+	// ignored repos are left out of the decision and of the vmaas request
 	var thirdPartyCount int64
 	err = database.DB.Table("system_repo sr").
 		Joins("join repo r on r.id = sr.repo_id").
+		Joins("left join repo_classification rc on rc.rh_account_id = sr.rh_account_id and rc.repo_id = sr.repo_id").
 		Where("sr.rh_account_id = ?", system.Inventory.RhAccountID).
 		Where("sr.system_id = ?", system.Inventory.ID).
-		Where("r.third_party = true").
+		Where("coalesce(rc.classification = ?, r.third_party) = true", models.RepoClassificationThirdParty).
 		Count(&thirdPartyCount).Error
 	if err != nil {
 		utils.LogWarn("err", err, "accountID", system.Inventory.RhAccountID, "systemID", system.Inventory.ID,
@@ -576,6 +625,20 @@ func analyzeRepos(system *models.SystemPlatformV2) (thirdParty bool, err error)
 	return thirdParty, nil
 }
 
+// Names of system repos ignored by org classification, they are left out of the vmaas request
+func loadIgnoredRepos(system *models.SystemPlatformV2) ([]string, error) {
+	var names []string
+	err := database.DB.Table("system_repo sr").
+		Joins("join repo r on r.id = sr.repo_id").
+		Joins("join repo_classification rc on rc.rh_account_id = sr.rh_account_id and rc.repo_id = sr.repo_id").
+		Where("sr.rh_account_id = ?", system.Inventory.RhAccountID).
+		Where("sr.system_id = ?", system.Inventory.ID).
+		Where("rc.classification = ?", models.RepoClassificationIgnored).
+		Order("r.name").
+		Pluck("r.name", &names).Error
+	return names, err
+}
+
 func incrementAdvisoryTypeCounts(advisory models.AdvisoryMetadata, enhCount, bugCount, secCount *int) {
 	switch advisory.AdvisoryTypeID {
 	case enhancement:
@@ -589,7 +652,7 @@ func incrementAdvisoryTypeCounts(advisory models.AdvisoryMetadata, enhCount, bug
 
 // nolint: funlen
 func updateSystemPlatform(tx *gorm.DB, system *models.SystemPlatformV2,
-	advisories SystemAdvisoryMap, installed, installable, applicable int) error {
+	advisories SystemAdvisoryMap, installed, installable, applicable, held int) error {
 	defer utils.ObserveSecondsSince(time.Now(), evaluationPartDuration.WithLabelValues("system-update"))
 	if system.Inventory.LastUpload != nil {
 		defer utils.ObserveSecondsSince(*system.Inventory.LastUpload, uploadEvaluationDelay)
@@ -599,7 +662,7 @@ func updateSystemPlatform(tx *gorm.DB, system *models.SystemPlatformV2,
 	}
 
 	lastEval := time.Now()
-	data := make(map[string]interface{}, 13)
+	data := make(map[string]interface{}, 14)
 	data["last_evaluation"] = lastEval
 
 	var (
@@ -611,7 +674,14 @@ func updateSystemPlatform(tx *gorm.DB, system *models.SystemPlatformV2,
 		if advisories == nil {
 			return errors.New("Invalid args")
 		}
+		excluded, err := loadExcludedAdvisories(tx, system.Inventory.RhAccountID, system.Inventory.ID)
+		if err != nil {
+			return errors.Wrap(err, "Unable to load advisory exclusions")
+		}
 		for _, sa := range advisories {
+			if excluded[sa.AdvisoryID] || sa.StatusID == NOTAPPLICABLE {
+				continue
+			}
 			if sa.StatusID == INSTALLABLE {
 				incrementAdvisoryTypeCounts(sa.Advisory, &installableEnhCount, &installableBugCount, &installableSecCount)
 				installableCount++
@@ -629,12 +699,14 @@ func updateSystemPlatform(tx *gorm.DB, system *models.SystemPlatformV2,
 		data["applicable_advisory_enh_count_cache"] = applicableEnhCount
 		data["applicable_advisory_bug_count_cache"] = applicableBugCount
 		data["applicable_advisory_sec_count_cache"] = applicableSecCount
+		data["baseline_advisory_count_cache"] = system.Patch.BaselineAdvisoryCountCache
 	}
 
 	if enablePackageAnalysis {
 		data["packages_installed"] = installed
 		data["packages_installable"] = installable
 		data["packages_applicable"] = applicable
+		data["packages_held"] = held
 	}
 
 	if enableRepoAnalysis {
@@ -673,6 +745,7 @@ func updateSystemPlatform(tx *gorm.DB, system *models.SystemPlatformV2,
 		system.Patch.PackagesInstalled = installed
 		system.Patch.PackagesInstallable = installable
 		system.Patch.PackagesApplicable = applicable
+		system.Patch.PackagesHeld = held
 	}
 	return nil
 }
@@ -680,6 +753,14 @@ func updateSystemPlatform(tx *gorm.DB, system *models.SystemPlatformV2,
 func callVMaas(ctx context.Context, request *vmaas.UpdatesV3Request) (*vmaas.UpdatesV3Response, error) {
 	defer utils.ObserveSecondsSince(time.Now(), evaluationPartDuration.WithLabelValues("vmaas-updates-call"))
 
+	if vmaasDump != nil {
+		dump, err := vmaasDump.Load()
+		if err != nil {
+			return nil, errors.Wrap(err, "vmaas dump loading failed")
+		}
+		return dump.Updates(request), nil
+	}
+
 	vmaasCallFunc := func() (interface{}, *http.Response, error) {
 		utils.LogTrace("request", *request, "vmaas /updates request")
 		vmaasData := vmaas.UpdatesV3Response{}
@@ -693,7 +774,7 @@ func callVMaas(ctx context.Context, request *vmaas.UpdatesV3Request) (*vmaas.Upd
 		return &vmaasData, resp, err
 	}
 
-	vmaasDataPtr, err := utils.HTTPCallRetry(vmaasCallFunc, vmaasCallUseExpRetry,
+	vmaasDataPtr, err := vmaasClient.CallRetry(vmaasCallFunc, vmaasCallUseExpRetry,
 		vmaasCallMaxRetries, http.StatusServiceUnavailable)
 	if err != nil {
 		return nil, errors.Wrap(err, "vmaas /v3/updates API call failed")
@@ -769,14 +850,20 @@ func evaluateHandler(m mqueue.KafkaMessage) error {
 		nRequestIDs := len(event.RequestIDs)
 		for i, id := range event.SystemIDs {
 			ptEvent.InventoryID = id
+			ctx := m.Context()
 			if nRequestIDs > i {
 				ptEvent.RequestID = &event.RequestIDs[i]
+				ctx = api.ContextWithRequestID(ctx, event.RequestIDs[i])
 			}
-			ptEvent, err = runEvaluate(base.Context, event, id, evalLabel, ptEvent, &wg, guard)
+			ptEvent, err = runEvaluate(ctx, event, id, evalLabel, ptEvent, &wg, guard)
 			ptEvents = append(ptEvents, ptEvent)
 		}
 	} else {
-		ptEvent, err = runEvaluate(base.Context, event, event.ID, evalLabel, ptEvent, &wg, guard)
+		ctx := m.Context()
+		if len(event.RequestIDs) > 0 {
+			ctx = api.ContextWithRequestID(ctx, event.RequestIDs[0])
+		}
+		ptEvent, err = runEvaluate(ctx, event, event.ID, evalLabel, ptEvent, &wg, guard)
 		ptEvents = append(ptEvents, ptEvent)
 	}
 	wg.Wait()
@@ -787,7 +874,7 @@ func evaluateHandler(m mqueue.KafkaMessage) error {
 
 	// send kafka message to payload tracker
 	if evalLabel == uploadLabel {
-		ptErr := mqueue.SendMessages(base.Context, ptWriter, &ptEvents)
+		ptErr := mqueue.SendMessages(m.Context(), ptWriter, &ptEvents)
 		if ptErr != nil {
 			// don't fail with err, just log that we couldn't send msg to payload tracker
 			utils.LogWarn("err", ptErr, WarnPayloadTracker)
@@ -799,10 +886,11 @@ func evaluateHandler(m mqueue.KafkaMessage) error {
 func loadCache() {
 	memoryPackageCache = NewPackageCache(enablePackageCache, preloadPackageCache, packageCacheSize, packageNameCacheSize)
 	memoryPackageCache.Load()
-	memoryVmaasCache = NewVmaasPackageCache(enableVmaasCache, vmaasCacheSize, vmaasCacheCheckDuration)
-	if memoryVmaasCache.enabled {
+	vmaasCache = NewVmaasPackageCache(enableVmaasCache, vmaasCacheSize, vmaasCacheCheckDuration,
+		vmaasCacheSharedBackend)
+	if vmaasCache.enabled {
 		// no need to check cache validity when cache is not enabled
-		go memoryVmaasCache.CheckValidity()
+		go vmaasCache.CheckValidity()
 	}
 }
 
@@ -26,6 +26,8 @@ const (
 
 type extendedAdvisory struct {
 	change ChangeType
+	// status stored before the Update change
+	prevStatusID int
 	models.SystemAdvisories
 }
 
@@ -92,9 +94,11 @@ func evaluateChanges(vmaasData *vmaas.UpdatesV3Response, stored SystemAdvisoryMa
 	for reportedName, reportedStatusID := range reported {
 		if storedAdvisory, found := stored[reportedName]; found {
 			if reportedStatusID != storedAdvisory.StatusID {
+				prevStatusID := storedAdvisory.StatusID
 				storedAdvisory.StatusID = reportedStatusID
 				extendedAdvisories[reportedName] = extendedAdvisory{
 					change:           Update,
+					prevStatusID:     prevStatusID,
 					SystemAdvisories: storedAdvisory,
 				}
 			} else {
@@ -261,8 +265,9 @@ func storeAdvisoryData(tx *gorm.DB, system *models.SystemPlatformV2, advisoriesB
 	return systemAdvisoriesNew, nil
 }
 
+// Advisories excluded for the system are not counted, caches are refreshed when the exclusion changes
 func calcAdvisoryChanges(system *models.SystemPlatformV2, //nolint: funlen
-	advisoriesByName extendedAdvisoryMap) []models.AdvisoryAccountData {
+	advisoriesByName extendedAdvisoryMap, excluded map[int64]bool) []models.AdvisoryAccountData {
 	// If system is stale, we won't change any rows in advisory_account_data
 	if system.Inventory.Stale {
 		return []models.AdvisoryAccountData{}
@@ -270,6 +275,24 @@ func calcAdvisoryChanges(system *models.SystemPlatformV2, //nolint: funlen
 
 	aadMap := make(map[int64]models.AdvisoryAccountData, len(advisoriesByName))
 	for _, advisory := range advisoriesByName {
+		if excluded[advisory.AdvisoryID] {
+			continue
+		}
+		if advisory.StatusID == NOTAPPLICABLE {
+			// advisories beyond the baseline are not counted, update makes them no longer counted
+			if advisory.change == Update {
+				aad := models.AdvisoryAccountData{
+					AdvisoryID:        advisory.AdvisoryID,
+					RhAccountID:       system.Inventory.RhAccountID,
+					SystemsApplicable: -1,
+				}
+				if advisory.prevStatusID == INSTALLABLE {
+					aad.SystemsInstallable = -1
+				}
+				aadMap[advisory.AdvisoryID] = aad
+			}
+			continue
+		}
 		switch advisory.change {
 		case Remove:
 			aadMap[advisory.AdvisoryID] = models.AdvisoryAccountData{
@@ -362,9 +385,10 @@ func processAdvisories(system *models.SystemPlatformV2, advisoriesByName extende
 			advisoryObjs = append(advisoryObjs, adv)
 			fallthrough
 		case Keep:
-			if advisory.StatusID == INSTALLABLE {
+			switch advisory.StatusID {
+			case INSTALLABLE:
 				installableCnt++
-			} else { // APPLICABLE
+			case APPLICABLE:
 				applicableCnt++
 			}
 		}
@@ -417,7 +441,11 @@ func updateAdvisoryAccountData(
 	system *models.SystemPlatformV2,
 	advisoriesByName extendedAdvisoryMap,
 ) error {
-	changes := calcAdvisoryChanges(system, advisoriesByName)
+	excluded, err := loadExcludedAdvisories(tx, system.Inventory.RhAccountID, system.InternalSystemID())
+	if err != nil {
+		return errors.Wrap(err, "Unable to load advisory exclusions")
+	}
+	changes := calcAdvisoryChanges(system, advisoriesByName, excluded)
 
 	if len(changes) == 0 {
 		return nil
@@ -304,14 +304,33 @@ func TestCalcAdvisoryChanges(t *testing.T) {
 			change:           Add,
 			SystemAdvisories: models.SystemAdvisories{AdvisoryID: int64(105), StatusID: APPLICABLE},
 		},
+		"ER-106": {
+			change:           Update,
+			prevStatusID:     INSTALLABLE,
+			SystemAdvisories: models.SystemAdvisories{AdvisoryID: int64(106), StatusID: NOTAPPLICABLE},
+		},
+		"ER-107": {
+			change:           Add,
+			SystemAdvisories: models.SystemAdvisories{AdvisoryID: int64(107), StatusID: NOTAPPLICABLE},
+		},
+		"ER-108": {
+			change:           Remove,
+			SystemAdvisories: models.SystemAdvisories{AdvisoryID: int64(108), StatusID: NOTAPPLICABLE},
+		},
+		"ER-109": {
+			change:           Add,
+			SystemAdvisories: models.SystemAdvisories{AdvisoryID: int64(109), StatusID: INSTALLABLE},
+		},
 	}
 
-	changes := calcAdvisoryChanges(system, advisoriesByName)
+	// ER-109 is excluded for the system
+	changes := calcAdvisoryChanges(system, advisoriesByName, map[int64]bool{109: true})
 	expected := map[int64]models.AdvisoryAccountData{
 		102: {SystemsApplicable: 1, SystemsInstallable: 1},
 		103: {SystemsApplicable: -1, SystemsInstallable: -1},
 		104: {SystemsInstallable: -1},
 		105: {SystemsApplicable: 1},
+		106: {SystemsApplicable: -1, SystemsInstallable: -1},
 	}
 	assert.Equal(t, len(expected), len(changes))
 	for _, change := range changes {
@@ -131,8 +131,13 @@ func loadPackages(system *models.SystemPlatformV2, vmaasData *vmaas.UpdatesV3Res
 	map[string]namedPackage, int, int, int, error) {
 	defer utils.ObserveSecondsSince(time.Now(), evaluationPartDuration.WithLabelValues("packages-load"))
 
-	packages, installed, installable, applicable := packagesFromUpdateList(system.GetInventoryID(), vmaasData)
-	err := loadSystemNEVRAsFromDB(system, packages)
+	holds, err := loadPackageHolds(database.DB, system.Inventory.RhAccountID, system.InternalSystemID())
+	if err != nil {
+		return nil, 0, 0, 0, errors.Wrap(err, "loading package holds")
+	}
+
+	packages, installed, installable, applicable := packagesFromUpdateList(system.GetInventoryID(), vmaasData, holds)
+	err = loadSystemNEVRAsFromDB(system, packages)
 	if err != nil {
 		return nil, 0, 0, 0, errors.Wrap(err, "loading packages")
 	}
@@ -140,7 +145,7 @@ func loadPackages(system *models.SystemPlatformV2, vmaasData *vmaas.UpdatesV3Res
 	return packages, installed, installable, applicable, nil
 }
 
-func packagesFromUpdateList(inventoryID uuid.UUID, vmaasData *vmaas.UpdatesV3Response) (
+func packagesFromUpdateList(inventoryID uuid.UUID, vmaasData *vmaas.UpdatesV3Response, holds packageHolds) (
 	map[string]namedPackage, int, int, int) {
 	installed := 0
 	installable := 0
@@ -159,13 +164,14 @@ func packagesFromUpdateList(inventoryID uuid.UUID, vmaasData *vmaas.UpdatesV3Res
 		// before we used nevra.EVRAString() function which shows only non zero epoch, keep it consistent
 		// maybe we need here something like: evra := strings.TrimPrefix(upData.GetEVRA(), "0:")
 		if ok {
-			installableID, applicableID := latestPackagesFromUpdatesList(availableUpdates)
+			installableID, applicableID, heldID := latestPackagesFromUpdatesList(availableUpdates, pkgMeta.NameID, holds)
 			packages[nevra] = namedPackage{
 				NameID:        pkgMeta.NameID,
 				PackageID:     pkgMeta.ID,
 				Change:        Add,
 				InstallableID: installableID,
 				ApplicableID:  applicableID,
+				HeldID:        heldID,
 			}
 			if installableID != nil {
 				installable++
@@ -181,7 +187,7 @@ func packagesFromUpdateList(inventoryID uuid.UUID, vmaasData *vmaas.UpdatesV3Res
 
 func loadSystemNEVRAsFromDB(system *models.SystemPlatformV2, packages map[string]namedPackage) error {
 	rows, err := database.DB.Table("system_package2 sp2").
-		Select("sp2.name_id, sp2.package_id, sp2.installable_id, sp2.applicable_id").
+		Select("sp2.name_id, sp2.package_id, sp2.installable_id, sp2.applicable_id, sp2.held_id").
 		Where("rh_account_id = ? AND system_id = ?", system.Inventory.RhAccountID, system.InternalSystemID()).
 		Rows()
 	if err != nil {
@@ -215,6 +221,7 @@ func loadSystemNEVRAsFromDB(system *models.SystemPlatformV2, packages map[string
 				Change:        Remove,
 				ApplicableID:  columns.ApplicableID,
 				InstallableID: columns.InstallableID,
+				HeldID:        columns.HeldID,
 			}
 		}
 	}
@@ -243,7 +250,20 @@ func latestPkgsChanged(current, stored namedPackage) bool {
 	applicableEqual := (current.ApplicableID == nil && stored.ApplicableID == nil) ||
 		(current.ApplicableID != nil && stored.ApplicableID != nil &&
 			*current.ApplicableID == *stored.ApplicableID)
-	return !(installableEqual && applicableEqual)
+	heldEqual := (current.HeldID == nil && stored.HeldID == nil) ||
+		(current.HeldID != nil && stored.HeldID != nil && *current.HeldID == *stored.HeldID)
+	return !(installableEqual && applicableEqual && heldEqual)
+}
+
+// Count installed packages with an update held by a package hold
+func heldPackagesCount(packagesByNEVRA map[string]namedPackage) int {
+	held := 0
+	for _, pkg := range packagesByNEVRA {
+		if pkg.Change != Remove && pkg.HeldID != nil {
+			held++
+		}
+	}
+	return held
 }
 
 func createSystemPackage(system *models.SystemPlatformV2, pkg namedPackage) models.SystemPackage {
@@ -254,6 +274,7 @@ func createSystemPackage(system *models.SystemPlatformV2, pkg namedPackage) mode
 		NameID:        pkg.NameID,
 		InstallableID: pkg.InstallableID,
 		ApplicableID:  pkg.ApplicableID,
+		HeldID:        pkg.HeldID,
 	}
 	return systemPackage
 }
@@ -299,19 +320,136 @@ func updateSystemPackages(tx *gorm.DB, system *models.SystemPlatformV2,
 		return err
 	}
 
+	if err := storePackageChanges(tx, system, packagesByNEVRA); err != nil {
+		return err
+	}
+
 	err := database.UnnestInsert(tx,
-		`INSERT INTO system_package2 (rh_account_id, system_id, package_id, name_id, installable_id, applicable_id)
-				(select * from unnest($1::int[], $2::bigint[], $3::bigint[], $4::bigint[], $5::bigint[], $6::bigint[]))
+		`INSERT INTO system_package2 (rh_account_id, system_id, package_id, name_id, installable_id, applicable_id,
+				held_id)
+				(select * from unnest($1::int[], $2::bigint[], $3::bigint[], $4::bigint[], $5::bigint[], $6::bigint[],
+					$7::bigint[]))
 		 ON CONFLICT (rh_account_id, system_id, package_id)
-		 DO UPDATE SET installable_id = EXCLUDED.installable_id, applicable_id = EXCLUDED.applicable_id`, updatedPackages)
+		 DO UPDATE SET installable_id = EXCLUDED.installable_id, applicable_id = EXCLUDED.applicable_id,
+				held_id = EXCLUDED.held_id`, updatedPackages)
 	return errors.Wrap(err,
 		"Storing system packages")
 }
 
-func latestPackagesFromUpdatesList(updatePkgData []vmaas.UpdatesV3ResponseAvailableUpdates) (*int64, *int64) {
+// Compare installed packages with packages stored since the last evaluation per package name.
+// Single removed and single added version of the same package is an upgrade (or downgrade),
+// otherwise (e.g. multiple kernels) the versions are journaled as installed and removed.
+func packageChanges(system *models.SystemPlatformV2, packagesByNEVRA map[string]namedPackage,
+) []models.SystemPackageChange {
+	type nameChanges struct {
+		added, removed []*PackageCacheMetadata
+	}
+	hasStored := false
+	byName := map[int64]*nameChanges{}
+	for _, pkg := range packagesByNEVRA {
+		if pkg.Change != Add {
+			hasStored = true
+		}
+		if pkg.Change != Add && pkg.Change != Remove {
+			continue
+		}
+		meta, ok := memoryPackageCache.GetByID(pkg.PackageID)
+		if !ok {
+			utils.LogWarn("packageID", pkg.PackageID, "package missing in cache, skipping package change")
+			continue
+		}
+		if _, ok := byName[pkg.NameID]; !ok {
+			byName[pkg.NameID] = &nameChanges{}
+		}
+		if pkg.Change == Add {
+			byName[pkg.NameID].added = append(byName[pkg.NameID].added, meta)
+		} else {
+			byName[pkg.NameID].removed = append(byName[pkg.NameID].removed, meta)
+		}
+	}
+	// first evaluation of the system, all packages would be journaled as installed
+	if !hasStored {
+		return nil
+	}
+
+	changed := time.Now()
+	if system.Inventory.LastUpload != nil {
+		changed = *system.Inventory.LastUpload
+	}
+	newChange := func(nameID int64, change string, oldPkg, newPkg *PackageCacheMetadata) models.SystemPackageChange {
+		c := models.SystemPackageChange{
+			RhAccountID: system.Inventory.RhAccountID,
+			SystemID:    system.InternalSystemID(),
+			NameID:      nameID,
+			Change:      change,
+			Changed:     changed,
+		}
+		if oldPkg != nil {
+			c.OldEvra = &oldPkg.Evra
+		}
+		if newPkg != nil {
+			c.NewEvra = &newPkg.Evra
+		}
+		return c
+	}
+
+	nameIDs := make([]int64, 0, len(byName))
+	for nameID := range byName {
+		nameIDs = append(nameIDs, nameID)
+	}
+	slices.Sort(nameIDs)
+
+	changes := make([]models.SystemPackageChange, 0, len(nameIDs))
+	for _, nameID := range nameIDs {
+		c := byName[nameID]
+		if len(c.added) == 1 && len(c.removed) == 1 {
+			change := models.PackageChangeUpgraded
+			if evraCmp(c.removed[0], c.added[0]) > 0 {
+				change = models.PackageChangeDowngraded
+			}
+			changes = append(changes, newChange(nameID, change, c.removed[0], c.added[0]))
+			continue
+		}
+		slices.SortFunc(c.removed, evraCmp)
+		slices.SortFunc(c.added, evraCmp)
+		for _, pkg := range c.removed {
+			changes = append(changes, newChange(nameID, models.PackageChangeRemoved, pkg, nil))
+		}
+		for _, pkg := range c.added {
+			changes = append(changes, newChange(nameID, models.PackageChangeInstalled, nil, pkg))
+		}
+	}
+	return changes
+}
+
+func evraCmp(a, b *PackageCacheMetadata) int {
+	nevraA, errA := utils.ParseNameEVRA(a.Name, a.Evra)
+	nevraB, errB := utils.ParseNameEVRA(b.Name, b.Evra)
+	if errA != nil || errB != nil {
+		return strings.Compare(a.Evra, b.Evra)
+	}
+	return nevraA.EVRACmp(nevraB)
+}
+
+func storePackageChanges(tx *gorm.DB, system *models.SystemPlatformV2,
+	packagesByNEVRA map[string]namedPackage) error {
+	if !enablePackageHistory {
+		return nil
+	}
+	changes := packageChanges(system, packagesByNEVRA)
+	if len(changes) == 0 {
+		return nil
+	}
+	err := tx.Omit("id").Create(&changes).Error
+	return errors.Wrap(err, "Storing system package changes")
+}
+
+// Updates violating a package hold are not installable nor applicable, the latest of them is held instead
+func latestPackagesFromUpdatesList(updatePkgData []vmaas.UpdatesV3ResponseAvailableUpdates, nameID int64,
+	holds packageHolds) (*int64, *int64, *int64) {
 	var (
-		latestInstallable, latestApplicable string
-		installableID, applicableID         *int64
+		latestInstallable, latestApplicable, latestHeld string
+		installableID, applicableID, heldID             *int64
 	)
 	for _, upData := range updatePkgData {
 		nevra := upData.GetPackage()
@@ -319,6 +457,10 @@ func latestPackagesFromUpdatesList(updatePkgData []vmaas.UpdatesV3ResponseAvaila
 			// no update
 			continue
 		}
+		if holds.violated(nameID, upData.GetEVRA()) {
+			latestHeld = nevra
+			continue
+		}
 		switch upData.StatusID {
 		case INSTALLABLE:
 			latestInstallable = nevra
@@ -336,7 +478,12 @@ func latestPackagesFromUpdatesList(updatePkgData []vmaas.UpdatesV3ResponseAvaila
 			applicableID = &applicableFromCache.ID
 		}
 	}
-	return installableID, applicableID
+	if len(latestHeld) > 0 {
+		if heldFromCache, ok := memoryPackageCache.GetByNevra(latestHeld); ok {
+			heldID = &heldFromCache.ID
+		}
+	}
+	return installableID, applicableID, heldID
 }
 
 func deleteOldSystemPackages(tx *gorm.DB, system *models.SystemPlatformV2, pkgIDs []int64) error {
@@ -363,5 +510,6 @@ type namedPackage struct {
 	PackageID     int64
 	InstallableID *int64
 	ApplicableID  *int64
+	HeldID        *int64
 	Change        ChangeType
 }
@@ -79,6 +79,9 @@ func TestSystemPackageRemoval(t *testing.T) {
 	assert.Equal(t, 0, installable)
 	assert.Equal(t, 0, applicable)
 	database.CheckSystemPackages(t, system.Inventory.RhAccountID, system.InternalSystemID(), 1)
+	// first evaluation is not journaled
+	assert.Equal(t, 0, len(database.GetSystemPackageChanges(t, system.Inventory.RhAccountID,
+		system.InternalSystemID())))
 
 	// downgrade kernel
 	vmaasData = vmaas.UpdatesV3Response{UpdateList: &map[string]*vmaas.UpdatesV3ResponseUpdateList{
@@ -98,8 +101,14 @@ func TestSystemPackageRemoval(t *testing.T) {
 	assert.Equal(t, 1, applicable)
 	// previous kernel package needs to be deleted, we expect only 1 package in system_package2
 	database.CheckSystemPackages(t, system.Inventory.RhAccountID, system.InternalSystemID(), 1)
+	changes := database.GetSystemPackageChanges(t, system.Inventory.RhAccountID, system.InternalSystemID())
+	assert.Equal(t, 1, len(changes))
+	assert.Equal(t, models.PackageChangeDowngraded, changes[0].Change)
+	assert.Equal(t, "5.6.14-200.fc31.x86_64", *changes[0].OldEvra)
+	assert.Equal(t, "5.6.13-200.fc31.x86_64", *changes[0].NewEvra)
 
 	// cleanup
+	database.DeleteSystemPackageChanges(t, system.Inventory.RhAccountID, system.InternalSystemID())
 	database.DeleteSystemPackages(t, system.Inventory.RhAccountID, system.InternalSystemID())
 	database.DeleteNewlyAddedPackages(t)
 }
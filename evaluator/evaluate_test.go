@@ -101,6 +101,54 @@ func TestEvaluate(t *testing.T) {
 	assert.Equal(t, 2, len(mockWriter.Messages))
 }
 
+func TestAnalyzeReposClassification(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+	configure()
+
+	// system 2 of account 1 has repo1 and repo2 which are not third party
+	system := models.SystemPlatformV2{Inventory: models.SystemInventory{ID: 2, RhAccountID: 1}}
+	thirdParty, err := analyzeRepos(&system)
+	assert.NoError(t, err)
+	assert.False(t, thirdParty)
+
+	database.CreateRepoClassification(t, 1, 2, models.RepoClassificationThirdParty)
+	defer database.DeleteRepoClassification(t, 1, 2)
+	thirdParty, err = analyzeRepos(&system)
+	assert.NoError(t, err)
+	assert.True(t, thirdParty)
+
+	// ignored repo is not third party and it is left out of the vmaas request
+	database.CreateRepoClassification(t, 1, 2, models.RepoClassificationIgnored)
+	thirdParty, err = analyzeRepos(&system)
+	assert.NoError(t, err)
+	assert.False(t, thirdParty)
+	ignoredRepos, err := loadIgnoredRepos(&system)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"repo2"}, ignoredRepos)
+
+	// override of another org is not applied
+	database.CreateRepoClassification(t, 2, 1, models.RepoClassificationThirdParty)
+	defer database.DeleteRepoClassification(t, 2, 1)
+	thirdParty, err = analyzeRepos(&system)
+	assert.NoError(t, err)
+	assert.False(t, thirdParty)
+}
+
+func TestTryGetVmaasRequestIgnoredRepos(t *testing.T) {
+	vmaasJSON := `{"package_list": ["kernel-5.6.13-200.fc31.x86_64"], "repository_list": ["repo1", "repo2"]}`
+	system := models.SystemPlatformV2{Inventory: models.SystemInventory{VmaasJSON: &vmaasJSON}}
+
+	req, err := tryGetVmaasRequest(&system, []string{"repo2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"repo1"}, req.RepositoryList)
+
+	// no repositories are left
+	req, err = tryGetVmaasRequest(&system, []string{"repo1", "repo2"})
+	assert.NoError(t, err)
+	assert.Nil(t, req)
+}
+
 func TestEvaluateYum(t *testing.T) {
 	utils.SkipWithoutDB(t)
 	utils.SkipWithoutPlatform(t)
@@ -290,7 +338,7 @@ func TestSatelliteSystemAdvisories(t *testing.T) {
 
 	// lets add the checksum to the cache, so we do not actually call vmaas
 	vmaasJSONChecksum := "1337"
-	memoryVmaasCache.Add(&vmaasJSONChecksum, &vmaasData)
+	vmaasCache.Add(vmaasCacheKey(&vmaasJSONChecksum, false, vmaasCallUseOptimisticUpdates, nil), &vmaasData)
 
 	// this satellite system has 1 git installable advisory which is the same as the applicable one from vmaas
 	// and 1 sqlite different installable advisory
@@ -435,7 +483,7 @@ func TestBootcSystemAdvisories(t *testing.T) {
 	assert.Nil(t, err)
 
 	vmaasJSONChecksum := "bootc-1337"
-	memoryVmaasCache.Add(&vmaasJSONChecksum, &vmaasData)
+	vmaasCache.Add(vmaasCacheKey(&vmaasJSONChecksum, false, vmaasCallUseOptimisticUpdates, nil), &vmaasData)
 
 	yumUpdatesRaw := []byte(`
 		{
@@ -76,6 +76,13 @@ var (
 		Name:      "vmaas_cache",
 	}, []string{"type"})
 
+	vmaasCacheBackendCnt = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Help:      "How many vmaas checksums hit/miss cache by cache backend",
+		Namespace: "patchman_engine",
+		Subsystem: "evaluator",
+		Name:      "vmaas_cache_backend",
+	}, []string{"backend", "type"})
+
 	vmaasCacheGauge = prometheus.NewGauge(prometheus.GaugeOpts{
 		Help:      "VMaaS cache size",
 		Namespace: "patchman_engine",
@@ -87,7 +94,7 @@ var (
 func RunMetrics() {
 	prometheus.MustRegister(evaluationCnt, updatesCnt, evaluationDuration, evaluationPartDuration,
 		uploadEvaluationDelay, twoEvaluationsInterval, packageCacheCnt, packageCacheGauge,
-		vmaasCacheCnt, vmaasCacheGauge)
+		vmaasCacheCnt, vmaasCacheBackendCnt, vmaasCacheGauge)
 
 	// create web app
 	app := gin.New()
@@ -0,0 +1,52 @@
+package evaluator
+
+import (
+	"app/base/utils"
+	"path"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EVRA patterns of package holds covering the system by package name id
+type packageHolds map[int64][]string
+
+// Load package holds covering the system, updates of held packages not matching any of the patterns are held
+func loadPackageHolds(tx *gorm.DB, accountID int, systemID int64) (packageHolds, error) {
+	defer utils.ObserveSecondsSince(time.Now(), evaluationPartDuration.WithLabelValues("package-holds-load"))
+	var rows []struct {
+		NameID      int64
+		EvraPattern string
+	}
+	err := tx.Table("package_hold_system phs").
+		Joins("JOIN package_hold ph ON ph.rh_account_id = phs.rh_account_id AND ph.id = phs.hold_id").
+		Select("ph.name_id, ph.evra_pattern").
+		Where("phs.rh_account_id = ? AND phs.system_id = ?", accountID, systemID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	holds := make(packageHolds, len(rows))
+	for _, r := range rows {
+		holds[r.NameID] = append(holds[r.NameID], r.EvraPattern)
+	}
+	return holds, nil
+}
+
+// Update violates the holds of the package name when its EVRA does not match any of the hold patterns
+func (h packageHolds) violated(nameID int64, evra string) bool {
+	patterns, ok := h[nameID]
+	if !ok {
+		return false
+	}
+	// package.evra omits zero epoch, patterns are matched in the same form
+	evra = strings.TrimPrefix(evra, "0:")
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, evra); err == nil && matched {
+			return false
+		}
+	}
+	return true
+}
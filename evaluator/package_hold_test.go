@@ -0,0 +1,70 @@
+package evaluator
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/base/vmaas"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageHoldsViolated(t *testing.T) {
+	holds := packageHolds{101: {"5.14.0-427.*"}, 102: {"76.*", "77.0.1-*"}}
+
+	assert.False(t, holds.violated(101, "5.14.0-427.13.1.el9_4.x86_64"))
+	assert.False(t, holds.violated(101, "0:5.14.0-427.13.1.el9_4.x86_64"))
+	assert.True(t, holds.violated(101, "5.14.0-503.11.1.el9_5.x86_64"))
+	assert.True(t, holds.violated(101, "1:5.14.0-427.13.1.el9_4.x86_64"))
+	assert.False(t, holds.violated(102, "77.0.1-1.fc31.x86_64"))
+	assert.True(t, holds.violated(102, "78.0-1.fc31.x86_64"))
+	// package without hold
+	assert.False(t, holds.violated(103, "5.1-1.el8.x86_64"))
+}
+
+func TestLoadPackageHolds(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	id1 := database.CreatePackageHold(t, 1, 101, "5.6.13-*", []int64{1, 2})
+	defer database.DeletePackageHold(t, 1, id1)
+	id2 := database.CreatePackageHold(t, 1, 102, "76.*", []int64{2})
+	defer database.DeletePackageHold(t, 1, id2)
+
+	holds, err := loadPackageHolds(database.DB, 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, packageHolds{101: {"5.6.13-*"}}, holds)
+}
+
+func TestAnalyzePackagesHeld(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+	configure()
+	loadCache()
+
+	system := &models.SystemPlatformV2{
+		Inventory: models.SystemInventory{ID: 11, RhAccountID: 2},
+		Patch:     models.SystemPatch{},
+	}
+	holdID := database.CreatePackageHold(t, 2, 102, "76.*", []int64{11})
+	defer database.DeletePackageHold(t, 2, holdID)
+
+	vmaasData := vmaas.UpdatesV3Response{UpdateList: &map[string]*vmaas.UpdatesV3ResponseUpdateList{
+		"firefox-0:76.0.1-1.fc31.x86_64": {AvailableUpdates: &[]vmaas.UpdatesV3ResponseAvailableUpdates{{
+			Package:     utils.PtrString("firefox-0:77.0.1-1.fc31.x86_64"),
+			PackageName: utils.PtrString("firefox"),
+			EVRA:        utils.PtrString("0:77.0.1-1.fc31.x86_64"),
+		}}}}}
+
+	pkgByName, installed, installable, applicable, err := lazySaveAndLoadPackages(system, &vmaasData)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, installed)
+	assert.Equal(t, 0, installable)
+	assert.Equal(t, 0, applicable)
+	assert.Equal(t, 1, heldPackagesCount(pkgByName))
+	pkg := pkgByName["firefox-0:76.0.1-1.fc31.x86_64"]
+	assert.Nil(t, pkg.InstallableID)
+	assert.NotNil(t, pkg.HeldID)
+}
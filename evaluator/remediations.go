@@ -33,7 +33,10 @@ func createRemediationsStateMsg(id uuid.UUID, response *vmaas.UpdatesV3Response)
 	var state RemediationsState
 	state.HostID = id
 	state.Issues = make([]string, 0, len(advisories)+len(packages))
-	for a := range advisories {
+	for a, status := range advisories {
+		if status == NOTAPPLICABLE {
+			continue
+		}
 		state.Issues = append(state.Issues, fmt.Sprintf("patch:%s", a))
 	}
 	for p := range packages {
@@ -65,6 +68,10 @@ func getReportedPackageUpdates(vmaasData *vmaas.UpdatesV3Response) map[string]bo
 	packages := make(map[string]bool, len(updateList))
 	for _, updates := range updateList {
 		for _, u := range updates.GetAvailableUpdates() {
+			if u.StatusID == NOTAPPLICABLE {
+				// updates beyond the baseline are not remediated
+				continue
+			}
 			packages[u.GetPackage()] = true
 		}
 	}
@@ -43,3 +43,16 @@ func TestCreateRemediationsState(t *testing.T) {
 		"patch:firefox-0:77.0.1-1.fc31.x86_64", "patch:firefox-1:76.0.1-1.fc31.x86_64",
 		"patch:kernel-5.6.13-201.fc31.x86_64"})
 }
+
+func TestCreateRemediationsStateNotApplicable(t *testing.T) {
+	updates := []vmaas.UpdatesV3ResponseAvailableUpdates{
+		{Erratum: utils.PtrString("RH-1"), Package: utils.PtrString("firefox-0:77.0.1-1.fc31.x86_64")},
+		{Erratum: utils.PtrString("RH-2"), Package: utils.PtrString("firefox-1:76.0.1-1.fc31.x86_64"),
+			StatusID: NOTAPPLICABLE},
+	}
+	updateList := map[string]*vmaas.UpdatesV3ResponseUpdateList{
+		"firefox-76.0.1-1.fc31.x86_64": {AvailableUpdates: &updates},
+	}
+	state := createRemediationsStateMsg(testInventoryID, &vmaas.UpdatesV3Response{UpdateList: &updateList})
+	assert.Equal(t, []string{"patch:RH-1", "patch:firefox-0:77.0.1-1.fc31.x86_64"}, state.Issues)
+}
@@ -2,11 +2,14 @@ package evaluator
 
 import "app/base/database"
 
-var STATUS = make(map[int]string, 2)
+var STATUS = make(map[int]string, 3)
 
 const INSTALLABLE = 0
 const APPLICABLE = 1
 
+// update beyond the baseline assigned to the system, stored but not counted
+const NOTAPPLICABLE = 2
+
 type statusRow struct {
 	ID   int
 	Name string
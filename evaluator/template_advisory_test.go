@@ -143,7 +143,7 @@ func TestGetUpdatesDataTemplateAdvisoryEval(t *testing.T) {
 		enableTemplateAdvisoryEval = ogTemplateEval
 	}()
 
-	memoryVmaasCache.Add(&vmaasJSONChecksum, &vmaasData)
+	vmaasCache.Add(vmaasCacheKey(&vmaasJSONChecksum, false, vmaasCallUseOptimisticUpdates, nil), &vmaasData)
 	database.CreateTemplateAdvisories(t, 1, templateID, []int64{1})
 	defer database.DeleteTemplateAdvisories(t, templateID, []int64{1})
 
@@ -1,54 +1,93 @@
 package evaluator
 
 import (
+	"app/base/database"
+	"app/base/models"
 	"app/base/types"
 	"app/base/utils"
 	"app/base/vmaas"
 	"app/tasks/vmaas_sync"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/bytedance/sonic"
 	lru "github.com/hashicorp/golang-lru/v2"
+	"gorm.io/gorm/clause"
 )
 
-var memoryVmaasCache *VmaasCache
+const (
+	VmaasCacheBackendMemory     = "memory"
+	VmaasCacheBackendPostgreSQL = "postgresql"
+)
+
+var vmaasCache *VmaasCache
+
+// vmaasCacheBackend is a single tier of the vmaas response cache,
+// `validity` is VmaasExported timestamp the cached responses were computed for
+type vmaasCacheBackend interface {
+	Name() string
+	Get(checksum string, validity *types.Rfc3339TimestampWithZ) (*vmaas.UpdatesV3Response, bool)
+	Add(checksum string, validity *types.Rfc3339TimestampWithZ, response *vmaas.UpdatesV3Response)
+	Purge(validity *types.Rfc3339TimestampWithZ)
+}
 
 type VmaasCache struct {
 	enabled       bool
-	size          int
-	currentSize   int
 	validity      *types.Rfc3339TimestampWithZ
 	checkDuration time.Duration
-	data          *lru.TwoQueueCache[string, *vmaas.UpdatesV3Response]
+	// backends ordered from the fastest one, hit in a slower tier is propagated to the faster ones
+	backends []vmaasCacheBackend
 }
 
-func NewVmaasPackageCache(enabled bool, size int, checkDuration time.Duration) *VmaasCache {
+// vmaasCacheKey identifies vmaas response by package profile checksum and request flags and repos changing
+// the response
+func vmaasCacheKey(checksum *string, thirdParty, optimisticUpdates bool, ignoredRepos []string) *string {
+	if checksum == nil {
+		return nil
+	}
+	key := fmt.Sprintf("%s|third_party=%t|optimistic=%t", *checksum, thirdParty, optimisticUpdates)
+	if len(ignoredRepos) > 0 {
+		key += "|ignored=" + strings.Join(ignoredRepos, ",")
+	}
+	return &key
+}
+
+func NewVmaasPackageCache(enabled bool, size int, checkDuration time.Duration, sharedBackend string) *VmaasCache {
 	c := new(VmaasCache)
 
 	c.enabled = enabled
-	c.size = size
-	c.currentSize = 0
 	c.validity = vmaas_sync.GetLastSync(vmaas_sync.VmaasExported)
 	c.checkDuration = checkDuration
 	vmaasCacheGauge.Set(0)
 
 	if c.enabled {
-		var err error
-		c.data, err = lru.New2Q[string, *vmaas.UpdatesV3Response](c.size)
-		if err != nil {
-			panic(err)
+		c.backends = append(c.backends, newMemoryVmaasCache(size))
+		switch sharedBackend {
+		case "":
+		case VmaasCacheBackendPostgreSQL:
+			c.backends = append(c.backends, &postgresqlVmaasCache{})
+		default:
+			utils.LogError("backend", sharedBackend, "Unknown shared vmaas cache backend, using memory only")
 		}
-		return c
 	}
 	return c
 }
 
 func (c *VmaasCache) Get(checksum *string) (*vmaas.UpdatesV3Response, bool) {
 	if c.enabled && checksum != nil {
-		val, ok := c.data.Get(*checksum)
-		if ok {
-			vmaasCacheCnt.WithLabelValues("hit").Inc()
-			utils.LogTrace("checksum", *checksum, "VmaasCache.Get cache hit")
-			return val, true
+		for i, backend := range c.backends {
+			val, ok := backend.Get(*checksum, c.validity)
+			if ok {
+				vmaasCacheCnt.WithLabelValues("hit").Inc()
+				vmaasCacheBackendCnt.WithLabelValues(backend.Name(), "hit").Inc()
+				utils.LogTrace("checksum", *checksum, "backend", backend.Name(), "VmaasCache.Get cache hit")
+				for _, upper := range c.backends[:i] {
+					upper.Add(*checksum, c.validity, val)
+				}
+				return val, true
+			}
+			vmaasCacheBackendCnt.WithLabelValues(backend.Name(), "miss").Inc()
 		}
 	}
 	vmaasCacheCnt.WithLabelValues("miss").Inc()
@@ -57,19 +96,18 @@ func (c *VmaasCache) Get(checksum *string) (*vmaas.UpdatesV3Response, bool) {
 
 func (c *VmaasCache) Add(checksum *string, response *vmaas.UpdatesV3Response) {
 	if c.enabled && checksum != nil {
-		c.data.Add(*checksum, response)
-		if c.currentSize <= c.size {
-			c.currentSize++
-			vmaasCacheGauge.Inc()
+		for _, backend := range c.backends {
+			backend.Add(*checksum, c.validity, response)
 		}
 	}
 }
 
 func (c *VmaasCache) Reset(ts *types.Rfc3339TimestampWithZ) {
 	if c.enabled {
-		c.data.Purge()
+		for _, backend := range c.backends {
+			backend.Purge(ts)
+		}
 		c.validity = ts
-		vmaasCacheGauge.Set(0)
 	}
 }
 
@@ -81,3 +119,102 @@ func (c *VmaasCache) CheckValidity() {
 		}
 	}
 }
+
+// memoryVmaasCache is a per-pod LRU cache
+type memoryVmaasCache struct {
+	size        int
+	currentSize int
+	data        *lru.TwoQueueCache[string, *vmaas.UpdatesV3Response]
+}
+
+func newMemoryVmaasCache(size int) *memoryVmaasCache {
+	data, err := lru.New2Q[string, *vmaas.UpdatesV3Response](size)
+	if err != nil {
+		panic(err)
+	}
+	return &memoryVmaasCache{size: size, data: data}
+}
+
+func (m *memoryVmaasCache) Name() string {
+	return VmaasCacheBackendMemory
+}
+
+func (m *memoryVmaasCache) Get(checksum string, _ *types.Rfc3339TimestampWithZ) (*vmaas.UpdatesV3Response, bool) {
+	return m.data.Get(checksum)
+}
+
+func (m *memoryVmaasCache) Add(checksum string, _ *types.Rfc3339TimestampWithZ, response *vmaas.UpdatesV3Response) {
+	m.data.Add(checksum, response)
+	if m.currentSize <= m.size {
+		m.currentSize++
+		vmaasCacheGauge.Inc()
+	}
+}
+
+func (m *memoryVmaasCache) Purge(_ *types.Rfc3339TimestampWithZ) {
+	m.data.Purge()
+	m.currentSize = 0
+	vmaasCacheGauge.Set(0)
+}
+
+// postgresqlVmaasCache is shared by all evaluator replicas, it is stored in an unlogged table
+// and entries are bound to the VmaasExported timestamp, so stale rows are never returned
+type postgresqlVmaasCache struct{}
+
+func (p *postgresqlVmaasCache) Name() string {
+	return VmaasCacheBackendPostgreSQL
+}
+
+func (p *postgresqlVmaasCache) Get(checksum string, validity *types.Rfc3339TimestampWithZ,
+) (*vmaas.UpdatesV3Response, bool) {
+	if validity == nil {
+		return nil, false
+	}
+	var row models.VmaasCache
+	err := database.DB.Select("response").
+		Where("checksum = ? AND exported = ?", checksum, validity.Time()).
+		Limit(1).Find(&row).Error
+	if err != nil {
+		utils.LogWarn("err", err, "checksum", checksum, "Unable to read shared vmaas cache")
+		return nil, false
+	}
+	if row.Response == nil {
+		return nil, false
+	}
+	var response vmaas.UpdatesV3Response
+	if err = sonic.Unmarshal(row.Response, &response); err != nil {
+		utils.LogWarn("err", err, "checksum", checksum, "Unable to parse shared vmaas cache entry")
+		return nil, false
+	}
+	return &response, true
+}
+
+func (p *postgresqlVmaasCache) Add(checksum string, validity *types.Rfc3339TimestampWithZ,
+	response *vmaas.UpdatesV3Response) {
+	if validity == nil {
+		return
+	}
+	data, err := sonic.Marshal(response)
+	if err != nil {
+		utils.LogWarn("err", err, "checksum", checksum, "Unable to serialize vmaas response")
+		return
+	}
+	row := models.VmaasCache{Checksum: checksum, Exported: *validity.Time(), Response: data, Created: time.Now()}
+	err = database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "checksum"}},
+		DoUpdates: clause.AssignmentColumns([]string{"exported", "response", "created"}),
+	}).Create(&row).Error
+	if err != nil {
+		utils.LogWarn("err", err, "checksum", checksum, "Unable to store shared vmaas cache entry")
+	}
+}
+
+func (p *postgresqlVmaasCache) Purge(validity *types.Rfc3339TimestampWithZ) {
+	tx := database.DB.Where("true")
+	if validity != nil {
+		tx = database.DB.Where("exported < ?", validity.Time())
+	}
+	if err := tx.Delete(&models.VmaasCache{}).Error; err != nil {
+		utils.LogWarn("err", err, "Unable to purge shared vmaas cache")
+	}
+}
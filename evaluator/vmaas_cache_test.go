@@ -0,0 +1,87 @@
+package evaluator
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"app/base/types"
+	"app/base/utils"
+	"app/base/vmaas"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testVmaasCacheResponse() *vmaas.UpdatesV3Response {
+	return &vmaas.UpdatesV3Response{
+		RepositoryList: &[]string{"rhel-8-for-x86_64-baseos-rpms"},
+		Basearch:       utils.PtrString("x86_64"),
+	}
+}
+
+func TestVmaasCacheMemory(t *testing.T) {
+	c := &VmaasCache{enabled: true, backends: []vmaasCacheBackend{newMemoryVmaasCache(10)}}
+	checksum := "memory-checksum"
+
+	_, ok := c.Get(&checksum)
+	assert.False(t, ok)
+	c.Add(&checksum, testVmaasCacheResponse())
+	val, ok := c.Get(&checksum)
+	assert.True(t, ok)
+	assert.Equal(t, "x86_64", *val.Basearch)
+
+	c.Reset(nil)
+	_, ok = c.Get(&checksum)
+	assert.False(t, ok)
+}
+
+func TestVmaasCacheKeyFlags(t *testing.T) {
+	c := &VmaasCache{enabled: true, backends: []vmaasCacheBackend{newMemoryVmaasCache(10)}}
+	checksum := "flags-checksum"
+
+	c.Add(vmaasCacheKey(&checksum, false, false, nil), testVmaasCacheResponse())
+	_, ok := c.Get(vmaasCacheKey(&checksum, false, false, nil))
+	assert.True(t, ok)
+	// response for third party repos or optimistic updates must not be served from cache of other flags
+	_, ok = c.Get(vmaasCacheKey(&checksum, true, true, nil))
+	assert.False(t, ok)
+	_, ok = c.Get(vmaasCacheKey(&checksum, false, true, nil))
+	assert.False(t, ok)
+	// ignored repos are left out of the request
+	_, ok = c.Get(vmaasCacheKey(&checksum, false, false, []string{"repo2"}))
+	assert.False(t, ok)
+	assert.Nil(t, vmaasCacheKey(nil, false, false, nil))
+}
+
+func TestVmaasCacheSharedBackfill(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	exported := types.Rfc3339TimestampWithZ(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	checksum := "shared-checksum"
+	defer database.DB.Where("checksum = ?", checksum).Delete(&models.VmaasCache{})
+
+	// another replica stores the response into the shared tier
+	other := &VmaasCache{enabled: true, validity: &exported,
+		backends: []vmaasCacheBackend{newMemoryVmaasCache(10), &postgresqlVmaasCache{}}}
+	other.Add(&checksum, testVmaasCacheResponse())
+
+	memory := newMemoryVmaasCache(10)
+	c := &VmaasCache{enabled: true, validity: &exported, backends: []vmaasCacheBackend{memory, &postgresqlVmaasCache{}}}
+	val, ok := c.Get(&checksum)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"rhel-8-for-x86_64-baseos-rpms"}, *val.RepositoryList)
+	// hit in the shared tier is propagated to memory
+	_, ok = memory.Get(checksum, nil)
+	assert.True(t, ok)
+
+	// entries computed for older vmaas data are not returned and are purged on reset
+	newer := types.Rfc3339TimestampWithZ(time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC))
+	_, ok = (&postgresqlVmaasCache{}).Get(checksum, &newer)
+	assert.False(t, ok)
+	c.Reset(&newer)
+	var cnt int64
+	assert.NoError(t, database.DB.Model(&models.VmaasCache{}).Where("checksum = ?", checksum).Count(&cnt).Error)
+	assert.Equal(t, int64(0), cnt)
+}
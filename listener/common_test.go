@@ -45,6 +45,8 @@ func deleteData(t *testing.T) {
 			"(SELECT rh_account_id, id FROM system_inventory WHERE inventory_id = ?)", testInventoryID).Error)
 	assert.Nil(t, database.DB.Unscoped().
 		Where("inventory_id = ?", testInventoryID).Delete(&models.SystemInventory{}).Error)
+	assert.Nil(t, database.DB.Unscoped().
+		Where("inventory_id = ?", testInventoryID).Delete(&models.RejectedUpload{}).Error)
 	assert.Nil(t, database.DB.Unscoped().Where("name = ?", testOrgID).Delete(&models.RhAccount{}).Error)
 	assert.Nil(t, database.DB.Unscoped().Where("inventory_id = ?", testInventoryID).Delete(&models.DeletedSystem{}).Error)
 }
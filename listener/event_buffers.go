@@ -3,11 +3,14 @@ package listener
 import (
 	"app/base"
 	"app/base/mqueue"
+	"app/base/tracing"
 	"app/base/utils"
+	"context"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type eventBuffer struct {
@@ -17,6 +20,8 @@ type eventBuffer struct {
 	flushTimer *time.Timer
 	evalWriter *mqueue.Writer
 	ptWriter   *mqueue.Writer
+	// traces of buffered uploads, linked from the flush span
+	links []trace.Link
 }
 
 func (b *eventBuffer) initEventBuffer(evalWriter, ptWriter *mqueue.Writer) {
@@ -33,6 +38,7 @@ func (b *eventBuffer) initEventBuffer(evalWriter, ptWriter *mqueue.Writer) {
 
 // send events after full buffer or timeout
 func (b *eventBuffer) bufferEvalEvents(
+	ctx context.Context,
 	inventoryID uuid.UUID,
 	rhAccountID int,
 	ptEvent *mqueue.PayloadTrackerEvent,
@@ -48,6 +54,9 @@ func (b *eventBuffer) bufferEvalEvents(
 	}
 	b.evalBuffer = append(b.evalBuffer, evalData)
 	b.ptBuffer = append(b.ptBuffer, *ptEvent)
+	if link, ok := tracing.Link(ctx); ok {
+		b.links = append(b.links, link)
+	}
 
 	b.flushTimer.Reset(uploadEvalTimeout)
 	shouldFlush := len(b.evalBuffer) >= eventBufferSize
@@ -63,12 +72,14 @@ func (b *eventBuffer) flushEvalEvents() {
 	tStart := time.Now()
 	b.lock.Lock()
 	defer b.lock.Unlock()
-	err := mqueue.SendMessages(base.Context, *b.evalWriter, b.evalBuffer)
+	ctx, span := tracing.Start(base.Context, "listener flush eval events", trace.WithLinks(b.links...))
+	defer span.End()
+	err := mqueue.SendMessages(ctx, *b.evalWriter, b.evalBuffer)
 	if err != nil {
 		utils.LogError("err", err, ErrorKafkaSend)
 	}
 	utils.ObserveSecondsSince(tStart, messagePartDuration.WithLabelValues("buffer-sent-evaluator"))
-	err = mqueue.SendMessages(base.Context, *b.ptWriter, b.ptBuffer)
+	err = mqueue.SendMessages(ctx, *b.ptWriter, b.ptBuffer)
 	if err != nil {
 		utils.LogWarn("err", err, WarnPayloadTracker)
 	}
@@ -78,4 +89,5 @@ func (b *eventBuffer) flushEvalEvents() {
 	// empty buffer
 	b.evalBuffer = b.evalBuffer[:0]
 	b.ptBuffer = b.ptBuffer[:0]
+	b.links = b.links[:0]
 }
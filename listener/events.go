@@ -55,7 +55,7 @@ func EventsMessageHandler(m mqueue.KafkaMessage) error {
 				"Invalid 'updated' message format")
 			return nil
 		}
-		return HandleUpload(event)
+		return HandleUpload(m.Context(), event)
 	default:
 		utils.LogWarn("msg", string(m.Value), WarnUnknownType)
 		return nil
@@ -28,7 +28,7 @@ func TestUpdateSystem(t *testing.T) {
 	name := "TEST_NAME"
 	ev.Host.DisplayName = &name
 	ev.Host.SystemProfile.InstalledPackages = &[]string{"kernel-0:4.18.0-193.1.2.el8_2.x86_64"}
-	assert.NoError(t, HandleUpload(ev))
+	assert.NoError(t, HandleUpload(t.Context(), ev))
 
 	var system models.SystemInventory
 	assert.NoError(t, database.DB.Order("ID DESC").Find(&system, "inventory_id = ?", testInventoryID).Error)
@@ -106,7 +106,7 @@ func TestUploadAfterDelete(t *testing.T) {
 
 	// upload will be skipped and system won't be created
 	uploadEvent := createTestUploadEvent("1", testInventoryID, "puptoo", true, false, "created")
-	err = HandleUpload(uploadEvent)
+	err = HandleUpload(t.Context(), uploadEvent)
 	assert.NoError(t, err)
 	assertSystemNotInDB(t)
 
@@ -122,7 +122,7 @@ func TestCreateDeleteUpload(t *testing.T) {
 	uploadEvent := createTestUploadEvent("1", testInventoryID, "puptoo", true, false, "created")
 	originalName := "UPLOADED"
 	uploadEvent.Host.DisplayName = &originalName
-	err := HandleUpload(uploadEvent)
+	err := HandleUpload(t.Context(), uploadEvent)
 	assert.NoError(t, err)
 
 	// delete marks the system but not physically delete it
@@ -134,7 +134,7 @@ func TestCreateDeleteUpload(t *testing.T) {
 	// second upload of now deleted system should not change anything
 	changedName := "UPDATED"
 	uploadEvent.Host.DisplayName = &changedName
-	err = HandleUpload(uploadEvent)
+	err = HandleUpload(t.Context(), uploadEvent)
 	assert.NoError(t, err)
 
 	var system models.SystemInventory
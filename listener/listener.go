@@ -19,6 +19,7 @@ import (
 
 var (
 	eventsTopic                string
+	replayTopic                string
 	eventsConsumers            int
 	enableTemplates            bool
 	templatesTopic             string
@@ -54,6 +55,8 @@ const (
 func configure() {
 	core.ConfigureApp()
 	eventsTopic = utils.FailIfEmpty(utils.CoreCfg.EventsTopic, "EVENTS_TOPIC")
+	// Rejected uploads replayed from admin API, optional
+	replayTopic = utils.CoreCfg.ReplayTopic
 	evalTopic := utils.FailIfEmpty(utils.CoreCfg.EvalTopic, "EVAL_TOPIC")
 	createdTopic := utils.FailIfEmpty(utils.CoreCfg.CreatedSystemsTopic, "CREATED_SYSTEMS_TOPIC")
 	ptTopic := utils.FailIfEmpty(utils.CoreCfg.PayloadTrackerTopic, "PAYLOAD_TRACKER_TOPIC")
@@ -124,6 +127,10 @@ func runReaders(wg *sync.WaitGroup, readerBuilder mqueue.CreateReader) {
 		mqueue.SpawnReader(base.Context, wg, eventsTopic, readerBuilder, mqueue.MakeRetryingHandler(EventsMessageHandler))
 		utils.LogDebug("spawned eventsTopic reader", i)
 	}
+	if replayTopic != "" {
+		mqueue.SpawnReader(base.Context, wg, replayTopic, readerBuilder, mqueue.MakeRetryingHandler(EventsMessageHandler))
+		utils.LogDebug("spawned replayTopic reader")
+	}
 	if enableTemplates {
 		for i := 0; i < templatesConsumers; i++ {
 			mqueue.SpawnReader(base.Context, wg, templatesTopic, readerBuilder, mqueue.MakeRetryingHandler(TemplatesMessageHandler)) //nolint:lll
@@ -0,0 +1,112 @@
+package listener
+
+import (
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// number of offending items stored in the sample of a rejected upload
+const rejectedSampleSize = 10
+
+const (
+	RejectedNoPackages  = "no-packages"
+	RejectedBadPackages = "malformed-packages"
+)
+
+// only rejections the customer can act on are stored, excluded reporters and host types are skipped on purpose
+var rejectedReasonByErr = map[error]string{
+	ErrNoPackages:  RejectedNoPackages,
+	ErrBadPackages: RejectedBadPackages,
+}
+
+func rejectedReason(err error) (string, bool) {
+	for e, reason := range rejectedReasonByErr {
+		if errors.Is(err, e) {
+			return reason, true
+		}
+	}
+	return "", false
+}
+
+// sample of the data which caused the upload rejection
+func rejectedSample(reason string, host *Host, err error) map[string]interface{} {
+	if reason != RejectedBadPackages {
+		return nil
+	}
+	packages := host.SystemProfile.GetInstalledPackages()
+	if len(packages) > rejectedSampleSize {
+		packages = packages[:rejectedSampleSize]
+	}
+	return map[string]interface{}{"installed_packages": packages, "error": err.Error()}
+}
+
+// Store upload rejected by validation so customers can see why the host is missing in patch
+func storeRejectedUpload(event *HostEvent, err error) error {
+	reason, ok := rejectedReason(err)
+	if !ok || event.Host.OrgID == nil || *event.Host.OrgID == "" {
+		return nil
+	}
+
+	accountID, err2 := middlewares.GetOrCreateAccount(*event.Host.OrgID)
+	if err2 != nil {
+		return errors.Wrap(err2, "saving account into the database")
+	}
+
+	eventJSON, err2 := sonic.Marshal(event)
+	if err2 != nil {
+		return errors.Wrap(err2, "serializing rejected event")
+	}
+	var sampleJSON []byte
+	if sample := rejectedSample(reason, &event.Host, err); sample != nil {
+		if sampleJSON, err2 = sonic.Marshal(sample); err2 != nil {
+			return errors.Wrap(err2, "serializing rejected sample")
+		}
+	}
+
+	var workspaceID *uuid.UUID
+	if len(event.Host.Groups) > 0 {
+		if id, err2 := uuid.Parse(event.Host.Groups[0].ID); err2 == nil {
+			workspaceID = &id
+		}
+	}
+
+	now := time.Now()
+	rejected := models.RejectedUpload{
+		RhAccountID:   accountID,
+		InventoryID:   event.Host.ID,
+		DisplayName:   event.Host.DisplayName,
+		Reporter:      event.Host.Reporter,
+		HostType:      utils.EmptyToNil(&event.Host.SystemProfile.HostType),
+		WorkspaceID:   workspaceID,
+		Reason:        reason,
+		Sample:        sampleJSON,
+		Event:         eventJSON,
+		FirstRejected: now,
+		LastRejected:  now,
+		RejectedCount: 1,
+	}
+	updateCols := clause.AssignmentColumns([]string{"display_name", "reporter", "host_type", "workspace_id",
+		"reason", "sample", "event", "last_rejected"})
+	updateCols = append(updateCols, clause.Assignment{
+		Column: clause.Column{Name: "rejected_count"},
+		Value:  gorm.Expr("rejected_upload.rejected_count + 1"),
+	})
+	return database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "rh_account_id"}, {Name: "inventory_id"}},
+		DoUpdates: updateCols,
+	}).Create(&rejected).Error
+}
+
+// Host was uploaded successfully, it is not rejected anymore
+func deleteRejectedUpload(tx *gorm.DB, accountID int, inventoryID uuid.UUID) error {
+	return tx.Delete(&models.RejectedUpload{}, "rh_account_id = ? AND inventory_id = ?", accountID, inventoryID).Error
+}
@@ -0,0 +1,58 @@
+package listener
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejectedSample(t *testing.T) {
+	event := createTestUploadEvent(testOrgID, testInventoryID, "yupana", true, false, "created")
+	assert.Nil(t, rejectedSample(RejectedNoPackages, &event.Host, ErrNoPackages))
+
+	reason, ok := rejectedReason(checkPackagesEpoch([]string{"kernel"}))
+	assert.False(t, ok)
+	assert.Equal(t, "", reason)
+
+	// excluded reporters and host types are not actionable
+	for _, err := range []error{ErrReporter, ErrHostType} {
+		_, ok = rejectedReason(err)
+		assert.False(t, ok)
+	}
+}
+
+func TestStoreRejectedUpload(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+	deleteData(t)
+	defer deleteData(t)
+
+	event := createTestUploadEvent(testOrgID, testInventoryID, "puptoo", true, false, "created")
+	// excluded host type is not stored
+	event.Host.SystemProfile.HostType = "edge"
+	assert.NoError(t, storeRejectedUpload(&event, ErrHostType))
+	var cnt int64
+	assert.NoError(t, database.DB.Model(&models.RejectedUpload{}).
+		Where("inventory_id = ?", testInventoryID).Count(&cnt).Error)
+	assert.Equal(t, int64(0), cnt)
+
+	assert.NoError(t, storeRejectedUpload(&event, ErrNoPackages))
+	assert.NoError(t, storeRejectedUpload(&event, ErrNoPackages))
+
+	var rejected models.RejectedUpload
+	assert.NoError(t, database.DB.Find(&rejected, "inventory_id = ?", testInventoryID).Error)
+	assert.Equal(t, RejectedNoPackages, rejected.Reason)
+	assert.Equal(t, "edge", *rejected.HostType)
+	assert.Equal(t, 2, rejected.RejectedCount)
+	assert.Nil(t, rejected.Sample)
+
+	// successful upload removes the rejected upload
+	assert.NoError(t, deleteRejectedUpload(database.DB, rejected.RhAccountID, testInventoryID))
+	assert.NoError(t, database.DB.Model(&models.RejectedUpload{}).
+		Where("inventory_id = ?", testInventoryID).Count(&cnt).Error)
+	assert.Equal(t, int64(0), cnt)
+}
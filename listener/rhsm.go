@@ -60,7 +60,7 @@ func httpCallCandlepinEnv(ctx context.Context, consumer string) (interface{}, *h
 
 func callCandlepinEnvironment(ctx context.Context, consumer string) (
 	*candlepin.ConsumersDetailResponse, error) {
-	candlepinRespPtr, err := utils.HTTPCallRetry(
+	candlepinRespPtr, err := candlepinClient.CallRetry(
 		func() (interface{}, *http.Response, error) {
 			return httpCallCandlepinEnv(ctx, consumer)
 		},
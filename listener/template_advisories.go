@@ -205,7 +205,7 @@ func httpCallCSTemplateAdvisories(ctx context.Context, templateUUID string) (
 // Returns an unpaginated list of advisory IDs (e.g. RHSA-1234:0001)
 func callCSTemplateAdvisories(ctx context.Context,
 	templateUUID string) (*content_sources.TemplateAdvisoryIDsResponse, error) {
-	contentSourcesRespPtr, err := utils.HTTPCallRetry(
+	contentSourcesRespPtr, err := contentSourcesClient.CallRetry(
 		func() (interface{}, *http.Response, error) {
 			return httpCallCSTemplateAdvisories(ctx, templateUUID)
 		},
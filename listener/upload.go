@@ -8,10 +8,12 @@ import (
 	"app/base/inventory"
 	"app/base/models"
 	"app/base/mqueue"
+	"app/base/tracing"
 	"app/base/types"
 	"app/base/utils"
 	"app/base/vmaas"
 	"app/manager/middlewares"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -30,6 +32,8 @@ import (
 	"github.com/bytedance/sonic"
 	"github.com/bytedance/sonic/encoder"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -137,9 +141,14 @@ func (y *YumUpdates) GetBuiltPkgcache() bool {
 	return y.BuiltPkgcache
 }
 
-func HandleUpload(event HostEvent) error {
+func HandleUpload(ctx context.Context, event HostEvent) (err error) {
 	tStart := time.Now()
 	defer utils.ObserveSecondsSince(tStart, messageHandlingDuration.WithLabelValues(EventUpload))
+	ctx, span := tracing.Start(ctx, "listener upload", trace.WithAttributes(
+		attribute.String("inventory_id", event.Host.ID.String()), attribute.String("org_id", event.Host.GetOrgID()),
+		attribute.String("request_id", event.Metadata.RequestID)))
+	defer func() { tracing.End(span, err) }()
+	ctx = api.ContextWithRequestID(ctx, event.Metadata.RequestID)
 
 	httpClient = &api.Client{
 		HTTPClient: &http.Client{},
@@ -155,17 +164,20 @@ func HandleUpload(event HostEvent) error {
 	}
 
 	if err := validateHost(&event.Host); err != nil {
+		if storeErr := storeRejectedUpload(&event, err); storeErr != nil {
+			utils.LogWarn("inventoryID", event.Host.ID, "err", storeErr, "Could not store rejected upload")
+		}
 		return handleListenerErrors(err, &event, &ptEvent, tStart, ReceivedStatus)
 	}
 
 	sendPayloadStatus(ptWriter, ptEvent, "", "Received by listener")
-	yumUpdates, err := getYumUpdates(event, httpClient)
+	yumUpdates, err := getYumUpdates(ctx, event, httpClient)
 	if err != nil {
 		// don't fail, use vmaas evaluation
 		utils.LogError("err", err, "Could not get yum updates")
 	}
 
-	sys, err := processUpload(&event.Host, yumUpdates)
+	sys, err := processUpload(ctx, &event.Host, yumUpdates)
 	if err != nil {
 		return handleListenerErrors(stdErrors.Join(ErrProcessUpload, err), &event, &ptEvent, tStart, ErrorStatus)
 	}
@@ -184,9 +196,9 @@ func HandleUpload(event HostEvent) error {
 
 	ptEvent.StatusMsg = ProcessingStatus
 	if event.Type == "created" {
-		createdEventsBuffer.bufferEvalEvents(sys.GetInventoryID(), sys.Inventory.RhAccountID, &ptEvent)
+		createdEventsBuffer.bufferEvalEvents(ctx, sys.GetInventoryID(), sys.Inventory.RhAccountID, &ptEvent)
 	} else {
-		updatedEventsBuffer.bufferEvalEvents(sys.GetInventoryID(), sys.Inventory.RhAccountID, &ptEvent)
+		updatedEventsBuffer.bufferEvalEvents(ctx, sys.GetInventoryID(), sys.Inventory.RhAccountID, &ptEvent)
 	}
 	logAndObserve(UploadSuccess, ReceivedSuccess, &event, &ptEvent, tStart, SuccessStatus, false)
 	return nil
@@ -287,7 +299,7 @@ func hostTemplate(tx *gorm.DB, accountID int, host *Host) *int64 {
 		}
 
 		// check system's env in candlepin
-		resp, err := callCandlepinEnvironment(base.Context, host.SystemProfile.OwnerID.String())
+		resp, err := callCandlepinEnvironment(tx.Statement.Context, host.SystemProfile.OwnerID.String())
 		if err != nil {
 			utils.LogWarn("inventoryID", host.ID, "err", errors.Wrap(err, "Unable to assign templates"))
 		}
@@ -774,7 +786,7 @@ func processModules(systemProfile *inventory.SystemProfile) *[]vmaas.UpdatesV3Re
 }
 
 // We have received new upload, update stored host data, and re-evaluate the host against VMaaS
-func processUpload(host *Host, yumUpdates *YumUpdates) (*models.SystemPlatformV2, error) {
+func processUpload(ctx context.Context, host *Host, yumUpdates *YumUpdates) (*models.SystemPlatformV2, error) {
 	tStart := time.Now()
 	defer utils.ObserveSecondsSince(tStart, messagePartDuration.WithLabelValues("upload-processing"))
 	// Ensure we have account stored
@@ -805,7 +817,7 @@ func processUpload(host *Host, yumUpdates *YumUpdates) (*models.SystemPlatformV2
 		updatesReq.SetReleasever(releasever)
 	}
 
-	tx := database.DB.WithContext(base.Context).Begin()
+	tx := database.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	var deleted models.DeletedSystem
@@ -823,6 +835,9 @@ func processUpload(host *Host, yumUpdates *YumUpdates) (*models.SystemPlatformV2
 	if err != nil {
 		return nil, errors.Wrap(err, "saving system into the database")
 	}
+	if err = deleteRejectedUpload(tx, accountID, host.ID); err != nil {
+		return nil, errors.Wrap(err, "removing rejected upload")
+	}
 	err = tx.Commit().Error
 	if err != nil {
 		return nil, base.WrapFatalDBError(err, "committing changes")
@@ -830,14 +845,14 @@ func processUpload(host *Host, yumUpdates *YumUpdates) (*models.SystemPlatformV2
 	return sys, nil
 }
 
-func getYumUpdates(event HostEvent, client *api.Client) (*YumUpdates, error) {
+func getYumUpdates(ctx context.Context, event HostEvent, client *api.Client) (*YumUpdates, error) {
 	var parsed vmaas.UpdatesV3Response
 	res := &YumUpdates{}
 	yumUpdates := event.PlatformMetadata.CustomMetadata.YumUpdates
 	yumUpdatesURL := event.PlatformMetadata.CustomMetadata.YumUpdatesS3URL
 
 	if yumUpdatesURL != nil && *yumUpdatesURL != "" {
-		resp, err := client.Request(&base.Context, http.MethodGet, *yumUpdatesURL, nil, &parsed)
+		resp, err := client.Request(&ctx, http.MethodGet, *yumUpdatesURL, nil, &parsed)
 		if resp != nil && resp.Body != nil {
 			defer func() {
 				if closeErr := resp.Body.Close(); closeErr != nil {
@@ -221,7 +221,7 @@ func TestUploadHandlerCreatedSystem(t *testing.T) {
 			repos := append(event.Host.SystemProfile.GetYumRepos(), inventory.YumRepo{ID: "epel", Enabled: true})
 			event.Host.SystemProfile.YumRepos = &repos
 
-			err := HandleUpload(event)
+			err := HandleUpload(t.Context(), event)
 			assert.NoError(t, err)
 
 			reporterID := 1
@@ -238,7 +238,7 @@ func TestUploadHandlerCreatedSystem(t *testing.T) {
 			// Test that second upload did not cause re-evaluation
 			logHook := utils.NewTestLogHook()
 			log.AddHook(logHook)
-			err = HandleUpload(event)
+			err = HandleUpload(t.Context(), event)
 			assert.NoError(t, err)
 			assertInLogs(t, UploadSuccessNoEval, logHook.LogEntries...)
 			assertSystemReposInDB(t, inv.ID, []string{"epel-8"})
@@ -252,12 +252,13 @@ func TestUploadHandlerWarn(t *testing.T) {
 	configure()
 	logHook := utils.NewTestLogHook()
 	log.AddHook(logHook)
-	noPkgsEvent := createTestUploadEvent("1", testInventoryID, "puptoo", false, false, "created")
-	err := HandleUpload(noPkgsEvent)
+	noPkgsEvent := createTestUploadEvent(testOrgID, testInventoryID, "puptoo", false, false, "created")
+	err := HandleUpload(t.Context(), noPkgsEvent)
 	if assert.Error(t, err) {
 		assert.ErrorIs(t, err, ErrNoPackages)
 	}
 	assertInLogs(t, ErrNoPackages.Error(), logHook.LogEntries...)
+	deleteData(t)
 }
 
 func TestUploadHandlerWarnSkipReporter(t *testing.T) {
@@ -265,12 +266,13 @@ func TestUploadHandlerWarnSkipReporter(t *testing.T) {
 	configure()
 	logHook := utils.NewTestLogHook()
 	log.AddHook(logHook)
-	noPkgsEvent := createTestUploadEvent("1", testInventoryID, "yupana", false, false, "created")
-	err := HandleUpload(noPkgsEvent)
+	noPkgsEvent := createTestUploadEvent(testOrgID, testInventoryID, "yupana", false, false, "created")
+	err := HandleUpload(t.Context(), noPkgsEvent)
 	if assert.Error(t, err) {
 		assert.ErrorIs(t, err, ErrReporter)
 	}
 	assertInLogs(t, ErrReporter.Error(), logHook.LogEntries...)
+	deleteData(t)
 }
 
 func TestUploadHandlerWarnSkipHostType(t *testing.T) {
@@ -278,13 +280,14 @@ func TestUploadHandlerWarnSkipHostType(t *testing.T) {
 	configure()
 	logHook := utils.NewTestLogHook()
 	log.AddHook(logHook)
-	event := createTestUploadEvent("1", testInventoryID, "puptoo", true, false, "created")
+	event := createTestUploadEvent(testOrgID, testInventoryID, "puptoo", true, false, "created")
 	event.Host.SystemProfile.HostType = "edge"
-	err := HandleUpload(event)
+	err := HandleUpload(t.Context(), event)
 	if assert.Error(t, err) {
 		assert.ErrorIs(t, err, ErrHostType)
 	}
 	assertInLogs(t, ErrHostType.Error(), logHook.LogEntries...)
+	deleteData(t)
 }
 
 // error when parsing identity
@@ -295,7 +298,7 @@ func TestUploadHandlerError1(t *testing.T) {
 	log.AddHook(logHook)
 	event := createTestUploadEvent("1", testInventoryID, "puptoo", true, false, "created")
 	*event.Host.OrgID = ""
-	err := HandleUpload(event)
+	err := HandleUpload(t.Context(), event)
 	if assert.Error(t, err) {
 		assert.ErrorIs(t, err, ErrNoAccountProvided)
 	}
@@ -320,7 +323,7 @@ func TestUploadHandlerError2(t *testing.T) {
 	log.AddHook(logHook)
 	_ = getOrCreateTestAccount(t)
 	event := createTestUploadEvent("1", testInventoryID, "puptoo", true, false, "created")
-	err := HandleUpload(event)
+	err := HandleUpload(t.Context(), event)
 	assert.Nil(t, err)
 	time.Sleep(2 * uploadEvalTimeout)
 	assertInLogs(t, ErrorKafkaSend, logHook.LogEntries...)
@@ -418,7 +421,7 @@ func TestUpdateSystemPlatformYumUpdates(t *testing.T) {
 		Debug:      true,
 	}
 	hostEvent := createTestUploadEvent("1", testInventoryID, "puptoo", false, true, "created")
-	yumUpdates, err := getYumUpdates(hostEvent, httpClient)
+	yumUpdates, err := getYumUpdates(t.Context(), hostEvent, httpClient)
 	assert.Nil(t, err)
 
 	req := vmaas.UpdatesV3Request{}
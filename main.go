@@ -3,14 +3,19 @@ package main
 import (
 	"app/aggregator"
 	"app/base"
+	"app/base/tracing"
 	"app/base/utils"
 	"app/database_admin"
 	"app/evaluator"
 	"app/listener"
 	"app/manager"
 	"app/platform"
+	"app/tasks"
+	"app/tasks/advisory_exclusions"
 	"app/tasks/caches"
 	"app/tasks/cleaning"
+	"app/tasks/org_transfer"
+	"app/tasks/partitions"
 	"app/tasks/repack"
 	"app/tasks/system_advisories_0_recovery"
 	"app/tasks/system_culling"
@@ -18,6 +23,7 @@ import (
 	"app/turnpike"
 	"log"
 	"os"
+	"time"
 
 	_ "go.uber.org/automaxprocs" // automatically sets GOMAXPROCS based on the CPU limit
 )
@@ -26,6 +32,7 @@ func main() {
 	base.HandleSignals()
 
 	defer utils.LogPanics(true)
+	defer tracing.Shutdown()
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
 		case "admin":
@@ -52,9 +59,28 @@ func main() {
 		case "print_clowder_params":
 			utils.PrintClowderParams()
 			return
+		case "lint_migrations":
+			database_admin.LintMigrations(os.Args[2])
+			return
 		case "check_upgraded":
 			database_admin.CheckUpgraded(os.Args[2])
 			return
+		case "org_export":
+			if len(os.Args) < 4 {
+				log.Fatal("Usage: org_export <org_id> <file>")
+			}
+			org_transfer.RunExport(os.Args[2], os.Args[3])
+			return
+		case "org_import":
+			if len(os.Args) < 3 {
+				log.Fatal("Usage: org_import <file> [org_id]")
+			}
+			orgID := ""
+			if len(os.Args) > 3 {
+				orgID = os.Args[3]
+			}
+			org_transfer.RunImport(os.Args[2], orgID)
+			return
 		case "job":
 			runJob(os.Args[2])
 			return
@@ -64,24 +90,38 @@ func main() {
 }
 
 func runJob(name string) {
+	start := time.Now()
+	var err error
 	switch name {
 	case "vmaas_sync":
-		vmaas_sync.RunVmaasSync()
+		err = vmaas_sync.RunVmaasSync()
 	case "system_culling":
-		system_culling.RunSystemCulling()
+		err = system_culling.RunSystemCulling()
 	case "advisory_cache_refresh":
-		caches.RunAdvisoryRefresh()
+		err = caches.RunAdvisoryRefresh()
 	case "delete_unused":
-		cleaning.RunDeleteUnusedData()
+		err = cleaning.RunDeleteUnusedData()
 	case "packages_cache_refresh":
-		caches.RunPackageRefresh()
+		err = caches.RunPackageRefresh()
 	case "repack":
-		repack.RunRepack()
+		err = repack.RunRepack()
 	case "account_advisory_backfill":
-		caches.RunAccountAdvisoryBackfill()
+		err = caches.RunAccountAdvisoryBackfill()
 	case "clean_advisory_account_data":
-		cleaning.RunCleanAdvisoryAccountData()
+		err = cleaning.RunCleanAdvisoryAccountData()
 	case "system_advisories_0_recovery":
-		system_advisories_0_recovery.Run()
+		err = system_advisories_0_recovery.Run()
+	case "expire_advisory_exclusions":
+		err = advisory_exclusions.RunExpireAdvisoryExclusions()
+	case "partition_maintenance":
+		err = partitions.RunPartitionMaintenance()
+	default:
+		utils.LogError("job", name, "Unknown job")
+		return
+	}
+	if err != nil {
+		utils.LogError("job", name, "err", err, "Job failed, last run is not recorded")
+		return
 	}
+	tasks.RecordRun(name, start)
 }
@@ -21,11 +21,40 @@ var (
 
 	// Send recalc message for systems which have been assigned to a different template
 	EnableTemplateChangeEval = utils.PodConfig.GetBool("template_change_eval", true)
+	// Send recalc message for systems which have been assigned to a different baseline or their baseline changed
+	EnableBaselineChangeEval = utils.PodConfig.GetBool("baseline_change_eval", true)
+	// Send recalc message for systems covered by a created or deleted package hold
+	EnablePackageHoldChangeEval = utils.PodConfig.GetBool("package_hold_change_eval", true)
+	// Send recalc message for systems using a repository with changed org classification
+	EnableRepoClassificationChangeEval = utils.PodConfig.GetBool("repo_classification_change_eval", true)
 	// Honor rbac permissions (can be disabled for tests)
 	EnableRBACCHeck = utils.PodConfig.GetBool("rbac", true)
 
+	// Authorize template assignment by Kessel check of each system, not only by workspace access
+	EnableKesselHostCheck = utils.PodConfig.GetBool("kessel_host_check", true)
+	// Number of hosts checked by one Kessel bulk check call
+	KesselHostCheckBatchSize = utils.PodConfig.GetInt("kessel_host_check_batch_size", 100)
+	// Max number of hosts checked for one request, requests with more hosts are rejected
+	KesselHostCheckMaxHosts = utils.PodConfig.GetInt("kessel_host_check_max_hosts", 1000)
+	// Number of RBAC/Kessel permission decisions kept in memory
+	PermissionCacheSize = utils.PodConfig.GetInt("permission_cache_size", 10000)
+	// How long (in seconds) to keep granted permission decisions, 0 disables the cache
+	PermissionCacheTTL = utils.PodConfig.GetInt("permission_cache_ttl", 60)
+	// How long (in seconds) to keep denied permission decisions, 0 disables caching of denials
+	PermissionCacheNegativeTTL = utils.PodConfig.GetInt("permission_cache_negative_ttl", 10)
+
 	// Expose templates API (feature flag)
 	EnableTemplates = utils.PodConfig.GetBool("templates_api", true)
 	// Use precomputed per-workspace advisory counts from account_advisory table
 	EnableAccountAdvisoryReadPath = utils.PodConfig.GetBool("account_advisory", true)
+
+	// Database serving GET requests: primary, replica or replica_max_lag
+	DBReplicaPolicy = utils.PodConfig.GetString("db_replica_policy", "replica")
+	// Comma separated `<route>|<policy>` pairs overriding db_replica_policy,
+	// e.g. `/api/patch/v3/systems/:inventory_id|primary`
+	DBReplicaRoutePolicies = utils.PodConfig.GetString("db_replica_routes", "")
+	// Max replication lag (in seconds) of read replica used by replica_max_lag policy
+	DBReplicaMaxLag = utils.PodConfig.GetInt("db_replica_max_lag", 30)
+	// How often (in seconds) to measure replication lag of read replica
+	DBReplicaLagInterval = utils.PodConfig.GetInt("db_replica_lag_interval", 10)
 )
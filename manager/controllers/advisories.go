@@ -258,7 +258,8 @@ func buildAdvisoryAccountDataQuery(db *gorm.DB, account int, workspaceIDs []stri
 		Select(`sa.advisory_id, si.rh_account_id as rh_account_id,
 		        count(si.*) filter (where sa.status_id = 0) as systems_installable,
 		        count(si.*) as systems_applicable`).
-		Where("si.stale = false").
+		// advisories beyond the system baseline are not counted
+		Where("si.stale = false AND sa.status_id <> 2").
 		Group("si.rh_account_id, sa.advisory_id")
 
 	return query
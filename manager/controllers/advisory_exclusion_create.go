@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AdvisoryExclusionCreateRequest struct {
+	// Advisory name
+	Advisory string `json:"advisory" example:"RHSA-2024:0001"`
+	// Exclusion kind - accepted_risk or excluded
+	Kind string `json:"kind" example:"accepted_risk"`
+	// Reason why the advisory is not going to be applied
+	Justification string `json:"justification"`
+	// Person responsible for the exclusion, the requesting user when empty
+	Owner string `json:"owner"`
+	// Exclusion is removed after it expires, never expires when empty
+	Expires *time.Time `json:"expires"`
+	// Systems covered by the exclusion, all systems when empty. The selection is resolved on creation,
+	// systems matching it later are not covered, tag and group_name filters are not supported.
+	Systems PatchPlanSelection `json:"systems"`
+}
+
+func (r *AdvisoryExclusionCreateRequest) validate() error {
+	if strings.TrimSpace(r.Advisory) == "" {
+		return errors.New("advisory must not be empty")
+	}
+	if !slices.Contains([]string{models.AdvisoryExclusionAcceptedRisk, models.AdvisoryExclusionExcluded}, r.Kind) {
+		return errors.New("kind must be accepted_risk or excluded")
+	}
+	if strings.TrimSpace(r.Justification) == "" {
+		return errors.New("justification must not be empty")
+	}
+	if strings.TrimSpace(r.Owner) == "" {
+		return errors.New("owner must not be empty")
+	}
+	if r.Expires != nil && !r.Expires.After(time.Now()) {
+		return errors.New("expires must be in the future")
+	}
+	// tags and inventory groups change over time, matching systems would silently diverge from the stored ones
+	if len(r.Systems.Tags) > 0 {
+		return errors.New("tags are not supported for systems")
+	}
+	if _, ok := r.Systems.Filter["group_name"]; ok {
+		return errors.New("group_name filter is not supported for systems")
+	}
+	return validateSystemsListIDs(r.Systems.IDs)
+}
+
+// @Summary Create an advisory exclusion
+// @Description Mark an advisory as accepted risk or excluded for selected systems with a justification, owner
+// @Description and optional expiration. Excluded advisories are not counted in system and advisory counts
+// @Description and they are shown with a distinct status in system advisories. Systems selection is resolved
+// @Description when the exclusion is created, systems matching it later are not covered by the exclusion.
+// @ID createAdvisoryExclusion
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    body    body   AdvisoryExclusionCreateRequest true "Request body"
+// @Success 200 {object} AdvisoryExclusionDetailResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /advisory-exclusions [post]
+func AdvisoryExclusionCreateHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	var req AdvisoryExclusionCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid advisory exclusion request "+err.Error())
+		return
+	}
+	if req.Owner == "" {
+		req.Owner = c.GetString(utils.KeyUser)
+	}
+	if err := req.validate(); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid advisory exclusion request: "+err.Error())
+		return
+	}
+
+	systemFilters, err := req.Systems.filters(SystemsFields)
+	if err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid systems selection: "+err.Error())
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	var advisoryIDs []int64
+	err = database.AdvisoryMetadata(db).Where("am.name = ?", req.Advisory).Pluck("am.id", &advisoryIDs).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+	if len(advisoryIDs) == 0 {
+		utils.LogAndRespNotFound(c, errors.New("no rows returned"), "Advisory not found")
+		return
+	}
+
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	exclusion := models.AdvisoryExclusion{
+		RhAccountID:   account,
+		AdvisoryID:    advisoryIDs[0],
+		Kind:          req.Kind,
+		Justification: req.Justification,
+		Owner:         req.Owner,
+		Expires:       req.Expires,
+	}
+	if err = tx.Create(&exclusion).Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not create advisory exclusion")
+		return
+	}
+
+	systemIDs, err := insertAdvisoryExclusionSystems(tx, account, workspaceIDs, &exclusion, &req.Systems, systemFilters)
+	if err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid systems selection: "+err.Error())
+		return
+	}
+	if len(systemIDs) == 0 {
+		err = errors.New("no systems selected")
+		utils.LogAndRespBadRequest(c, err, "Invalid systems selection: "+err.Error())
+		return
+	}
+
+	if err = database.RefreshAdvisoryExclusionCaches(tx, account, exclusion.AdvisoryID, systemIDs); err != nil {
+		utils.LogAndRespError(c, err, "Could not create advisory exclusion")
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not create advisory exclusion")
+		return
+	}
+
+	respondAdvisoryExclusionDetail(c, db, account, workspaceIDs, exclusion.ID)
+}
+
+// Store systems matching the selection with the exclusion and return their ids
+func insertAdvisoryExclusionSystems(tx *gorm.DB, account int, workspaceIDs []string,
+	exclusion *models.AdvisoryExclusion, selection *PatchPlanSelection, systemFilters Filters) ([]int64, error) {
+	systems, err := selection.systemsQuery(tx, account, workspaceIDs, systemFilters)
+	if err != nil {
+		return nil, err
+	}
+	systems = systems.Select("si.rh_account_id, ?::bigint, si.id, ?::bigint", exclusion.ID, exclusion.AdvisoryID)
+	err = tx.Exec("INSERT INTO advisory_exclusion_system (rh_account_id, exclusion_id, system_id, advisory_id) ?",
+		systems).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var systemIDs []int64
+	err = tx.Model(&models.AdvisoryExclusionSystem{}).
+		Where("rh_account_id = ? AND exclusion_id = ?", account, exclusion.ID).
+		Pluck("system_id", &systemIDs).Error
+	return systemIDs, err
+}
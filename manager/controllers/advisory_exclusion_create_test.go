@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdvisoryExclusionCreate(t *testing.T) {
+	core.SetupTest(t)
+	expires := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	data := `{
+		"advisory": "RH-1",
+		"kind": "excluded",
+		"justification": "mitigated by firewall",
+		"owner": "admin",
+		"expires": "` + expires + `",
+		"systems": {"ids": ["00000000-0000-0000-0000-000000000001"]}
+	}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", AdvisoryExclusionCreateHandler)
+
+	var output AdvisoryExclusionDetailResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	defer database.DeleteAdvisoryExclusion(t, 1, output.Data.ID)
+	assert.Equal(t, "RH-1", output.Data.Attributes.Advisory)
+	assert.Equal(t, models.AdvisoryExclusionExcluded, output.Data.Attributes.Kind)
+	assert.Equal(t, "admin", output.Data.Attributes.Owner)
+	assert.Equal(t, 1, output.Data.Attributes.Systems)
+	assert.Equal(t, "active", output.Data.Attributes.Status)
+	database.CheckAdvisoryExclusionSystems(t, 1, output.Data.ID, 1)
+}
+
+func TestAdvisoryExclusionCreateInvalid(t *testing.T) {
+	core.SetupTest(t)
+	for _, data := range []string{
+		`{"kind": "excluded", "justification": "j", "owner": "o"}`,
+		`{"advisory": "RH-1", "kind": "unknown", "justification": "j", "owner": "o"}`,
+		`{"advisory": "RH-1", "kind": "excluded", "justification": " ", "owner": "o"}`,
+		`{"advisory": "RH-1", "kind": "excluded", "justification": "j", "owner": "o", "expires": "2020-01-01T00:00:00Z"}`,
+		`{"advisory": "RH-1", "kind": "excluded", "justification": "j", "owner": "o", "systems": {"ids": ["invalid"]}}`,
+		`{"advisory": "RH-1", "kind": "excluded", "justification": "j", "owner": "o", "systems": {"tags": ["ns1/k1=val1"]}}`,
+		`{"advisory": "RH-1", "kind": "excluded", "justification": "j", "owner": "o",
+			"systems": {"filter": {"group_name": "group1"}}}`,
+	} {
+		w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json",
+			AdvisoryExclusionCreateHandler)
+		assert.Equal(t, http.StatusBadRequest, w.Code, data)
+	}
+}
+
+func TestAdvisoryExclusionCreateUnknownAdvisory(t *testing.T) {
+	core.SetupTest(t)
+	data := `{"advisory": "RH-unknown", "kind": "excluded", "justification": "j", "owner": "o"}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", AdvisoryExclusionCreateHandler)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdvisoryExclusionCreateNoSystems(t *testing.T) {
+	core.SetupTest(t)
+	// system 12 belongs to account 3
+	data := `{"advisory": "RH-1", "kind": "excluded", "justification": "j", "owner": "o",
+		"systems": {"ids": ["00000000-0000-0000-0000-000000000012"]}}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", AdvisoryExclusionCreateHandler)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Delete an advisory exclusion
+// @Description Delete an advisory exclusion by given exclusion id, the advisory is counted again for covered systems
+// @ID deleteAdvisoryExclusion
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    exclusion_id    path    int     true    "Advisory exclusion ID"
+// @Success 200
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /advisory-exclusions/{exclusion_id} [delete]
+func AdvisoryExclusionDeleteHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+
+	exclusionID, err := parseAdvisoryExclusionID(c)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	exclusion, err := getAdvisoryExclusion(c, tx, account, exclusionID)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var systemIDs []int64
+	err = tx.Model(&models.AdvisoryExclusionSystem{}).
+		Where("rh_account_id = ? AND exclusion_id = ?", account, exclusionID).
+		Pluck("system_id", &systemIDs).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "Could not delete advisory exclusion")
+		return
+	}
+
+	// covered systems are removed by ON DELETE CASCADE
+	err = tx.Where("rh_account_id = ? AND id = ?", account, exclusionID).Delete(&models.AdvisoryExclusion{}).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "Could not delete advisory exclusion")
+		return
+	}
+
+	if err = database.RefreshAdvisoryExclusionCaches(tx, account, exclusion.AdvisoryID, systemIDs); err != nil {
+		utils.LogAndRespError(c, err, "Could not delete advisory exclusion")
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not delete advisory exclusion")
+		return
+	}
+	c.Status(http.StatusOK)
+}
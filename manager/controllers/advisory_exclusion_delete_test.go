@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdvisoryExclusionDelete(t *testing.T) {
+	core.SetupTest(t)
+	exclusionID := database.CreateAdvisoryExclusion(t, 1, 1, nil, []int64{1, 2})
+
+	w := CreateRequestRouterWithParams("DELETE", "/:exclusion_id", fmt.Sprint(exclusionID), "", nil, "",
+		AdvisoryExclusionDeleteHandler, 1)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	database.CheckAdvisoryExclusionSystems(t, 1, exclusionID, 0)
+}
+
+func TestAdvisoryExclusionDeleteNotFound(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("DELETE", "/:exclusion_id", "999999", "", nil, "",
+		AdvisoryExclusionDeleteHandler, 1)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type AdvisoryExclusionDetailResponse struct {
+	Data AdvisoryExclusionItem `json:"data"`
+}
+
+func parseAdvisoryExclusionID(c *gin.Context) (int64, error) {
+	exclusionID, err := strconv.ParseInt(c.Param("exclusion_id"), 10, 64)
+	if err != nil {
+		utils.LogAndRespBadRequest(c, err, "incorrect exclusion_id format")
+		return 0, err
+	}
+	return exclusionID, nil
+}
+
+func getAdvisoryExclusion(c *gin.Context, tx *gorm.DB, account int, exclusionID int64) (
+	*models.AdvisoryExclusion, error) {
+	var exclusion models.AdvisoryExclusion
+	err := tx.Where("rh_account_id = ? AND id = ?", account, exclusionID).
+		// use Find() not First() otherwise it returns error "no rows found" if exclusion is not present
+		Find(&exclusion).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return nil, err
+	}
+	if exclusion.ID == 0 {
+		err := errors.New("Advisory exclusion not found")
+		utils.LogAndRespNotFound(c, err, err.Error())
+		return nil, err
+	}
+	return &exclusion, nil
+}
+
+func respondAdvisoryExclusionDetail(c *gin.Context, db *gorm.DB, account int, workspaceIDs []string,
+	exclusionID int64) {
+	var exclusions []AdvisoryExclusionsDBLookup
+	err := advisoryExclusionsQuery(db, account, workspaceIDs).Where("ae.id = ?", exclusionID).Find(&exclusions).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+	if len(exclusions) == 0 {
+		utils.LogAndRespNotFound(c, errors.New("no rows returned"), "Advisory exclusion not found")
+		return
+	}
+
+	data, _ := advisoryExclusionsData(exclusions)
+	c.JSON(http.StatusOK, &AdvisoryExclusionDetailResponse{Data: data[0]})
+}
+
+// @Summary Show me details of an advisory exclusion by given exclusion id
+// @Description Show me details of an advisory exclusion including count of covered systems
+// @ID detailAdvisoryExclusion
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    exclusion_id    path    int     true    "Advisory exclusion ID"
+// @Success 200 {object} AdvisoryExclusionDetailResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /advisory-exclusions/{exclusion_id} [get]
+func AdvisoryExclusionDetailHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	exclusionID, err := parseAdvisoryExclusionID(c)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	respondAdvisoryExclusionDetail(c, db, account, workspaceIDs, exclusionID)
+}
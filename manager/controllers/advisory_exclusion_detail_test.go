@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdvisoryExclusionDetail(t *testing.T) {
+	core.SetupTest(t)
+	exclusionID := database.CreateAdvisoryExclusion(t, 1, 1, nil, []int64{1, 2})
+	defer database.DeleteAdvisoryExclusion(t, 1, exclusionID)
+
+	w := CreateRequestRouterWithParams("GET", "/:exclusion_id", fmt.Sprint(exclusionID), "", nil, "",
+		AdvisoryExclusionDetailHandler, 1)
+
+	var output AdvisoryExclusionDetailResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, exclusionID, output.Data.ID)
+	assert.Equal(t, "RH-1", output.Data.Attributes.Advisory)
+	assert.Equal(t, 2, output.Data.Attributes.Systems)
+	assert.Nil(t, output.Data.Attributes.Expires)
+}
+
+func TestAdvisoryExclusionDetailOtherAccount(t *testing.T) {
+	core.SetupTest(t)
+	exclusionID := database.CreateAdvisoryExclusion(t, 1, 1, nil, []int64{1})
+	defer database.DeleteAdvisoryExclusion(t, 1, exclusionID)
+
+	w := CreateRequestRouterWithParams("GET", "/:exclusion_id", fmt.Sprint(exclusionID), "", nil, "",
+		AdvisoryExclusionDetailHandler, 3)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdvisoryExclusionDetailInvalidID(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:exclusion_id", "invalid", "", nil, "",
+		AdvisoryExclusionDetailHandler, 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var AdvisoryExclusionFields = database.MustGetQueryAttrs(&AdvisoryExclusionsDBLookup{})
+var AdvisoryExclusionSelect = database.MustGetSelect(&AdvisoryExclusionsDBLookup{})
+var AdvisoryExclusionOpts = ListOpts{
+	Fields:         AdvisoryExclusionFields,
+	DefaultFilters: nil,
+	DefaultSort:    "-created",
+	StableSort:     "ae.id",
+	SearchFields:   []string{"am.name", "ae.justification", "ae.owner"},
+}
+
+type AdvisoryExclusionsDBLookup struct {
+	ID int64 `json:"id" csv:"id" query:"ae.id" gorm:"column:id"`
+	// a helper to get total number of exclusions
+	MetaTotalHelper
+
+	AdvisoryExclusionItemAttributes
+}
+
+// nolint: lll
+type AdvisoryExclusionItemAttributes struct {
+	// Excluded advisory name
+	Advisory string `json:"advisory" csv:"advisory" query:"am.name" gorm:"column:advisory"`
+	// Exclusion kind - accepted_risk or excluded
+	Kind          string `json:"kind" csv:"kind" query:"ae.kind" gorm:"column:kind"`
+	Justification string `json:"justification" csv:"justification" query:"ae.justification" gorm:"column:justification"`
+	Owner         string `json:"owner" csv:"owner" query:"ae.owner" gorm:"column:owner"`
+	// Exclusion is removed after it expires, never expires when empty
+	Expires *time.Time `json:"expires" csv:"expires" query:"ae.expires" gorm:"column:expires"`
+	Created time.Time  `json:"created" csv:"created" query:"ae.created" gorm:"column:created"`
+	// Count of systems covered by the exclusion
+	Systems int `json:"systems" csv:"systems" query:"coalesce(aes.systems, 0)" gorm:"column:systems"`
+	// Exclusion status - active or expired (not yet removed)
+	Status string `json:"status" csv:"status" query:"CASE WHEN ae.expires <= now() THEN 'expired' ELSE 'active' END" gorm:"column:status"`
+}
+
+type AdvisoryExclusionItem struct {
+	Attributes AdvisoryExclusionItemAttributes `json:"attributes"` // Additional advisory exclusion attributes
+	ID         int64                           `json:"id"`         // Unique advisory exclusion id
+	Type       string                          `json:"type"`       // Document type name
+}
+
+type AdvisoryExclusionsResponse struct {
+	Data  []AdvisoryExclusionItem `json:"data"`  // Advisory exclusion items
+	Links Links                   `json:"links"` // Pagination links
+	Meta  ListMeta                `json:"meta"`  // Generic response fields (pagination params, filters etc.)
+}
+
+// @Summary Show all advisory exclusions for an account
+// @Description Show all advisories accepted as a risk or excluded for a set of systems
+// @ID listAdvisoryExclusions
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort    query   string  false   "Sort field" Enums(id,advisory,kind,owner,expires,created,systems,status)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[id]             query   int     false "Filter"
+// @Param    filter[advisory]       query   string  false "Filter"
+// @Param    filter[kind]           query   string  false "Filter" Enums(accepted_risk,excluded)
+// @Param    filter[owner]          query   string  false "Filter"
+// @Param    filter[expires]        query   string  false "Filter"
+// @Param    filter[status]         query   string  false "Filter" Enums(active,expired)
+// @Success 200 {object} AdvisoryExclusionsResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /advisory-exclusions [get]
+func AdvisoryExclusionsListHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+	filters, err := ParseAllFilters(c, AdvisoryExclusionOpts)
+	if err != nil {
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	query := advisoryExclusionsQuery(db, account, workspaceIDs)
+
+	query, meta, params, err := ListCommon(query, c, filters, AdvisoryExclusionOpts)
+	if err != nil {
+		// Error handling and setting of result code & content is done in ListCommon
+		return
+	}
+
+	var exclusions []AdvisoryExclusionsDBLookup
+	err = query.Find(&exclusions).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	data, total := advisoryExclusionsData(exclusions)
+	meta, links, err := UpdateMetaLinks(c, meta, total, nil, params...)
+	if err != nil {
+		return // Error handled in method itself
+	}
+
+	resp := AdvisoryExclusionsResponse{
+		Data:  data,
+		Links: *links,
+		Meta:  *meta,
+	}
+	c.JSON(http.StatusOK, &resp)
+}
+
+// Exclusions with count of covered systems from user's workspaces
+func advisoryExclusionsQuery(db *gorm.DB, account int, workspaceIDs []string) *gorm.DB {
+	subq := database.Systems(db, account, workspaceIDs).
+		Joins("JOIN advisory_exclusion_system aes ON aes.rh_account_id = si.rh_account_id AND aes.system_id = si.id").
+		Select("aes.exclusion_id, count(*) AS systems").
+		Group("aes.exclusion_id")
+
+	query := db.Table("advisory_exclusion ae").
+		Select(AdvisoryExclusionSelect).
+		Joins("JOIN advisory_metadata am ON am.id = ae.advisory_id").
+		Joins("LEFT JOIN (?) aes ON aes.exclusion_id = ae.id", subq).
+		Where("ae.rh_account_id = ?", account)
+	return query
+}
+
+func advisoryExclusionsData(exclusions []AdvisoryExclusionsDBLookup) ([]AdvisoryExclusionItem, int) {
+	var total int
+	if len(exclusions) > 0 {
+		total = exclusions[0].Total
+	}
+	data := make([]AdvisoryExclusionItem, len(exclusions))
+	for i, exclusion := range exclusions {
+		data[i] = AdvisoryExclusionItem{
+			Attributes: exclusion.AdvisoryExclusionItemAttributes,
+			ID:         exclusion.ID,
+			Type:       "advisory_exclusion",
+		}
+	}
+	return data, total
+}
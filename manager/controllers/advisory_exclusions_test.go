@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdvisoryExclusionsList(t *testing.T) {
+	core.SetupTest(t)
+	expired := time.Now().Add(-time.Hour)
+	activeID := database.CreateAdvisoryExclusion(t, 1, 1, nil, []int64{1, 2})
+	defer database.DeleteAdvisoryExclusion(t, 1, activeID)
+	expiredID := database.CreateAdvisoryExclusion(t, 1, 3, &expired, []int64{1})
+	defer database.DeleteAdvisoryExclusion(t, 1, expiredID)
+
+	w := CreateRequestRouterWithParams("GET", "/", "", "?sort=id", nil, "", AdvisoryExclusionsListHandler, 1)
+
+	var output AdvisoryExclusionsResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 2, len(output.Data))
+	assert.Equal(t, 2, output.Meta.TotalItems)
+	assert.Equal(t, activeID, output.Data[0].ID)
+	assert.Equal(t, "advisory_exclusion", output.Data[0].Type)
+	assert.Equal(t, "RH-1", output.Data[0].Attributes.Advisory)
+	assert.Equal(t, "accepted_risk", output.Data[0].Attributes.Kind)
+	assert.Equal(t, 2, output.Data[0].Attributes.Systems)
+	assert.Equal(t, "active", output.Data[0].Attributes.Status)
+	assert.Equal(t, "RH-3", output.Data[1].Attributes.Advisory)
+	assert.Equal(t, "expired", output.Data[1].Attributes.Status)
+}
+
+func TestAdvisoryExclusionsListFilter(t *testing.T) {
+	core.SetupTest(t)
+	exclusionID := database.CreateAdvisoryExclusion(t, 1, 1, nil, []int64{1})
+	defer database.DeleteAdvisoryExclusion(t, 1, exclusionID)
+
+	w := CreateRequestRouterWithParams("GET", "/", "", "?filter[advisory]=RH-2", nil, "",
+		AdvisoryExclusionsListHandler, 1)
+
+	var output AdvisoryExclusionsResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 0, len(output.Data))
+}
+
+func TestAdvisoryExclusionsListWrongSort(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/", "", "?sort=unknown", nil, "", AdvisoryExclusionsListHandler, 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
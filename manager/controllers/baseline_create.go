@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type BaselineCreateRequest struct {
+	// Baseline name
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	// Inventory ID of the reference system, updates not installed on the reference system are not applicable
+	ReferenceSystem *uuid.UUID `json:"reference_system" example:"00000000-0000-0000-0000-000000000001"`
+	// Advisories published after the cutoff are not applicable
+	AdvisoryCutoff *time.Time `json:"advisory_cutoff" example:"2024-01-01T00:00:00Z"`
+}
+
+func (r *BaselineCreateRequest) validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("name must not be empty")
+	}
+	if r.Description != nil && strings.TrimSpace(*r.Description) == "" {
+		r.Description = nil
+	}
+	if r.ReferenceSystem == nil && r.AdvisoryCutoff == nil {
+		return errors.New("reference_system or advisory_cutoff must be set")
+	}
+	return nil
+}
+
+// Find internal id of the reference system in user's workspaces
+func baselineReferenceSystemID(c *gin.Context, db *gorm.DB, account int, workspaceIDs []string,
+	inventoryID *uuid.UUID) (*int64, error) {
+	if inventoryID == nil {
+		return nil, nil
+	}
+	var systemIDs []int64
+	err := database.Systems(db, account, workspaceIDs).
+		Where("si.inventory_id = ?", *inventoryID).
+		Pluck("si.id", &systemIDs).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return nil, err
+	}
+	if len(systemIDs) == 0 {
+		err = fmt.Errorf("unknown reference system: %s", inventoryID)
+		utils.LogAndRespNotFound(c, err, err.Error())
+		return nil, err
+	}
+	return &systemIDs[0], nil
+}
+
+// @Summary Create a baseline
+// @Description Create a baseline defined by packages installed on a reference system and/or by an advisory cutoff date.
+// @Description Advisories beyond the baseline are not applicable by policy to systems assigned to the baseline.
+// @ID createBaseline
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    body    body   BaselineCreateRequest true "Request body"
+// @Success 200 {object} BaselineDetailResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /baselines [post]
+func BaselineCreateHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	var req BaselineCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid baseline request "+err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid baseline request: "+err.Error())
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	referenceSystemID, err := baselineReferenceSystemID(c, db, account, workspaceIDs, req.ReferenceSystem)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	baseline := models.Baseline{
+		RhAccountID:       account,
+		Name:              req.Name,
+		Description:       req.Description,
+		ReferenceSystemID: referenceSystemID,
+		AdvisoryCutoff:    req.AdvisoryCutoff,
+	}
+	if err = db.Create(&baseline).Error; err != nil {
+		if database.IsPgErrorCode(db, err, gorm.ErrDuplicatedKey) {
+			utils.LogWarnAndResp(c, http.StatusConflict, fmt.Sprintf("Baseline '%s' already exists", req.Name))
+			return
+		}
+		utils.LogAndRespError(c, err, "Could not create baseline")
+		return
+	}
+
+	respondBaselineDetail(c, db, account, workspaceIDs, baseline.ID)
+}
+
+func respondBaselineDetail(c *gin.Context, db *gorm.DB, account int, workspaceIDs []string, baselineID int64) {
+	var baselines []BaselinesDBLookup
+	err := baselinesQuery(db, account, workspaceIDs).Where("bl.id = ?", baselineID).Find(&baselines).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+	if len(baselines) == 0 {
+		utils.LogAndRespNotFound(c, errors.New("no rows returned"), "Baseline not found")
+		return
+	}
+
+	data, _ := baselinesData(baselines)
+	c.JSON(http.StatusOK, &BaselineDetailResponse{Data: data[0]})
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselineCreate(t *testing.T) {
+	core.SetupTest(t)
+	data := `{
+		"name": "baseline_new",
+		"description": "new baseline",
+		"reference_system": "00000000-0000-0000-0000-000000000003",
+		"advisory_cutoff": "2020-01-01T00:00:00Z"
+	}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", BaselineCreateHandler)
+
+	var output BaselineDetailResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	defer database.DeleteBaseline(t, 1, "baseline_new")
+	assert.Equal(t, "baseline_new", output.Data.Attributes.Name)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000003", *output.Data.Attributes.ReferenceSystem)
+	assert.Equal(t, "2020-01-01T00:00:00Z", output.Data.Attributes.AdvisoryCutoff.UTC().Format("2006-01-02T15:04:05Z"))
+	assert.Equal(t, 0, output.Data.Attributes.Systems)
+
+	// baseline names are unique within account
+	w = CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", BaselineCreateHandler)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestBaselineCreateNoDefinition(t *testing.T) {
+	core.SetupTest(t)
+	data := `{"name": "baseline_new"}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", BaselineCreateHandler)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBaselineCreateEmptyName(t *testing.T) {
+	core.SetupTest(t)
+	data := `{"name": " ", "advisory_cutoff": "2020-01-01T00:00:00Z"}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", BaselineCreateHandler)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBaselineCreateUnknownReference(t *testing.T) {
+	core.SetupTest(t)
+	// system 12 belongs to account 3
+	data := `{"name": "baseline_new", "reference_system": "00000000-0000-0000-0000-000000000012"}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", BaselineCreateHandler)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/config"
+	"app/manager/kafka"
+	"app/manager/middlewares"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Delete a baseline
+// @Description Delete a baseline by given baseline id, systems assigned to the baseline are unassigned and re-evaluated
+// @ID deleteBaseline
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    baseline_id    path    int     true    "Baseline ID"
+// @Success 200
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /baselines/{baseline_id} [delete]
+func BaselineDeleteHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	orgID := c.GetString(utils.KeyOrgID)
+
+	baselineID, err := parseBaselineID(c)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	inventoryIDs, err := baselineSystems(tx, account, baselineID)
+	if err != nil {
+		utils.LogAndRespError(c, err, "Database error")
+		return
+	}
+
+	// unassign systems first, system_patch references the baseline
+	err = tx.Model(&models.SystemPatch{}).
+		Where("rh_account_id = ? AND baseline_id = ?", account, baselineID).
+		Update("baseline_id", nil).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "Could not delete baseline")
+		return
+	}
+
+	query := tx.Where("rh_account_id = ? AND id = ?", account, baselineID).Delete(&models.Baseline{})
+	if err := query.Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not delete baseline")
+		return
+	}
+	if query.RowsAffected == 0 {
+		utils.LogAndRespNotFound(c, errors.New("no rows returned"), "Baseline not found")
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not delete baseline")
+		return
+	}
+
+	// re-evaluate systems removed from the baseline
+	if config.EnableBaselineChangeEval && len(inventoryIDs) > 0 {
+		kafka.RecalcSystems(kafka.InventoryIDs2EvalData(account, orgID, inventoryIDs))
+	}
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselineDelete(t *testing.T) {
+	core.SetupTest(t)
+	cutoff := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	baselineID := database.CreateBaseline(t, 1, "baseline_del", nil, &cutoff, []int64{4, 5})
+
+	w := CreateRequestRouterWithParams("DELETE", "/:baseline_id", fmt.Sprint(baselineID), "", nil, "",
+		BaselineDeleteHandler, 1)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	database.CheckBaselineSystems(t, 1, baselineID, []uuid.UUID{})
+}
+
+func TestBaselineDeleteNotFound(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("DELETE", "/:baseline_id", "999999", "", nil, "",
+		BaselineDeleteHandler, 1)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
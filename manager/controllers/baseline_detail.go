@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type BaselineDetailResponse struct {
+	Data BaselineItem `json:"data"`
+}
+
+func parseBaselineID(c *gin.Context) (int64, error) {
+	baselineID, err := strconv.ParseInt(c.Param("baseline_id"), 10, 64)
+	if err != nil {
+		utils.LogAndRespBadRequest(c, err, "incorrect baseline_id format")
+		return 0, err
+	}
+	return baselineID, nil
+}
+
+func getBaseline(c *gin.Context, tx *gorm.DB, account int, baselineID int64) (*models.Baseline, error) {
+	var baseline models.Baseline
+	err := tx.Where("rh_account_id = ? AND id = ?", account, baselineID).
+		// use Find() not First() otherwise it returns error "no rows found" if baseline is not present
+		Find(&baseline).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return nil, err
+	}
+	if baseline.ID == 0 {
+		err := errors.New("Baseline not found")
+		utils.LogAndRespNotFound(c, err, err.Error())
+		return nil, err
+	}
+	return &baseline, nil
+}
+
+// @Summary Show me details of a baseline by given baseline id
+// @Description Show me details of a baseline including count of assigned systems
+// @ID detailBaseline
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    baseline_id    path    int     true    "Baseline ID"
+// @Success 200 {object} BaselineDetailResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /baselines/{baseline_id} [get]
+func BaselineDetailHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	baselineID, err := parseBaselineID(c)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	respondBaselineDetail(c, db, account, workspaceIDs, baselineID)
+}
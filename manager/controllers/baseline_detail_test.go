@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"app/base/core"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselineDetail(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:baseline_id", "1", "", nil, "", BaselineDetailHandler, 1)
+
+	var output BaselineDetailResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, int64(1), output.Data.ID)
+	assert.Equal(t, "baseline1-1", output.Data.Attributes.Name)
+	assert.Equal(t, "desc1", *output.Data.Attributes.Description)
+	assert.Equal(t, 1, output.Data.Attributes.Systems)
+}
+
+func TestBaselineDetailOtherAccount(t *testing.T) {
+	core.SetupTest(t)
+	// baseline 3 belongs to account 3
+	w := CreateRequestRouterWithParams("GET", "/:baseline_id", "3", "", nil, "", BaselineDetailHandler, 1)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestBaselineDetailBadID(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:baseline_id", "abc", "", nil, "", BaselineDetailHandler, 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
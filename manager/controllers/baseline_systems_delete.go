@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"app/base/utils"
+	"app/manager/config"
+	"app/manager/kafka"
+	"app/manager/middlewares"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Remove systems from baseline
+// @Description Remove systems from their baseline
+// @ID removeBaselineSystems
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    body    body   BaselineSystemsUpdateRequest true "Request body"
+// @Success 200
+// @Failure 400 {object} 	utils.ErrorResponse
+// @Failure 404 {object} 	utils.ErrorResponse
+// @Failure 500 {object} 	utils.ErrorResponse
+// @Router /baselines/systems [DELETE]
+func BaselineSystemsDeleteHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	orgID := c.GetString(utils.KeyOrgID)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	var req BaselineSystemsUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid baseline delete request "+err.Error())
+		return
+	}
+
+	if err := checkSystemsLimit(len(req.Systems), BaselineSystemsUpdateLimit, c); err != nil {
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	if err := checkBaselineSystems(c, db, account, req.Systems, workspaceIDs); err != nil {
+		return
+	}
+
+	// unassign system from baseline => assign NULL as baseline_id
+	if err := assignBaselineSystems(c, db, account, nil, req.Systems); err != nil {
+		return
+	}
+
+	// re-evaluate systems removed from baselines
+	if config.EnableBaselineChangeEval {
+		kafka.RecalcSystems(kafka.InventoryIDs2EvalData(account, orgID, req.Systems))
+	}
+	c.Status(http.StatusOK)
+}
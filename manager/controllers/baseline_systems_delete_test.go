@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselineSystemsDelete(t *testing.T) {
+	core.SetupTest(t)
+	cutoff := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	baselineID := database.CreateBaseline(t, 1, "baseline_sys", nil, &cutoff, []int64{4, 5})
+	defer database.DeleteBaseline(t, 1, "baseline_sys")
+
+	bodyJSON, err := sonic.Marshal(&BaselineSystemsUpdateRequest{Systems: baselineSystems45[:1]})
+	assert.Nil(t, err)
+	w := CreateRequestRouterWithParams("DELETE", "/systems", "", "", bytes.NewBuffer(bodyJSON), "",
+		BaselineSystemsDeleteHandler, 1)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	database.CheckBaselineSystems(t, 1, baselineID, baselineSystems45[1:])
+}
+
+func TestBaselineSystemsDeleteUnknownSystem(t *testing.T) {
+	core.SetupTest(t)
+	bodyJSON, err := sonic.Marshal(&BaselineSystemsUpdateRequest{Systems: []uuid.UUID{uuid.New()}})
+	assert.Nil(t, err)
+	w := CreateRequestRouterWithParams("DELETE", "/systems", "", "", bytes.NewBuffer(bodyJSON), "",
+		BaselineSystemsDeleteHandler, 1)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/config"
+	"app/manager/kafka"
+	"app/manager/middlewares"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+const BaselineSystemsUpdateLimit = 1000
+
+type BaselineSystemsUpdateRequest struct {
+	// List of inventory IDs to be assigned to (or removed from) the baseline
+	Systems []uuid.UUID `json:"systems" example:"system1-uuid, system2-uuid, ..."`
+}
+
+// Inventory IDs of systems assigned to the baseline
+func baselineSystems(tx *gorm.DB, account int, baselineID int64) ([]uuid.UUID, error) {
+	var inventoryIDs []uuid.UUID
+	err := tx.Table("system_inventory si").
+		Joins("JOIN system_patch spatch ON si.id = spatch.system_id AND si.rh_account_id = spatch.rh_account_id").
+		Where("spatch.rh_account_id = ? AND spatch.baseline_id = ?", account, baselineID).
+		Pluck("si.inventory_id", &inventoryIDs).Error
+	return inventoryIDs, err
+}
+
+// All systems must exist in user's workspaces, unlike templates baselines can contain any system
+func checkBaselineSystems(c *gin.Context, db *gorm.DB, accountID int, inventoryIDs []uuid.UUID,
+	workspaceIDs []string) error {
+	if len(inventoryIDs) == 0 {
+		err := errors.New(InvalidInventoryIDsErr)
+		utils.LogAndRespBadRequest(c, err, InvalidInventoryIDsErr)
+		return err
+	}
+
+	var found []uuid.UUID
+	err := database.Systems(db, accountID, workspaceIDs).
+		Where("si.inventory_id IN (?)", inventoryIDs).
+		Pluck("si.inventory_id", &found).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "Database error")
+		return err
+	}
+	foundMap := make(map[uuid.UUID]bool, len(found))
+	for _, id := range found {
+		foundMap[id] = true
+	}
+	missing := []uuid.UUID{}
+	for _, id := range inventoryIDs {
+		if !foundMap[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		err = fmt.Errorf("unknown inventory_ids: %v", missing)
+		utils.LogAndRespNotFound(c, err, err.Error())
+		return err
+	}
+	return nil
+}
+
+func recalcBaselineSystems(db *gorm.DB, account int, orgID string, baselineID int64) error {
+	if !config.EnableBaselineChangeEval {
+		return nil
+	}
+	inventoryIDs, err := baselineSystems(db, account, baselineID)
+	if err != nil {
+		return err
+	}
+	if len(inventoryIDs) > 0 {
+		kafka.RecalcSystems(kafka.InventoryIDs2EvalData(account, orgID, inventoryIDs))
+	}
+	return nil
+}
+
+func assignBaselineSystems(c *gin.Context, db *gorm.DB, accountID int, baselineID *int64,
+	inventoryIDs []uuid.UUID) error {
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	siSub := tx.Model(&models.SystemInventory{}).
+		Select("id").
+		Where("rh_account_id = ? AND inventory_id IN (?)", accountID, inventoryIDs)
+	tx = tx.Model(&models.SystemPatch{}).
+		Where("rh_account_id = ? AND system_id IN (?)", accountID, siSub).
+		Update("baseline_id", baselineID)
+	if err := tx.Error; err != nil {
+		utils.LogAndRespError(c, err, "Database error")
+		return err
+	}
+	if int(tx.RowsAffected) != len(inventoryIDs) {
+		err := errors.New(InvalidInventoryIDsErr)
+		utils.LogAndRespBadRequest(c, err, InvalidInventoryIDsErr)
+		return err
+	}
+
+	err := tx.Commit().Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "Database error")
+		return err
+	}
+	return nil
+}
+
+// @Summary Add systems to a baseline
+// @Description Add systems to a baseline, systems are removed from their previous baseline
+// @ID updateBaselineSystems
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    baseline_id    path    int     true    "Baseline ID"
+// @Param    body    body   BaselineSystemsUpdateRequest true "Request body"
+// @Success 200
+// @Failure 400 {object} 	utils.ErrorResponse
+// @Failure 404 {object} 	utils.ErrorResponse
+// @Failure 500 {object} 	utils.ErrorResponse
+// @Router /baselines/{baseline_id}/systems [PUT]
+func BaselineSystemsUpdateHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	orgID := c.GetString(utils.KeyOrgID)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	baselineID, err := parseBaselineID(c)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var req BaselineSystemsUpdateRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid baseline update request "+err.Error())
+		return
+	}
+
+	if err = checkSystemsLimit(len(req.Systems), BaselineSystemsUpdateLimit, c); err != nil {
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	baseline, err := getBaseline(c, db, account, baselineID)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	if err = checkBaselineSystems(c, db, account, req.Systems, workspaceIDs); err != nil {
+		return
+	}
+
+	if err = assignBaselineSystems(c, db, account, &baseline.ID, req.Systems); err != nil {
+		return
+	}
+
+	// re-evaluate systems added to the baseline
+	if config.EnableBaselineChangeEval {
+		kafka.RecalcSystems(kafka.InventoryIDs2EvalData(account, orgID, req.Systems))
+	}
+	c.Status(http.StatusOK)
+}
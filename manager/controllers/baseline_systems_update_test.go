@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/utils"
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var baselineSystems45 = []uuid.UUID{
+	uuid.MustParse("00000000-0000-0000-0000-000000000004"),
+	uuid.MustParse("00000000-0000-0000-0000-000000000005"),
+}
+
+func testBaselineSystemsUpdate(t *testing.T, baselineID string, body BaselineSystemsUpdateRequest,
+	status int) *httptest.ResponseRecorder {
+	bodyJSON, err := sonic.Marshal(&body)
+	if err != nil {
+		panic(err)
+	}
+
+	w := CreateRequestRouterWithParams("PUT", "/:baseline_id/systems", baselineID, "", bytes.NewBuffer(bodyJSON), "",
+		BaselineSystemsUpdateHandler, 1)
+
+	assert.Equal(t, status, w.Code)
+	return w
+}
+
+func TestBaselineSystemsUpdate(t *testing.T) {
+	core.SetupTest(t)
+	cutoff := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	baselineID := database.CreateBaseline(t, 1, "baseline_sys", nil, &cutoff, nil)
+	defer database.DeleteBaseline(t, 1, "baseline_sys")
+
+	req := BaselineSystemsUpdateRequest{Systems: baselineSystems45}
+	testBaselineSystemsUpdate(t, fmt.Sprint(baselineID), req, http.StatusOK)
+	database.CheckBaselineSystems(t, 1, baselineID, baselineSystems45)
+}
+
+func TestBaselineSystemsUpdateUnknownSystem(t *testing.T) {
+	core.SetupTest(t)
+	req := BaselineSystemsUpdateRequest{Systems: []uuid.UUID{
+		uuid.MustParse("00000000-0000-0000-0000-000000000012"), // account 3
+	}}
+	testBaselineSystemsUpdate(t, "2", req, http.StatusNotFound)
+	database.CheckBaselineSystems(t, 1, 2, []uuid.UUID{})
+}
+
+func TestBaselineSystemsUpdateEmpty(t *testing.T) {
+	core.SetupTest(t)
+	testBaselineSystemsUpdate(t, "2", BaselineSystemsUpdateRequest{}, http.StatusBadRequest)
+}
+
+func TestBaselineSystemsUpdateBaselineNotFound(t *testing.T) {
+	core.SetupTest(t)
+	req := BaselineSystemsUpdateRequest{Systems: baselineSystems45}
+	testBaselineSystemsUpdate(t, "3", req, http.StatusNotFound)
+}
+
+func TestBaselineSystemsUpdateLimit(t *testing.T) {
+	core.SetupTest(t)
+	systems := make([]uuid.UUID, 0, BaselineSystemsUpdateLimit+1)
+	for i := 0; i < BaselineSystemsUpdateLimit+1; i++ {
+		systems = append(systems, uuid.New())
+	}
+	w := testBaselineSystemsUpdate(t, "2", BaselineSystemsUpdateRequest{Systems: systems}, http.StatusBadRequest)
+
+	var errResp utils.ErrorResponse
+	CheckResponse(t, w, http.StatusBadRequest, &errResp)
+	assert.Equal(t, fmt.Sprintf("Cannot process more than %d systems at once", BaselineSystemsUpdateLimit),
+		errResp.Error)
+}
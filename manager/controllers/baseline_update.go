@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// @Summary Update a baseline
+// @Description Update name, description, reference system and advisory cutoff of a baseline.
+// @Description Systems assigned to the baseline are re-evaluated.
+// @ID updateBaseline
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    baseline_id    path    int     true    "Baseline ID"
+// @Param    body    body   BaselineCreateRequest true "Request body"
+// @Success 200 {object} BaselineDetailResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /baselines/{baseline_id} [put]
+func BaselineUpdateHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	orgID := c.GetString(utils.KeyOrgID)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	baselineID, err := parseBaselineID(c)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var req BaselineCreateRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid baseline request "+err.Error())
+		return
+	}
+	if err = req.validate(); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid baseline request: "+err.Error())
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	if _, err = getBaseline(c, db, account, baselineID); err != nil {
+		return
+	} // Error handled in method itself
+
+	referenceSystemID, err := baselineReferenceSystemID(c, db, account, workspaceIDs, req.ReferenceSystem)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	err = db.Model(&models.Baseline{}).
+		Where("rh_account_id = ? AND id = ?", account, baselineID).
+		Updates(map[string]interface{}{
+			"name":                req.Name,
+			"description":         req.Description,
+			"reference_system_id": referenceSystemID,
+			"advisory_cutoff":     req.AdvisoryCutoff,
+			"last_edited":         gorm.Expr("now()"),
+		}).Error
+	if err != nil {
+		if database.IsPgErrorCode(db, err, gorm.ErrDuplicatedKey) {
+			utils.LogWarnAndResp(c, http.StatusConflict, fmt.Sprintf("Baseline '%s' already exists", req.Name))
+			return
+		}
+		utils.LogAndRespError(c, err, "Could not update baseline")
+		return
+	}
+
+	if err = recalcBaselineSystems(db, account, orgID, baselineID); err != nil {
+		utils.LogAndRespError(c, err, "Database error")
+		return
+	}
+	respondBaselineDetail(c, db, account, workspaceIDs, baselineID)
+}
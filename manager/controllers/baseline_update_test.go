@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselineUpdate(t *testing.T) {
+	core.SetupTest(t)
+	cutoff := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	baselineID := database.CreateBaseline(t, 1, "baseline_upd", nil, &cutoff, nil)
+	defer database.DeleteBaseline(t, 1, "baseline_upd2")
+	defer database.DeleteBaseline(t, 1, "baseline_upd")
+
+	data := `{"name": "baseline_upd2", "reference_system": "00000000-0000-0000-0000-000000000001"}`
+	w := CreateRequestRouterWithParams("PUT", "/:baseline_id", fmt.Sprint(baselineID), "",
+		bytes.NewBufferString(data), "application/json", BaselineUpdateHandler, 1)
+
+	var output BaselineDetailResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, baselineID, output.Data.ID)
+	assert.Equal(t, "baseline_upd2", output.Data.Attributes.Name)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", *output.Data.Attributes.ReferenceSystem)
+	assert.Nil(t, output.Data.Attributes.AdvisoryCutoff)
+}
+
+func TestBaselineUpdateDuplicateName(t *testing.T) {
+	core.SetupTest(t)
+	data := `{"name": "baseline2-1", "reference_system": "00000000-0000-0000-0000-000000000002"}`
+	w := CreateRequestRouterWithParams("PUT", "/:baseline_id", "1", "",
+		bytes.NewBufferString(data), "application/json", BaselineUpdateHandler, 1)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestBaselineUpdateNotFound(t *testing.T) {
+	core.SetupTest(t)
+	data := `{"name": "baseline_upd", "advisory_cutoff": "2020-01-01T00:00:00Z"}`
+	w := CreateRequestRouterWithParams("PUT", "/:baseline_id", "999999", "",
+		bytes.NewBufferString(data), "application/json", BaselineUpdateHandler, 1)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
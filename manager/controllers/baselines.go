@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var BaselineFields = database.MustGetQueryAttrs(&BaselinesDBLookup{})
+var BaselineSelect = database.MustGetSelect(&BaselinesDBLookup{})
+var BaselineOpts = ListOpts{
+	Fields:         BaselineFields,
+	DefaultFilters: nil,
+	DefaultSort:    "name",
+	StableSort:     "bl.id",
+	SearchFields:   []string{"bl.name"},
+}
+
+type BaselinesDBLookup struct {
+	ID int64 `json:"id" csv:"id" query:"bl.id" gorm:"column:id"`
+	// a helper to get total number of baselines
+	MetaTotalHelper
+
+	BaselineItemAttributes
+}
+
+// nolint: lll
+type BaselineItemAttributes struct {
+	// Baseline name
+	Name        string  `json:"name" csv:"name" query:"bl.name" gorm:"column:name"`
+	Description *string `json:"description" csv:"description" query:"bl.description" gorm:"column:description"`
+	// Inventory ID of the reference system, only updates installed on the reference system are applicable
+	ReferenceSystem *string `json:"reference_system" csv:"reference_system" query:"rsi.inventory_id::text" gorm:"column:reference_system"`
+	// Only advisories published up to the cutoff are applicable
+	AdvisoryCutoff *time.Time `json:"advisory_cutoff" csv:"advisory_cutoff" query:"bl.advisory_cutoff" gorm:"column:advisory_cutoff"`
+	// Count of systems assigned to the baseline
+	Systems    int       `json:"systems" csv:"systems" query:"coalesce(bls.systems, 0)" gorm:"column:systems"`
+	Created    time.Time `json:"created" csv:"created" query:"bl.created" gorm:"column:created"`
+	LastEdited time.Time `json:"last_edited" csv:"last_edited" query:"bl.last_edited" gorm:"column:last_edited"`
+}
+
+type BaselineItem struct {
+	Attributes BaselineItemAttributes `json:"attributes"` // Additional baseline attributes
+	ID         int64                  `json:"id"`         // Unique baseline id
+	Type       string                 `json:"type"`       // Document type name
+}
+
+type BaselinesResponse struct {
+	Data  []BaselineItem `json:"data"`  // Baseline items
+	Links Links          `json:"links"` // Pagination links
+	Meta  ListMeta       `json:"meta"`  // Generic response fields (pagination params, filters etc.)
+}
+
+// @Summary Show all baselines for an account
+// @Description Show all baselines for an account with count of assigned systems
+// @ID listBaselines
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field"    Enums(id,name,advisory_cutoff,systems,created,last_edited)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[id]                 query   int     false "Filter"
+// @Param    filter[name]               query   string  false "Filter"
+// @Param    filter[reference_system]   query   string  false "Filter"
+// @Param    filter[advisory_cutoff]    query   string  false "Filter"
+// @Param    filter[systems]            query   int     false "Filter"
+// @Success 200 {object} BaselinesResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /baselines [get]
+func BaselinesListHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+	filters, err := ParseAllFilters(c, BaselineOpts)
+	if err != nil {
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	query := baselinesQuery(db, account, workspaceIDs)
+
+	query, meta, params, err := ListCommon(query, c, filters, BaselineOpts)
+	if err != nil {
+		// Error handling and setting of result code & content is done in ListCommon
+		return
+	}
+
+	var baselines []BaselinesDBLookup
+	err = query.Find(&baselines).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	data, total := baselinesData(baselines)
+	meta, links, err := UpdateMetaLinks(c, meta, total, nil, params...)
+	if err != nil {
+		return // Error handled in method itself
+	}
+
+	resp := BaselinesResponse{
+		Data:  data,
+		Links: *links,
+		Meta:  *meta,
+	}
+	c.JSON(http.StatusOK, &resp)
+}
+
+// Baselines with count of assigned systems from user's workspaces
+func baselinesQuery(db *gorm.DB, account int, workspaceIDs []string) *gorm.DB {
+	subq := database.Systems(db, account, workspaceIDs).
+		Select("spatch.baseline_id, count(*) AS systems").
+		Where("spatch.baseline_id IS NOT NULL").
+		Group("spatch.baseline_id")
+
+	query := db.Table("baseline bl").
+		Select(BaselineSelect).
+		Joins("LEFT JOIN (?) bls ON bls.baseline_id = bl.id", subq).
+		Joins(`LEFT JOIN system_inventory rsi ON rsi.id = bl.reference_system_id
+			AND rsi.rh_account_id = bl.rh_account_id`).
+		Where("bl.rh_account_id = ?", account)
+	return query
+}
+
+func baselinesData(baselines []BaselinesDBLookup) ([]BaselineItem, int) {
+	var total int
+	if len(baselines) > 0 {
+		total = baselines[0].Total
+	}
+	data := make([]BaselineItem, len(baselines))
+	for i, baseline := range baselines {
+		data[i] = BaselineItem{
+			Attributes: baseline.BaselineItemAttributes,
+			ID:         baseline.ID,
+			Type:       "baseline",
+		}
+	}
+	return data, total
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"app/base/core"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselinesList(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/", "", "", nil, "", BaselinesListHandler, 1)
+
+	var output BaselinesResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 2, len(output.Data))
+	assert.Equal(t, 2, output.Meta.TotalItems)
+	assert.Equal(t, int64(1), output.Data[0].ID)
+	assert.Equal(t, "baseline", output.Data[0].Type)
+	assert.Equal(t, "baseline1-1", output.Data[0].Attributes.Name)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000002", *output.Data[0].Attributes.ReferenceSystem)
+	assert.Nil(t, output.Data[0].Attributes.AdvisoryCutoff)
+	assert.Equal(t, 1, output.Data[0].Attributes.Systems)
+	assert.Equal(t, "baseline2-1", output.Data[1].Attributes.Name)
+	assert.Nil(t, output.Data[1].Attributes.ReferenceSystem)
+	assert.NotNil(t, output.Data[1].Attributes.AdvisoryCutoff)
+	assert.Equal(t, 0, output.Data[1].Attributes.Systems)
+}
+
+func TestBaselinesListFilter(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/", "", "?filter[systems]=0", nil, "", BaselinesListHandler, 1)
+
+	var output BaselinesResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 1, len(output.Data))
+	assert.Equal(t, "baseline2-1", output.Data[0].Attributes.Name)
+}
+
+func TestBaselinesListWrongSort(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/", "", "?sort=unknown", nil, "", BaselinesListHandler, 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
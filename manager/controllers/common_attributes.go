@@ -37,23 +37,24 @@ type SystemWorkspace struct {
 	WorkspaceName *string    `json:"workspace_name" csv:"workspace_name" query:"si.workspace_name" gorm:"column:workspace_name"`
 }
 
-// baseline attributes are obsoleted and we keep them only for backward API compatibility
-// now they always return default value
+// baseline attributes require database.JoinBaselines
 type BaselineAttributes struct {
 	BaselineNameAttr
 	BaselineUpToDateAttr
 }
 
+// nolint: lll
 type BaselineUpToDateAttr struct {
-	BaselineUpToDate *bool `json:"baseline_uptodate" csv:"baseline_uptodate" query:"null" gorm:"column:baseline_uptodate"`
+	// System assigned to a baseline has no installable advisories within the baseline, null without baseline
+	BaselineUpToDate *bool `json:"baseline_uptodate" csv:"baseline_uptodate" query:"CASE WHEN spatch.baseline_id IS NULL THEN NULL ELSE spatch.installable_advisory_count_cache = 0 END" gorm:"column:baseline_uptodate"`
 }
 
 type BaselineNameAttr struct {
-	BaselineName string `json:"baseline_name" csv:"baseline_name" query:"''" gorm:"column:baseline_name"`
+	BaselineName string `json:"baseline_name" csv:"baseline_name" query:"coalesce(bl.name, '')" gorm:"column:baseline_name"`
 }
 
 type BaselineIDAttr struct {
-	BaselineID int64 `json:"baseline_id" csv:"baseline_id" query:"0" gorm:"column:baseline_id"`
+	BaselineID int64 `json:"baseline_id" csv:"baseline_id" query:"coalesce(spatch.baseline_id, 0)" gorm:"column:baseline_id"`
 }
 
 type TemplateAttibutes struct {
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CullingPolicyRequest struct {
+	// Number of days culled systems are kept after their inventory culled timestamp
+	CullingDelayDays int `json:"culling_delay_days"`
+	// Number of days before deletion of a culled system when the org is notified
+	NoticeDays int `json:"notice_days"`
+}
+
+type CullingPolicyResponse struct {
+	CullingDelayDays int        `json:"culling_delay_days"`
+	NoticeDays       int        `json:"notice_days"`
+	LastEdited       *time.Time `json:"last_edited"`
+}
+
+func (r *CullingPolicyRequest) validate() error {
+	if r.CullingDelayDays < 0 || r.NoticeDays < 0 {
+		return errors.New("culling_delay_days and notice_days must not be negative")
+	}
+	return nil
+}
+
+// @Summary Show me org culling policy
+// @Description Show me how long culled systems are kept before deletion and when the org is notified about it.
+// @Description Defaults with zero days are returned when the org has no policy set.
+// @ID getCullingPolicy
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Success 200 {object} CullingPolicyResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /culling/policy [get]
+func CullingPolicyHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+
+	db := middlewares.DBFromContext(c)
+	var policy models.CullingPolicy
+	// use Find() not First() otherwise it returns error "no rows found" if policy is not set
+	if err := db.Where("rh_account_id = ?", account).Find(&policy).Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not get culling policy")
+		return
+	}
+
+	resp := CullingPolicyResponse{CullingDelayDays: policy.CullingDelayDays, NoticeDays: policy.NoticeDays}
+	if policy.RhAccountID != 0 {
+		resp.LastEdited = &policy.LastEdited
+	}
+	c.JSON(http.StatusOK, &resp)
+}
+
+// @Summary Set org culling policy
+// @Description Keep culled systems and their patch data culling_delay_days after the inventory culled timestamp
+// @Description and notify the org notice_days before the systems are deleted.
+// @ID updateCullingPolicy
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    body       body    CullingPolicyRequest   true "Request body"
+// @Success 200 {object} CullingPolicyResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /culling/policy [put]
+func CullingPolicyUpdateHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+
+	var req CullingPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid culling policy request "+err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid culling policy request: "+err.Error())
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	policy := models.CullingPolicy{
+		RhAccountID:      account,
+		CullingDelayDays: req.CullingDelayDays,
+		NoticeDays:       req.NoticeDays,
+		LastEdited:       time.Now(),
+	}
+	err := database.OnConflictUpdate(db, "rh_account_id", "culling_delay_days", "notice_days", "last_edited").
+		Create(&policy).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "Could not update culling policy")
+		return
+	}
+	c.JSON(http.StatusOK, &CullingPolicyResponse{
+		CullingDelayDays: policy.CullingDelayDays,
+		NoticeDays:       policy.NoticeDays,
+		LastEdited:       &policy.LastEdited,
+	})
+}
+
+// @Summary Remove org culling policy
+// @Description Remove org culling policy, culled systems are deleted at their inventory culled timestamp again
+// @ID deleteCullingPolicy
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Success 200
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /culling/policy [delete]
+func CullingPolicyDeleteHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+
+	db := middlewares.DBFromContext(c)
+	query := db.Where("rh_account_id = ?", account).Delete(&models.CullingPolicy{})
+	if err := query.Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not delete culling policy")
+		return
+	}
+	if query.RowsAffected == 0 {
+		utils.LogAndRespNotFound(c, errors.New("no rows returned"), "Culling policy not found")
+		return
+	}
+	c.Status(http.StatusOK)
+}
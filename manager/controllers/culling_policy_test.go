@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCullingPolicyDefault(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/policy", "", "", nil, "", CullingPolicyHandler, 1)
+
+	var output CullingPolicyResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 0, output.CullingDelayDays)
+	assert.Equal(t, 0, output.NoticeDays)
+	assert.Nil(t, output.LastEdited)
+}
+
+func TestCullingPolicyUpdate(t *testing.T) {
+	core.SetupTest(t)
+	defer database.DeleteCullingPolicy(t, 1)
+
+	for _, data := range []string{`{"culling_delay_days": 14, "notice_days": 3}`, `{"culling_delay_days": 30}`} {
+		w := CreateRequestRouterWithParams("PUT", "/policy", "", "", bytes.NewBufferString(data),
+			"application/json", CullingPolicyUpdateHandler, 1)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	w := CreateRequestRouterWithParams("GET", "/policy", "", "", nil, "", CullingPolicyHandler, 1)
+	var output CullingPolicyResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 30, output.CullingDelayDays)
+	assert.Equal(t, 0, output.NoticeDays)
+	assert.NotNil(t, output.LastEdited)
+
+	var policies []models.CullingPolicy
+	assert.Nil(t, database.DB.Find(&policies).Error)
+	assert.Len(t, policies, 1)
+}
+
+func TestCullingPolicyUpdateInvalid(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("PUT", "/policy", "", "", bytes.NewBufferString(`{"notice_days": -1}`),
+		"application/json", CullingPolicyUpdateHandler, 1)
+
+	var errResp utils.ErrorResponse
+	CheckResponse(t, w, http.StatusBadRequest, &errResp)
+	assert.Equal(t, "Invalid culling policy request: culling_delay_days and notice_days must not be negative",
+		errResp.Error)
+}
+
+func TestCullingPolicyDelete(t *testing.T) {
+	core.SetupTest(t)
+	database.CreateCullingPolicy(t, 1, 7, 1)
+
+	w := CreateRequestRouterWithParams("DELETE", "/policy", "", "", nil, "", CullingPolicyDeleteHandler, 1)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = CreateRequestRouterWithParams("DELETE", "/policy", "", "", nil, "", CullingPolicyDeleteHandler, 1)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var cullingSystemFields = database.MustGetQueryAttrs(&CullingSystemsDBLookup{})
+var cullingSystemSelect = database.MustGetSelect(&CullingSystemsDBLookup{})
+var CullingSystemOpts = ListOpts{
+	Fields:         cullingSystemFields,
+	DefaultFilters: map[string]FilterData{},
+	DefaultSort:    "culling_date",
+	StableSort:     "si.id",
+	SearchFields:   []string{"si.display_name"},
+}
+
+type CullingSystemsDBLookup struct {
+	SystemIDAttribute
+	// a helper to get total number of systems
+	MetaTotalHelper
+	CullingSystemAttributes
+}
+
+// nolint: lll
+type CullingSystemAttributes struct {
+	SystemDisplayName
+	SystemStale
+	SystemTimestamps
+	SystemTags
+	SystemGroups
+	// Date when the system is deleted, culled timestamp delayed by the org culling policy
+	CullingDate *time.Time `json:"culling_date" csv:"culling_date" query:"si.culled_timestamp + make_interval(days => coalesce(cp.culling_delay_days, 0))" gorm:"column:culling_date"`
+	// When the org was notified about the current culling date
+	Notified *time.Time `json:"notified" csv:"notified" query:"CASE WHEN scn.culling_date = si.culled_timestamp + make_interval(days => coalesce(cp.culling_delay_days, 0)) THEN scn.notified END" gorm:"column:notified"`
+}
+
+type CullingSystemItem struct {
+	Attributes CullingSystemAttributes `json:"attributes"`
+	// Inventory ID (uuid format)
+	InventoryID uuid.UUID `json:"inventory_id"`
+	// Document type name
+	Type string `json:"type"`
+}
+
+type CullingSystemsResponse struct {
+	Data  []CullingSystemItem `json:"data"`
+	Links Links               `json:"links"`
+	Meta  ListMeta            `json:"meta"`
+}
+
+func cullingSystemsQuery(db *gorm.DB, account int, workspaceIDs []string) *gorm.DB {
+	return database.Systems(db, account, workspaceIDs, database.JoinCullingPolicy).
+		Joins("LEFT JOIN system_culling_notice scn ON scn.rh_account_id = si.rh_account_id AND scn.system_id = si.id").
+		Where("si.culled_timestamp IS NOT NULL").
+		Select(cullingSystemSelect)
+}
+
+func cullingSystemsData(systems []CullingSystemsDBLookup) ([]CullingSystemItem, int) {
+	var total int
+	if len(systems) > 0 {
+		total = systems[0].Total
+	}
+	data := make([]CullingSystemItem, len(systems))
+	for i, s := range systems {
+		data[i] = CullingSystemItem{
+			Attributes:  s.CullingSystemAttributes,
+			InventoryID: s.ID,
+			Type:        "culling_system",
+		}
+	}
+	return data, total
+}
+
+// nolint: lll
+// @Summary Show me systems scheduled for culling
+// @Description Show me systems scheduled for culling with the date when they are deleted by the org culling policy
+// @ID listCullingSystems
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field"    Enums(id,display_name,stale,stale_timestamp,stale_warning_timestamp,culled_timestamp,created,groups,culling_date,notified)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[display_name]           query   string  false "Filter"
+// @Param    filter[stale]                  query   string  false "Filter"
+// @Param    filter[culling_date]           query   string  false "Filter"
+// @Param    tags           query   []string  false "Tag filter"
+// @Param    filter[group_name] 									query []string 	false "Filter systems by inventory groups"
+// @Success 200 {object} CullingSystemsResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /culling/systems [get]
+func CullingSystemsListHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	db := middlewares.DBFromContext(c)
+	query := cullingSystemsQuery(db, account, workspaceIDs)
+	filters, err := ParseAllFilters(c, CullingSystemOpts)
+	if err != nil {
+		return
+	} // Error handled in method itself
+	query, _ = ApplyInventoryFilter(filters, query, "si.inventory_id")
+	query, meta, params, err := ListCommon(query, c, filters, CullingSystemOpts)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var systems []CullingSystemsDBLookup
+	if err = query.Find(&systems).Error; err != nil {
+		utils.LogAndRespError(c, err, "Database error")
+		return
+	}
+
+	data, total := cullingSystemsData(systems)
+	meta, links, err := UpdateMetaLinks(c, meta, total, nil, params...)
+	if err != nil {
+		return
+	} // Error handled in method itself
+	c.JSON(http.StatusOK, &CullingSystemsResponse{Data: data, Links: *links, Meta: *meta})
+}
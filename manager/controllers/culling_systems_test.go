@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setCulledTimestamp(t *testing.T, account int, systemID int64, culled *time.Time) {
+	assert.Nil(t, database.DB.Model(&models.SystemInventory{}).
+		Where("rh_account_id = ? AND id = ?", account, systemID).
+		Update("culled_timestamp", culled).Error)
+}
+
+func TestCullingSystemsList(t *testing.T) {
+	core.SetupTest(t)
+	culled := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	setCulledTimestamp(t, 1, 1, &culled)
+	defer setCulledTimestamp(t, 1, 1, nil)
+	database.CreateCullingPolicy(t, 1, 10, 0)
+	defer database.DeleteCullingPolicy(t, 1)
+
+	w := CreateRequestRouterWithParams("GET", "/systems", "", "", nil, "", CullingSystemsListHandler, 1)
+
+	var output CullingSystemsResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 1, len(output.Data))
+	assert.Equal(t, 1, output.Meta.TotalItems)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", output.Data[0].InventoryID.String())
+	assert.Equal(t, "culling_system", output.Data[0].Type)
+	assert.Equal(t, culled.AddDate(0, 0, 10), output.Data[0].Attributes.CullingDate.UTC())
+	assert.Nil(t, output.Data[0].Attributes.Notified)
+}
+
+func TestCullingSystemsListEmpty(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/systems", "", "", nil, "", CullingSystemsListHandler, 1)
+
+	var output CullingSystemsResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 0, len(output.Data))
+}
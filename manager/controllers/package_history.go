@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var PackageHistoryFields = database.MustGetQueryAttrs(&PackageHistoryDBLookup{})
+var PackageHistorySelect = database.MustGetSelect(&PackageHistoryDBLookup{})
+var PackageHistoryOpts = ListOpts{
+	Fields:         PackageHistoryFields,
+	DefaultFilters: map[string]FilterData{},
+	DefaultSort:    "-changed",
+	StableSort:     "spc.id",
+	SearchFields:   []string{"si.display_name"},
+}
+
+type PackageHistoryItem struct {
+	SystemIDAttribute
+	SystemDisplayName
+	PackageChangeAttributes
+	SystemTags
+}
+
+type PackageHistoryDBLookup struct {
+	MetaTotalHelper
+	PackageHistoryItem
+}
+
+type PackageHistoryResponse struct {
+	Data  []PackageHistoryItem `json:"data"`
+	Links Links                `json:"links"`
+	Meta  ListMeta             `json:"meta"`
+}
+
+func packageHistoryQuery(db *gorm.DB, account int, workspaceIDs []string, packageNameIDs []int) *gorm.DB {
+	return database.Systems(db, account, workspaceIDs).
+		Joins("JOIN system_package_change spc ON spc.system_id = si.id AND spc.rh_account_id = si.rh_account_id").
+		Select(PackageHistorySelect).
+		Where("spc.name_id IN (?)", packageNameIDs)
+}
+
+// nolint: lll
+// @Summary Show me changes of a package recorded on my systems
+// @Description Show me when a package was installed, removed, upgraded or downgraded on my systems
+// @ID packageHistory
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    package_name   path    string  true    "Package name"
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field" Enums(id,display_name,change,old_evra,new_evra,changed)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[display_name]  query   string  false "Filter"
+// @Param    filter[change]        query   string  false "Filter" Enums(installed,removed,upgraded,downgraded)
+// @Param    filter[old_evra]      query   string  false "Filter"
+// @Param    filter[new_evra]      query   string  false "Filter"
+// @Param    filter[changed]       query   string  false "Filter"
+// @Param    tags                  query   []string  false "Tag filter"
+// @Param    filter[group_name] 									query []string 	false "Filter systems by inventory groups"
+// @Param    filter[system_profile][sap_system]						query bool  	false "Filter only SAP systems"
+// @Param    filter[system_profile][sap_sids]						query []string  false "Filter systems by their SAP SIDs"
+// @Success 200 {object} PackageHistoryResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /packages/{package_name}/history [get]
+func PackageHistoryHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	packageName := c.Param("package_name")
+	if packageName == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse{Error: "package_name param not found"})
+		return
+	}
+
+	filters, err := ParseAllFilters(c, PackageHistoryOpts)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	var packageNameIDs []int
+	if err = packagesNameID(db, packageName).Pluck("pn.id", &packageNameIDs).Error; err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+	if len(packageNameIDs) == 0 {
+		utils.LogAndRespNotFound(c, errors.New("not found"), "package not found")
+		return
+	}
+
+	query := packageHistoryQuery(db, account, workspaceIDs, packageNameIDs)
+	query, _ = ApplyInventoryFilter(filters, query, "si.inventory_id")
+	query, meta, params, err := ListCommon(query, c, filters, PackageHistoryOpts)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var changes []PackageHistoryDBLookup
+	err = query.Find(&changes).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	var total int
+	if len(changes) > 0 {
+		total = changes[0].Total
+	}
+	data := make([]PackageHistoryItem, len(changes))
+	for i := range changes {
+		data[i] = changes[i].PackageHistoryItem
+	}
+
+	meta, links, err := UpdateMetaLinks(c, meta, total, nil, params...)
+	if err != nil {
+		return // Error handled in method itself
+	}
+
+	response := PackageHistoryResponse{
+		Data:  data,
+		Links: *links,
+		Meta:  *meta,
+	}
+	c.JSON(http.StatusOK, &response)
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/utils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPackageHistory(t *testing.T, param, queryString string, expectedStatus int, output interface{}) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:package_name/history", param, queryString, nil, "",
+		PackageHistoryHandler, 1)
+	CheckResponse(t, w, expectedStatus, output)
+}
+
+func TestPackageHistory(t *testing.T) {
+	var output PackageHistoryResponse
+	testPackageHistory(t, "kernel", "", http.StatusOK, &output)
+	assert.Equal(t, 2, output.Meta.TotalItems)
+	// newest change first
+	assert.Equal(t, "00000000-0000-0000-0000-000000000002", output.Data[0].ID)
+	assert.Equal(t, "upgraded", output.Data[0].Change)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000003", output.Data[1].ID)
+}
+
+func TestPackageHistoryTags(t *testing.T) {
+	var output PackageHistoryResponse
+	testPackageHistory(t, "kernel", "?tags=ns1/k3=val4", http.StatusOK, &output)
+	assert.Equal(t, 1, len(output.Data))
+	assert.Equal(t, "00000000-0000-0000-0000-000000000003", output.Data[0].ID)
+}
+
+func TestPackageHistoryOtherAccount(t *testing.T) {
+	var output PackageHistoryResponse
+	testPackageHistory(t, "bash", "?filter[change]=installed", http.StatusOK, &output)
+	assert.Equal(t, 0, len(output.Data))
+}
+
+func TestPackageHistoryUnknownPackage(t *testing.T) {
+	var errResp utils.ErrorResponse
+	testPackageHistory(t, "not-existing", "", http.StatusNotFound, &errResp)
+	assert.Equal(t, "package not found", errResp.Error)
+}
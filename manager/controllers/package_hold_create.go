@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/config"
+	"app/manager/kafka"
+	"app/manager/middlewares"
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PackageHoldCreateRequest struct {
+	// Held package name
+	PackageName string `json:"package_name" example:"kernel"`
+	// Glob pattern of allowed EVRAs, updates not matching the pattern are held
+	EvraPattern string `json:"evra_pattern" example:"5.14.0-427.*"`
+	// Package hold description
+	Description *string `json:"description"`
+	// Systems covered by the package hold, all systems when empty
+	Systems PatchPlanSelection `json:"systems"`
+}
+
+func (r *PackageHoldCreateRequest) validate() error {
+	if strings.TrimSpace(r.PackageName) == "" {
+		return errors.New("package_name must not be empty")
+	}
+	if strings.TrimSpace(r.EvraPattern) == "" {
+		return errors.New("evra_pattern must not be empty")
+	}
+	if _, err := path.Match(r.EvraPattern, ""); err != nil {
+		return errors.New("evra_pattern is not a valid glob pattern")
+	}
+	return validateSystemsListIDs(r.Systems.IDs)
+}
+
+// @Summary Create a package hold
+// @Description Pin versions of a package for selected systems, e.g. kernel must stay on 5.14.0-427.*.
+// @Description Updates not matching the EVRA pattern are reported as held instead of installable.
+// @ID createPackageHold
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    body    body   PackageHoldCreateRequest true "Request body"
+// @Success 200 {object} PackageHoldDetailResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /package-holds [post]
+func PackageHoldCreateHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	orgID := c.GetString(utils.KeyOrgID)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	var req PackageHoldCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid package hold request "+err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid package hold request: "+err.Error())
+		return
+	}
+
+	systemFilters, err := req.Systems.filters(SystemsFields)
+	if err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid systems selection: "+err.Error())
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	var nameIDs []int64
+	err = db.Model(&models.PackageName{}).Where("name = ?", req.PackageName).Pluck("id", &nameIDs).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+	if len(nameIDs) == 0 {
+		utils.LogAndRespNotFound(c, errors.New("no rows returned"), "Package not found")
+		return
+	}
+
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	hold := models.PackageHold{
+		RhAccountID: account,
+		NameID:      nameIDs[0],
+		EvraPattern: req.EvraPattern,
+		Description: req.Description,
+	}
+	if err = tx.Create(&hold).Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not create package hold")
+		return
+	}
+
+	nSystems, err := insertPackageHoldSystems(tx, account, workspaceIDs, &hold, &req.Systems, systemFilters)
+	if err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid systems selection: "+err.Error())
+		return
+	}
+	if nSystems == 0 {
+		err = errors.New("no systems selected")
+		utils.LogAndRespBadRequest(c, err, "Invalid systems selection: "+err.Error())
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not create package hold")
+		return
+	}
+
+	if config.EnablePackageHoldChangeEval {
+		inventoryIDs, err := packageHoldSystems(db, account, hold.ID)
+		if err != nil {
+			utils.LogAndRespError(c, err, "Could not re-evaluate package hold systems")
+			return
+		}
+		kafka.RecalcSystems(kafka.InventoryIDs2EvalData(account, orgID, inventoryIDs))
+	}
+
+	respondPackageHoldDetail(c, db, account, workspaceIDs, hold.ID)
+}
+
+// Store systems matching the selection with the package hold and return their count
+func insertPackageHoldSystems(tx *gorm.DB, account int, workspaceIDs []string, hold *models.PackageHold,
+	selection *PatchPlanSelection, systemFilters Filters) (int64, error) {
+	systems, err := selection.systemsQuery(tx, account, workspaceIDs, systemFilters)
+	if err != nil {
+		return 0, err
+	}
+	systems = systems.Select("si.rh_account_id, ?::bigint, si.id", hold.ID)
+	res := tx.Exec("INSERT INTO package_hold_system (rh_account_id, hold_id, system_id) ?", systems)
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageHoldCreate(t *testing.T) {
+	core.SetupTest(t)
+	data := `{
+		"package_name": "kernel",
+		"evra_pattern": "5.6.13-*",
+		"description": "stay on 5.6.13",
+		"systems": {"ids": ["00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000002"]}
+	}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", PackageHoldCreateHandler)
+
+	var output PackageHoldDetailResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	defer database.DeletePackageHold(t, 1, output.Data.ID)
+	assert.Equal(t, "kernel", output.Data.Attributes.PackageName)
+	assert.Equal(t, "5.6.13-*", output.Data.Attributes.EvraPattern)
+	assert.Equal(t, "stay on 5.6.13", *output.Data.Attributes.Description)
+	assert.Equal(t, 2, output.Data.Attributes.Systems)
+	database.CheckPackageHoldSystems(t, 1, output.Data.ID, 2)
+}
+
+func TestPackageHoldCreateInvalid(t *testing.T) {
+	core.SetupTest(t)
+	for _, data := range []string{
+		`{"evra_pattern": "5.6.13-*"}`,
+		`{"package_name": "kernel", "evra_pattern": " "}`,
+		`{"package_name": "kernel", "evra_pattern": "5.6.[13-*"}`,
+		`{"package_name": "kernel", "evra_pattern": "5.6.13-*", "systems": {"ids": ["invalid"]}}`,
+	} {
+		w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", PackageHoldCreateHandler)
+		assert.Equal(t, http.StatusBadRequest, w.Code, data)
+	}
+}
+
+func TestPackageHoldCreateUnknownPackage(t *testing.T) {
+	core.SetupTest(t)
+	data := `{"package_name": "unknown-package", "evra_pattern": "1.*"}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", PackageHoldCreateHandler)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPackageHoldCreateNoSystems(t *testing.T) {
+	core.SetupTest(t)
+	// system 12 belongs to account 3
+	data := `{"package_name": "kernel", "evra_pattern": "5.6.13-*",
+		"systems": {"ids": ["00000000-0000-0000-0000-000000000012"]}}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", PackageHoldCreateHandler)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
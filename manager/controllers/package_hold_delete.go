@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/config"
+	"app/manager/kafka"
+	"app/manager/middlewares"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Delete a package hold
+// @Description Delete a package hold by given hold id, covered systems are re-evaluated
+// @ID deletePackageHold
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    hold_id    path    int     true    "Package hold ID"
+// @Success 200
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /package-holds/{hold_id} [delete]
+func PackageHoldDeleteHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	orgID := c.GetString(utils.KeyOrgID)
+
+	holdID, err := parsePackageHoldID(c)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	if _, err = getPackageHold(c, tx, account, holdID); err != nil {
+		return
+	} // Error handled in method itself
+
+	inventoryIDs, err := packageHoldSystems(tx, account, holdID)
+	if err != nil {
+		utils.LogAndRespError(c, err, "Could not delete package hold")
+		return
+	}
+
+	// covered systems are removed by ON DELETE CASCADE
+	err = tx.Where("rh_account_id = ? AND id = ?", account, holdID).Delete(&models.PackageHold{}).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "Could not delete package hold")
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not delete package hold")
+		return
+	}
+
+	if config.EnablePackageHoldChangeEval && len(inventoryIDs) > 0 {
+		kafka.RecalcSystems(kafka.InventoryIDs2EvalData(account, orgID, inventoryIDs))
+	}
+	c.Status(http.StatusOK)
+}
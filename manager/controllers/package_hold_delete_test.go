@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageHoldDelete(t *testing.T) {
+	core.SetupTest(t)
+	holdID := database.CreatePackageHold(t, 1, 101, "5.6.13-*", []int64{1, 2})
+
+	w := CreateRequestRouterWithParams("DELETE", "/:hold_id", fmt.Sprint(holdID), "", nil, "",
+		PackageHoldDeleteHandler, 1)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	database.CheckPackageHoldSystems(t, 1, holdID, 0)
+}
+
+func TestPackageHoldDeleteNotFound(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("DELETE", "/:hold_id", "999999", "", nil, "", PackageHoldDeleteHandler, 1)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type PackageHoldDetailResponse struct {
+	Data PackageHoldItem `json:"data"`
+}
+
+func parsePackageHoldID(c *gin.Context) (int64, error) {
+	holdID, err := strconv.ParseInt(c.Param("hold_id"), 10, 64)
+	if err != nil {
+		utils.LogAndRespBadRequest(c, err, "incorrect hold_id format")
+		return 0, err
+	}
+	return holdID, nil
+}
+
+func getPackageHold(c *gin.Context, tx *gorm.DB, account int, holdID int64) (*models.PackageHold, error) {
+	var hold models.PackageHold
+	err := tx.Where("rh_account_id = ? AND id = ?", account, holdID).
+		// use Find() not First() otherwise it returns error "no rows found" if hold is not present
+		Find(&hold).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return nil, err
+	}
+	if hold.ID == 0 {
+		err := errors.New("Package hold not found")
+		utils.LogAndRespNotFound(c, err, err.Error())
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// Inventory IDs of systems covered by the package hold
+func packageHoldSystems(tx *gorm.DB, account int, holdID int64) ([]uuid.UUID, error) {
+	var inventoryIDs []uuid.UUID
+	err := tx.Table("system_inventory si").
+		Joins("JOIN package_hold_system phs ON phs.rh_account_id = si.rh_account_id AND phs.system_id = si.id").
+		Where("phs.rh_account_id = ? AND phs.hold_id = ?", account, holdID).
+		Pluck("si.inventory_id", &inventoryIDs).Error
+	return inventoryIDs, err
+}
+
+func respondPackageHoldDetail(c *gin.Context, db *gorm.DB, account int, workspaceIDs []string, holdID int64) {
+	var holds []PackageHoldsDBLookup
+	err := packageHoldsQuery(db, account, workspaceIDs).Where("hold.id = ?", holdID).Find(&holds).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+	if len(holds) == 0 {
+		utils.LogAndRespNotFound(c, errors.New("no rows returned"), "Package hold not found")
+		return
+	}
+
+	data, _ := packageHoldsData(holds)
+	c.JSON(http.StatusOK, &PackageHoldDetailResponse{Data: data[0]})
+}
+
+// @Summary Show me details of a package hold by given hold id
+// @Description Show me details of a package hold including count of covered systems
+// @ID detailPackageHold
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    hold_id    path    int     true    "Package hold ID"
+// @Success 200 {object} PackageHoldDetailResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /package-holds/{hold_id} [get]
+func PackageHoldDetailHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	holdID, err := parsePackageHoldID(c)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	respondPackageHoldDetail(c, db, account, workspaceIDs, holdID)
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageHoldDetail(t *testing.T) {
+	core.SetupTest(t)
+	holdID := database.CreatePackageHold(t, 1, 102, "76.*", []int64{1, 2})
+	defer database.DeletePackageHold(t, 1, holdID)
+
+	w := CreateRequestRouterWithParams("GET", "/:hold_id", fmt.Sprint(holdID), "", nil, "",
+		PackageHoldDetailHandler, 1)
+
+	var output PackageHoldDetailResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, holdID, output.Data.ID)
+	assert.Equal(t, "firefox", output.Data.Attributes.PackageName)
+	assert.Equal(t, 2, output.Data.Attributes.Systems)
+}
+
+func TestPackageHoldDetailOtherAccount(t *testing.T) {
+	core.SetupTest(t)
+	holdID := database.CreatePackageHold(t, 1, 102, "76.*", []int64{1})
+	defer database.DeletePackageHold(t, 1, holdID)
+
+	w := CreateRequestRouterWithParams("GET", "/:hold_id", fmt.Sprint(holdID), "", nil, "",
+		PackageHoldDetailHandler, 3)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPackageHoldDetailInvalidID(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:hold_id", "invalid", "", nil, "", PackageHoldDetailHandler, 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var PackageHoldFields = database.MustGetQueryAttrs(&PackageHoldsDBLookup{})
+var PackageHoldSelect = database.MustGetSelect(&PackageHoldsDBLookup{})
+var PackageHoldOpts = ListOpts{
+	Fields:         PackageHoldFields,
+	DefaultFilters: nil,
+	DefaultSort:    "-created",
+	StableSort:     "hold.id",
+	SearchFields:   []string{"pn.name", "hold.description"},
+}
+
+type PackageHoldsDBLookup struct {
+	ID int64 `json:"id" csv:"id" query:"hold.id" gorm:"column:id"`
+	// a helper to get total number of package holds
+	MetaTotalHelper
+
+	PackageHoldItemAttributes
+}
+
+type PackageHoldItemAttributes struct {
+	// Held package name
+	PackageName string `json:"package_name" csv:"package_name" query:"pn.name" gorm:"column:package_name"`
+	// Glob pattern of allowed EVRAs, e.g. 5.14.0-427.*
+	EvraPattern string    `json:"evra_pattern" csv:"evra_pattern" query:"hold.evra_pattern" gorm:"column:evra_pattern"`
+	Description *string   `json:"description" csv:"description" query:"hold.description" gorm:"column:description"`
+	Created     time.Time `json:"created" csv:"created" query:"hold.created" gorm:"column:created"`
+	// Count of systems covered by the package hold
+	Systems int `json:"systems" csv:"systems" query:"coalesce(phs.systems, 0)" gorm:"column:systems"`
+}
+
+type PackageHoldItem struct {
+	Attributes PackageHoldItemAttributes `json:"attributes"` // Additional package hold attributes
+	ID         int64                     `json:"id"`         // Unique package hold id
+	Type       string                    `json:"type"`       // Document type name
+}
+
+type PackageHoldsResponse struct {
+	Data  []PackageHoldItem `json:"data"`  // Package hold items
+	Links Links             `json:"links"` // Pagination links
+	Meta  ListMeta          `json:"meta"`  // Generic response fields (pagination params, filters etc.)
+}
+
+// @Summary Show all package holds for an account
+// @Description Show all package holds pinning package versions of a set of systems
+// @ID listPackageHolds
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field"    Enums(id,package_name,evra_pattern,created,systems)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[id]             query   int     false "Filter"
+// @Param    filter[package_name]   query   string  false "Filter"
+// @Param    filter[evra_pattern]   query   string  false "Filter"
+// @Param    filter[systems]        query   int     false "Filter"
+// @Success 200 {object} PackageHoldsResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /package-holds [get]
+func PackageHoldsListHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+	filters, err := ParseAllFilters(c, PackageHoldOpts)
+	if err != nil {
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	query := packageHoldsQuery(db, account, workspaceIDs)
+
+	query, meta, params, err := ListCommon(query, c, filters, PackageHoldOpts)
+	if err != nil {
+		// Error handling and setting of result code & content is done in ListCommon
+		return
+	}
+
+	var holds []PackageHoldsDBLookup
+	err = query.Find(&holds).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	data, total := packageHoldsData(holds)
+	meta, links, err := UpdateMetaLinks(c, meta, total, nil, params...)
+	if err != nil {
+		return // Error handled in method itself
+	}
+
+	resp := PackageHoldsResponse{
+		Data:  data,
+		Links: *links,
+		Meta:  *meta,
+	}
+	c.JSON(http.StatusOK, &resp)
+}
+
+// Package holds with count of covered systems from user's workspaces
+func packageHoldsQuery(db *gorm.DB, account int, workspaceIDs []string) *gorm.DB {
+	subq := database.Systems(db, account, workspaceIDs).
+		Joins("JOIN package_hold_system phs ON phs.rh_account_id = si.rh_account_id AND phs.system_id = si.id").
+		Select("phs.hold_id, count(*) AS systems").
+		Group("phs.hold_id")
+
+	query := db.Table("package_hold hold").
+		Select(PackageHoldSelect).
+		Joins("JOIN package_name pn ON pn.id = hold.name_id").
+		Joins("LEFT JOIN (?) phs ON phs.hold_id = hold.id", subq).
+		Where("hold.rh_account_id = ?", account)
+	return query
+}
+
+func packageHoldsData(holds []PackageHoldsDBLookup) ([]PackageHoldItem, int) {
+	var total int
+	if len(holds) > 0 {
+		total = holds[0].Total
+	}
+	data := make([]PackageHoldItem, len(holds))
+	for i, hold := range holds {
+		data[i] = PackageHoldItem{
+			Attributes: hold.PackageHoldItemAttributes,
+			ID:         hold.ID,
+			Type:       "package_hold",
+		}
+	}
+	return data, total
+}
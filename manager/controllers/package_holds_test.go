@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageHoldsList(t *testing.T) {
+	core.SetupTest(t)
+	holdID := database.CreatePackageHold(t, 1, 101, "5.6.13-*", []int64{1, 2})
+	defer database.DeletePackageHold(t, 1, holdID)
+
+	w := CreateRequestRouterWithParams("GET", "/", "", "", nil, "", PackageHoldsListHandler, 1)
+
+	var output PackageHoldsResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 1, len(output.Data))
+	assert.Equal(t, 1, output.Meta.TotalItems)
+	assert.Equal(t, holdID, output.Data[0].ID)
+	assert.Equal(t, "package_hold", output.Data[0].Type)
+	assert.Equal(t, "kernel", output.Data[0].Attributes.PackageName)
+	assert.Equal(t, "5.6.13-*", output.Data[0].Attributes.EvraPattern)
+	assert.Equal(t, 2, output.Data[0].Attributes.Systems)
+}
+
+func TestPackageHoldsListFilter(t *testing.T) {
+	core.SetupTest(t)
+	holdID := database.CreatePackageHold(t, 1, 101, "5.6.13-*", []int64{1})
+	defer database.DeletePackageHold(t, 1, holdID)
+
+	w := CreateRequestRouterWithParams("GET", "/", "", "?filter[package_name]=firefox", nil, "",
+		PackageHoldsListHandler, 1)
+
+	var output PackageHoldsResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 0, len(output.Data))
+}
+
+func TestPackageHoldsListWrongSort(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/", "", "?sort=unknown", nil, "", PackageHoldsListHandler, 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -38,7 +38,9 @@ type PackageSystemItem struct {
 	SystemSatelliteManaged
 	BaselineIDAttr
 	OSAttributes
-	UpdateStatus string `json:"update_status" csv:"update_status" query:"CASE WHEN spkg.installable_id is not null THEN 'Installable' WHEN spkg.applicable_id is not null THEN 'Applicable' ELSE 'None' END" gorm:"column:update_status"`
+	UpdateStatus string `json:"update_status" csv:"update_status" query:"CASE WHEN spkg.installable_id is not null THEN 'Installable' WHEN spkg.applicable_id is not null THEN 'Applicable' WHEN spkg.held_id is not null THEN 'Held' ELSE 'None' END" gorm:"column:update_status"`
+	// Latest update violating a package hold
+	HeldEVRA string `json:"held_evra" csv:"held_evra" query:"ph.evra" gorm:"column:held_evra"`
 	SystemGroups
 	SystemWorkspace
 }
@@ -58,7 +60,8 @@ type PackageSystemsResponse struct {
 func packageSystemsQuery(db *gorm.DB, acc int, workspaceIDs []string, packageName string, packageIDs []int,
 ) *gorm.DB {
 	query := database.SystemPackages(db, acc, workspaceIDs,
-		database.JoinTemplates, database.JoinInstallableApplicablePackages).
+		database.JoinTemplates, database.JoinBaselines, database.JoinInstallableApplicablePackages,
+		database.JoinHeldPackages).
 		Select(PackageSystemsSelect).
 		Where("si.stale = false").
 		Where("pn.name = ?", packageName).
@@ -39,16 +39,16 @@ func TestPackageSystemsExportHandlerCSV(t *testing.T) {
 	assert.Equal(t, 5, len(lines))
 	assert.Equal(t, "id,display_name,installed_evra,available_evra,updatable,tags,"+
 		"baseline_name,baseline_uptodate,template_name,template_uuid,satellite_managed,baseline_id,os,rhsm,"+
-		"update_status,groups,workspace_id,workspace_name", lines[0])
+		"update_status,held_evra,groups,workspace_id,workspace_name", lines[0])
 	assert.Equal(t, "00000000-0000-0000-0000-000000000012,00000000-0000-0000-0000-000000000012,"+
 		"5.6.13-200.fc31.x86_64,5.6.13-201.fc31.x86_64,true,"+
-		"\"[{'key':'k1','namespace':'ns1','value':'val1'}]\",,,,,false,0,RHEL 8.1,8.1,Installable,"+
+		"\"[{'key':'k1','namespace':'ns1','value':'val1'}]\",,,,,false,0,RHEL 8.1,8.1,Installable,,"+
 		"\"[{'id':'00000000-0000-0000-0000-999999999999','name':'root-ws'}]\","+
 		"00000000-0000-0000-0000-999999999999,root-ws",
 		lines[1])
 	assert.Equal(t, "00000000-0000-0000-0000-000000000013,00000000-0000-0000-0000-000000000013,"+
 		"5.6.13-200.fc31.x86_64,,false,\"[{'key':'k1','namespace':'ns1','value':'val1'}]\",,,,,"+
-		"false,0,RHEL 8.2,8.2,None,\"[{'id':'00000000-0000-0000-0000-999999999999','name':'root-ws'}]\","+
+		"false,0,RHEL 8.2,8.2,None,,\"[{'id':'00000000-0000-0000-0000-999999999999','name':'root-ws'}]\","+
 		"00000000-0000-0000-0000-999999999999,root-ws", lines[2])
 }
 
@@ -91,7 +91,7 @@ func packagesQuery(db *gorm.DB, filters map[string]FilterData, acc int, workspac
 // @Produce  json
 // @Param    limit          query        int     false   "Limit for paging" minimum(1) maximum(100)
 // @Param    offset         query        int     false   "Offset for paging"
-// @Param    sort           query        string  false   "Sort field" Enums(id,name,systems_installed,systems_installable,systems_applicable)
+// @Param    sort           query        string  false   "Sort field" Enums(name,systems_installed,systems_installable,systems_applicable)
 // @Param    search         query        string  false   "Find matching text"
 // @Param    filter[name]   query        string  false "Filter"
 // @Param    filter[systems_installed]   query   int     false "Filter"
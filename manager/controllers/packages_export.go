@@ -14,7 +14,7 @@ import (
 // @Security RhIdentity
 // @Accept   json
 // @Produce  json,text/csv
-// @Param    sort           query      string  false   "Sort field" Enums(id,name,systems_installed,systems_installable,systems_applicable)
+// @Param    sort           query      string  false   "Sort field" Enums(name,systems_installed,systems_installable,systems_applicable)
 // @Param    search         query      string  false   "Find matching text"
 // @Param    filter[name]    query     string  false "Filter"
 // @Param    filter[systems_installed]   query string  false "Filter"
@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type PatchPlanSelection struct {
+	// List of IDs, inventory IDs for systems and advisory names for advisories
+	IDs []string `json:"ids" example:"id1, id2, ..."`
+	// Filters in the same format as `filter[...]` query params of the systems or system advisories list
+	Filter map[string]string `json:"filter" example:"stale:false"`
+	// Tag filter in 'namespace/key=val' format, applicable to systems only
+	Tags []string `json:"tags" example:"ns1/k1=val1"`
+}
+
+type PatchPlanCreateRequest struct {
+	// Patch plan name
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	// Scheduled maintenance window
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+	// Systems to patch, all systems when empty
+	Systems PatchPlanSelection `json:"systems"`
+	// Advisories to apply, all advisories of selected systems when empty
+	Advisories PatchPlanSelection `json:"advisories"`
+}
+
+func (r *PatchPlanCreateRequest) validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("name must not be empty")
+	}
+	if r.Description != nil && strings.TrimSpace(*r.Description) == "" {
+		r.Description = nil
+	}
+	if r.WindowStart.IsZero() || r.WindowEnd.IsZero() {
+		return errors.New("window_start and window_end must be set")
+	}
+	if !r.WindowStart.Before(r.WindowEnd) {
+		return errors.New("window_start must be before window_end")
+	}
+	if len(r.Advisories.Tags) > 0 {
+		return errors.New("tags are not supported for advisories")
+	}
+	return validateSystemsListIDs(r.Systems.IDs)
+}
+
+// Build filters from request body in the same way as they are parsed from query params
+func (s *PatchPlanSelection) filters(allowedFields database.AttrMap) (Filters, error) {
+	filters := Filters{}
+	for _, t := range s.Tags {
+		if err := updateTagFilter(filters, t); err != nil {
+			return nil, err
+		}
+	}
+	for subject, v := range s.Filter {
+		if err := updateFilter(filters, allowedFields, subject, v); err != nil {
+			return nil, err
+		}
+	}
+	return filters, nil
+}
+
+// @Summary Create a patch plan
+// @Description Create a patch plan for selected systems and advisories with a scheduled maintenance window.
+// @Description System/advisory pairs which are currently reported in system advisories are stored with the plan
+// @Description and the plan progress is tracked as the advisories disappear from re-evaluated systems.
+// @Description Selection which matches no system advisory is rejected.
+// @ID createPatchPlan
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    body    body   PatchPlanCreateRequest true "Request body"
+// @Success 200 {object} PatchPlanDetailResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /patch-plans [post]
+func PatchPlanCreateHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	var req PatchPlanCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid patch plan request "+err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid patch plan request: "+err.Error())
+		return
+	}
+
+	systemFilters, err := req.Systems.filters(SystemsFields)
+	if err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid systems selection: "+err.Error())
+		return
+	}
+	advisoryFilters, err := req.Advisories.filters(SystemAdvisoriesFields)
+	if err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid advisories selection: "+err.Error())
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	plan := models.PatchPlan{
+		RhAccountID: account,
+		Name:        req.Name,
+		Description: req.Description,
+		WindowStart: req.WindowStart,
+		WindowEnd:   req.WindowEnd,
+	}
+	if err = tx.Create(&plan).Error; err != nil {
+		if database.IsPgErrorCode(db, err, gorm.ErrDuplicatedKey) {
+			utils.LogWarnAndResp(c, http.StatusConflict, fmt.Sprintf("Patch plan '%s' already exists", req.Name))
+			return
+		}
+		utils.LogAndRespError(c, err, "Could not create patch plan")
+		return
+	}
+
+	itemsQuery, err := patchPlanItemsQuery(tx, account, workspaceIDs, plan.ID, &req, systemFilters, advisoryFilters)
+	if err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid patch plan selection: "+err.Error())
+		return
+	}
+	inserted := tx.Exec("INSERT INTO patch_plan_item (rh_account_id, plan_id, system_id, advisory_id) ?", itemsQuery)
+	if inserted.Error != nil {
+		utils.LogAndRespError(c, inserted.Error, "Could not create patch plan")
+		return
+	}
+	// plan without items would be reported as completed right away
+	if inserted.RowsAffected == 0 {
+		err = errors.New("no system advisories selected")
+		utils.LogAndRespBadRequest(c, err, "Invalid patch plan selection: "+err.Error())
+		return
+	}
+
+	var plans []PatchPlansDBLookup
+	err = patchPlansQuery(tx, account, workspaceIDs).Where("pp.id = ?", plan.ID).Find(&plans).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not create patch plan")
+		return
+	}
+
+	data, _ := patchPlansData(plans)
+	c.JSON(http.StatusOK, &PatchPlanDetailResponse{Data: data[0]})
+}
+
+// Select ids of systems matching the selection, IDs have to be validated by validateSystemsListIDs
+func (s *PatchPlanSelection) systemsQuery(tx *gorm.DB, account int, workspaceIDs []string,
+	systemFilters Filters) (*gorm.DB, error) {
+	systems := database.Systems(tx, account, workspaceIDs, database.JoinTemplates, database.JoinBaselines).
+		Select("si.id")
+	if len(s.IDs) > 0 {
+		inventoryIDs := make([]uuid.UUID, 0, len(s.IDs))
+		for _, id := range s.IDs {
+			inventoryIDs = append(inventoryIDs, uuid.MustParse(id)) // already validated
+		}
+		systems = systems.Where("si.inventory_id IN (?)", inventoryIDs)
+	}
+	// inventory and tag filters are validated by s.filters(), unknown filters are rejected with 400 there
+	systems, _ = ApplyInventoryFilter(systemFilters, systems, "si.inventory_id")
+	return systemFilters.Apply(systems, SystemsFields)
+}
+
+// Select system/advisory pairs from system_advisories matching the plan selection
+func patchPlanItemsQuery(tx *gorm.DB, account int, workspaceIDs []string, planID int64, req *PatchPlanCreateRequest,
+	systemFilters, advisoryFilters Filters) (*gorm.DB, error) {
+	systems, err := req.Systems.systemsQuery(tx, account, workspaceIDs, systemFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	items := database.JoinAdvisoryExclusions(
+		database.JoinAdvisoryType(database.JoinAdvisoryMetadata(tx.Table("system_advisories sa")))).
+		Joins("JOIN status ON sa.status_id = status.id").
+		Joins("LEFT JOIN advisory_severity sev ON am.severity_id = sev.id").
+		Select("sa.rh_account_id, ?::bigint, sa.system_id, sa.advisory_id", planID).
+		Where("sa.rh_account_id = ? AND sa.system_id IN (?)", account, systems).
+		// advisories beyond the system baseline are not planned
+		Where("sa.status_id <> 2")
+	if len(req.Advisories.IDs) > 0 {
+		items = items.Where("am.name IN (?)", req.Advisories.IDs)
+	}
+	return advisoryFilters.Apply(items, SystemAdvisoriesFields)
+}
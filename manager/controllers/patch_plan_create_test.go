@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchPlanCreate(t *testing.T) {
+	core.SetupTest(t)
+	data := `{
+		"name": "plan1",
+		"window_start": "2030-01-01T00:00:00Z",
+		"window_end": "2030-01-02T00:00:00Z",
+		"systems": {"ids": ["00000000-0000-0000-0000-000000000001"]},
+		"advisories": {"ids": ["RH-1", "RH-3"]}
+	}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", PatchPlanCreateHandler)
+
+	var output PatchPlanDetailResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	defer database.DeletePatchPlan(t, 1, output.Data.ID)
+	assert.Equal(t, "plan1", output.Data.Attributes.Name)
+	assert.Equal(t, 1, output.Data.Attributes.Systems)
+	assert.Equal(t, 2, output.Data.Attributes.ItemsTotal)
+	assert.Equal(t, "scheduled", output.Data.Attributes.Status)
+	database.CheckPatchPlanItems(t, 1, output.Data.ID, 2)
+
+	// plan names are unique within account
+	w = CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", PatchPlanCreateHandler)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestPatchPlanCreateFilter(t *testing.T) {
+	core.SetupTest(t)
+	data := `{
+		"name": "plan1",
+		"window_start": "2030-01-01T00:00:00Z",
+		"window_end": "2030-01-02T00:00:00Z",
+		"systems": {"filter": {"display_name": "00000000-0000-0000-0000-000000000002"}},
+		"advisories": {"filter": {"advisory_type_name": "enhancement"}}
+	}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", PatchPlanCreateHandler)
+
+	var output PatchPlanDetailResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	defer database.DeletePatchPlan(t, 1, output.Data.ID)
+	// system 2 has only RH-1 which is an enhancement
+	assert.Equal(t, 1, output.Data.Attributes.ItemsTotal)
+}
+
+func TestPatchPlanCreateFilterBaseline(t *testing.T) {
+	core.SetupTest(t)
+	data := `{
+		"name": "plan1",
+		"window_start": "2030-01-01T00:00:00Z",
+		"window_end": "2030-01-02T00:00:00Z",
+		"systems": {"filter": {"baseline_name": "baseline1-1"}}
+	}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", PatchPlanCreateHandler)
+
+	var output PatchPlanDetailResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	defer database.DeletePatchPlan(t, 1, output.Data.ID)
+	// only system 6 is assigned to baseline1-1, it has only RH-1
+	assert.Equal(t, 1, output.Data.Attributes.Systems)
+	assert.Equal(t, 1, output.Data.Attributes.Advisories)
+	assert.Equal(t, 1, output.Data.Attributes.ItemsTotal)
+	database.CheckPatchPlanItems(t, 1, output.Data.ID, 1)
+}
+
+func TestPatchPlanCreateEmpty(t *testing.T) {
+	core.SetupTest(t)
+	data := `{
+		"name": "plan1",
+		"window_start": "2030-01-01T00:00:00Z",
+		"window_end": "2030-01-02T00:00:00Z",
+		"systems": {"ids": ["00000000-0000-0000-0000-000000000002"]},
+		"advisories": {"ids": ["RH-3"]}
+	}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", PatchPlanCreateHandler)
+
+	var errResp utils.ErrorResponse
+	CheckResponse(t, w, http.StatusBadRequest, &errResp)
+	assert.Equal(t, "Invalid patch plan selection: no system advisories selected", errResp.Error)
+	// plan is rolled back with the items
+	var count int64
+	assert.Nil(t, database.DB.Model(&models.PatchPlan{}).Where("rh_account_id = 1 AND name = 'plan1'").
+		Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestPatchPlanCreateInvalidWindow(t *testing.T) {
+	core.SetupTest(t)
+	data := `{
+		"name": "plan1",
+		"window_start": "2030-01-02T00:00:00Z",
+		"window_end": "2030-01-01T00:00:00Z"
+	}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", PatchPlanCreateHandler)
+
+	var errResp utils.ErrorResponse
+	CheckResponse(t, w, http.StatusBadRequest, &errResp)
+	assert.Equal(t, "Invalid patch plan request: window_start must be before window_end", errResp.Error)
+}
+
+func TestPatchPlanCreateInvalidFilter(t *testing.T) {
+	core.SetupTest(t)
+	data := `{
+		"name": "plan1",
+		"window_start": "2030-01-01T00:00:00Z",
+		"window_end": "2030-01-02T00:00:00Z",
+		"systems": {"filter": {"unknown": "1"}}
+	}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", PatchPlanCreateHandler)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
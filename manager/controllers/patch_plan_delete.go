@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Delete a patch plan
+// @Description Delete a patch plan by given plan id
+// @ID deletePatchPlan
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    plan_id    path    int     true    "Patch plan ID"
+// @Success 200
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /patch-plans/{plan_id} [delete]
+func PatchPlanDeleteHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+
+	planID, err := parsePatchPlanID(c)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	// plan items are removed by ON DELETE CASCADE
+	query := db.Where("rh_account_id = ? AND id = ?", account, planID).Delete(&models.PatchPlan{})
+	if err := query.Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not delete patch plan")
+		return
+	}
+	if query.RowsAffected == 0 {
+		utils.LogAndRespNotFound(c, errors.New("no rows returned"), "Patch plan not found")
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchPlanDelete(t *testing.T) {
+	core.SetupTest(t)
+	planID := database.CreatePatchPlan(t, 1, "plan1", patchPlanItems)
+
+	w := CreateRequestRouterWithParams("DELETE", "/:plan_id", fmt.Sprint(planID), "", nil, "",
+		PatchPlanDeleteHandler, 1)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	database.CheckPatchPlanItems(t, 1, planID, 0)
+}
+
+func TestPatchPlanDeleteNotFound(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("DELETE", "/:plan_id", "999999", "", nil, "",
+		PatchPlanDeleteHandler, 1)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type PatchPlanDetailResponse struct {
+	Data PatchPlanItem `json:"data"`
+}
+
+func parsePatchPlanID(c *gin.Context) (int64, error) {
+	planID, err := strconv.ParseInt(c.Param("plan_id"), 10, 64)
+	if err != nil {
+		utils.LogAndRespBadRequest(c, err, "incorrect plan_id format")
+		return 0, err
+	}
+	return planID, nil
+}
+
+func getPatchPlan(c *gin.Context, tx *gorm.DB, account int, planID int64) (*models.PatchPlan, error) {
+	var plan models.PatchPlan
+	err := tx.Where("rh_account_id = ? AND id = ?", account, planID).
+		// use Find() not First() otherwise it returns error "no rows found" if plan is not present
+		Find(&plan).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return nil, err
+	}
+	if plan.ID == 0 {
+		err := errors.New("Patch plan not found")
+		utils.LogAndRespNotFound(c, err, err.Error())
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// @Summary Show me details of a patch plan by given plan id
+// @Description Show me details of a patch plan including its status and completion percentage
+// @ID detailPatchPlan
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    plan_id    path    int     true    "Patch plan ID"
+// @Success 200 {object} PatchPlanDetailResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /patch-plans/{plan_id} [get]
+func PatchPlanDetailHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	planID, err := parsePatchPlanID(c)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	var plans []PatchPlansDBLookup
+	err = patchPlansQuery(db, account, workspaceIDs).Where("pp.id = ?", planID).Find(&plans).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+	if len(plans) == 0 {
+		utils.LogAndRespNotFound(c, errors.New("no rows returned"), "Patch plan not found")
+		return
+	}
+
+	data, _ := patchPlansData(plans)
+	c.JSON(http.StatusOK, &PatchPlanDetailResponse{Data: data[0]})
+}
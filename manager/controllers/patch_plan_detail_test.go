@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchPlanDetail(t *testing.T) {
+	core.SetupTest(t)
+	planID := database.CreatePatchPlan(t, 1, "plan1", patchPlanItems)
+	defer database.DeletePatchPlan(t, 1, planID)
+
+	w := CreateRequestRouterWithParams("GET", "/:plan_id", fmt.Sprint(planID), "", nil, "",
+		PatchPlanDetailHandler, 1)
+
+	var output PatchPlanDetailResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, planID, output.Data.ID)
+	assert.Equal(t, "plan1", output.Data.Attributes.Name)
+	assert.Equal(t, 4, output.Data.Attributes.ItemsTotal)
+	assert.Equal(t, 3, output.Data.Attributes.ItemsRemaining)
+}
+
+func TestPatchPlanDetailNotFound(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:plan_id", "999999", "", nil, "", PatchPlanDetailHandler, 1)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPatchPlanDetailBadID(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:plan_id", "abc", "", nil, "", PatchPlanDetailHandler, 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
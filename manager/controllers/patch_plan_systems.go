@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var PatchPlanSystemFields = database.MustGetQueryAttrs(&PatchPlanSystemsDBLookup{})
+var PatchPlanSystemSelect = database.MustGetSelect(&PatchPlanSystemsDBLookup{})
+var PatchPlanSystemOpts = ListOpts{
+	Fields:         PatchPlanSystemFields,
+	DefaultFilters: map[string]FilterData{},
+	DefaultSort:    "display_name",
+	StableSort:     "si.id",
+	SearchFields:   []string{"si.display_name"},
+}
+
+type PatchPlanSystemsDBLookup struct {
+	SystemIDAttribute
+	// a helper to get total number of systems
+	MetaTotalHelper
+	PatchPlanSystemAttributes
+}
+
+// nolint: lll
+type PatchPlanSystemAttributes struct {
+	SystemDisplayName
+	OSAttributes
+	SystemTags
+	SystemGroups
+	SystemWorkspace
+	SystemLastUpload
+	LastEvaluation *time.Time `json:"last_evaluation" csv:"last_evaluation" query:"spatch.last_evaluation" gorm:"column:last_evaluation"`
+	// Count of plan advisories selected for the system and of those which are still not remediated
+	ItemsTotal     int `json:"items_total" csv:"items_total" query:"ppi.items_total" gorm:"column:items_total"`
+	ItemsRemaining int `json:"items_remaining" csv:"items_remaining" query:"ppi.items_remaining" gorm:"column:items_remaining"`
+	// Percentage of remediated plan advisories
+	Completion int `json:"completion" csv:"completion" query:"100 * (ppi.items_total - ppi.items_remaining) / ppi.items_total" gorm:"column:completion"`
+}
+
+type PatchPlanSystemItem struct {
+	Attributes PatchPlanSystemAttributes `json:"attributes"`
+	// Inventory ID of the system (uuid format)
+	ID uuid.UUID `json:"id"`
+	// Document type name
+	Type string `json:"type"`
+}
+
+type PatchPlanSystemsResponse struct {
+	Data  []PatchPlanSystemItem `json:"data"`
+	Links Links                 `json:"links"`
+	Meta  ListMeta              `json:"meta"`
+}
+
+// nolint: lll
+// @Summary Show me progress of systems in a patch plan
+// @Description Show me systems selected by a patch plan and how many of the plan advisories are already applied
+// @ID listPatchPlanSystems
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    plan_id        path    int     true    "Patch plan ID"
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field"    Enums(id,display_name,os,last_upload,last_evaluation,items_total,items_remaining,completion)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[display_name]       query   string  false "Filter"
+// @Param    filter[os]                 query   string  false "Filter"
+// @Param    filter[items_remaining]    query   int     false "Filter"
+// @Param    filter[completion]         query   int     false "Filter"
+// @Param    tags                       query   []string  false "Tag filter"
+// @Param    filter[group_name]         query   []string  false "Filter systems by inventory groups"
+// @Success 200 {object} PatchPlanSystemsResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /patch-plans/{plan_id}/systems [get]
+func PatchPlanSystemsHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	planID, err := parsePatchPlanID(c)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	plan, err := getPatchPlan(c, db, account, planID)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	filters, err := ParseAllFilters(c, PatchPlanSystemOpts)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	query := patchPlanSystemsQuery(db, account, workspaceIDs, plan.ID)
+	query, _ = ApplyInventoryFilter(filters, query, "si.inventory_id")
+	query, meta, params, err := ListCommon(query, c, filters, PatchPlanSystemOpts)
+	if err != nil {
+		// Error handling and setting of result code & content is done in ListCommon
+		return
+	}
+
+	var systems []PatchPlanSystemsDBLookup
+	err = query.Find(&systems).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	data, total := patchPlanSystemsData(systems)
+	meta, links, err := UpdateMetaLinks(c, meta, total, nil, params...)
+	if err != nil {
+		return // Error handled in method itself
+	}
+	var resp = PatchPlanSystemsResponse{
+		Data:  data,
+		Links: *links,
+		Meta:  *meta,
+	}
+	c.JSON(http.StatusOK, &resp)
+}
+
+func patchPlanSystemsQuery(db *gorm.DB, account int, workspaceIDs []string, planID int64) *gorm.DB {
+	subq := db.Table("patch_plan_item ppi").
+		Joins(`LEFT JOIN system_advisories sa ON sa.rh_account_id = ppi.rh_account_id
+			AND sa.system_id = ppi.system_id AND sa.advisory_id = ppi.advisory_id`).
+		Where("ppi.rh_account_id = ? AND ppi.plan_id = ?", account, planID).
+		Select("ppi.system_id, count(*) AS items_total, count(sa.advisory_id) AS items_remaining").
+		Group("ppi.system_id")
+
+	return database.Systems(db, account, workspaceIDs).
+		Joins("JOIN (?) ppi ON ppi.system_id = si.id", subq).
+		Select(PatchPlanSystemSelect)
+}
+
+func patchPlanSystemsData(systems []PatchPlanSystemsDBLookup) ([]PatchPlanSystemItem, int) {
+	var total int
+	if len(systems) > 0 {
+		total = systems[0].Total
+	}
+	data := make([]PatchPlanSystemItem, len(systems))
+	for i, system := range systems {
+		data[i] = PatchPlanSystemItem{
+			Attributes: system.PatchPlanSystemAttributes,
+			ID:         system.ID,
+			Type:       "patch_plan_system",
+		}
+	}
+	return data, total
+}
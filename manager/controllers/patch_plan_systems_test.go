@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchPlanSystems(t *testing.T) {
+	core.SetupTest(t)
+	planID := database.CreatePatchPlan(t, 1, "plan1", patchPlanItems)
+	defer database.DeletePatchPlan(t, 1, planID)
+
+	w := CreateRequestRouterWithParams("GET", "/:plan_id/systems", fmt.Sprint(planID), "", nil, "",
+		PatchPlanSystemsHandler, 1)
+
+	var output PatchPlanSystemsResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 2, len(output.Data))
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", output.Data[0].ID.String())
+	assert.Equal(t, "patch_plan_system", output.Data[0].Type)
+	assert.Equal(t, 2, output.Data[0].Attributes.ItemsRemaining)
+	assert.Equal(t, 0, output.Data[0].Attributes.Completion)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000002", output.Data[1].ID.String())
+	assert.Equal(t, 1, output.Data[1].Attributes.ItemsRemaining)
+	assert.Equal(t, 50, output.Data[1].Attributes.Completion)
+}
+
+func TestPatchPlanSystemsNotFound(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:plan_id/systems", "999999", "", nil, "",
+		PatchPlanSystemsHandler, 1)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var PatchPlanFields = database.MustGetQueryAttrs(&PatchPlansDBLookup{})
+var PatchPlanSelect = database.MustGetSelect(&PatchPlansDBLookup{})
+var PatchPlanOpts = ListOpts{
+	Fields:         PatchPlanFields,
+	DefaultFilters: nil,
+	DefaultSort:    "window_start",
+	StableSort:     "pp.id",
+	SearchFields:   []string{"pp.name"},
+}
+
+type PatchPlansDBLookup struct {
+	ID int64 `json:"id" csv:"id" query:"pp.id" gorm:"column:id"`
+	// a helper to get total number of plans
+	MetaTotalHelper
+
+	PatchPlanItemAttributes
+}
+
+// nolint: lll
+type PatchPlanItemAttributes struct {
+	// Patch plan name
+	Name        string  `json:"name" csv:"name" query:"pp.name" gorm:"column:name"`
+	Description *string `json:"description" csv:"description" query:"pp.description" gorm:"column:description"`
+	// Scheduled maintenance window
+	WindowStart time.Time `json:"window_start" csv:"window_start" query:"pp.window_start" gorm:"column:window_start"`
+	WindowEnd   time.Time `json:"window_end" csv:"window_end" query:"pp.window_end" gorm:"column:window_end"`
+	Created     time.Time `json:"created" csv:"created" query:"pp.created" gorm:"column:created"`
+	// Count of systems and advisories covered by the plan
+	Systems    int `json:"systems" csv:"systems" query:"coalesce(ppi.systems, 0)" gorm:"column:systems"`
+	Advisories int `json:"advisories" csv:"advisories" query:"coalesce(ppi.advisories, 0)" gorm:"column:advisories"`
+	// Count of system/advisory pairs selected by the plan and of those which are still not remediated
+	ItemsTotal     int `json:"items_total" csv:"items_total" query:"coalesce(ppi.items_total, 0)" gorm:"column:items_total"`
+	ItemsRemaining int `json:"items_remaining" csv:"items_remaining" query:"coalesce(ppi.items_remaining, 0)" gorm:"column:items_remaining"`
+	// Percentage of remediated system/advisory pairs
+	Completion int `json:"completion" csv:"completion" query:"CASE WHEN coalesce(ppi.items_total, 0) = 0 THEN 100 ELSE 100 * (ppi.items_total - ppi.items_remaining) / ppi.items_total END" gorm:"column:completion"`
+	// Plan status - scheduled, in_progress, overdue or completed
+	Status string `json:"status" csv:"status" query:"CASE WHEN coalesce(ppi.items_remaining, 0) = 0 THEN 'completed' WHEN now() < pp.window_start THEN 'scheduled' WHEN now() <= pp.window_end THEN 'in_progress' ELSE 'overdue' END" gorm:"column:status"`
+}
+
+type PatchPlanItem struct {
+	Attributes PatchPlanItemAttributes `json:"attributes"` // Additional patch plan attributes
+	ID         int64                   `json:"id"`         // Unique patch plan id
+	Type       string                  `json:"type"`       // Document type name
+}
+
+type PatchPlansResponse struct {
+	Data  []PatchPlanItem `json:"data"`  // Patch plan items
+	Links Links           `json:"links"` // Pagination links
+	Meta  ListMeta        `json:"meta"`  // Generic response fields (pagination params, filters etc.)
+}
+
+// @Summary Show all patch plans for an account
+// @Description Show all patch plans for an account together with their progress
+// @ID listPatchPlans
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field"    Enums(id,name,window_start,window_end,created,systems,advisories,completion,status)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[id]             query   int     false "Filter"
+// @Param    filter[name]           query   string  false "Filter"
+// @Param    filter[window_start]   query   string  false "Filter"
+// @Param    filter[window_end]     query   string  false "Filter"
+// @Param    filter[completion]     query   int     false "Filter"
+// @Param    filter[status]         query   string  false "Filter" Enums(scheduled,in_progress,overdue,completed)
+// @Success 200 {object} PatchPlansResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /patch-plans [get]
+func PatchPlansListHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+	filters, err := ParseAllFilters(c, PatchPlanOpts)
+	if err != nil {
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	query := patchPlansQuery(db, account, workspaceIDs)
+
+	query, meta, params, err := ListCommon(query, c, filters, PatchPlanOpts)
+	if err != nil {
+		// Error handling and setting of result code & content is done in ListCommon
+		return
+	}
+
+	var plans []PatchPlansDBLookup
+	err = query.Find(&plans).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	data, total := patchPlansData(plans)
+	meta, links, err := UpdateMetaLinks(c, meta, total, nil, params...)
+	if err != nil {
+		return // Error handled in method itself
+	}
+
+	resp := PatchPlansResponse{
+		Data:  data,
+		Links: *links,
+		Meta:  *meta,
+	}
+	c.JSON(http.StatusOK, &resp)
+}
+
+// Plans with progress computed from system_advisories of systems in user's workspaces,
+// plan item which is no longer in system_advisories has been remediated
+func patchPlansQuery(db *gorm.DB, account int, workspaceIDs []string) *gorm.DB {
+	subq := database.Systems(db, account, workspaceIDs).
+		Joins("JOIN patch_plan_item ppi ON ppi.rh_account_id = si.rh_account_id AND ppi.system_id = si.id").
+		Joins(`LEFT JOIN system_advisories sa ON sa.rh_account_id = ppi.rh_account_id
+			AND sa.system_id = ppi.system_id AND sa.advisory_id = ppi.advisory_id`).
+		Select(`ppi.plan_id, count(DISTINCT ppi.system_id) AS systems, count(DISTINCT ppi.advisory_id) AS advisories,
+			count(*) AS items_total, count(sa.advisory_id) AS items_remaining`).
+		Group("ppi.plan_id")
+
+	query := db.Table("patch_plan pp").
+		Select(PatchPlanSelect).
+		Joins("LEFT JOIN (?) ppi ON ppi.plan_id = pp.id", subq).
+		Where("pp.rh_account_id = ?", account)
+	return query
+}
+
+func patchPlansData(plans []PatchPlansDBLookup) ([]PatchPlanItem, int) {
+	var total int
+	if len(plans) > 0 {
+		total = plans[0].Total
+	}
+	data := make([]PatchPlanItem, len(plans))
+	for i, plan := range plans {
+		data[i] = PatchPlanItem{
+			Attributes: plan.PatchPlanItemAttributes,
+			ID:         plan.ID,
+			Type:       "patch_plan",
+		}
+	}
+	return data, total
+}
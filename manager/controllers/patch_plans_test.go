@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// system 1 has RH-1 and RH-3 in system_advisories, system 2 only RH-1
+var patchPlanItems = map[int64][]int64{1: {1, 3}, 2: {1, 3}}
+
+func TestPatchPlansList(t *testing.T) {
+	core.SetupTest(t)
+	planID := database.CreatePatchPlan(t, 1, "plan1", patchPlanItems)
+	defer database.DeletePatchPlan(t, 1, planID)
+
+	w := CreateRequest("GET", "/", nil, "", PatchPlansListHandler)
+
+	var output PatchPlansResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 1, len(output.Data))
+	assert.Equal(t, planID, output.Data[0].ID)
+	assert.Equal(t, "patch_plan", output.Data[0].Type)
+	assert.Equal(t, "plan1", output.Data[0].Attributes.Name)
+	assert.Equal(t, 2, output.Data[0].Attributes.Systems)
+	assert.Equal(t, 2, output.Data[0].Attributes.Advisories)
+	assert.Equal(t, 4, output.Data[0].Attributes.ItemsTotal)
+	assert.Equal(t, 3, output.Data[0].Attributes.ItemsRemaining)
+	assert.Equal(t, 25, output.Data[0].Attributes.Completion)
+	assert.Equal(t, "in_progress", output.Data[0].Attributes.Status)
+	assert.Equal(t, 1, output.Meta.TotalItems)
+}
+
+func TestPatchPlansListFilterStatus(t *testing.T) {
+	core.SetupTest(t)
+	planID := database.CreatePatchPlan(t, 1, "plan1", patchPlanItems)
+	defer database.DeletePatchPlan(t, 1, planID)
+
+	w := CreateRequest("GET", "/?filter[status]=completed", nil, "", PatchPlansListHandler)
+
+	var output PatchPlansResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 0, len(output.Data))
+}
+
+func TestPatchPlansListWrongSort(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequest("GET", "/?sort=unknown_key", nil, "", PatchPlansListHandler)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
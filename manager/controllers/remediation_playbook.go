@@ -0,0 +1,355 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	RemediationFormatAnsible = "ansible"
+	RemediationFormatShell   = "shell"
+)
+
+type RemediationPlaybookRequest struct {
+	// Inventory IDs of systems to remediate
+	Systems []string `json:"systems" example:"system1-uuid, system2-uuid, ..."`
+	// Advisories to apply, all installable advisories are applied when both advisories and packages are empty
+	Advisories []string `json:"advisories" example:"RHSA-2021:3801, ..."`
+	// Packages to update to their installable version
+	Packages []string `json:"packages" example:"kernel, firefox, ..."`
+	// Output format, ansible playbook (default) or dnf shell script
+	Format string `json:"format" enums:"ansible,shell"`
+}
+
+type remediationDBLookup struct {
+	InventoryID string `gorm:"column:inventory_id"`
+	DisplayName string `gorm:"column:display_name"`
+	Name        string `gorm:"column:name"`
+	// installable version of package
+	EVRA           string `gorm:"column:evra"`
+	IsPackage      bool   `gorm:"column:is_package"`
+	RebootRequired bool   `gorm:"column:reboot_required"`
+	// package with updates held by a package hold
+	IsHeld bool `gorm:"column:is_held"`
+}
+
+type remediationHost struct {
+	InventoryID string
+	DisplayName string
+}
+
+type remediationActions struct {
+	Advisories []string
+	Packages   []string
+	// held packages excluded from advisory updates
+	Excludes []string
+	Reboot   bool
+}
+
+func (a *remediationActions) key() string {
+	return fmt.Sprintf("%s|%s|%s|%t", strings.Join(a.Advisories, ","), strings.Join(a.Packages, ","),
+		strings.Join(a.Excludes, ","), a.Reboot)
+}
+
+// Hosts which require the same remediation actions
+type remediationGroup struct {
+	remediationActions
+	Hosts []remediationHost
+}
+
+func (r *RemediationPlaybookRequest) validate() error {
+	if len(r.Systems) == 0 {
+		return errors.New("systems must not be empty")
+	}
+	switch r.Format {
+	case "":
+		r.Format = RemediationFormatAnsible
+	case RemediationFormatAnsible, RemediationFormatShell:
+	default:
+		return fmt.Errorf("unknown format '%s'", r.Format)
+	}
+	return validateSystemsListIDs(r.Systems)
+}
+
+// @Summary Generate remediation playbook for selected systems
+// @Description Generate Ansible playbook or dnf shell script applying selected advisories and package updates
+// @Description to selected systems. Hosts requiring the same actions are grouped together and reboot is added
+// @Description for hosts where any of the applied updates requires it. Hosts are identified by inventory ID,
+// @Description packages are pinned to their installable version and packages held by a package hold
+// @Description are excluded from advisory updates. Advisories covered by an advisory exclusion are not applied.
+// @ID remediationPlaybook
+// @Security RhIdentity
+// @Accept   json
+// @Produce  plain
+// @Param    body    body   RemediationPlaybookRequest true "Request body"
+// @Success 200 {string} string
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /remediations/playbook [post]
+func RemediationPlaybookHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	var req RemediationPlaybookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid request body: "+err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid remediation request: "+err.Error())
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	var items []remediationDBLookup
+	if len(req.Advisories) > 0 || len(req.Packages) == 0 {
+		var advisories []remediationDBLookup
+		if err := remediationAdvisoriesQuery(db, account, workspaceIDs, &req).Find(&advisories).Error; err != nil {
+			utils.LogAndRespError(c, err, "database error")
+			return
+		}
+		items = append(items, advisories...)
+
+		var held []remediationDBLookup
+		if err := remediationHeldQuery(db, account, workspaceIDs, &req).Find(&held).Error; err != nil {
+			utils.LogAndRespError(c, err, "database error")
+			return
+		}
+		items = append(items, held...)
+	}
+	if len(req.Packages) > 0 {
+		var packages []remediationDBLookup
+		if err := remediationPackagesQuery(db, account, workspaceIDs, &req).Find(&packages).Error; err != nil {
+			utils.LogAndRespError(c, err, "database error")
+			return
+		}
+		items = append(items, packages...)
+	}
+
+	if len(items) == 0 {
+		utils.LogAndRespNotFound(c, errors.New("no installable updates"),
+			"No installable updates found for selected systems")
+		return
+	}
+
+	groups := groupRemediations(items)
+	if req.Format == RemediationFormatShell {
+		c.Data(http.StatusOK, "text/x-shellscript; charset=utf-8", []byte(renderShellScript(groups)))
+		return
+	}
+	c.Data(http.StatusOK, "application/x-yaml; charset=utf-8", []byte(renderAnsiblePlaybook(groups)))
+}
+
+func remediationInventoryIDs(systems []string) []uuid.UUID {
+	inventoryIDs := make([]uuid.UUID, 0, len(systems))
+	for _, id := range systems {
+		inventoryIDs = append(inventoryIDs, uuid.MustParse(id)) // already validated
+	}
+	return inventoryIDs
+}
+
+// Installable advisories of selected systems, advisories excluded or with accepted risk are not applied
+func remediationAdvisoriesQuery(db *gorm.DB, account int, workspaceIDs []string,
+	req *RemediationPlaybookRequest) *gorm.DB {
+	query := database.SystemAdvisories(db, account, workspaceIDs, database.JoinAdvisoryMetadata,
+		database.JoinAdvisoryExclusions).
+		Select("si.inventory_id, si.display_name, am.name, false AS is_package, am.reboot_required").
+		Where("sa.status_id = 0 AND aex.kind IS NULL AND si.inventory_id IN (?)",
+			remediationInventoryIDs(req.Systems))
+	if len(req.Advisories) > 0 {
+		query = query.Where("am.name IN (?)", req.Advisories)
+	}
+	return query
+}
+
+// Packages of selected systems with installable update,
+// reboot is required when the advisory of installable package requires it
+func remediationPackagesQuery(db *gorm.DB, account int, workspaceIDs []string,
+	req *RemediationPlaybookRequest) *gorm.DB {
+	return database.SystemPackages(db, account, workspaceIDs).
+		Joins("JOIN package pi ON pi.id = spkg.installable_id").
+		Joins("LEFT JOIN advisory_metadata am ON am.id = pi.advisory_id").
+		Select(`si.inventory_id, si.display_name, pn.name, pi.evra, true AS is_package,
+			coalesce(am.reboot_required, false) AS reboot_required`).
+		Where("si.inventory_id IN (?) AND pn.name IN (?)", remediationInventoryIDs(req.Systems), req.Packages)
+}
+
+// Packages of selected systems with updates held by a package hold,
+// `dnf upgrade --advisory` would update them to the advisory version regardless of the hold
+func remediationHeldQuery(db *gorm.DB, account int, workspaceIDs []string,
+	req *RemediationPlaybookRequest) *gorm.DB {
+	return database.SystemPackages(db, account, workspaceIDs).
+		Select("si.inventory_id, si.display_name, pn.name, true AS is_held").
+		Where("spkg.held_id IS NOT NULL AND si.inventory_id IN (?)", remediationInventoryIDs(req.Systems))
+}
+
+// Collect actions for each host and group hosts requiring the same actions
+func groupRemediations(items []remediationDBLookup) []remediationGroup {
+	hosts := map[remediationHost]*remediationActions{}
+	for _, item := range items {
+		if item.IsHeld {
+			continue
+		}
+		host := remediationHost{InventoryID: item.InventoryID, DisplayName: item.DisplayName}
+		actions, ok := hosts[host]
+		if !ok {
+			actions = &remediationActions{}
+			hosts[host] = actions
+		}
+		if item.IsPackage {
+			// NEVRA pins the update to the installable version instead of the latest one
+			actions.Packages = append(actions.Packages, item.Name+"-"+item.EVRA)
+		} else {
+			actions.Advisories = append(actions.Advisories, item.Name)
+		}
+		actions.Reboot = actions.Reboot || item.RebootRequired
+	}
+	for _, item := range items {
+		// exclusion alone is not an action, only hosts with updates are remediated
+		actions, ok := hosts[remediationHost{InventoryID: item.InventoryID, DisplayName: item.DisplayName}]
+		if item.IsHeld && ok && len(actions.Advisories) > 0 {
+			actions.Excludes = append(actions.Excludes, item.Name)
+		}
+	}
+
+	sortedHosts := make([]remediationHost, 0, len(hosts))
+	for host := range hosts {
+		sortedHosts = append(sortedHosts, host)
+	}
+	sort.Slice(sortedHosts, func(i, j int) bool {
+		if sortedHosts[i].DisplayName != sortedHosts[j].DisplayName {
+			return sortedHosts[i].DisplayName < sortedHosts[j].DisplayName
+		}
+		return sortedHosts[i].InventoryID < sortedHosts[j].InventoryID
+	})
+
+	groups := []remediationGroup{}
+	groupIdx := map[string]int{}
+	for _, host := range sortedHosts {
+		actions := hosts[host]
+		sort.Strings(actions.Advisories)
+		sort.Strings(actions.Packages)
+		sort.Strings(actions.Excludes)
+		key := actions.key()
+		if i, ok := groupIdx[key]; ok {
+			groups[i].Hosts = append(groups[i].Hosts, host)
+			continue
+		}
+		groupIdx[key] = len(groups)
+		groups = append(groups, remediationGroup{remediationActions: *actions, Hosts: []remediationHost{host}})
+	}
+	return groups
+}
+
+// JSON string is a valid YAML double-quoted scalar
+func yamlQuote(s string) string {
+	b, err := sonic.Marshal(s)
+	if err != nil {
+		return strconv.Quote(s)
+	}
+	return string(b)
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func dnfAdvisoryCmd(advisories, excludes []string, quote func(string) string) string {
+	args := make([]string, 0, len(advisories)+len(excludes))
+	for _, a := range advisories {
+		args = append(args, "--advisory="+quote(a))
+	}
+	for _, e := range excludes {
+		args = append(args, "--exclude="+quote(e))
+	}
+	return "dnf upgrade -y " + strings.Join(args, " ")
+}
+
+func renderAnsiblePlaybook(groups []remediationGroup) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	for i, g := range groups {
+		// hosts are inventory IDs, as in Insights remediation playbooks, display names are not inventory hostnames
+		ids := make([]string, 0, len(g.Hosts))
+		for _, h := range g.Hosts {
+			ids = append(ids, h.InventoryID)
+		}
+		fmt.Fprintf(&b, "- name: %s\n", yamlQuote(fmt.Sprintf("Apply updates (group %d)", i+1)))
+		fmt.Fprintf(&b, "  hosts: %s\n", yamlQuote(strings.Join(ids, ",")))
+		b.WriteString("  become: true\n")
+		b.WriteString("  vars:\n")
+		b.WriteString("    insights_display_names:\n")
+		for _, h := range g.Hosts {
+			fmt.Fprintf(&b, "      - %s\n", yamlQuote(h.DisplayName))
+		}
+		b.WriteString("  tasks:\n")
+		if len(g.Advisories) > 0 {
+			b.WriteString("    - name: Apply advisories\n")
+			cmd := dnfAdvisoryCmd(g.Advisories, g.Excludes, shellQuote)
+			fmt.Fprintf(&b, "      ansible.builtin.command: %s\n", yamlQuote(cmd))
+		}
+		if len(g.Packages) > 0 {
+			b.WriteString("    - name: Update packages\n")
+			b.WriteString("      ansible.builtin.dnf:\n")
+			b.WriteString("        name:\n")
+			for _, p := range g.Packages {
+				fmt.Fprintf(&b, "          - %s\n", yamlQuote(p))
+			}
+			b.WriteString("        state: present\n")
+		}
+		if g.Reboot {
+			b.WriteString("    - name: Reboot system\n")
+			b.WriteString("      ansible.builtin.reboot:\n")
+		}
+	}
+	return b.String()
+}
+
+func renderShellScript(groups []remediationGroup) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# usage: <script> <inventory ID of the host>\n")
+	b.WriteString("set -e\n\n")
+	b.WriteString("case \"${1:-$INSIGHTS_INVENTORY_ID}\" in\n")
+	for _, g := range groups {
+		ids := make([]string, 0, len(g.Hosts))
+		for _, h := range g.Hosts {
+			ids = append(ids, shellQuote(h.InventoryID))
+			fmt.Fprintf(&b, "# %s\n", h.DisplayName)
+		}
+		fmt.Fprintf(&b, "%s)\n", strings.Join(ids, "|"))
+		if len(g.Advisories) > 0 {
+			fmt.Fprintf(&b, "    %s\n", dnfAdvisoryCmd(g.Advisories, g.Excludes, shellQuote))
+		}
+		if len(g.Packages) > 0 {
+			pkgs := make([]string, 0, len(g.Packages))
+			for _, p := range g.Packages {
+				pkgs = append(pkgs, shellQuote(p))
+			}
+			fmt.Fprintf(&b, "    dnf upgrade -y %s\n", strings.Join(pkgs, " "))
+		}
+		if g.Reboot {
+			b.WriteString("    systemctl reboot\n")
+		}
+		b.WriteString("    ;;\n")
+	}
+	b.WriteString("*)\n")
+	b.WriteString("    echo \"host not in plan\" >&2\n")
+	b.WriteString("    exit 1\n")
+	b.WriteString("    ;;\n")
+	b.WriteString("esac\n")
+	return b.String()
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRemediationItems = []remediationDBLookup{
+	{InventoryID: "00000000-0000-0000-0000-000000000002", DisplayName: "host2", Name: "RH-1"},
+	{InventoryID: "00000000-0000-0000-0000-000000000001", DisplayName: "host1", Name: "RH-3", RebootRequired: true},
+	{InventoryID: "00000000-0000-0000-0000-000000000001", DisplayName: "host1", Name: "RH-1"},
+	{InventoryID: "00000000-0000-0000-0000-000000000003", DisplayName: "host3", Name: "RH-1"},
+	{InventoryID: "00000000-0000-0000-0000-000000000003", DisplayName: "host3", Name: "kernel",
+		EVRA: "5.6.13-200.fc31.x86_64", IsPackage: true},
+	{InventoryID: "00000000-0000-0000-0000-000000000004", DisplayName: "host4", Name: "RH-1"},
+}
+
+func TestGroupRemediations(t *testing.T) {
+	groups := groupRemediations(testRemediationItems)
+	assert.Equal(t, 3, len(groups))
+	assert.Equal(t, []string{"RH-1", "RH-3"}, groups[0].Advisories)
+	assert.True(t, groups[0].Reboot)
+	assert.Equal(t, 1, len(groups[0].Hosts))
+	assert.Equal(t, []remediationHost{
+		{InventoryID: "00000000-0000-0000-0000-000000000002", DisplayName: "host2"},
+		{InventoryID: "00000000-0000-0000-0000-000000000004", DisplayName: "host4"},
+	}, groups[1].Hosts)
+	assert.False(t, groups[1].Reboot)
+	assert.Equal(t, []string{"kernel-5.6.13-200.fc31.x86_64"}, groups[2].Packages)
+}
+
+func TestRenderAnsiblePlaybook(t *testing.T) {
+	playbook := renderAnsiblePlaybook(groupRemediations(testRemediationItems[:3]))
+	assert.Equal(t, `---
+- name: "Apply updates (group 1)"
+  hosts: "00000000-0000-0000-0000-000000000001"
+  become: true
+  vars:
+    insights_display_names:
+      - "host1"
+  tasks:
+    - name: Apply advisories
+      ansible.builtin.command: "dnf upgrade -y --advisory='RH-1' --advisory='RH-3'"
+    - name: Reboot system
+      ansible.builtin.reboot:
+- name: "Apply updates (group 2)"
+  hosts: "00000000-0000-0000-0000-000000000002"
+  become: true
+  vars:
+    insights_display_names:
+      - "host2"
+  tasks:
+    - name: Apply advisories
+      ansible.builtin.command: "dnf upgrade -y --advisory='RH-1'"
+`, playbook)
+}
+
+func TestRenderAnsiblePlaybookPackages(t *testing.T) {
+	playbook := renderAnsiblePlaybook(groupRemediations(testRemediationItems[4:5]))
+	assert.Contains(t, playbook, `      ansible.builtin.dnf:
+        name:
+          - "kernel-5.6.13-200.fc31.x86_64"
+        state: present
+`)
+}
+
+func TestRenderShellScript(t *testing.T) {
+	script := renderShellScript(groupRemediations(testRemediationItems[3:5]))
+	assert.Equal(t, `#!/bin/sh
+# usage: <script> <inventory ID of the host>
+set -e
+
+case "${1:-$INSIGHTS_INVENTORY_ID}" in
+# host3
+'00000000-0000-0000-0000-000000000003')
+    dnf upgrade -y --advisory='RH-1'
+    dnf upgrade -y 'kernel-5.6.13-200.fc31.x86_64'
+    ;;
+*)
+    echo "host not in plan" >&2
+    exit 1
+    ;;
+esac
+`, script)
+}
+
+func TestRemediationPlaybook(t *testing.T) {
+	core.SetupTest(t)
+	database.SetAdvisoriesRebootRequired(t, []int64{3}, true)
+	defer database.SetAdvisoriesRebootRequired(t, []int64{3}, false)
+
+	data := `{"systems": ["00000000-0000-0000-0000-000000000001"], "advisories": ["RH-1", "RH-2", "RH-3"]}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", RemediationPlaybookHandler)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	// RH-2 is only applicable
+	assert.Contains(t, w.Body.String(), "dnf upgrade -y --advisory='RH-1' --advisory='RH-3'")
+	assert.Contains(t, w.Body.String(), "ansible.builtin.reboot:")
+}
+
+func TestGroupRemediationsHeld(t *testing.T) {
+	items := append([]remediationDBLookup{}, testRemediationItems[3:5]...)
+	items = append(items,
+		remediationDBLookup{InventoryID: "00000000-0000-0000-0000-000000000003", DisplayName: "host3",
+			Name: "firefox", IsHeld: true},
+		// held package of host without advisories is not an action
+		remediationDBLookup{InventoryID: "00000000-0000-0000-0000-000000000005", DisplayName: "host5",
+			Name: "firefox", IsHeld: true})
+	groups := groupRemediations(items)
+	assert.Equal(t, 1, len(groups))
+	assert.Equal(t, []string{"firefox"}, groups[0].Excludes)
+	assert.Contains(t, renderShellScript(groups), "dnf upgrade -y --advisory='RH-1' --exclude='firefox'\n")
+}
+
+func TestRemediationPlaybookHeld(t *testing.T) {
+	core.SetupTest(t)
+	// kernel update of system 1 is held
+	held := models.SystemPackage{RhAccountID: 1, SystemID: 1, PackageID: 1, NameID: 101, HeldID: utils.PtrInt64(11)}
+	assert.NoError(t, database.DB.Create(&held).Error)
+	defer database.DB.Delete(&held)
+
+	data := `{"systems": ["00000000-0000-0000-0000-000000000001"], "advisories": ["RH-1"], "format": "shell"}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", RemediationPlaybookHandler)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "dnf upgrade -y --advisory='RH-1' --exclude='kernel'\n")
+}
+
+func TestRemediationPlaybookExcluded(t *testing.T) {
+	core.SetupTest(t)
+	exclusionID := database.CreateAdvisoryExclusion(t, 1, 3, nil, []int64{1})
+	defer database.DeleteAdvisoryExclusion(t, 1, exclusionID)
+
+	data := `{"systems": ["00000000-0000-0000-0000-000000000001"], "advisories": ["RH-1", "RH-3"], "format": "shell"}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", RemediationPlaybookHandler)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	// RH-3 is excluded on the system
+	assert.Contains(t, w.Body.String(), "dnf upgrade -y --advisory='RH-1'\n")
+}
+
+func TestRemediationPlaybookNotFound(t *testing.T) {
+	core.SetupTest(t)
+	data := `{"systems": ["00000000-0000-0000-0000-000000000001"], "advisories": ["RH-2"], "format": "shell"}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", RemediationPlaybookHandler)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRemediationPlaybookInvalid(t *testing.T) {
+	core.SetupTest(t)
+	data := `{"systems": ["00000000-0000-0000-0000-000000000001"], "format": "puppet"}`
+	w := CreateRequest("POST", "/", bytes.NewBufferString(data), "application/json", RemediationPlaybookHandler)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = CreateRequest("POST", "/", bytes.NewBufferString(`{"systems": []}`), "application/json",
+		RemediationPlaybookHandler)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/config"
+	"app/manager/kafka"
+	"app/manager/middlewares"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RepoClassificationRequest struct {
+	// Org classification of the repository overriding the global third party flag
+	Classification string `json:"classification" enums:"trusted,third_party,ignored"`
+}
+
+type RepoClassificationResponse struct {
+	Name           string `json:"name"`
+	Classification string `json:"classification"`
+}
+
+func (r *RepoClassificationRequest) validate() error {
+	switch r.Classification {
+	case models.RepoClassificationTrusted, models.RepoClassificationThirdParty, models.RepoClassificationIgnored:
+		return nil
+	}
+	return fmt.Errorf("unknown classification '%s', use trusted, third_party or ignored", r.Classification)
+}
+
+// @Summary Set org classification of a repository
+// @Description Classify repository as trusted, third party or ignored for the org. The classification overrides
+// @Description the global third party flag of the repository when third party systems are evaluated,
+// @Description ignored repository is not used for evaluation of updates at all.
+// @Description Systems of the org using the repository are re-evaluated.
+// @ID updateRepoClassification
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    repo_name  path    string                      true "Repository name"
+// @Param    body       body    RepoClassificationRequest   true "Request body"
+// @Success 200 {object} RepoClassificationResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /repos/{repo_name}/classification [put]
+func RepoClassificationUpdateHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	orgID := c.GetString(utils.KeyOrgID)
+
+	var req RepoClassificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid classification request "+err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		utils.LogAndRespBadRequest(c, err, "Invalid classification request: "+err.Error())
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	repo, err := repoByName(c, db)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	classification := models.RepoClassification{
+		RhAccountID:    account,
+		RepoID:         repo.ID,
+		Classification: req.Classification,
+	}
+	err = database.OnConflictUpdateMulti(db, []string{"rh_account_id", "repo_id"}, "classification").
+		Create(&classification).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "Could not update repo classification")
+		return
+	}
+	if err = recalcRepoSystems(db, account, orgID, repo.ID); err != nil {
+		utils.LogAndRespError(c, err, "Could not re-evaluate repo systems")
+		return
+	}
+	c.JSON(http.StatusOK, &RepoClassificationResponse{Name: repo.Name, Classification: req.Classification})
+}
+
+// @Summary Remove org classification of a repository
+// @Description Remove org classification of a repository, the global third party flag is used again.
+// @Description Systems of the org using the repository are re-evaluated.
+// @ID deleteRepoClassification
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    repo_name  path    string  true "Repository name"
+// @Success 200
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /repos/{repo_name}/classification [delete]
+func RepoClassificationDeleteHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	orgID := c.GetString(utils.KeyOrgID)
+
+	db := middlewares.DBFromContext(c)
+	repo, err := repoByName(c, db)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	query := db.Where("rh_account_id = ? AND repo_id = ?", account, repo.ID).Delete(&models.RepoClassification{})
+	if err := query.Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not delete repo classification")
+		return
+	}
+	if query.RowsAffected == 0 {
+		utils.LogAndRespNotFound(c, errors.New("no rows returned"), "Repo classification not found")
+		return
+	}
+	if err = recalcRepoSystems(db, account, orgID, repo.ID); err != nil {
+		utils.LogAndRespError(c, err, "Could not re-evaluate repo systems")
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// Inventory IDs of the org systems using the repo
+func repoSystems(tx *gorm.DB, account int, repoID int64) ([]uuid.UUID, error) {
+	var inventoryIDs []uuid.UUID
+	err := tx.Table("system_inventory si").
+		Joins("JOIN system_repo sr ON sr.rh_account_id = si.rh_account_id AND sr.system_id = si.id").
+		Where("sr.rh_account_id = ? AND sr.repo_id = ?", account, repoID).
+		Pluck("si.inventory_id", &inventoryIDs).Error
+	return inventoryIDs, err
+}
+
+// Send recalc messages for systems using the repo, classification changes their third party flag and updates
+func recalcRepoSystems(tx *gorm.DB, account int, orgID string, repoID int64) error {
+	if !config.EnableRepoClassificationChangeEval {
+		return nil
+	}
+	inventoryIDs, err := repoSystems(tx, account, repoID)
+	if err != nil {
+		return err
+	}
+	if len(inventoryIDs) > 0 {
+		kafka.RecalcSystems(kafka.InventoryIDs2EvalData(account, orgID, inventoryIDs))
+	}
+	return nil
+}
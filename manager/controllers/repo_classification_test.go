@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoClassificationUpdate(t *testing.T) {
+	core.SetupTest(t)
+	defer database.DeleteRepoClassification(t, 1, 2)
+
+	for _, classification := range []string{models.RepoClassificationThirdParty, models.RepoClassificationIgnored} {
+		data := `{"classification": "` + classification + `"}`
+		w := CreateRequestRouterWithParams("PUT", "/:repo_name/classification", "repo2", "",
+			bytes.NewBufferString(data), "application/json", RepoClassificationUpdateHandler, 1)
+
+		var output RepoClassificationResponse
+		CheckResponse(t, w, http.StatusOK, &output)
+		assert.Equal(t, RepoClassificationResponse{Name: "repo2", Classification: classification}, output)
+	}
+
+	var classifications []models.RepoClassification
+	assert.Nil(t, database.DB.Where("rh_account_id = 1").Find(&classifications).Error)
+	assert.Equal(t, []models.RepoClassification{{RhAccountID: 1, RepoID: 2, Classification: "ignored"}},
+		classifications)
+}
+
+func TestRepoClassificationUpdateInvalid(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("PUT", "/:repo_name/classification", "repo2", "",
+		bytes.NewBufferString(`{"classification": "unknown"}`), "application/json", RepoClassificationUpdateHandler, 1)
+
+	var errResp utils.ErrorResponse
+	CheckResponse(t, w, http.StatusBadRequest, &errResp)
+	assert.Equal(t, "Invalid classification request: unknown classification 'unknown', "+
+		"use trusted, third_party or ignored", errResp.Error)
+}
+
+func TestRepoClassificationUpdateUnknownRepo(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("PUT", "/:repo_name/classification", "unknown-repo", "",
+		bytes.NewBufferString(`{"classification": "trusted"}`), "application/json", RepoClassificationUpdateHandler, 1)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRepoClassificationDelete(t *testing.T) {
+	core.SetupTest(t)
+	database.CreateRepoClassification(t, 1, 2, models.RepoClassificationTrusted)
+
+	w := CreateRequestRouterWithParams("DELETE", "/:repo_name/classification", "repo2", "", nil, "",
+		RepoClassificationDeleteHandler, 1)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = CreateRequestRouterWithParams("DELETE", "/:repo_name/classification", "repo2", "", nil, "",
+		RepoClassificationDeleteHandler, 1)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRepoClassificationSystems(t *testing.T) {
+	core.SetupTest(t)
+	inventoryIDs, err := repoSystems(database.DB, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{uuid.MustParse("00000000-0000-0000-0000-000000000002")}, inventoryIDs)
+}
+
+func TestReposClassification(t *testing.T) {
+	core.SetupTest(t)
+	database.CreateRepoClassification(t, 1, 2, models.RepoClassificationThirdParty)
+	defer database.DeleteRepoClassification(t, 1, 2)
+
+	output := testRepos(t, "/?filter[classification]=third_party")
+	assert.Equal(t, 1, len(output.Data))
+	assert.Equal(t, "repo2", output.Data[0].Name)
+	assert.True(t, output.Data[0].ThirdParty)
+	assert.Equal(t, utils.PtrString(models.RepoClassificationThirdParty), output.Data[0].Classification)
+}
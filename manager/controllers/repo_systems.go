@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var RepoSystemFields = database.MustGetQueryAttrs(&RepoSystemDBLookup{})
+var RepoSystemsSelect = database.MustGetSelect(&RepoSystemDBLookup{})
+var RepoSystemsOpts = ListOpts{
+	Fields:         RepoSystemFields,
+	DefaultFilters: map[string]FilterData{},
+	DefaultSort:    "display_name",
+	StableSort:     "si.id",
+	SearchFields:   []string{"si.display_name"},
+}
+
+// nolint: lll
+type RepoSystemItem struct {
+	SystemIDAttribute
+	SystemDisplayName
+	OSAttributes
+	// System has any third party repository enabled
+	ThirdParty bool `json:"third_party" csv:"third_party" query:"spatch.third_party" gorm:"column:third_party"`
+	SystemLastUpload
+	SystemTags
+	SystemGroups
+	SystemWorkspace
+}
+
+type RepoSystemDBLookup struct {
+	MetaTotalHelper
+	RepoSystemItem
+}
+
+type RepoSystemsResponse struct {
+	Data  []RepoSystemItem `json:"data"`
+	Links Links            `json:"links"`
+	Meta  ListMeta         `json:"meta"`
+}
+
+func repoSystemsQuery(db *gorm.DB, account int, workspaceIDs []string, repoID int64) *gorm.DB {
+	return database.Systems(db, account, workspaceIDs).
+		Joins("JOIN system_repo sr ON sr.system_id = si.id AND sr.rh_account_id = si.rh_account_id").
+		Select(RepoSystemsSelect).
+		Where("si.stale = false").
+		Where("sr.repo_id = ?", repoID)
+}
+
+func repoSystemsCommon(c *gin.Context, export bool) (*gorm.DB, *ListMeta, []string, error) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	db := middlewares.DBFromContext(c)
+	repo, err := repoByName(c, db)
+	if err != nil {
+		return nil, nil, nil, err
+	} // Error handled in method itself
+
+	filters, err := ParseAllFilters(c, RepoSystemsOpts)
+	if err != nil {
+		return nil, nil, nil, err
+	} // Error handled in method itself
+
+	query := repoSystemsQuery(db, account, workspaceIDs, repo.ID)
+	query, _ = ApplyInventoryFilter(filters, query, "si.inventory_id")
+	if export {
+		query, err = ExportListCommon(query, c, RepoSystemsOpts)
+		return query, nil, nil, err
+	}
+	query, meta, params, err := ListCommon(query, c, filters, RepoSystemsOpts)
+	// Error handled in method itself
+	return query, meta, params, err
+}
+
+// nolint: lll
+// @Summary Show me all my systems which have a repository enabled
+// @Description Show me all my systems which have a repository enabled
+// @ID repoSystems
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    repo_name      path    string  true    "Repository name"
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field" Enums(id,display_name,os,rhsm,third_party,last_upload,groups)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[display_name]    query   string  false "Filter"
+// @Param    filter[os]              query   string  false "Filter"
+// @Param    filter[third_party]     query   bool    false "Filter"
+// @Param    filter[last_upload]     query   string  false "Filter"
+// @Param    tags                    query   []string  false "Tag filter"
+// @Param    filter[group_name] 									query []string 	false "Filter systems by inventory groups"
+// @Param    filter[system_profile][sap_system]						query bool  	false "Filter only SAP systems"
+// @Param    filter[system_profile][sap_sids]						query []string  false "Filter systems by their SAP SIDs"
+// @Success 200 {object} RepoSystemsResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /repos/{repo_name}/systems [get]
+func RepoSystemsListHandler(c *gin.Context) {
+	query, meta, params, err := repoSystemsCommon(c, false)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var systems []RepoSystemDBLookup
+	err = query.Find(&systems).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	data, total := repoSystemDBLookups2RepoSystemItems(systems)
+	meta, links, err := UpdateMetaLinks(c, meta, total, nil, params...)
+	if err != nil {
+		return // Error handled in method itself
+	}
+
+	response := RepoSystemsResponse{
+		Data:  data,
+		Links: *links,
+		Meta:  *meta,
+	}
+	c.JSON(http.StatusOK, &response)
+}
+
+// nolint: lll
+// @Summary Show me all my systems which have a repository enabled
+// @Description Show me inventory IDs of all my systems which have a repository enabled
+// @ID repoSystemsIds
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    repo_name      path    string  true    "Repository name"
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field" Enums(id,display_name,os,rhsm,third_party,last_upload,groups)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[display_name]    query   string  false "Filter"
+// @Param    filter[os]              query   string  false "Filter"
+// @Param    filter[third_party]     query   bool    false "Filter"
+// @Param    filter[last_upload]     query   string  false "Filter"
+// @Param    tags                    query   []string  false "Tag filter"
+// @Success 200 {object} IDsPlainResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /ids/repos/{repo_name}/systems [get]
+func RepoSystemsListIDsHandler(c *gin.Context) {
+	query, meta, _, err := repoSystemsCommon(c, false)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var sids []SystemsID
+	err = query.Find(&sids).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	resp, err := systemsIDs(c, sids, meta)
+	if err != nil {
+		return // Error handled in method itself
+	}
+	c.JSON(http.StatusOK, &resp)
+}
+
+func repoSystemDBLookups2RepoSystemItems(systems []RepoSystemDBLookup) ([]RepoSystemItem, int) {
+	var total int
+	if len(systems) > 0 {
+		total = systems[0].Total
+	}
+	data := make([]RepoSystemItem, len(systems))
+	for i := range systems {
+		data[i] = systems[i].RepoSystemItem
+	}
+	return data, total
+}
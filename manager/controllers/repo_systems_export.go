@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"app/base/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// nolint: lll
+// @Summary Show me all my systems which have a repository enabled
+// @Description Show me all my systems which have a repository enabled. Export endpoints are not paginated.
+// @ID exportRepoSystems
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json,text/csv
+// @Param    repo_name      path    string  true    "Repository name"
+// @Param    sort           query   string  false   "Sort field" Enums(id,display_name,os,rhsm,third_party,last_upload,groups)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[display_name]    query   string  false "Filter"
+// @Param    filter[os]              query   string  false "Filter"
+// @Param    filter[third_party]     query   bool    false "Filter"
+// @Param    filter[last_upload]     query   string  false "Filter"
+// @Param    tags                    query   []string  false "Tag filter"
+// @Success 200 {array} RepoSystemItem
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 415 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /export/repos/{repo_name}/systems [get]
+func RepoSystemsExportHandler(c *gin.Context) {
+	query, _, _, err := repoSystemsCommon(c, true)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var systems []RepoSystemDBLookup
+	err = query.Find(&systems).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	data, _ := repoSystemDBLookups2RepoSystemItems(systems)
+	OutputExportData(c, data)
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/utils"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRepoSystems(t *testing.T, param, queryString string) RepoSystemsResponse {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:repo_name/systems", param, queryString, nil, "",
+		RepoSystemsListHandler, 1)
+
+	var output RepoSystemsResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	return output
+}
+
+func TestRepoSystems(t *testing.T) {
+	output := testRepoSystems(t, "repo1", "")
+	assert.Equal(t, 3, len(output.Data))
+	assert.Equal(t, "00000000-0000-0000-0000-000000000002", output.Data[0].ID.String())
+	assert.Equal(t, "00000000-0000-0000-0000-000000000002", output.Data[0].DisplayName)
+	assert.Equal(t, "RHEL 8.1", output.Data[0].OS)
+	assert.False(t, output.Data[0].ThirdParty)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000003", output.Data[1].ID.String())
+	assert.Equal(t, "00000000-0000-0000-0000-000000000017", output.Data[2].ID.String())
+	assert.Equal(t, 3, output.Meta.TotalItems)
+}
+
+func TestRepoSystemsFilter(t *testing.T) {
+	output := testRepoSystems(t, "repo1", "?filter[display_name]=00000000-0000-0000-0000-000000000003")
+	assert.Equal(t, 1, len(output.Data))
+	assert.Equal(t, "00000000-0000-0000-0000-000000000003", output.Data[0].ID.String())
+
+	output = testRepoSystems(t, "repo2", "")
+	assert.Equal(t, 1, len(output.Data))
+	assert.Equal(t, "00000000-0000-0000-0000-000000000002", output.Data[0].ID.String())
+}
+
+func TestRepoSystemsUnknownRepo(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:repo_name/systems", "unknown-repo", "", nil, "",
+		RepoSystemsListHandler, 1)
+
+	var errResp utils.ErrorResponse
+	CheckResponse(t, w, http.StatusNotFound, &errResp)
+	assert.Equal(t, "repo not found", errResp.Error)
+}
+
+func TestRepoSystemsWrongOffset(t *testing.T) {
+	doTestWrongOffset(t, "/:repo_name/systems", "repo1", "?offset=1000", RepoSystemsListHandler)
+}
+
+func TestRepoSystemsIDs(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:repo_name/systems", "repo1", "", nil, "",
+		RepoSystemsListIDsHandler, 1)
+
+	var output IDsPlainResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, []string{"00000000-0000-0000-0000-000000000002", "00000000-0000-0000-0000-000000000003",
+		"00000000-0000-0000-0000-000000000017"}, output.IDs)
+}
+
+func TestRepoSystemsExportCSV(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:repo_name/systems", "repo2", "", nil, "text/csv",
+		RepoSystemsExportHandler, 1)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	lines := strings.Split(w.Body.String(), "\r\n")
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, "id,display_name,os,rhsm,third_party,last_upload,tags,groups,workspace_id,workspace_name",
+		lines[0])
+	assert.True(t, strings.HasPrefix(lines[1],
+		"00000000-0000-0000-0000-000000000002,00000000-0000-0000-0000-000000000002,RHEL 8.1,8.1,false,"))
+}
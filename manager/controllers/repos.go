@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var ReposFields = database.MustGetQueryAttrs(&RepoDBLookup{})
+var ReposSelect = database.MustGetSelect(&RepoDBLookup{})
+var ReposOpts = ListOpts{
+	Fields:         ReposFields,
+	DefaultFilters: nil,
+	DefaultSort:    "name",
+	StableSort:     "r.id",
+	SearchFields:   []string{"r.name"},
+}
+
+type RepoDBLookup struct {
+	// a helper to get total number of repos
+	MetaTotalHelper
+	RepoItem
+}
+
+// nolint: lll
+type RepoItem struct {
+	// Repository name (label)
+	Name string `json:"name" csv:"name" query:"r.name" gorm:"column:name"`
+	// Repository does not provide Red Hat content, org classification overrides the global flag
+	ThirdParty bool `json:"third_party" csv:"third_party" query:"coalesce(rc.classification = 'third_party', r.third_party)" gorm:"column:third_party"`
+	// Org classification of the repository (trusted, third_party, ignored)
+	Classification *string `json:"classification" csv:"classification" query:"rc.classification" gorm:"column:classification"`
+	// Count of systems with the repository enabled
+	Systems int `json:"systems" csv:"systems" query:"res.systems" gorm:"column:systems"`
+}
+
+type ReposResponse struct {
+	Data  []RepoItem `json:"data"`
+	Links Links      `json:"links"`
+	Meta  ListMeta   `json:"meta"`
+}
+
+func reposQuery(db *gorm.DB, filters map[string]FilterData, account int, workspaceIDs []string) *gorm.DB {
+	systems := database.Systems(db, account, workspaceIDs).
+		Select("si.id").
+		Where("si.stale = false")
+	// We need to apply tag filtering on subquery
+	systems, _ = ApplyInventoryFilter(filters, systems, "si.inventory_id")
+
+	subQ := db.Table("system_repo sr").
+		Select("sr.repo_id, count(*) AS systems").
+		Where("sr.rh_account_id = ? AND sr.system_id IN (?)", account, systems).
+		Group("sr.repo_id")
+
+	return db.Table("repo r").
+		Select(ReposSelect).
+		Joins("JOIN (?) res ON res.repo_id = r.id", subQ).
+		Joins("LEFT JOIN repo_classification rc ON rc.repo_id = r.id AND rc.rh_account_id = ?", account)
+}
+
+func reposCommon(c *gin.Context) (*gorm.DB, *ListMeta, []string, error) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	filters, err := ParseAllFilters(c, ReposOpts)
+	if err != nil {
+		return nil, nil, nil, err
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	query := reposQuery(db, filters, account, workspaceIDs)
+	query, meta, params, err := ListCommon(query, c, filters, ReposOpts)
+	// Error handled in method itself
+	return query, meta, params, err
+}
+
+func repoByName(c *gin.Context, db *gorm.DB) (*models.Repo, error) {
+	repoName := c.Param("repo_name")
+	if repoName == "" {
+		err := errors.New("repo_name param not found")
+		utils.LogAndRespBadRequest(c, err, err.Error())
+		return nil, err
+	}
+
+	var repo models.Repo
+	err := db.Where("name = ?", repoName).Take(&repo).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.LogAndRespNotFound(c, err, "repo not found")
+		} else {
+			utils.LogAndRespError(c, err, "database error")
+		}
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// nolint: lll
+// @Summary Show me all repositories enabled on my systems
+// @Description Show me all repositories enabled on my systems with count of systems and third party flag
+// @ID listRepos
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field" Enums(name,third_party,classification,systems)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[name]          query   string  false "Filter"
+// @Param    filter[third_party]   query   bool    false "Filter"
+// @Param    filter[classification] query  string  false "Filter" Enums(trusted,third_party,ignored)
+// @Param    filter[systems]       query   int     false "Filter"
+// @Param    tags                  query   []string  false "Tag filter"
+// @Param    filter[group_name] 									query []string 	false "Filter systems by inventory groups"
+// @Param    filter[system_profile][sap_system]						query bool  	false "Filter only SAP systems"
+// @Param    filter[system_profile][sap_sids]						query []string  false "Filter systems by their SAP SIDs"
+// @Success 200 {object} ReposResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /repos [get]
+func ReposListHandler(c *gin.Context) {
+	query, meta, params, err := reposCommon(c)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var repos []RepoDBLookup
+	err = query.Find(&repos).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	data, total := repoDBLookups2RepoItems(repos)
+	meta, links, err := UpdateMetaLinks(c, meta, total, nil, params...)
+	if err != nil {
+		return // Error handled in method itself
+	}
+
+	response := ReposResponse{
+		Data:  data,
+		Links: *links,
+		Meta:  *meta,
+	}
+	c.JSON(http.StatusOK, &response)
+}
+
+// nolint: lll
+// @Summary Show me all repositories enabled on my systems
+// @Description Show me names of all repositories enabled on my systems
+// @ID listReposIds
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field" Enums(name,third_party,classification,systems)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[name]          query   string  false "Filter"
+// @Param    filter[third_party]   query   bool    false "Filter"
+// @Param    filter[classification] query  string  false "Filter" Enums(trusted,third_party,ignored)
+// @Param    filter[systems]       query   int     false "Filter"
+// @Param    tags                  query   []string  false "Tag filter"
+// @Success 200 {object} IDsPlainResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /ids/repos [get]
+func ReposListIDsHandler(c *gin.Context) {
+	query, _, _, err := reposCommon(c)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var repos []RepoDBLookup
+	err = query.Find(&repos).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	names := make([]string, len(repos))
+	for i := range repos {
+		names[i] = repos[i].Name
+	}
+	resp := reposIDs(names)
+	c.JSON(http.StatusOK, &resp)
+}
+
+func repoDBLookups2RepoItems(repos []RepoDBLookup) ([]RepoItem, int) {
+	var total int
+	if len(repos) > 0 {
+		total = repos[0].Total
+	}
+	data := make([]RepoItem, len(repos))
+	for i := range repos {
+		data[i] = repos[i].RepoItem
+	}
+	return data, total
+}
+
+// repository names are used as IDs
+func reposIDs(names []string) IDsPlainResponse {
+	resp := IDsPlainResponse{}
+	resp.IDs = names
+	resp.Data = make([]IDPlain, 0, len(names))
+	for _, name := range names {
+		resp.Data = append(resp.Data, IDPlain{ID: name})
+	}
+	return resp
+}
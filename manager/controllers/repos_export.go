@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"app/base/utils"
+	"app/manager/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// nolint: lll
+// @Summary Show me all repositories enabled on my systems
+// @Description Show me all repositories enabled on my systems. Export endpoints are not paginated.
+// @ID exportRepos
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json,text/csv
+// @Param    sort           query   string  false   "Sort field" Enums(name,third_party,classification,systems)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[name]          query   string  false "Filter"
+// @Param    filter[third_party]   query   bool    false "Filter"
+// @Param    filter[classification] query  string  false "Filter" Enums(trusted,third_party,ignored)
+// @Param    filter[systems]       query   int     false "Filter"
+// @Param    tags                  query   []string  false "Tag filter"
+// @Success 200 {array} RepoItem
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 415 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /export/repos [get]
+func ReposExportHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+	filters, err := ParseAllFilters(c, ReposOpts)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	query := reposQuery(db, filters, account, workspaceIDs)
+	query, err = ExportListCommon(query, c, ReposOpts)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var repos []RepoDBLookup
+	err = query.Find(&repos).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	data, _ := repoDBLookups2RepoItems(repos)
+	OutputExportData(c, data)
+}
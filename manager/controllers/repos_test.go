@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"app/base/core"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRepos(t *testing.T, url string) ReposResponse {
+	core.SetupTest(t)
+	w := CreateRequest("GET", url, nil, "", ReposListHandler)
+
+	var output ReposResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	return output
+}
+
+func TestReposDefault(t *testing.T) {
+	output := testRepos(t, "/")
+	assert.Equal(t, 2, len(output.Data))
+	assert.Equal(t, RepoItem{Name: "repo1", ThirdParty: false, Systems: 3}, output.Data[0])
+	assert.Equal(t, RepoItem{Name: "repo2", ThirdParty: false, Systems: 1}, output.Data[1])
+	assert.Equal(t, 2, output.Meta.TotalItems)
+	assert.Equal(t, []string{"name"}, output.Meta.Sort)
+}
+
+func TestReposSort(t *testing.T) {
+	output := testRepos(t, "/?sort=systems")
+	assert.Equal(t, 2, len(output.Data))
+	assert.Equal(t, "repo2", output.Data[0].Name)
+	assert.Equal(t, "repo1", output.Data[1].Name)
+}
+
+func TestReposFilter(t *testing.T) {
+	output := testRepos(t, "/?filter[systems]=gt:1")
+	assert.Equal(t, 1, len(output.Data))
+	assert.Equal(t, "repo1", output.Data[0].Name)
+
+	output = testRepos(t, "/?filter[third_party]=true")
+	assert.Equal(t, 0, len(output.Data))
+}
+
+func TestReposSortID(t *testing.T) {
+	core.SetupTest(t)
+	// repos have no id attribute
+	w := CreateRequest("GET", "/?sort=id", nil, "", ReposListHandler)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestReposInvalidFilter(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequest("GET", "/?filter[unknown]=1", nil, "", ReposListHandler)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestReposTags(t *testing.T) {
+	output := testRepos(t, "/?tags=ns1/k3=val3")
+	assert.Equal(t, 2, len(output.Data))
+	assert.Equal(t, RepoItem{Name: "repo1", ThirdParty: false, Systems: 1}, output.Data[0])
+}
+
+func TestReposIDs(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequest("GET", "/", nil, "", ReposListIDsHandler)
+
+	var output IDsPlainResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, []string{"repo1", "repo2"}, output.IDs)
+	assert.Equal(t, IDPlain{ID: "repo1"}, output.Data[0])
+}
+
+func TestReposExportCSV(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequest("GET", "/", nil, "text/csv", ReposExportHandler)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "name,third_party,classification,systems\r\nrepo1,false,,3\r\nrepo2,false,,1\r\n", w.Body.String())
+}
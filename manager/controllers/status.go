@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"app/base/api"
 	"app/base/database"
 	"app/base/utils"
 	"net/http"
@@ -8,14 +9,19 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+type StatusResponse struct {
+	Upstreams []api.UpstreamStatus `json:"upstreams"`
+}
+
 // @Summary	Status endpoint
-// @Success 200 {int}		http.StatusOK
+// @Description Database connectivity and circuit breaker state of upstream services
+// @Success 200 {object}	StatusResponse
 // @Failure 503 {object} 	utils.ErrorResponse
 func Status(c *gin.Context) {
 	sqlDB, _ := database.DB.DB()
 	if err := sqlDB.Ping(); err != nil {
 		utils.LogAndRespStatusError(c, http.StatusServiceUnavailable, err, "Database not connected")
 	} else {
-		c.Status(http.StatusOK)
+		c.JSON(http.StatusOK, StatusResponse{Upstreams: api.UpstreamsStatus()})
 	}
 }
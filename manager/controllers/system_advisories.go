@@ -37,9 +37,12 @@ type SystemAdvisoriesDBLookup struct {
 	SystemAdvisoryItemAttributes
 }
 
+// nolint: lll
 type SystemAdvisoryItemAttributes struct {
 	AdvisoryItemAttributesCommon
-	Status *string `json:"status" csv:"status,omitempty" query:"status.name" gorm:"column:status"`
+	// Installable, Applicable or Not applicable by policy (beyond the system baseline),
+	// pairs covered by an advisory exclusion are Excluded or Accepted risk
+	Status *string `json:"status" csv:"status,omitempty" query:"CASE aex.kind WHEN 'accepted_risk' THEN 'Accepted risk' WHEN 'excluded' THEN 'Excluded' ELSE status.name END" gorm:"column:status"`
 }
 
 type SystemAdvisoryItem struct {
@@ -71,7 +74,7 @@ func (v *RelList) Scan(value interface{}) error {
 	return (*datatypes.JSONSlice[string])(v).Scan(value)
 }
 
-func systemAdvisoriesCommon(c *gin.Context) (*gorm.DB, *ListMeta, []string, error) {
+func systemAdvisoriesCommon(c *gin.Context, scopes ...func(*gorm.DB) *gorm.DB) (*gorm.DB, *ListMeta, []string, error) {
 	account := c.GetInt(utils.KeyAccount)
 	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
 
@@ -101,7 +104,7 @@ func systemAdvisoriesCommon(c *gin.Context) (*gorm.DB, *ListMeta, []string, erro
 		return nil, nil, nil, err
 	}
 
-	query := buildSystemAdvisoriesQuery(db, account, workspaceIDs, inventoryID)
+	query := buildSystemAdvisoriesQuery(db, account, workspaceIDs, inventoryID).Scopes(scopes...)
 	query, meta, params, err := ListCommon(query, c, filters, SystemAdvisoriesOpts)
 	// Error handling and setting of result code & content is done in ListCommon
 	return query, meta, params, err
@@ -132,7 +135,11 @@ func systemAdvisoriesCommon(c *gin.Context) (*gorm.DB, *ListMeta, []string, erro
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /systems/{inventory_id}/advisories [get]
 func SystemAdvisoriesHandler(c *gin.Context) {
-	query, meta, params, err := systemAdvisoriesCommon(c)
+	systemAdvisoriesList(c)
+}
+
+func systemAdvisoriesList(c *gin.Context, scopes ...func(*gorm.DB) *gorm.DB) {
+	query, meta, params, err := systemAdvisoriesCommon(c, scopes...)
 	if err != nil {
 		return
 	} // Error handled in method itself
@@ -198,7 +205,7 @@ func SystemAdvisoriesIDsHandler(c *gin.Context) {
 
 func buildSystemAdvisoriesQuery(db *gorm.DB, account int, workspaceIDs []string, inventoryID uuid.UUID) *gorm.DB {
 	query := database.SystemAdvisoriesByInventoryID(db, account, workspaceIDs, inventoryID,
-		database.JoinAdvisoryMetadata, database.JoinAdvisoryType).
+		database.JoinAdvisoryMetadata, database.JoinAdvisoryType, database.JoinAdvisoryExclusions).
 		Joins("JOIN status ON sa.status_id = status.id").
 		Joins("LEFT JOIN advisory_severity sev ON am.severity_id = sev.id").
 		Select(SystemAdvisoriesSelect)
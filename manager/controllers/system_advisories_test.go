@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"app/base/core"
+	"app/base/database"
 	"app/base/utils"
 	"fmt"
 	"net/http"
@@ -139,6 +140,21 @@ func TestSystemAdvisoriesSearch(t *testing.T) {
 	assert.Equal(t, "Installable", *output.Data[0].Attributes.Status)
 }
 
+func TestSystemAdvisoriesExcluded(t *testing.T) {
+	core.SetupTest(t)
+	exclusionID := database.CreateAdvisoryExclusion(t, 1, 3, nil, []int64{1})
+	defer database.DeleteAdvisoryExclusion(t, 1, exclusionID)
+
+	w := CreateRequestRouterWithPath("GET", "/:inventory_id", "00000000-0000-0000-0000-000000000001",
+		"?search=h-3", nil, "", SystemAdvisoriesHandler)
+
+	var output SystemAdvisoriesResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 1, len(output.Data))
+	assert.Equal(t, "RH-3", output.Data[0].ID)
+	assert.Equal(t, "Accepted risk", *output.Data[0].Attributes.Status)
+}
+
 func TestSystemAdvisoriesWrongOffset(t *testing.T) {
 	doTestWrongOffset(t, "/:inventory_id", "00000000-0000-0000-0000-000000000001", "?offset=1000",
 		SystemAdvisoriesHandler)
@@ -59,7 +59,7 @@ func SystemDetailHandler(c *gin.Context) {
 
 	var systemDetail SystemDetailLookup
 	db := middlewares.DBFromContext(c)
-	query := database.Systems(db, account, workspaceIDs, database.JoinTemplates).
+	query := database.Systems(db, account, workspaceIDs, database.JoinTemplates, database.JoinBaselines).
 		Select(database.MustGetSelect(&systemDetail)).
 		Where("si.inventory_id = ?", inventoryID)
 
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var SystemPackageHistoryFields = database.MustGetQueryAttrs(&SystemPackageHistoryDBLookup{})
+var SystemPackageHistorySelect = database.MustGetSelect(&SystemPackageHistoryDBLookup{})
+var SystemPackageHistoryOpts = ListOpts{
+	Fields:         SystemPackageHistoryFields,
+	DefaultFilters: nil,
+	DefaultSort:    "-changed",
+	StableSort:     "spc.id",
+	SearchFields:   []string{"pn.name"},
+}
+
+// nolint: lll
+type PackageChangeAttributes struct {
+	// Type of the change (installed, removed, upgraded, downgraded)
+	Change string `json:"change" csv:"change" query:"spc.change" gorm:"column:change"`
+	// EVRA before the change, empty for installed packages
+	OldEvra *string `json:"old_evra" csv:"old_evra" query:"spc.old_evra" gorm:"column:old_evra"`
+	// EVRA after the change, empty for removed packages
+	NewEvra *string `json:"new_evra" csv:"new_evra" query:"spc.new_evra" gorm:"column:new_evra"`
+	// Time of the upload which brought the change
+	Changed time.Time `json:"changed" csv:"changed" query:"spc.changed" gorm:"column:changed"`
+}
+
+type SystemPackageHistoryItem struct {
+	Name string `json:"name" csv:"name" query:"pn.name" gorm:"column:name"`
+	PackageChangeAttributes
+}
+
+type SystemPackageHistoryDBLookup struct {
+	MetaTotalHelper
+	SystemPackageHistoryItem
+}
+
+type SystemPackageHistoryResponse struct {
+	Data  []SystemPackageHistoryItem `json:"data"`
+	Links Links                      `json:"links"`
+	Meta  ListMeta                   `json:"meta"`
+}
+
+func systemPackageHistoryQuery(db *gorm.DB, account int, workspaceIDs []string, inventoryID uuid.UUID) *gorm.DB {
+	return database.Systems(db, account, workspaceIDs).
+		Joins("JOIN system_package_change spc ON spc.system_id = si.id AND spc.rh_account_id = si.rh_account_id").
+		Joins("JOIN package_name pn ON pn.id = spc.name_id").
+		Select(SystemPackageHistorySelect).
+		Where("si.inventory_id = ?", inventoryID)
+}
+
+// @Summary Show me package changes recorded on a system by given inventory id
+// @Description Show me packages installed, removed, upgraded or downgraded on a system by given inventory id
+// @ID systemPackageHistory
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    inventory_id   path    string  true    "Inventory ID"
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field" Enums(name,change,old_evra,new_evra,changed)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[name]          query   string  false "Filter"
+// @Param    filter[change]        query   string  false "Filter" Enums(installed,removed,upgraded,downgraded)
+// @Param    filter[old_evra]      query   string  false "Filter"
+// @Param    filter[new_evra]      query   string  false "Filter"
+// @Param    filter[changed]       query   string  false "Filter"
+// @Success 200 {object} SystemPackageHistoryResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /systems/{inventory_id}/package_history [get]
+func SystemPackageHistoryHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	inventoryID, err := uuid.Parse(c.Param("inventory_id"))
+	if err != nil {
+		utils.LogAndRespBadRequest(c, err, "incorrect inventory_id format")
+		return
+	}
+
+	filters, err := ParseAllFilters(c, SystemPackageHistoryOpts)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	var exists int64
+	err = database.Systems(db, account, workspaceIDs).
+		Where("si.inventory_id = ?", inventoryID).
+		Count(&exists).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+	if exists == 0 {
+		utils.LogAndRespNotFound(c, errors.New("system not found"), "Systems not found")
+		return
+	}
+
+	query := systemPackageHistoryQuery(db, account, workspaceIDs, inventoryID)
+	query, meta, params, err := ListCommon(query, c, filters, SystemPackageHistoryOpts)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var changes []SystemPackageHistoryDBLookup
+	err = query.Find(&changes).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	var total int
+	if len(changes) > 0 {
+		total = changes[0].Total
+	}
+	data := make([]SystemPackageHistoryItem, len(changes))
+	for i := range changes {
+		data[i] = changes[i].SystemPackageHistoryItem
+	}
+
+	meta, links, err := UpdateMetaLinks(c, meta, total, nil, params...)
+	if err != nil {
+		return // Error handled in method itself
+	}
+
+	response := SystemPackageHistoryResponse{
+		Data:  data,
+		Links: *links,
+		Meta:  *meta,
+	}
+	c.JSON(http.StatusOK, &response)
+}
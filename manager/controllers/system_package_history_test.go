@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/utils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSystemPackageHistory(t *testing.T, param, queryString string, expectedStatus int, output interface{}) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:inventory_id/package_history", param, queryString, nil, "",
+		SystemPackageHistoryHandler, 1)
+	CheckResponse(t, w, expectedStatus, output)
+}
+
+func TestSystemPackageHistory(t *testing.T) {
+	var output SystemPackageHistoryResponse
+	testSystemPackageHistory(t, "00000000-0000-0000-0000-000000000002", "?sort=name", http.StatusOK, &output)
+	assert.Equal(t, 2, output.Meta.TotalItems)
+	assert.Equal(t, "firefox", output.Data[0].Name)
+	assert.Equal(t, "installed", output.Data[0].Change)
+	assert.Nil(t, output.Data[0].OldEvra)
+	assert.Equal(t, "76.0.1-1.fc31.x86_64", *output.Data[0].NewEvra)
+	assert.Equal(t, "kernel", output.Data[1].Name)
+	assert.Equal(t, "upgraded", output.Data[1].Change)
+	assert.Equal(t, "5.6.12-200.fc31.x86_64", *output.Data[1].OldEvra)
+	assert.Equal(t, "5.6.13-200.fc31.x86_64", *output.Data[1].NewEvra)
+}
+
+func TestSystemPackageHistoryFilter(t *testing.T) {
+	var output SystemPackageHistoryResponse
+	testSystemPackageHistory(t, "00000000-0000-0000-0000-000000000003", "?filter[change]=removed", http.StatusOK,
+		&output)
+	assert.Equal(t, 1, len(output.Data))
+	assert.Equal(t, "bash", output.Data[0].Name)
+	assert.Nil(t, output.Data[0].NewEvra)
+}
+
+func TestSystemPackageHistoryEmpty(t *testing.T) {
+	var output SystemPackageHistoryResponse
+	testSystemPackageHistory(t, "00000000-0000-0000-0000-000000000001", "", http.StatusOK, &output)
+	assert.Equal(t, 0, len(output.Data))
+}
+
+func TestSystemPackageHistoryNotFound(t *testing.T) {
+	var errResp utils.ErrorResponse
+	testSystemPackageHistory(t, "ffffffff-ffff-ffff-ffff-ffffffffffff", "", http.StatusNotFound, &errResp)
+	assert.Equal(t, "Systems not found", errResp.Error)
+}
+
+func TestSystemPackageHistoryInvalidID(t *testing.T) {
+	var errResp utils.ErrorResponse
+	testSystemPackageHistory(t, "invalid", "", http.StatusBadRequest, &errResp)
+	assert.Equal(t, "incorrect inventory_id format", errResp.Error)
+}
@@ -24,7 +24,7 @@ type SystemPackagesAttrs struct {
 	Summary      string `json:"summary" csv:"summary" query:"sum.value" gorm:"column:summary"`
 	Description  string `json:"description" csv:"description" query:"descr.value" gorm:"column:description"`
 	Updatable    bool   `json:"updatable" csv:"updatable" query:"(spkg.installable_id is not null)" gorm:"column:updatable"`
-	UpdateStatus string `json:"update_status" csv:"update_status" query:"CASE WHEN spkg.installable_id is not null THEN 'Installable' WHEN spkg.applicable_id is not null THEN 'Applicable' ELSE 'None' END" gorm:"column:update_status"`
+	UpdateStatus string `json:"update_status" csv:"update_status" query:"CASE WHEN spkg.installable_id is not null THEN 'Installable' WHEN spkg.applicable_id is not null THEN 'Applicable' WHEN spkg.held_id is not null THEN 'Held' ELSE 'None' END" gorm:"column:update_status"`
 }
 
 type SystemPackageData struct {
@@ -53,12 +53,14 @@ type SystemPackageDBLoad struct {
 	// helper to get Updates
 	InstallableEVRA string `json:"-" csv:"-" query:"pi.evra" gorm:"column:installable_evra"`
 	ApplicableEVRA  string `json:"-" csv:"-" query:"pa.evra" gorm:"column:applicable_evra"`
+	HeldEVRA        string `json:"-" csv:"-" query:"ph.evra" gorm:"column:held_evra"`
 	// a helper to get total number of systems
 	MetaTotalHelper
 }
 
 func systemPackageQuery(db *gorm.DB, account int, workspaceIDs []string, inventoryID uuid.UUID) *gorm.DB {
-	query := database.SystemPackages(db, account, workspaceIDs, database.JoinInstallableApplicablePackages).
+	query := database.SystemPackages(db, account, workspaceIDs, database.JoinInstallableApplicablePackages,
+		database.JoinHeldPackages).
 		Joins("LEFT JOIN strings AS descr ON p.description_hash = descr.id").
 		Joins("LEFT JOIN strings AS sum ON p.summary_hash = sum.id").
 		Select(SystemPackagesSelect).
@@ -154,6 +156,12 @@ func buildSystemPackageData(loaded []SystemPackageDBLoad) (int, []SystemPackageD
 				EVRA: sp.ApplicableEVRA, Status: "Applicable",
 			})
 		}
+		// latest update violating a package hold
+		if len(sp.HeldEVRA) > 0 {
+			data[i].Updates = append(data[i].Updates, models.PackageUpdate{
+				EVRA: sp.HeldEVRA, Status: "Held",
+			})
+		}
 	}
 	return total, data
 }
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// restrict system advisories to installable ones which require reboot after applying
+func installableRebootRequired(tx *gorm.DB) *gorm.DB {
+	return tx.Where("sa.status_id = 0 AND am.reboot_required = true")
+}
+
+// nolint:lll
+// @Summary Show me installable advisories which require a reboot of a system by given inventory id
+// @Description Show me installable advisories which would cause a reboot of a system when applied
+// @ID listSystemRebootPlan
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    inventory_id   path    string  true    "Inventory ID"
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field"    Enums(id,name,type,synopsis,public_date)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[id]                  query   string  false "Filter"
+// @Param    filter[description]         query   string  false "Filter"
+// @Param    filter[public_date]         query   string  false "Filter"
+// @Param    filter[synopsis]            query   string  false "Filter"
+// @Param    filter[advisory_type_name]  query   string  false "Filter" Enums(unknown,unspecified,other,enhancement,bugfix,security)
+// @Param    filter[severity]            query   int  	 false "Filter" minimum(1) maximum(4)
+// @Param    filter[severity_name]       query   string  false "Filter" Enums(Low,Medium,High,Critical)
+// @Success 200 {object} SystemAdvisoriesResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /systems/{inventory_id}/reboot_plan [get]
+func SystemRebootPlanHandler(c *gin.Context) {
+	systemAdvisoriesList(c, installableRebootRequired)
+}
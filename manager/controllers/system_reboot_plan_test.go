@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemRebootPlan(t *testing.T) {
+	core.SetupTest(t)
+	// RH-2 is only applicable for the system, RH-3 is installable
+	database.SetAdvisoriesRebootRequired(t, []int64{2, 3}, true)
+	defer database.SetAdvisoriesRebootRequired(t, []int64{2, 3}, false)
+
+	w := CreateRequestRouterWithPath("GET", "/:inventory_id", "00000000-0000-0000-0000-000000000001", "", nil, "",
+		SystemRebootPlanHandler)
+
+	var output SystemAdvisoriesResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 1, len(output.Data))
+	assert.Equal(t, "RH-3", output.Data[0].ID)
+	assert.Equal(t, true, output.Data[0].Attributes.RebootRequired)
+	assert.Equal(t, "Installable", *output.Data[0].Attributes.Status)
+	assert.Equal(t, 1, output.Meta.TotalItems)
+}
+
+func TestSystemRebootPlanEmpty(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithPath("GET", "/:inventory_id", "00000000-0000-0000-0000-000000000001", "", nil, "",
+		SystemRebootPlanHandler)
+
+	var output SystemAdvisoriesResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 0, len(output.Data))
+}
+
+func TestSystemRebootPlanNotFound(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithPath("GET", "/:inventory_id", "00000000-0000-0000-0000-000000000099", "", nil, "",
+		SystemRebootPlanHandler)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
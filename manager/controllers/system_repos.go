@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var SystemReposFields = database.MustGetQueryAttrs(&SystemRepoDBLookup{})
+var SystemReposSelect = database.MustGetSelect(&SystemRepoDBLookup{})
+var SystemReposOpts = ListOpts{
+	Fields:         SystemReposFields,
+	DefaultFilters: nil,
+	DefaultSort:    "name",
+	StableSort:     "r.id",
+	SearchFields:   []string{"r.name"},
+}
+
+// nolint: lll
+type SystemRepoItem struct {
+	// Repository name (label)
+	Name string `json:"name" csv:"name" query:"r.name" gorm:"column:name"`
+	// Repository does not provide Red Hat content, org classification overrides the global flag
+	ThirdParty bool `json:"third_party" csv:"third_party" query:"coalesce(rc.classification = 'third_party', r.third_party)" gorm:"column:third_party"`
+	// Org classification of the repository (trusted, third_party, ignored)
+	Classification *string `json:"classification" csv:"classification" query:"rc.classification" gorm:"column:classification"`
+}
+
+type SystemRepoDBLookup struct {
+	MetaTotalHelper
+	SystemRepoItem
+}
+
+type SystemReposResponse struct {
+	Data  []SystemRepoItem `json:"data"`
+	Links Links            `json:"links"`
+	Meta  ListMeta         `json:"meta"`
+}
+
+func systemReposQuery(db *gorm.DB, account int, workspaceIDs []string, inventoryID uuid.UUID) *gorm.DB {
+	return database.Systems(db, account, workspaceIDs).
+		Joins("JOIN system_repo sr ON sr.system_id = si.id AND sr.rh_account_id = si.rh_account_id").
+		Joins("JOIN repo r ON r.id = sr.repo_id").
+		Joins("LEFT JOIN repo_classification rc ON rc.repo_id = r.id AND rc.rh_account_id = sr.rh_account_id").
+		Select(SystemReposSelect).
+		Where("si.inventory_id = ?", inventoryID)
+}
+
+func systemReposCommon(c *gin.Context, export bool) (*gorm.DB, *ListMeta, []string, error) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	inventoryID, err := uuid.Parse(c.Param("inventory_id"))
+	if err != nil {
+		utils.LogAndRespBadRequest(c, err, "incorrect inventory_id format")
+		return nil, nil, nil, err
+	}
+
+	filters, err := ParseAllFilters(c, SystemReposOpts)
+	if err != nil {
+		return nil, nil, nil, err
+	} // Error handled in method itself
+
+	db := middlewares.DBFromContext(c)
+	var exists int64
+	err = database.Systems(db, account, workspaceIDs).
+		Where("si.inventory_id = ?", inventoryID).
+		Count(&exists).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return nil, nil, nil, err
+	}
+	if exists == 0 {
+		err = errors.New("system not found")
+		utils.LogAndRespNotFound(c, err, "Systems not found")
+		return nil, nil, nil, err
+	}
+
+	query := systemReposQuery(db, account, workspaceIDs, inventoryID)
+	if export {
+		query, err = ExportListCommon(query, c, SystemReposOpts)
+		return query, nil, nil, err
+	}
+	query, meta, params, err := ListCommon(query, c, filters, SystemReposOpts)
+	// Error handled in method itself
+	return query, meta, params, err
+}
+
+// @Summary Show me repositories enabled on a system by given inventory id
+// @Description Show me repositories enabled on a system by given inventory id
+// @ID listSystemRepos
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    inventory_id   path    string  true    "Inventory ID"
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field" Enums(name,third_party,classification)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[name]          query   string  false "Filter"
+// @Param    filter[third_party]   query   bool    false "Filter"
+// @Param    filter[classification] query  string  false "Filter" Enums(trusted,third_party,ignored)
+// @Success 200 {object} SystemReposResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /systems/{inventory_id}/repos [get]
+func SystemReposHandler(c *gin.Context) {
+	query, meta, params, err := systemReposCommon(c, false)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var repos []SystemRepoDBLookup
+	err = query.Find(&repos).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	data, total := systemRepoDBLookups2SystemRepoItems(repos)
+	meta, links, err := UpdateMetaLinks(c, meta, total, nil, params...)
+	if err != nil {
+		return // Error handled in method itself
+	}
+
+	response := SystemReposResponse{
+		Data:  data,
+		Links: *links,
+		Meta:  *meta,
+	}
+	c.JSON(http.StatusOK, &response)
+}
+
+// @Summary Show me repositories enabled on a system by given inventory id
+// @Description Show me names of repositories enabled on a system by given inventory id
+// @ID listSystemReposIds
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    inventory_id   path    string  true    "Inventory ID"
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field" Enums(name,third_party,classification)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[name]          query   string  false "Filter"
+// @Param    filter[third_party]   query   bool    false "Filter"
+// @Param    filter[classification] query  string  false "Filter" Enums(trusted,third_party,ignored)
+// @Success 200 {object} IDsPlainResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /ids/systems/{inventory_id}/repos [get]
+func SystemReposIDsHandler(c *gin.Context) {
+	query, _, _, err := systemReposCommon(c, false)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var repos []SystemRepoDBLookup
+	err = query.Find(&repos).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	names := make([]string, len(repos))
+	for i := range repos {
+		names[i] = repos[i].Name
+	}
+	resp := reposIDs(names)
+	c.JSON(http.StatusOK, &resp)
+}
+
+func systemRepoDBLookups2SystemRepoItems(repos []SystemRepoDBLookup) ([]SystemRepoItem, int) {
+	var total int
+	if len(repos) > 0 {
+		total = repos[0].Total
+	}
+	data := make([]SystemRepoItem, len(repos))
+	for i := range repos {
+		data[i] = repos[i].SystemRepoItem
+	}
+	return data, total
+}
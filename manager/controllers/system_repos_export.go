@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"app/base/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Show me repositories enabled on a system by given inventory id
+// @Description Show me repositories enabled on a system by given inventory id. Export endpoints are not paginated.
+// @ID exportSystemRepos
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json,text/csv
+// @Param    inventory_id   path    string  true    "Inventory ID"
+// @Param    sort           query   string  false   "Sort field" Enums(name,third_party,classification)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[name]          query   string  false "Filter"
+// @Param    filter[third_party]   query   bool    false "Filter"
+// @Param    filter[classification] query  string  false "Filter" Enums(trusted,third_party,ignored)
+// @Success 200 {array} SystemRepoItem
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 415 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /export/systems/{inventory_id}/repos [get]
+func SystemReposExportHandler(c *gin.Context) {
+	query, _, _, err := systemReposCommon(c, true)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var repos []SystemRepoDBLookup
+	err = query.Find(&repos).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "database error")
+		return
+	}
+
+	data, _ := systemRepoDBLookups2SystemRepoItems(repos)
+	OutputExportData(c, data)
+}
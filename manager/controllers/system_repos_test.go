@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/utils"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSystemRepos(t *testing.T, param, queryString string, expectedStatus int, output interface{}) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:inventory_id/repos", param, queryString, nil, "",
+		SystemReposHandler, 1)
+	CheckResponse(t, w, expectedStatus, output)
+}
+
+func TestSystemRepos(t *testing.T) {
+	var output SystemReposResponse
+	testSystemRepos(t, "00000000-0000-0000-0000-000000000002", "", http.StatusOK, &output)
+	assert.Equal(t, []SystemRepoItem{{Name: "repo1"}, {Name: "repo2"}}, output.Data)
+	assert.Equal(t, 2, output.Meta.TotalItems)
+}
+
+func TestSystemReposSortFilter(t *testing.T) {
+	var output SystemReposResponse
+	testSystemRepos(t, "00000000-0000-0000-0000-000000000002", "?sort=-name&filter[name]=repo2", http.StatusOK,
+		&output)
+	assert.Equal(t, []SystemRepoItem{{Name: "repo2"}}, output.Data)
+}
+
+func TestSystemReposSortID(t *testing.T) {
+	var errResp utils.ErrorResponse
+	testSystemRepos(t, "00000000-0000-0000-0000-000000000002", "?sort=id", http.StatusBadRequest, &errResp)
+	assert.Equal(t, "Invalid sort field: id", errResp.Error)
+}
+
+func TestSystemReposNoRepos(t *testing.T) {
+	var output SystemReposResponse
+	testSystemRepos(t, "00000000-0000-0000-0000-000000000001", "", http.StatusOK, &output)
+	assert.Equal(t, 0, len(output.Data))
+}
+
+func TestSystemReposNotFound(t *testing.T) {
+	var errResp utils.ErrorResponse
+	testSystemRepos(t, "ffffffff-ffff-ffff-ffff-ffffffffffff", "", http.StatusNotFound, &errResp)
+	assert.Equal(t, "Systems not found", errResp.Error)
+}
+
+func TestSystemReposInvalidID(t *testing.T) {
+	var errResp utils.ErrorResponse
+	testSystemRepos(t, "invalid", "", http.StatusBadRequest, &errResp)
+	assert.Equal(t, "incorrect inventory_id format", errResp.Error)
+}
+
+func TestSystemReposIDs(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:inventory_id/repos", "00000000-0000-0000-0000-000000000002", "",
+		nil, "", SystemReposIDsHandler, 1)
+
+	var output IDsPlainResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, []string{"repo1", "repo2"}, output.IDs)
+}
+
+func TestSystemReposExportCSV(t *testing.T) {
+	core.SetupTest(t)
+	w := CreateRequestRouterWithParams("GET", "/:inventory_id/repos", "00000000-0000-0000-0000-000000000002", "",
+		nil, "text/csv", SystemReposExportHandler, 1)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "name,third_party,classification\r\nrepo1,false,\r\nrepo2,false,\r\n", w.Body.String())
+}
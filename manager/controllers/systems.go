@@ -78,8 +78,10 @@ type SystemItemAttributes struct {
 	SystemSatelliteManaged
 	SystemBootc
 	SystemBuiltPkgcache
-	PackagesInstallable   int `json:"packages_installable" csv:"packages_installable" query:"spatch.packages_installable" gorm:"column:packages_installable"`
-	PackagesApplicable    int `json:"packages_applicable" csv:"packages_applicable" query:"spatch.packages_applicable" gorm:"column:packages_applicable"`
+	PackagesInstallable int `json:"packages_installable" csv:"packages_installable" query:"spatch.packages_installable" gorm:"column:packages_installable"`
+	PackagesApplicable  int `json:"packages_applicable" csv:"packages_applicable" query:"spatch.packages_applicable" gorm:"column:packages_applicable"`
+	// Packages with an update held by a package hold
+	PackagesHeld          int `json:"packages_held" csv:"packages_held" query:"spatch.packages_held" gorm:"column:packages_held"`
 	InstallableRhsaCount  int `json:"installable_rhsa_count" csv:"installable_rhsa_count" query:"spatch.installable_advisory_sec_count_cache" gorm:"column:installable_rhsa_count"`
 	InstallableRhbaCount  int `json:"installable_rhba_count" csv:"installable_rhba_count" query:"spatch.installable_advisory_bug_count_cache" gorm:"column:installable_rhba_count"`
 	InstallableRheaCount  int `json:"installable_rhea_count" csv:"installable_rhea_count" query:"spatch.installable_advisory_enh_count_cache" gorm:"column:installable_rhea_count"`
@@ -89,10 +91,13 @@ type SystemItemAttributes struct {
 	ApplicableRheaCount   int `json:"applicable_rhea_count" csv:"applicable_rhea_count" query:"spatch.applicable_advisory_enh_count_cache" gorm:"column:applicable_rhea_count"`
 	ApplicableOtherCount  int `json:"applicable_other_count" csv:"applicable_other_count" query:"(spatch.applicable_advisory_count_cache - spatch.installable_advisory_sec_count_cache - spatch.installable_advisory_bug_count_cache - spatch.installable_advisory_enh_count_cache)" gorm:"column:applicable_other_count"`
 	BaselineIDAttr
+	// Advisories not applicable because of the baseline assigned to the system
+	BaselineAdvisories int `json:"baseline_advisories" csv:"baseline_advisories" query:"spatch.baseline_advisory_count_cache" gorm:"column:baseline_advisories"`
 	TemplateAttibutes
 	SystemGroups
 	SystemWorkspace
 	SystemArch
+	RebootRequired bool `json:"reboot_required" csv:"reboot_required" query:"EXISTS (SELECT 1 FROM system_advisories rsa JOIN advisory_metadata ram ON ram.id = rsa.advisory_id WHERE rsa.rh_account_id = si.rh_account_id AND rsa.system_id = si.id AND rsa.status_id = 0 AND ram.reboot_required = true)" gorm:"column:reboot_required"`
 }
 
 // nolint: lll
@@ -266,6 +271,7 @@ func systemsListResponse(c *gin.Context, query *gorm.DB, meta *ListMeta, params
 // @Param    filter[packages_installed]     query   int    false   "Filter"
 // @Param    filter[packages_installable]   query   int    false   "Filter"
 // @Param    filter[packages_applicable]    query   int    false   "Filter"
+// @Param    filter[packages_held]          query   int    false   "Filter"
 // @Param    filter[stale_timestamp]        query   string  false   "Filter"
 // @Param    filter[stale_warning_timestamp] query  string  false   "Filter"
 // @Param    filter[culled_timestamp]       query   string  false   "Filter"
@@ -275,6 +281,7 @@ func systemsListResponse(c *gin.Context, query *gorm.DB, meta *ListMeta, params
 // @Param    filter[template_uuid]          query   string  false   "Filter"
 // @Param    filter[satellite_managed] 		query   bool    false   "Filter"
 // @Param    filter[built_pkgcache]         query   bool    false   "Filter"
+// @Param    filter[reboot_required]        query   bool    false   "Filter"
 // @Param    filter[arch]                   query   string  false   "Filter"
 // @Param    filter[os]                     query   string  false   "Filter OS version"
 // @Param    filter[osname]                 query   string  false   "Filter OS name"
@@ -337,6 +344,7 @@ func SystemsListHandler(c *gin.Context) {
 // @Param    filter[packages_installed]     query   int    false   "Filter"
 // @Param    filter[packages_installable]   query   int    false   "Filter"
 // @Param    filter[packages_applicable]    query   int    false   "Filter"
+// @Param    filter[packages_held]          query   int    false   "Filter"
 // @Param    filter[stale_timestamp]        query   string  false   "Filter"
 // @Param    filter[stale_warning_timestamp] query  string  false   "Filter"
 // @Param    filter[culled_timestamp]       query   string  false   "Filter"
@@ -346,6 +354,7 @@ func SystemsListHandler(c *gin.Context) {
 // @Param    filter[template_uuid]          query   string  false   "Filter"
 // @Param    filter[satellite_managed] 		query   bool    false   "Filter"
 // @Param    filter[built_pkgcache]         query   bool    false   "Filter"
+// @Param    filter[reboot_required]        query   bool    false   "Filter"
 // @Param    filter[arch]                   query   string  false   "Filter"
 // @Param    filter[os]                     query   string  false   "Filter OS version"
 // @Param    filter[osname]                 query   string  false   "Filter OS name"
@@ -418,6 +427,7 @@ func SystemsListPostHandler(c *gin.Context) {
 // @Param    filter[packages_installed]     query   int     false   "Filter"
 // @Param    filter[packages_installable]   query   int     false   "Filter"
 // @Param    filter[packages_applicable]    query   int     false   "Filter"
+// @Param    filter[packages_held]          query   int     false   "Filter"
 // @Param    filter[stale_timestamp]        query   string  false   "Filter"
 // @Param    filter[stale_warning_timestamp] query  string  false   "Filter"
 // @Param    filter[culled_timestamp]       query   string  false   "Filter"
@@ -431,6 +441,7 @@ func SystemsListPostHandler(c *gin.Context) {
 // @Param    filter[osminor]                query   string  false   "Filter OS minor version"
 // @Param    filter[satellite_managed]      query   bool    false   "Filter"
 // @Param    filter[built_pkgcache]         query   bool    false   "Filter"
+// @Param    filter[reboot_required]        query   bool    false   "Filter"
 // @Param    filter[arch]                   query   string  false   "Filter"
 // @Param    tags                           query   []string false  "Tag filter"
 // @Param    filter[group_name] 									query	[]string 	false "Filter systems by inventory groups"
@@ -470,5 +481,6 @@ func SystemsListIDsHandler(c *gin.Context) {
 }
 
 func querySystems(db *gorm.DB, account int, workspaceIDs []string) *gorm.DB {
-	return database.Systems(db, account, workspaceIDs, database.JoinTemplates).Select(SystemsSelect)
+	return database.Systems(db, account, workspaceIDs, database.JoinTemplates, database.JoinBaselines).
+		Select(SystemsSelect)
 }
@@ -0,0 +1,291 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maximal number of systems compared in a single request
+const maxDriftSystems = 100
+
+type SystemsDriftRequest struct {
+	// Systems to compare, at least two are required when reference_system is not set
+	Systems []SystemID `json:"systems"`
+	// System other systems are compared to, when `systems` are not set
+	// all systems matching the query filters are compared to it
+	ReferenceSystem *SystemID `json:"reference_system,omitempty"`
+}
+
+type PackageDrift struct {
+	Name string `json:"name"`
+	// Installed EVRAs per system, systems without the package are listed with an empty list
+	Systems map[SystemID][]string `json:"systems"`
+}
+
+type AdvisoryDrift struct {
+	Name string `json:"name"`
+	// Systems the advisory applies to
+	Systems []SystemID `json:"systems"`
+	// Compared systems the advisory does not apply to
+	Missing []SystemID `json:"missing"`
+}
+
+type SystemsDrift struct {
+	ReferenceSystem *SystemID       `json:"reference_system"`
+	Systems         []SystemID      `json:"systems"`
+	Packages        []PackageDrift  `json:"packages"`
+	Advisories      []AdvisoryDrift `json:"advisories"`
+}
+
+type SystemsDriftResponse struct {
+	Data SystemsDrift `json:"data"`
+}
+
+type systemsDriftSubDBLookup struct {
+	ID       int64    `query:"si.id" gorm:"column:id"`
+	SystemID SystemID `query:"si.inventory_id" gorm:"column:inventory_id"`
+}
+
+type systemsDriftPackageDBLoad struct {
+	SystemID SystemID `query:"si.inventory_id" gorm:"column:system_id"`
+	Name     string   `query:"pn.name" gorm:"column:name"`
+	Evra     string   `query:"p.evra" gorm:"column:evra"`
+}
+
+type systemsDriftAdvisoryDBLoad struct {
+	SystemID SystemID `query:"si.inventory_id" gorm:"column:system_id"`
+	Name     string   `query:"am.name" gorm:"column:name"`
+}
+
+var systemsDriftSelect = database.MustGetSelect(&systemsDriftSubDBLookup{})
+var systemsDriftPackageSelect = database.MustGetSelect(&systemsDriftPackageDBLoad{})
+var systemsDriftAdvisorySelect = database.MustGetSelect(&systemsDriftAdvisoryDBLoad{})
+var systemsDriftViewFields = database.MustGetQueryAttrs(&systemsDriftSubDBLookup{})
+var systemsDriftViewOpts = ListOpts{
+	Fields:         systemsDriftViewFields,
+	DefaultFilters: nil,
+	DefaultSort:    "inventory_id",
+	StableSort:     "inventory_id",
+	SearchFields:   nil,
+}
+
+// resolve request into the list of compared systems, reference system (if any) goes first
+func systemsDriftSystems(c *gin.Context, db *gorm.DB, acc int, workspaceIDs []string,
+	req SystemsDriftRequest) ([]systemsDriftSubDBLookup, error) {
+	if req.ReferenceSystem == nil && len(req.Systems) < 2 {
+		err := errors.New("at least two systems or a reference system are required")
+		utils.LogAndRespBadRequest(c, err, err.Error())
+		return nil, err
+	}
+
+	query := database.Systems(db, acc, workspaceIDs).Select(systemsDriftSelect)
+	requested := slices.Clone(req.Systems)
+	if req.ReferenceSystem != nil && !slices.Contains(requested, *req.ReferenceSystem) {
+		requested = append(requested, *req.ReferenceSystem)
+	}
+	if len(req.Systems) > 0 {
+		query = query.Where("si.inventory_id IN (?)", requested)
+	} else {
+		filters, err := ParseAllFilters(c, systemsDriftViewOpts)
+		if err != nil {
+			return nil, err
+		} // Error handled by method itself
+		matching := database.Systems(db, acc, workspaceIDs).
+			Select("si.id").
+			Where("si.stale = false")
+		matching, _ = ApplyInventoryFilter(filters, matching, "si.inventory_id")
+		query = query.Where("(si.inventory_id = ? OR si.id IN (?))", *req.ReferenceSystem, matching)
+	}
+
+	var systems []systemsDriftSubDBLookup
+	if err := query.Order("si.inventory_id").Limit(maxDriftSystems + 1).Find(&systems).Error; err != nil {
+		utils.LogAndRespError(c, err, "Database error")
+		return nil, err
+	}
+	if len(systems) > maxDriftSystems {
+		err := fmt.Errorf("too many systems to compare, maximum is %d", maxDriftSystems)
+		utils.LogAndRespBadRequest(c, err, err.Error())
+		return nil, err
+	}
+
+	found := make(map[SystemID]bool, len(systems))
+	for _, s := range systems {
+		found[s.SystemID] = true
+	}
+	for _, id := range requested {
+		if !found[id] {
+			err := fmt.Errorf("system %s not found", id)
+			utils.LogAndRespNotFound(c, err, "Systems not found")
+			return nil, err
+		}
+	}
+
+	if req.ReferenceSystem != nil {
+		idx := slices.IndexFunc(systems, func(s systemsDriftSubDBLookup) bool {
+			return s.SystemID == *req.ReferenceSystem
+		})
+		ref := systems[idx]
+		systems = append(systems[:idx], systems[idx+1:]...)
+		systems = append([]systemsDriftSubDBLookup{ref}, systems...)
+	}
+	return systems, nil
+}
+
+func systemsDriftPackages(db *gorm.DB, acc int, workspaceIDs []string, systemIDs []int64, inventoryIDs []SystemID,
+) ([]PackageDrift, error) {
+	var rows []systemsDriftPackageDBLoad
+	err := database.SystemPackages(db, acc, workspaceIDs).
+		Select(systemsDriftPackageSelect).
+		Where("si.id IN (?)", systemIDs).
+		Order("pn.name, si.inventory_id, p.evra").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byName := map[string]map[SystemID][]string{}
+	names := []string{}
+	for _, r := range rows {
+		if _, has := byName[r.Name]; !has {
+			byName[r.Name] = map[SystemID][]string{}
+			names = append(names, r.Name)
+		}
+		byName[r.Name][r.SystemID] = append(byName[r.Name][r.SystemID], r.Evra)
+	}
+
+	drift := []PackageDrift{}
+	for _, name := range names {
+		evras := byName[name]
+		for _, id := range inventoryIDs {
+			if _, has := evras[id]; !has {
+				evras[id] = []string{}
+			}
+		}
+		if packageDiffers(evras, inventoryIDs) {
+			drift = append(drift, PackageDrift{Name: name, Systems: evras})
+		}
+	}
+	return drift, nil
+}
+
+// package differs when it is not installed in the same EVRAs on all compared systems,
+// comparing to the first system covers the reference system which always goes first
+func packageDiffers(evras map[SystemID][]string, inventoryIDs []SystemID) bool {
+	for _, id := range inventoryIDs[1:] {
+		if !slices.Equal(evras[id], evras[inventoryIDs[0]]) {
+			return true
+		}
+	}
+	return false
+}
+
+func systemsDriftAdvisories(db *gorm.DB, acc int, workspaceIDs []string, systemIDs []int64,
+	inventoryIDs []SystemID) ([]AdvisoryDrift, error) {
+	var rows []systemsDriftAdvisoryDBLoad
+	err := database.SystemAdvisories(db, acc, workspaceIDs, database.JoinAdvisoryMetadata).
+		Select(systemsDriftAdvisorySelect).
+		Where("si.id IN (?)", systemIDs).
+		Order("am.name, si.inventory_id").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	bySystem := map[string]map[SystemID]bool{}
+	names := []string{}
+	for _, r := range rows {
+		if _, has := bySystem[r.Name]; !has {
+			bySystem[r.Name] = map[SystemID]bool{}
+			names = append(names, r.Name)
+		}
+		bySystem[r.Name][r.SystemID] = true
+	}
+
+	drift := []AdvisoryDrift{}
+	for _, name := range names {
+		applicable := bySystem[name]
+		item := AdvisoryDrift{Name: name, Systems: []SystemID{}, Missing: []SystemID{}}
+		for _, id := range inventoryIDs {
+			if applicable[id] {
+				item.Systems = append(item.Systems, id)
+			} else {
+				item.Missing = append(item.Missing, id)
+			}
+		}
+		if len(item.Missing) > 0 {
+			drift = append(drift, item)
+		}
+	}
+	return drift, nil
+}
+
+// @Summary Compare installed packages and applicable advisories of selected systems
+// @Description Show packages with different installed EVRAs and advisories which do not apply to all selected systems.
+// @Description Either pass at least two systems, or a reference system which is compared to the listed systems
+// @Description or to all systems matching the query filters.
+// @ID viewSystemsDrift
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    body    body    SystemsDriftRequest true "Request body"
+// @Param    tags                    query   []string  false "Tag filter"
+// @Param    filter[group_name] 									query []string 	false "Filter systems by inventory groups"
+// @Param    filter[system_profile][sap_system]						query bool  	false "Filter only SAP systems"
+// @Param    filter[system_profile][sap_sids]						query []string  false "Filter systems by their SAP SIDs"
+// @Success 200 {object} SystemsDriftResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /views/systems/drift [post]
+func PostSystemsDrift(c *gin.Context) {
+	var req SystemsDriftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.LogAndRespBadRequest(c, err, fmt.Sprintf("Invalid request body: %s", err.Error()))
+		return
+	}
+	acc := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+	db := middlewares.DBFromContext(c)
+
+	systems, err := systemsDriftSystems(c, db, acc, workspaceIDs, req)
+	if err != nil {
+		return
+	} // Error handled by method itself
+
+	systemIDs := make([]int64, len(systems))
+	inventoryIDs := make([]SystemID, len(systems))
+	for i, s := range systems {
+		systemIDs[i] = s.ID
+		inventoryIDs[i] = s.SystemID
+	}
+
+	packages, err := systemsDriftPackages(db, acc, workspaceIDs, systemIDs, inventoryIDs)
+	if err != nil {
+		utils.LogAndRespError(c, err, "Database error")
+		return
+	}
+	advisories, err := systemsDriftAdvisories(db, acc, workspaceIDs, systemIDs, inventoryIDs)
+	if err != nil {
+		utils.LogAndRespError(c, err, "Database error")
+		return
+	}
+
+	compared := inventoryIDs
+	if req.ReferenceSystem != nil {
+		compared = inventoryIDs[1:]
+	}
+	c.JSON(http.StatusOK, SystemsDriftResponse{Data: SystemsDrift{
+		ReferenceSystem: req.ReferenceSystem,
+		Systems:         compared,
+		Packages:        packages,
+		Advisories:      advisories,
+	}})
+}
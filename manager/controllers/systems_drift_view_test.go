@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/utils"
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSystemsDrift(t *testing.T, req SystemsDriftRequest, q string, expectedStatus int, output interface{}) {
+	core.SetupTest(t)
+	bodyJSON, err := sonic.Marshal(&req)
+	assert.Nil(t, err)
+	w := CreateRequestRouterWithParams("POST", "/", "", q, bytes.NewBuffer(bodyJSON), "", PostSystemsDrift, 1)
+	CheckResponse(t, w, expectedStatus, output)
+}
+
+func TestSystemsDriftIdentical(t *testing.T) {
+	var output SystemsDriftResponse
+	testSystemsDrift(t, SystemsDriftRequest{
+		Systems: []SystemID{"00000000-0000-0000-0000-000000000002", "00000000-0000-0000-0000-000000000003"},
+	}, "", http.StatusOK, &output)
+	assert.Nil(t, output.Data.ReferenceSystem)
+	assert.Equal(t, 2, len(output.Data.Systems))
+	assert.Equal(t, 0, len(output.Data.Packages))
+	assert.Equal(t, 0, len(output.Data.Advisories))
+}
+
+func TestSystemsDriftDiffers(t *testing.T) {
+	var output SystemsDriftResponse
+	testSystemsDrift(t, SystemsDriftRequest{
+		Systems: []SystemID{"00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000002"},
+	}, "", http.StatusOK, &output)
+	assert.Equal(t, 2, len(output.Data.Packages))
+	assert.Equal(t, "firefox", output.Data.Packages[0].Name)
+	assert.Equal(t, []string{}, output.Data.Packages[0].Systems["00000000-0000-0000-0000-000000000001"])
+	assert.Equal(t, []string{"5.6.13-200.fc31.x86_64"},
+		output.Data.Packages[1].Systems["00000000-0000-0000-0000-000000000002"])
+	// RH-1 applies to both systems, the rest only to the first one
+	assert.Equal(t, 7, len(output.Data.Advisories))
+	assert.Equal(t, []SystemID{"00000000-0000-0000-0000-000000000002"}, output.Data.Advisories[0].Missing)
+}
+
+func TestSystemsDriftReferenceTags(t *testing.T) {
+	var output SystemsDriftResponse
+	ref := SystemID("00000000-0000-0000-0000-000000000002")
+	testSystemsDrift(t, SystemsDriftRequest{ReferenceSystem: &ref}, "?tags=ns1/k3=val4", http.StatusOK, &output)
+	assert.Equal(t, ref, *output.Data.ReferenceSystem)
+	assert.Equal(t, []SystemID{"00000000-0000-0000-0000-000000000003", "00000000-0000-0000-0000-000000000004"},
+		output.Data.Systems)
+	assert.Equal(t, 2, len(output.Data.Packages))
+}
+
+func TestSystemsDriftNotEnoughSystems(t *testing.T) {
+	var errResp utils.ErrorResponse
+	testSystemsDrift(t, SystemsDriftRequest{Systems: []SystemID{"00000000-0000-0000-0000-000000000001"}}, "",
+		http.StatusBadRequest, &errResp)
+	assert.Equal(t, "at least two systems or a reference system are required", errResp.Error)
+}
+
+func TestSystemsDriftNotFound(t *testing.T) {
+	var errResp utils.ErrorResponse
+	testSystemsDrift(t, SystemsDriftRequest{
+		Systems: []SystemID{"00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000012"},
+	}, "", http.StatusNotFound, &errResp)
+	assert.Equal(t, "Systems not found", errResp.Error)
+}
@@ -34,6 +34,7 @@ import (
 // @Param    filter[packages_installed]   query   int   false   "Filter"
 // @Param    filter[packages_installable] query   int   false   "Filter"
 // @Param    filter[packages_applicable]  query   int   false   "Filter"
+// @Param    filter[packages_held]        query   int   false   "Filter"
 // @Param    filter[group_name] 									query []string 	false "Filter systems by inventory groups"
 // @Param    filter[system_profile][sap_system]						query bool  	false "Filter only SAP systems"
 // @Param    filter[system_profile][sap_sids]						query []string  false "Filter systems by their SAP SIDs"
@@ -16,10 +16,10 @@ import (
 var SystemCsvHeader = "id,display_name,os,rhsm,tags,last_evaluation," +
 	"rhsa_count,rhba_count,rhea_count,other_count,packages_installed," +
 	"baseline_name,last_upload,stale_timestamp,stale_warning_timestamp,culled_timestamp,created,stale," +
-	"satellite_managed,image_based,built_pkgcache,packages_installable,packages_applicable," +
+	"satellite_managed,image_based,built_pkgcache,packages_installable,packages_applicable,packages_held," +
 	"installable_rhsa_count,installable_rhba_count,installable_rhea_count,installable_other_count," +
 	"applicable_rhsa_count,applicable_rhba_count,applicable_rhea_count,applicable_other_count," +
-	"baseline_id,template_name,template_uuid,groups,workspace_id,workspace_name,arch"
+	"baseline_id,baseline_advisories,template_name,template_uuid,groups,workspace_id,workspace_name,arch,reboot_required"
 
 func makeRequest(t *testing.T, path string, contentType string) *httptest.ResponseRecorder {
 	core.SetupTest(t)
@@ -60,9 +60,9 @@ func TestSystemsExportCSV(t *testing.T) {
 		"\"[{'key':'k1','namespace':'ns1','value':'val1'},{'key':'k2','namespace':'ns1','value':'val2'}]\","+
 		"2018-09-22T16:00:00Z,2,2,1,0,0,,"+
 		"2020-09-22T16:00:00Z,2018-08-26T16:00:00Z,2018-09-02T16:00:00Z,,2018-08-26T16:00:00Z,"+
-		"false,false,true,false,0,0,2,2,1,0,2,3,3,3,0,temp1-1,99900000-0000-0000-0000-000000000001,"+
+		"false,false,true,false,0,0,0,2,2,1,0,2,3,3,3,0,0,temp1-1,99900000-0000-0000-0000-000000000001,"+
 		"\"[{'id':'00000000-0000-0000-0000-000000000001','name':'group1'}]\","+
-		"00000000-0000-0000-0000-000000000001,group1,x86_64",
+		"00000000-0000-0000-0000-000000000001,group1,x86_64,false",
 		lines[1])
 }
 
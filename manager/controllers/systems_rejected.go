@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/manager/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+var RejectedSystemsFields = database.MustGetQueryAttrs(&RejectedSystemsDBLookup{})
+var RejectedSystemsSelect = database.MustGetSelect(&RejectedSystemsDBLookup{})
+var RejectedSystemsOpts = ListOpts{
+	Fields:         RejectedSystemsFields,
+	DefaultFilters: map[string]FilterData{},
+	DefaultSort:    "-last_rejected",
+	StableSort:     "ru.inventory_id",
+	SearchFields:   []string{"ru.display_name"},
+}
+
+type RejectedSystemsDBLookup struct {
+	ID uuid.UUID `json:"id" csv:"id" query:"ru.inventory_id" gorm:"column:id"`
+	// a helper to get total number of systems
+	MetaTotalHelper
+	RejectedSystemAttributes
+}
+
+// nolint: lll
+type RejectedSystemAttributes struct {
+	DisplayName *string `json:"display_name" csv:"display_name" query:"ru.display_name" gorm:"column:display_name"`
+	Reporter    string  `json:"reporter" csv:"reporter" query:"ru.reporter" gorm:"column:reporter"`
+	HostType    *string `json:"host_type" csv:"host_type" query:"ru.host_type" gorm:"column:host_type"`
+	// Reason of the rejection: no-packages, malformed-packages
+	Reason string `json:"reason" csv:"reason" query:"ru.reason" gorm:"column:reason"`
+	// Sample of the data which caused the rejection
+	Sample        RejectedUploadSample `json:"sample" csv:"sample" query:"ru.sample" gorm:"column:sample"`
+	FirstRejected time.Time            `json:"first_rejected" csv:"first_rejected" query:"ru.first_rejected" gorm:"column:first_rejected"`
+	LastRejected  time.Time            `json:"last_rejected" csv:"last_rejected" query:"ru.last_rejected" gorm:"column:last_rejected"`
+	// Number of rejected uploads since the host was processed successfully
+	RejectedCount int        `json:"rejected_count" csv:"rejected_count" query:"ru.rejected_count" gorm:"column:rejected_count"`
+	Replayed      *time.Time `json:"replayed" csv:"replayed" query:"ru.replayed" gorm:"column:replayed"`
+}
+
+type RejectedUploadSample map[string]interface{}
+
+func (v *RejectedUploadSample) Scan(value interface{}) error {
+	switch b := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return sonic.Unmarshal(b, v)
+	case string:
+		return sonic.UnmarshalString(b, v)
+	}
+	return errors.Errorf("unsupported rejected upload sample type %T", value)
+}
+
+type RejectedSystemItem struct {
+	Attributes RejectedSystemAttributes `json:"attributes"`
+	// Inventory ID (uuid format)
+	InventoryID uuid.UUID `json:"inventory_id"`
+	// Document type name
+	Type string `json:"type"`
+}
+
+type RejectedSystemsResponse struct {
+	Data  []RejectedSystemItem `json:"data"`
+	Links Links                `json:"links"`
+	Meta  ListMeta             `json:"meta"`
+}
+
+func rejectedSystemsQuery(db *gorm.DB, account int, workspaceIDs []string) *gorm.DB {
+	query := db.Table("rejected_upload ru").
+		Select(RejectedSystemsSelect).
+		Where("ru.rh_account_id = ?", account)
+	if len(workspaceIDs) > 0 {
+		query = query.Where("ru.workspace_id IN (?)", workspaceIDs)
+	}
+	return query
+}
+
+func rejectedSystemsData(systems []RejectedSystemsDBLookup) ([]RejectedSystemItem, int) {
+	var total int
+	if len(systems) > 0 {
+		total = systems[0].Total
+	}
+	data := make([]RejectedSystemItem, len(systems))
+	for i, s := range systems {
+		data[i] = RejectedSystemItem{
+			Attributes:  s.RejectedSystemAttributes,
+			InventoryID: s.ID,
+			Type:        "rejected_system",
+		}
+	}
+	return data, total
+}
+
+// nolint: lll
+// @Summary Show me systems whose uploads were rejected
+// @Description Show me systems whose last upload was rejected by patch with the reason and a sample of offending data.
+// @Description Systems are removed from the list after their next successful upload.
+// @ID listRejectedSystems
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    limit          query   int     false   "Limit for paging" minimum(1) maximum(100)
+// @Param    offset         query   int     false   "Offset for paging"
+// @Param    sort           query   string  false   "Sort field"    Enums(id,display_name,reporter,host_type,reason,first_rejected,last_rejected,rejected_count,replayed)
+// @Param    search         query   string  false   "Find matching text"
+// @Param    filter[display_name]           query   string  false "Filter"
+// @Param    filter[reporter]               query   string  false "Filter"
+// @Param    filter[host_type]              query   string  false "Filter"
+// @Param    filter[reason]                 query   string  false "Filter"
+// @Success 200 {object} RejectedSystemsResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /systems/rejected [get]
+func RejectedSystemsListHandler(c *gin.Context) {
+	account := c.GetInt(utils.KeyAccount)
+	workspaceIDs := c.GetStringSlice(utils.KeyInventoryWorkspaces)
+
+	db := middlewares.DBFromContext(c)
+	query := rejectedSystemsQuery(db, account, workspaceIDs)
+	filters, err := ParseAllFilters(c, RejectedSystemsOpts)
+	if err != nil {
+		return
+	} // Error handled in method itself
+	query, meta, params, err := ListCommon(query, c, filters, RejectedSystemsOpts)
+	if err != nil {
+		return
+	} // Error handled in method itself
+
+	var systems []RejectedSystemsDBLookup
+	if err = query.Find(&systems).Error; err != nil {
+		utils.LogAndRespError(c, err, "Database error")
+		return
+	}
+
+	data, total := rejectedSystemsData(systems)
+	meta, links, err := UpdateMetaLinks(c, meta, total, nil, params...)
+	if err != nil {
+		return
+	} // Error handled in method itself
+	c.JSON(http.StatusOK, &RejectedSystemsResponse{Data: data, Links: *links, Meta: *meta})
+}
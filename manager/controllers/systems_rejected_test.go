@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func createRejectedUpload(t *testing.T, account int, inventoryID string, reason string, sample string) {
+	assert.Nil(t, database.DB.Create(&models.RejectedUpload{
+		RhAccountID:   account,
+		InventoryID:   uuid.MustParse(inventoryID),
+		DisplayName:   &inventoryID,
+		Reporter:      "puptoo",
+		WorkspaceID:   database.TestWorkspace1IDPtr(),
+		Reason:        reason,
+		Sample:        []byte(sample),
+		Event:         []byte("{}"),
+		FirstRejected: time.Now(),
+		LastRejected:  time.Now(),
+		RejectedCount: 1,
+	}).Error)
+}
+
+func deleteRejectedUploads(t *testing.T, account int) {
+	assert.Nil(t, database.DB.Delete(&models.RejectedUpload{}, "rh_account_id = ?", account).Error)
+}
+
+func TestRejectedSystemsList(t *testing.T) {
+	core.SetupTest(t)
+	createRejectedUpload(t, 1, "99c0ffee-0000-0000-0000-0000000000e1", "malformed-packages",
+		`{"error": "missing epoch"}`)
+	createRejectedUpload(t, 2, "99c0ffee-0000-0000-0000-0000000000e2", "no-packages", "null")
+	defer deleteRejectedUploads(t, 1)
+	defer deleteRejectedUploads(t, 2)
+
+	w := CreateRequestRouterWithParams("GET", "/systems/rejected", "", "", nil, "", RejectedSystemsListHandler, 1)
+
+	var output RejectedSystemsResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 1, len(output.Data))
+	assert.Equal(t, "99c0ffee-0000-0000-0000-0000000000e1", output.Data[0].InventoryID.String())
+	assert.Equal(t, "rejected_system", output.Data[0].Type)
+	assert.Equal(t, "malformed-packages", output.Data[0].Attributes.Reason)
+	assert.Equal(t, RejectedUploadSample{"error": "missing epoch"}, output.Data[0].Attributes.Sample)
+}
+
+func TestRejectedSystemsListFilter(t *testing.T) {
+	core.SetupTest(t)
+	createRejectedUpload(t, 1, "99c0ffee-0000-0000-0000-0000000000e1", "malformed-packages",
+		`{"error": "missing epoch"}`)
+	defer deleteRejectedUploads(t, 1)
+
+	w := CreateRequestRouterWithParams("GET", "/systems/rejected", "", "?filter[reason]=no-packages", nil, "",
+		RejectedSystemsListHandler, 1)
+
+	var output RejectedSystemsResponse
+	CheckResponse(t, w, http.StatusOK, &output)
+	assert.Equal(t, 0, len(output.Data))
+}
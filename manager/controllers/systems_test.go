@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"app/base/core"
+	"app/base/database"
 	"app/base/utils"
 	"bytes"
 	"fmt"
@@ -499,3 +500,14 @@ func TestWorkloadOrLogic(t *testing.T) {
 	assert.Equal(t, uuid.MustParse("00000000-0000-0000-0000-000000000002"), output.Data[1].ID)
 	assert.Equal(t, uuid.MustParse("00000000-0000-0000-0000-000000000017"), output.Data[2].ID)
 }
+
+func TestSystemsFilterRebootRequired(t *testing.T) {
+	core.SetupTest(t)
+	database.SetAdvisoriesRebootRequired(t, []int64{3}, true)
+	defer database.SetAdvisoriesRebootRequired(t, []int64{3}, false)
+
+	output := testSystems(t, `?filter[reboot_required]=true`, 1)
+	assert.Equal(t, 1, output.Meta.TotalItems)
+	assert.Equal(t, uuid.MustParse("00000000-0000-0000-0000-000000000001"), output.Data[0].ID)
+	assert.True(t, output.Data[0].Attributes.RebootRequired)
+}
@@ -4,6 +4,7 @@ import (
 	"app/base/core"
 	"app/base/database"
 	"app/base/utils"
+	"app/manager/config"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -62,6 +63,28 @@ func TestUpdateTemplateSubscribedSystems(t *testing.T) {
 	database.DeleteTemplate(t, templateAccount, templateUUID)
 }
 
+func TestUpdateTemplateSubscribedSystemsKessel(t *testing.T) {
+	core.SetupTest(t)
+
+	// system identity has no user to be checked by Kessel host check
+	originalKessel, originalHostCheck := utils.CoreCfg.KesselEnabled, config.EnableKesselHostCheck
+	utils.CoreCfg.KesselEnabled, config.EnableKesselHostCheck = true, true
+	defer func() {
+		utils.CoreCfg.KesselEnabled, config.EnableKesselHostCheck = originalKessel, originalHostCheck
+	}()
+
+	database.CreateTemplate(t, templateAccount, templateUUID, nil)
+
+	w := CreateRequestRouterWithParams("PATCH", "/:template_id/subscribed-systems", templateUUID, "", nil, "",
+		TemplateSubscribedSystemsUpdateHandler, templateAccount,
+		core.ContextKV{Key: utils.KeySystem, Value: subscriptionUUID},
+		core.ContextKV{Key: utils.KeyOrgID, Value: orgID})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	database.CheckTemplateSystems(t, templateAccount, templateUUID, []uuid.UUID{testInventoryID4})
+	database.DeleteTemplate(t, templateAccount, templateUUID)
+}
+
 func TestUpdateTemplateSubscribedSystemsInvalid(t *testing.T) {
 	core.SetupTest(t)
 
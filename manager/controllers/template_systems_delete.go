@@ -19,6 +19,7 @@ import (
 // @Param    body    body   TemplateSystemsUpdateRequest true "Request body"
 // @Success 200
 // @Failure 400 {object} 	utils.ErrorResponse
+// @Failure 403 {object} 	utils.ErrorResponse
 // @Failure 404 {object} 	utils.ErrorResponse
 // @Failure 500 {object} 	utils.ErrorResponse
 // @Router /templates/systems [DELETE]
@@ -33,7 +34,7 @@ func TemplateSystemsDeleteHandler(c *gin.Context) {
 		return
 	}
 
-	if err := checkTemplateSystemsLimit(len(req.Systems), c); err != nil {
+	if err := checkSystemsLimit(len(req.Systems), TemplateSystemsUpdateLimit, c); err != nil {
 		return
 	}
 
@@ -44,6 +45,10 @@ func TemplateSystemsDeleteHandler(c *gin.Context) {
 		return
 	}
 
+	if err = checkSystemsAccess(c, req.Systems); err != nil {
+		return
+	}
+
 	err = assignCandlepinEnvironment(c, db, account, nil, req.Systems, workspaceIDs)
 	if err != nil {
 		return
@@ -50,6 +50,7 @@ type SystemTemplateDBLookup struct {
 // @Param    template_id    path  string   true  "Template ID"
 // @Success 200
 // @Failure 400 {object} 	utils.ErrorResponse
+// @Failure 403 {object} 	utils.ErrorResponse
 // @Failure 404 {object} 	utils.ErrorResponse
 // @Failure 500 {object} 	utils.ErrorResponse
 // @Router /templates/{template_id}/systems [PATCH]
@@ -65,7 +66,7 @@ func TemplateSystemsUpdateHandler(c *gin.Context) {
 		return
 	}
 
-	if err := checkTemplateSystemsLimit(len(req.Systems), c); err != nil {
+	if err := checkSystemsLimit(len(req.Systems), TemplateSystemsUpdateLimit, c); err != nil {
 		return
 	}
 
@@ -81,6 +82,10 @@ func TemplateSystemsUpdateHandler(c *gin.Context) {
 		return
 	}
 
+	if err = checkSystemsAccess(c, req.Systems); err != nil {
+		return
+	}
+
 	err = assignCandlepinEnvironment(c, db, account, &template.EnvironmentID, req.Systems, workspaceIDs)
 	if err != nil {
 		return
@@ -131,6 +136,26 @@ func checkTemplateSystems(c *gin.Context, db *gorm.DB, accountID int, template *
 	return nil
 }
 
+// checkSystemsAccess checks permission of the user for each system, systems authenticated
+// by their own certificate (subscribed-systems) don't have user identity to be checked
+func checkSystemsAccess(c *gin.Context, inventoryIDs []uuid.UUID) error {
+	denied, err := middlewares.KesselCheckHosts(c, inventoryIDs)
+	if errors.Is(err, middlewares.ErrTooManyHosts) {
+		utils.LogAndRespBadRequest(c, err, err.Error())
+		return err
+	}
+	if err != nil {
+		utils.LogAndRespStatusError(c, http.StatusInternalServerError, err, "Communication with RBAC failed")
+		return err
+	}
+	if len(denied) > 0 {
+		err = fmt.Errorf("missing permission for systems: %v", denied)
+		utils.LogAndRespStatusError(c, http.StatusForbidden, err, err.Error())
+		return err
+	}
+	return nil
+}
+
 func assignTemplateSystems(c *gin.Context, db *gorm.DB, accountID int, template *models.Template,
 	inventoryIDs []uuid.UUID) error {
 	tx := db.Begin()
@@ -214,7 +239,7 @@ func callCandlepin(ctx context.Context, owner string, request *candlepin.Consume
 		return &candlepinResp, resp, err
 	}
 
-	candlepinRespPtr, err := utils.HTTPCallRetry(candlepinFunc,
+	candlepinRespPtr, err := candlepinClient.CallRetry(candlepinFunc,
 		candlepin.CandlepinExpRetries, candlepin.CandlepinRetries, http.StatusServiceUnavailable)
 	if err != nil {
 		return nil, errors.Wrap(err, "candlepin call "+candlepinEnvConsumersURL+" failed")
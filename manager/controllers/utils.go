@@ -36,10 +36,8 @@ func ApplySort(c *gin.Context, tx *gorm.DB, fieldExprs database.AttrMap,
 	query := c.DefaultQuery("sort", defaultSort)
 	fields := strings.Split(query, ",")
 	appliedFields := make([]string, 0, len(fields))
-	allowedFieldSet := map[string]bool{
-		"id": true,
-	}
-
+	// only fields with query expression can be sorted, e.g. `id` is not an attribute of every list
+	allowedFieldSet := map[string]bool{}
 	for f := range fieldExprs {
 		allowedFieldSet[f] = true
 	}
@@ -96,19 +94,9 @@ func ParseFilters(c *gin.Context, filters Filters, allowedFields database.AttrMa
 		if strings.HasPrefix(name, "filter[") {
 			subject := name[7 : len(name)-1] // strip key from "filter[...]"
 			for _, v := range values {
-				if _, ok := workloadFilters[subject]; ok {
-					filters.Update(WorkloadFilter, subject, v)
-					continue
-				}
-				if _, ok := inventoryFilters[subject]; ok {
-					filters.Update(InventoryFilter, subject, v)
-					continue
-				}
-				if _, ok := allowedFields[subject]; !ok {
-					return errors.Errorf(InvalidFilter, subject)
+				if err := updateFilter(filters, allowedFields, subject, v); err != nil {
+					return err
 				}
-
-				filters.Update(ColumnFilter, subject, v)
 			}
 		}
 	}
@@ -123,6 +111,24 @@ func ParseFilters(c *gin.Context, filters Filters, allowedFields database.AttrMa
 	return nil
 }
 
+// Add filter value of given subject (field name) into filters
+func updateFilter(filters Filters, allowedFields database.AttrMap, subject, value string) error {
+	if _, ok := workloadFilters[subject]; ok {
+		filters.Update(WorkloadFilter, subject, value)
+		return nil
+	}
+	if _, ok := inventoryFilters[subject]; ok {
+		filters.Update(InventoryFilter, subject, value)
+		return nil
+	}
+	if _, ok := allowedFields[subject]; !ok {
+		return errors.Errorf(InvalidFilter, subject)
+	}
+
+	filters.Update(ColumnFilter, subject, value)
+	return nil
+}
+
 type ListOpts struct {
 	Fields         database.AttrMap
 	DefaultFilters map[string]FilterData
@@ -335,33 +341,41 @@ func ParseAllFilters(c *gin.Context, opts ListOpts) (Filters, error) {
 func parseTags(c *gin.Context, filters Filters) error {
 	tags := c.QueryArray("tags")
 	for _, t := range tags {
-		tag, err := ParseTag(t)
-		if err != nil {
+		if err := updateTagFilter(filters, t); err != nil {
 			utils.LogAndRespBadRequest(c, err, err.Error())
 			return err
 		}
+	}
 
-		key := tag.Key
-		if tag.Namespace != nil {
-			key = *tag.Namespace + "/" + tag.Key
-		}
+	return nil
+}
 
-		var value []string
-		if value = []string{}; tag.Value != nil {
-			var val string
-			val, err := strconv.Unquote(*tag.Value)
-			if err != nil {
-				val = *tag.Value
-			}
-			value = strings.Split(val, ",")
-		}
-		filters[key] = FilterData{
-			Type:     TagFilter,
-			Operator: "eq",
-			Values:   value,
-		}
+// Add tag in 'namespace/key=val' format into filters
+func updateTagFilter(filters Filters, t string) error {
+	tag, err := ParseTag(t)
+	if err != nil {
+		return err
+	}
+
+	key := tag.Key
+	if tag.Namespace != nil {
+		key = *tag.Namespace + "/" + tag.Key
 	}
 
+	var value []string
+	if value = []string{}; tag.Value != nil {
+		var val string
+		val, err := strconv.Unquote(*tag.Value)
+		if err != nil {
+			val = *tag.Value
+		}
+		value = strings.Split(val, ",")
+	}
+	filters[key] = FilterData{
+		Type:     TagFilter,
+		Operator: "eq",
+		Values:   value,
+	}
 	return nil
 }
 
@@ -584,9 +598,9 @@ func isFilterInURLValid(c *gin.Context) bool {
 	return true
 }
 
-func checkTemplateSystemsLimit(numSystems int, c *gin.Context) error {
-	if numSystems > TemplateSystemsUpdateLimit {
-		msg := fmt.Sprintf("Cannot process more than %d systems at once", TemplateSystemsUpdateLimit)
+func checkSystemsLimit(numSystems, limit int, c *gin.Context) error {
+	if numSystems > limit {
+		msg := fmt.Sprintf("Cannot process more than %d systems at once", limit)
 		err := errors.New(msg)
 		utils.LogAndRespBadRequest(c, err, msg)
 		return err
@@ -51,3 +51,10 @@ func TestGroupNameFilter2(t *testing.T) {
 
 	assert.Equal(t, 9, len(systems))
 }
+
+func TestApplySortUnknownID(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/?sort=id", nil)
+	_, _, err := ApplySort(c, nil, database.AttrMap{"name": {}}, "name", "r.id")
+	assert.EqualError(t, err, "Invalid sort field: id")
+}
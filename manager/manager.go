@@ -40,9 +40,13 @@ func RunManager() {
 	utils.LogInfo("port", port, "Manager starting at port")
 	// create web app
 	app := gin.New()
+	// gin context falls back to request context carrying the trace
+	app.ContextWithFallback = true
 
 	// middlewares
 	app.Use(gin.Recovery())
+	app.Use(middlewares.Tracing())
+	app.Use(middlewares.RequestID())
 	middlewares.Prometheus().Use(app)
 	app.Use(middlewares.MaxConnections(utils.CoreCfg.MaxGinConnections))
 	app.Use(middlewares.Ratelimit(utils.CoreCfg.Ratelimit))
@@ -65,6 +69,7 @@ func RunManager() {
 	go utils.RunProfiler()
 
 	go base.TryExposeOnMetricsPort(app)
+	go middlewares.RunReplicaLagMonitor(base.Context)
 	controllers.InitAdvisoryDetailCache()
 	go controllers.PreloadAdvisoryCacheItems()
 
@@ -3,38 +3,166 @@ package middlewares
 import (
 	"app/base/database"
 	"app/base/utils"
+	"app/manager/config"
+	"context"
 	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 	"gorm.io/gorm"
 )
 
 const DBKey = "DB"
 const DBReadReplicaKey = "DBReadReplica"
 
+// ReplicaPolicy decides which database serves GET requests of a route
+type ReplicaPolicy string
+
+const (
+	// always use primary database
+	PolicyPrimary ReplicaPolicy = "primary"
+	// use read replica whenever it is configured and its last replication lag measurement succeeded recently
+	PolicyReplica ReplicaPolicy = "replica"
+	// use read replica unless its replication lag exceeds db_replica_max_lag or is unknown
+	PolicyReplicaMaxLag ReplicaPolicy = "replica_max_lag"
+)
+
+type replicaRouting struct {
+	defaultPolicy ReplicaPolicy
+	// policies by route path, e.g. /api/patch/v3/systems/:inventory_id
+	routes map[string]ReplicaPolicy
+	maxLag time.Duration
+}
+
+type replicaLagState struct {
+	lag      time.Duration
+	measured time.Time
+}
+
+// last measured replication lag, nil when unknown
+var replicaLag atomic.Pointer[replicaLagState]
+
+func parseReplicaPolicy(value string) (ReplicaPolicy, error) {
+	switch policy := ReplicaPolicy(strings.TrimSpace(value)); policy {
+	case PolicyPrimary, PolicyReplica, PolicyReplicaMaxLag:
+		return policy, nil
+	}
+	return "", errors.Errorf("invalid read replica policy %q", value)
+}
+
+func newReplicaRouting(defaultPolicy, routePolicies string, maxLag time.Duration) (replicaRouting, error) {
+	routing := replicaRouting{routes: map[string]ReplicaPolicy{}, maxLag: maxLag}
+	var err error
+	if routing.defaultPolicy, err = parseReplicaPolicy(defaultPolicy); err != nil {
+		return routing, err
+	}
+	for _, item := range strings.Split(routePolicies, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		route, policy, found := strings.Cut(item, "|")
+		if !found {
+			return routing, errors.Errorf("invalid read replica route policy %q", item)
+		}
+		if routing.routes[strings.TrimSpace(route)], err = parseReplicaPolicy(policy); err != nil {
+			return routing, err
+		}
+	}
+	return routing, nil
+}
+
 // Apply gin context to database so queries within context are canceled when request is aborted
 func DatabaseWithContext() gin.HandlerFunc {
+	routing, err := newReplicaRouting(config.DBReplicaPolicy, config.DBReplicaRoutePolicies,
+		time.Duration(config.DBReplicaMaxLag)*time.Second)
+	if err != nil {
+		utils.LogFatal("err", err, "Read replica routing")
+	}
 	return func(c *gin.Context) {
 		c.Set(DBKey, database.DB.WithContext(c))
-		if database.DBReadReplica != nil {
+		served := "primary"
+		if database.DBReadReplica != nil && routing.useReadReplica(c) {
 			c.Set(DBReadReplicaKey, database.DBReadReplica.WithContext(c))
+			served = "replica"
 		}
+		dbRequestCnt.WithLabelValues(c.FullPath(), served).Inc()
 		c.Next()
 	}
 }
 
 // DB handler stored in request context
 func DBFromContext(c *gin.Context) *gorm.DB {
-	if useReadReplica(c) {
-		return c.MustGet(DBReadReplicaKey).(*gorm.DB)
+	if db, ok := c.Get(DBReadReplicaKey); ok {
+		return db.(*gorm.DB)
 	}
 	return c.MustGet(DBKey).(*gorm.DB)
 }
 
-func useReadReplica(c *gin.Context) bool {
-	if utils.CoreCfg.DBReadReplicaEnabled && c.Request.Method == http.MethodGet {
-		// if Host or Port is not set, don't use read replica
-		return database.ReadReplicaConfigured()
+func (r replicaRouting) useReadReplica(c *gin.Context) bool {
+	// if Host or Port is not set, don't use read replica
+	if !utils.CoreCfg.DBReadReplicaEnabled || c.Request.Method != http.MethodGet || !database.ReadReplicaConfigured() {
+		return false
+	}
+	policy, ok := r.routes[c.FullPath()]
+	if !ok {
+		policy = r.defaultPolicy
+	}
+	switch policy {
+	case PolicyReplica:
+		return replicaLagKnown() != nil
+	case PolicyReplicaMaxLag:
+		return replicaLagWithin(r.maxLag)
 	}
 	return false
 }
+
+// replicaLagKnown returns recent replication lag measurement, nil when the last measurement failed or is outdated
+func replicaLagKnown() *replicaLagState {
+	state := replicaLag.Load()
+	if state == nil || time.Since(state.measured) > 3*replicaLagInterval() {
+		return nil
+	}
+	return state
+}
+
+// replicaLagWithin reports whether recently measured replication lag is within maxLag
+func replicaLagWithin(maxLag time.Duration) bool {
+	state := replicaLagKnown()
+	return state != nil && state.lag <= maxLag
+}
+
+func replicaLagInterval() time.Duration {
+	return time.Duration(config.DBReplicaLagInterval) * time.Second
+}
+
+// RunReplicaLagMonitor periodically measures replication lag of read replica until ctx is canceled
+func RunReplicaLagMonitor(ctx context.Context) {
+	if database.DBReadReplica == nil {
+		return
+	}
+	ticker := time.NewTicker(replicaLagInterval())
+	defer ticker.Stop()
+	for {
+		measureReplicaLag(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func measureReplicaLag(ctx context.Context) {
+	lag, err := database.ReplicaLag(database.DBReadReplica.WithContext(ctx))
+	if err != nil {
+		utils.LogWarn("err", err, "Unable to measure read replica lag")
+		replicaLagGauge.Set(-1)
+		replicaLag.Store(nil)
+		return
+	}
+	replicaLagGauge.Set(lag.Seconds())
+	replicaLag.Store(&replicaLagState{lag: lag, measured: time.Now()})
+}
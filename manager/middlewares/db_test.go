@@ -0,0 +1,67 @@
+package middlewares
+
+import (
+	"app/base/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReplicaRouting(t *testing.T) {
+	routing, err := newReplicaRouting("replica_max_lag",
+		"/api/patch/v3/systems/:inventory_id|primary, /api/patch/v3/advisories|replica", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyReplicaMaxLag, routing.defaultPolicy)
+	assert.Equal(t, map[string]ReplicaPolicy{
+		"/api/patch/v3/systems/:inventory_id": PolicyPrimary,
+		"/api/patch/v3/advisories":            PolicyReplica,
+	}, routing.routes)
+
+	_, err = newReplicaRouting("secondary", "", time.Second)
+	assert.Error(t, err)
+	_, err = newReplicaRouting("replica", "/api/patch/v3/advisories", time.Second)
+	assert.Error(t, err)
+}
+
+func TestUseReadReplica(t *testing.T) {
+	cfg := utils.CoreCfg
+	defer func() {
+		utils.CoreCfg = cfg
+		replicaLag.Store(nil)
+	}()
+	utils.CoreCfg.DBReadReplicaEnabled = true
+	utils.CoreCfg.DBReadReplicaHost = "replica"
+	utils.CoreCfg.DBReadReplicaPort = 5432
+
+	routing, err := newReplicaRouting("replica_max_lag",
+		"/systems/:inventory_id|primary,/advisories|replica", 10*time.Second)
+	require.NoError(t, err)
+	useReplica := func(method, path string) (result bool) {
+		router := gin.New()
+		router.Handle(method, path, func(c *gin.Context) { result = routing.useReadReplica(c) })
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+		return result
+	}
+
+	assert.False(t, useReplica(http.MethodGet, "/systems/:inventory_id"))
+	// unknown lag, replica may be down
+	assert.False(t, useReplica(http.MethodGet, "/advisories"))
+	assert.False(t, useReplica(http.MethodGet, "/packages"))
+
+	replicaLag.Store(&replicaLagState{lag: time.Second, measured: time.Now()})
+	assert.True(t, useReplica(http.MethodGet, "/advisories"))
+	assert.False(t, useReplica(http.MethodPost, "/advisories"))
+	assert.True(t, useReplica(http.MethodGet, "/packages"))
+	replicaLag.Store(&replicaLagState{lag: time.Minute, measured: time.Now()})
+	assert.True(t, useReplica(http.MethodGet, "/advisories"))
+	assert.False(t, useReplica(http.MethodGet, "/packages"))
+	// outdated measurement
+	replicaLag.Store(&replicaLagState{lag: time.Second, measured: time.Now().Add(-time.Hour)})
+	assert.False(t, useReplica(http.MethodGet, "/advisories"))
+	assert.False(t, useReplica(http.MethodGet, "/packages"))
+}
@@ -35,6 +35,14 @@ func setupClient() (kesselv2.KesselInventoryServiceClient, *grpc.ClientConn, err
 	return clientBuilder.Build()
 }
 
+// POST handlers which modify data and require edit permission
+var postEditHandlers = map[string]bool{
+	"PatchPlanCreateHandler":         true,
+	"BaselineCreateHandler":          true,
+	"AdvisoryExclusionCreateHandler": true,
+	"PackageHoldCreateHandler":       true,
+}
+
 func buildPermission(c *gin.Context) string {
 	permission := "patch_system_"
 	nameSplit := strings.Split(c.HandlerName(), ".")
@@ -44,7 +52,13 @@ func buildPermission(c *gin.Context) string {
 	}
 
 	switch c.Request.Method {
-	case http.MethodGet, http.MethodPost:
+	case http.MethodPost:
+		if postEditHandlers[handlerName] {
+			permission += "edit"
+		} else {
+			permission += "view"
+		}
+	case http.MethodGet:
 		permission += "view"
 	case http.MethodPatch, http.MethodPut, http.MethodDelete:
 		permission += "edit"
@@ -61,14 +75,9 @@ func useStreamedListObjects(
 
 	workspaces := make([]*kesselv2.StreamedListObjectsResponse, 0)
 	start := time.Now()
-	var userID string
-	switch {
-	case xrhid.Identity.User != nil && xrhid.Identity.User.UserID != "":
-		userID = xrhid.Identity.User.UserID
-	case xrhid.Identity.ServiceAccount != nil && xrhid.Identity.ServiceAccount.UserId != "":
-		userID = xrhid.Identity.ServiceAccount.UserId
-	default:
-		return nil, errors.New("user_id not found in identity")
+	userID, err := identityUserID(xrhid)
+	if err != nil {
+		return nil, err
 	}
 	for res, err := range kesselRbacV2.ListWorkspaces(
 		sloReqContext, client, kesselRbacV2.PrincipalSubject(userID, "redhat"), permission, "",
@@ -91,6 +100,23 @@ func useStreamedListObjects(
 }
 
 func hasPermissionKessel(c *gin.Context) {
+	xrhid, err := utils.ParseXRHID(c.GetHeader("x-rh-identity"))
+	if err != nil {
+		utils.LogError("err", err, "failed to ParseXRHID")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, utils.ErrorResponse{Error: "Invalid x-rh-identity header"})
+		return
+	}
+
+	permission := buildPermission(c)
+	invalidateOnNoCache(c, xrhid)
+	key, cacheable := permissionCacheKey(xrhid, permissionSourceKessel, permission)
+	if cacheable {
+		if decision, ok := getCachedPermission(permissionSourceKessel, key); ok {
+			setKesselWorkspaces(c, decision.WorkspaceIDs)
+			return
+		}
+	}
+
 	client, conn, err := setupClient()
 	if err != nil {
 		utils.LogError("err", err, "failed to setup Kessel service client")
@@ -106,14 +132,7 @@ func hasPermissionKessel(c *gin.Context) {
 		}
 	}()
 
-	xrhid, err := utils.ParseXRHID(c.GetHeader("x-rh-identity"))
-	if err != nil {
-		utils.LogError("err", err, "failed to ParseXRHID")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, utils.ErrorResponse{Error: "Invalid x-rh-identity header"})
-		return
-	}
-
-	workspaces, err := useStreamedListObjects(c, client, xrhid, buildPermission(c))
+	workspaces, err := useStreamedListObjects(c, client, xrhid, permission)
 	if err != nil {
 		// already logged in useStreamedListObjects
 		c.AbortWithStatusJSON(http.StatusInternalServerError, utils.ErrorResponse{
@@ -127,7 +146,13 @@ func hasPermissionKessel(c *gin.Context) {
 	for _, workspace := range workspaces {
 		workspaceIDs = append(workspaceIDs, workspace.Object.ResourceId)
 	}
+	if cacheable {
+		cachePermission(permissionSourceKessel, key, len(workspaceIDs) > 0, workspaceIDs)
+	}
+	setKesselWorkspaces(c, workspaceIDs)
+}
 
+func setKesselWorkspaces(c *gin.Context, workspaceIDs []string) {
 	if len(workspaceIDs) == 0 {
 		utils.LogWarn(errors.New("no workspaces found"))
 		c.AbortWithStatusJSON(http.StatusUnauthorized, utils.ErrorResponse{Error: "Missing permission"})
@@ -0,0 +1,149 @@
+package middlewares
+
+import (
+	"app/base/utils"
+	"app/manager/config"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/redhatinsights/platform-go-middlewares/v2/identity"
+
+	kesselv2 "github.com/project-kessel/kessel-sdk-go/kessel/inventory/v1beta2"
+	kesselRbacV2 "github.com/project-kessel/kessel-sdk-go/kessel/rbac/v2"
+)
+
+var ErrTooManyHosts = errors.New("too many systems to check")
+
+func hostCheckItem(userID, permission, inventoryID string) *kesselv2.CheckBulkRequestItem {
+	return &kesselv2.CheckBulkRequestItem{
+		Object: &kesselv2.ResourceReference{
+			ResourceType: "host",
+			ResourceId:   inventoryID,
+			Reporter:     &kesselv2.ReporterReference{Type: "hbi"},
+		},
+		Relation: permission,
+		Subject:  kesselRbacV2.PrincipalSubject(userID, "redhat"),
+	}
+}
+
+// checkHostsBulk checks hosts in one CheckBulk call, returns decisions in the order of inventoryIDs
+func checkHostsBulk(ctx context.Context, client kesselv2.KesselInventoryServiceClient, userID, permission string,
+	inventoryIDs []uuid.UUID) ([]bool, error) {
+	items := make([]*kesselv2.CheckBulkRequestItem, len(inventoryIDs))
+	for i, inventoryID := range inventoryIDs {
+		items[i] = hostCheckItem(userID, permission, inventoryID.String())
+	}
+
+	start := time.Now()
+	resp, err := client.CheckBulk(ctx, &kesselv2.CheckBulkRequest{Items: items})
+	kesselCheckDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		kesselCheckCnt.WithLabelValues("error").Inc()
+		return nil, err
+	}
+
+	pairs := resp.GetPairs()
+	if len(pairs) != len(items) {
+		kesselCheckCnt.WithLabelValues("error").Inc()
+		return nil, errors.Errorf("kessel returned %d decisions for %d hosts", len(pairs), len(items))
+	}
+	allowed := make([]bool, len(pairs))
+	for i, pair := range pairs {
+		if pairErr := pair.GetError(); pairErr != nil {
+			kesselCheckCnt.WithLabelValues("error").Inc()
+			return nil, errors.Errorf("check of host %s failed: %s", inventoryIDs[i], pairErr.GetMessage())
+		}
+		allowed[i] = pair.GetItem().GetAllowed() == kesselv2.Allowed_ALLOWED_TRUE
+		if allowed[i] {
+			kesselCheckCnt.WithLabelValues("allowed").Inc()
+		} else {
+			kesselCheckCnt.WithLabelValues("denied").Inc()
+		}
+	}
+	return allowed, nil
+}
+
+// Check permission of the request for each of the given hosts, returns list of denied hosts.
+// Cached decisions are used first, remaining hosts are checked in batches by Kessel bulk check.
+func KesselCheckHosts(c *gin.Context, inventoryIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if !utils.CoreCfg.KesselEnabled || !config.EnableKesselHostCheck {
+		return nil, nil
+	}
+	if len(inventoryIDs) > config.KesselHostCheckMaxHosts {
+		return nil, errors.Wrapf(ErrTooManyHosts, "%d systems requested, max %d", len(inventoryIDs),
+			config.KesselHostCheckMaxHosts)
+	}
+
+	xrhid, err := utils.ParseXRHID(c.GetHeader("x-rh-identity"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to ParseXRHID")
+	}
+	userID, err := identityUserID(xrhid)
+	if err != nil {
+		return nil, err
+	}
+
+	permission := buildPermission(c)
+	allowed := make(map[uuid.UUID]bool, len(inventoryIDs))
+	unchecked := make([]uuid.UUID, 0, len(inventoryIDs))
+	for _, inventoryID := range inventoryIDs {
+		key, cacheable := permissionCacheKey(xrhid, permissionSourceKesselCheck, permission, inventoryID.String())
+		if cacheable {
+			if decision, ok := getCachedPermission(permissionSourceKesselCheck, key); ok {
+				allowed[inventoryID] = decision.Granted
+				continue
+			}
+		}
+		unchecked = append(unchecked, inventoryID)
+	}
+	if len(unchecked) > 0 {
+		if err = checkHostsBatches(c, xrhid, userID, permission, unchecked, allowed); err != nil {
+			return nil, err
+		}
+	}
+
+	denied := []uuid.UUID{}
+	for _, inventoryID := range inventoryIDs {
+		if !allowed[inventoryID] {
+			denied = append(denied, inventoryID)
+		}
+	}
+	utils.LogDebug("permission", permission, "checked", len(inventoryIDs), "uncached", len(unchecked),
+		"denied", len(denied), "kessel host check")
+	return denied, nil
+}
+
+// checkHostsBatches checks hosts by Kessel bulk check in batches, decisions are cached and stored to allowed
+func checkHostsBatches(ctx context.Context, xrhid *identity.XRHID, userID, permission string,
+	inventoryIDs []uuid.UUID, allowed map[uuid.UUID]bool) error {
+
+	client, conn, err := setupClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to setup Kessel service client")
+	}
+	defer func() {
+		if closeErr := conn.Close(); closeErr != nil {
+			utils.LogError("err", closeErr, "failed to close gRPC client")
+		}
+	}()
+
+	batchSize := max(config.KesselHostCheckBatchSize, 1)
+	for batch := range slices.Chunk(inventoryIDs, batchSize) {
+		decisions, err := checkHostsBulk(ctx, client, userID, permission, batch)
+		if err != nil {
+			return errors.Wrap(err, "kessel check failed")
+		}
+		for i, inventoryID := range batch {
+			allowed[inventoryID] = decisions[i]
+			key, cacheable := permissionCacheKey(xrhid, permissionSourceKesselCheck, permission, inventoryID.String())
+			if cacheable {
+				cachePermission(permissionSourceKesselCheck, key, decisions[i], nil)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package middlewares
+
+import (
+	"app/base/utils"
+	"app/manager/config"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKesselCheckHosts(t *testing.T) {
+	allowedHost := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	deniedHost := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	cleanup := withTestKesselServer(t, nil, deniedHost.String())
+	defer cleanup()
+
+	originalEnabled := utils.CoreCfg.KesselEnabled
+	utils.CoreCfg.KesselEnabled = true
+	defer func() { utils.CoreCfg.KesselEnabled = originalEnabled }()
+
+	c, _ := newKesselTestContext()
+	denied, err := KesselCheckHosts(c, []uuid.UUID{allowedHost, deniedHost})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{deniedHost}, denied)
+	assert.Equal(t, []int{2}, testKessel.bulkCalls)
+
+	// decisions are cached
+	decision, ok := getCachedPermission(permissionSourceKesselCheck,
+		"11789772:6089719:kessel_check:patch_system_view:"+allowedHost.String())
+	assert.True(t, ok)
+	assert.True(t, decision.Granted)
+
+	// cached decisions are not checked again
+	denied, err = KesselCheckHosts(c, []uuid.UUID{deniedHost, allowedHost})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{deniedHost}, denied)
+	assert.Equal(t, []int{2}, testKessel.bulkCalls)
+}
+
+func TestKesselCheckHostsBatches(t *testing.T) {
+	hosts := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	cleanup := withTestKesselServer(t, nil, hosts[3].String())
+	defer cleanup()
+
+	originalEnabled := utils.CoreCfg.KesselEnabled
+	originalBatchSize := config.KesselHostCheckBatchSize
+	utils.CoreCfg.KesselEnabled = true
+	config.KesselHostCheckBatchSize = 2
+	defer func() {
+		utils.CoreCfg.KesselEnabled = originalEnabled
+		config.KesselHostCheckBatchSize = originalBatchSize
+	}()
+
+	c, _ := newKesselTestContext()
+	denied, err := KesselCheckHosts(c, hosts)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{hosts[3]}, denied)
+	assert.Equal(t, []int{2, 2, 1}, testKessel.bulkCalls)
+}
+
+func TestKesselCheckHostsTooMany(t *testing.T) {
+	originalEnabled := utils.CoreCfg.KesselEnabled
+	originalMaxHosts := config.KesselHostCheckMaxHosts
+	utils.CoreCfg.KesselEnabled = true
+	config.KesselHostCheckMaxHosts = 1
+	defer func() {
+		utils.CoreCfg.KesselEnabled = originalEnabled
+		config.KesselHostCheckMaxHosts = originalMaxHosts
+	}()
+
+	c, _ := newKesselTestContext()
+	_, err := KesselCheckHosts(c, []uuid.UUID{uuid.New(), uuid.New()})
+	assert.ErrorIs(t, err, ErrTooManyHosts)
+}
+
+func TestKesselCheckHostsDisabled(t *testing.T) {
+	originalEnabled := utils.CoreCfg.KesselEnabled
+	utils.CoreCfg.KesselEnabled = false
+	defer func() { utils.CoreCfg.KesselEnabled = originalEnabled }()
+
+	c, _ := newKesselTestContext()
+	denied, err := KesselCheckHosts(c, []uuid.UUID{uuid.New()})
+	assert.NoError(t, err)
+	assert.Empty(t, denied)
+}
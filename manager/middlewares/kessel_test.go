@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"app/base/utils"
+	"context"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -22,6 +23,29 @@ const testXRHIdentity = "ewogICAgImVudGl0bGVtZW50cyI6IHsKICAgICAgICAiaW5zaWdodHM
 type testKesselServer struct {
 	kesselv2.UnimplementedKesselInventoryServiceServer
 	workspaceIDs []string
+	deniedHosts  map[string]bool
+	// number of items of each CheckBulk call
+	bulkCalls []int
+}
+
+// server started by the last withTestKesselServer call
+var testKessel *testKesselServer
+
+func (s *testKesselServer) CheckBulk(_ context.Context, req *kesselv2.CheckBulkRequest,
+) (*kesselv2.CheckBulkResponse, error) {
+	s.bulkCalls = append(s.bulkCalls, len(req.GetItems()))
+	resp := &kesselv2.CheckBulkResponse{}
+	for _, item := range req.GetItems() {
+		allowed := kesselv2.Allowed_ALLOWED_TRUE
+		if s.deniedHosts[item.GetObject().GetResourceId()] {
+			allowed = kesselv2.Allowed_ALLOWED_FALSE
+		}
+		resp.Pairs = append(resp.Pairs, &kesselv2.CheckBulkResponsePair{
+			Request:  item,
+			Response: &kesselv2.CheckBulkResponsePair_Item{Item: &kesselv2.CheckBulkResponseItem{Allowed: allowed}},
+		})
+	}
+	return resp, nil
 }
 
 func (s *testKesselServer) StreamedListObjects(_ *kesselv2.StreamedListObjectsRequest,
@@ -40,18 +64,25 @@ func (s *testKesselServer) StreamedListObjects(_ *kesselv2.StreamedListObjectsRe
 	return nil
 }
 
-func withTestKesselServer(t *testing.T, workspaceIDs []string) func() {
+func withTestKesselServer(t *testing.T, workspaceIDs []string, deniedHosts ...string) func() {
 	t.Helper()
 
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	require.NoError(t, err)
 
 	grpcServer := grpc.NewServer()
-	kesselv2.RegisterKesselInventoryServiceServer(grpcServer, &testKesselServer{workspaceIDs: workspaceIDs})
+	server := &testKesselServer{workspaceIDs: workspaceIDs, deniedHosts: map[string]bool{}}
+	for _, id := range deniedHosts {
+		server.deniedHosts[id] = true
+	}
+	kesselv2.RegisterKesselInventoryServiceServer(grpcServer, server)
+	testKessel = server
 	go func() {
 		_ = grpcServer.Serve(listener)
 	}()
 
+	// drop decisions cached by previous tests
+	permissionCache.Invalidate("")
 	originalURL := utils.CoreCfg.KesselURL
 	originalInsecure := utils.CoreCfg.KesselInsecure
 	utils.CoreCfg.KesselURL = listener.Addr().String()
@@ -59,6 +90,7 @@ func withTestKesselServer(t *testing.T, workspaceIDs []string) func() {
 
 	return func() {
 		grpcServer.Stop()
+		permissionCache.Invalidate("")
 		_ = listener.Close()
 		utils.CoreCfg.KesselURL = originalURL
 		utils.CoreCfg.KesselInsecure = originalInsecure
@@ -0,0 +1,150 @@
+package middlewares
+
+import (
+	"app/base/utils"
+	"app/manager/config"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/pkg/errors"
+	"github.com/redhatinsights/platform-go-middlewares/v2/identity"
+)
+
+const (
+	permissionSourceRBAC        = "rbac"
+	permissionSourceKessel      = "kessel"
+	permissionSourceKesselCheck = "kessel_check"
+)
+
+type PermissionDecision struct {
+	Granted      bool      `json:"granted"`
+	WorkspaceIDs []string  `json:"workspace_ids,omitempty"`
+	Expires      time.Time `json:"expires"`
+}
+
+// Storage of permission decisions, in-memory LRU is used by default.
+// Shared storage (e.g. redis) can be plugged in by SetPermissionCacheBackend to share decisions between pods.
+type PermissionCacheBackend interface {
+	Get(key string) (*PermissionDecision, bool)
+	Add(key string, decision *PermissionDecision)
+	// Remove all decisions with keys starting with prefix
+	Invalidate(prefix string)
+	Len() int
+}
+
+type memoryPermissionCache struct {
+	data *lru.Cache[string, *PermissionDecision]
+}
+
+func NewMemoryPermissionCache(size int) PermissionCacheBackend {
+	data, err := lru.New[string, *PermissionDecision](size)
+	if err != nil {
+		panic(err)
+	}
+	return &memoryPermissionCache{data: data}
+}
+
+func (c *memoryPermissionCache) Get(key string) (*PermissionDecision, bool) {
+	return c.data.Get(key)
+}
+
+func (c *memoryPermissionCache) Add(key string, decision *PermissionDecision) {
+	c.data.Add(key, decision)
+}
+
+func (c *memoryPermissionCache) Invalidate(prefix string) {
+	for _, key := range c.data.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			c.data.Remove(key)
+		}
+	}
+}
+
+func (c *memoryPermissionCache) Len() int {
+	return c.data.Len()
+}
+
+var permissionCache = NewMemoryPermissionCache(config.PermissionCacheSize)
+
+func SetPermissionCacheBackend(backend PermissionCacheBackend) {
+	permissionCache = backend
+}
+
+func identityUserID(xrhid *identity.XRHID) (string, error) {
+	switch {
+	case xrhid.Identity.User != nil && xrhid.Identity.User.UserID != "":
+		return xrhid.Identity.User.UserID, nil
+	case xrhid.Identity.ServiceAccount != nil && xrhid.Identity.ServiceAccount.UserId != "":
+		return xrhid.Identity.ServiceAccount.UserId, nil
+	default:
+		return "", errors.New("user_id not found in identity")
+	}
+}
+
+func identityOrgID(xrhid *identity.XRHID) string {
+	if xrhid.Identity.OrgID != "" {
+		return xrhid.Identity.OrgID
+	}
+	return xrhid.Identity.Internal.OrgID
+}
+
+// Cache keys are `org_id:user_id:source:permission...`, decisions of an org or user can be invalidated by prefix
+func permissionCacheKey(xrhid *identity.XRHID, source string, permission ...string) (string, bool) {
+	userID, err := identityUserID(xrhid)
+	orgID := identityOrgID(xrhid)
+	if err != nil || orgID == "" {
+		return "", false
+	}
+	parts := append([]string{orgID, userID, source}, permission...)
+	return strings.Join(parts, ":"), true
+}
+
+// Request with `Cache-Control: no-cache` header drops cached decisions of the user
+func invalidateOnNoCache(c *gin.Context, xrhid *identity.XRHID) {
+	if !strings.Contains(c.GetHeader("Cache-Control"), "no-cache") {
+		return
+	}
+	if userID, err := identityUserID(xrhid); err == nil {
+		InvalidatePermissionCache(identityOrgID(xrhid), userID)
+	}
+}
+
+func getCachedPermission(source, key string) (*PermissionDecision, bool) {
+	decision, ok := permissionCache.Get(key)
+	if !ok || time.Now().After(decision.Expires) {
+		permissionCacheCnt.WithLabelValues(source, "miss").Inc()
+		return nil, false
+	}
+	permissionCacheCnt.WithLabelValues(source, "hit").Inc()
+	return decision, true
+}
+
+func cachePermission(source, key string, granted bool, workspaceIDs []string) {
+	ttl := config.PermissionCacheTTL
+	if !granted {
+		ttl = config.PermissionCacheNegativeTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+	permissionCache.Add(key, &PermissionDecision{
+		Granted:      granted,
+		WorkspaceIDs: workspaceIDs,
+		Expires:      time.Now().Add(time.Duration(ttl) * time.Second),
+	})
+	permissionCacheGauge.Set(float64(permissionCache.Len()))
+}
+
+// Drop cached decisions of given org, or of given users within the org
+func InvalidatePermissionCache(orgID string, userIDs ...string) {
+	utils.LogDebug("org_id", orgID, "users", len(userIDs), "invalidating permission cache")
+	if len(userIDs) == 0 {
+		permissionCache.Invalidate(orgID + ":")
+	}
+	for _, userID := range userIDs {
+		permissionCache.Invalidate(orgID + ":" + userID + ":")
+	}
+	permissionCacheGauge.Set(float64(permissionCache.Len()))
+}
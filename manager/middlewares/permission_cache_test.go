@@ -0,0 +1,80 @@
+package middlewares
+
+import (
+	"app/manager/config"
+	"testing"
+	"time"
+
+	"github.com/redhatinsights/platform-go-middlewares/v2/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionCacheKey(t *testing.T) {
+	key, ok := permissionCacheKey(mockXRHID("user"), permissionSourceKessel, "patch_system_view")
+	assert.True(t, ok)
+	assert.Equal(t, "12345:12345:kessel:patch_system_view", key)
+
+	// identity without user can't be cached
+	_, ok = permissionCacheKey(&identity.XRHID{Identity: identity.Identity{OrgID: "12345"}}, permissionSourceRBAC)
+	assert.False(t, ok)
+}
+
+func TestPermissionCacheTTL(t *testing.T) {
+	defer permissionCache.Invalidate("")
+
+	cachePermission(permissionSourceRBAC, "org:user:rbac:patch:*:read", true, []string{"ws1"})
+	decision, ok := getCachedPermission(permissionSourceRBAC, "org:user:rbac:patch:*:read")
+	assert.True(t, ok)
+	assert.True(t, decision.Granted)
+	assert.Equal(t, []string{"ws1"}, decision.WorkspaceIDs)
+
+	// expired decision
+	permissionCache.Add("org:user:rbac:patch:*:write", &PermissionDecision{Expires: time.Now().Add(-time.Second)})
+	_, ok = getCachedPermission(permissionSourceRBAC, "org:user:rbac:patch:*:write")
+	assert.False(t, ok)
+
+	// denials are not cached with zero negative ttl
+	originalTTL := config.PermissionCacheNegativeTTL
+	config.PermissionCacheNegativeTTL = 0
+	defer func() { config.PermissionCacheNegativeTTL = originalTTL }()
+	cachePermission(permissionSourceRBAC, "org:user:rbac:patch:*:write", false, nil)
+	_, ok = getCachedPermission(permissionSourceRBAC, "org:user:rbac:patch:*:write")
+	assert.False(t, ok)
+}
+
+func TestInvalidatePermissionCache(t *testing.T) {
+	defer permissionCache.Invalidate("")
+
+	cachePermission(permissionSourceRBAC, "org1:user1:rbac:patch:*:read", true, nil)
+	cachePermission(permissionSourceRBAC, "org1:user2:rbac:patch:*:read", true, nil)
+	cachePermission(permissionSourceRBAC, "org2:user1:rbac:patch:*:read", true, nil)
+
+	InvalidatePermissionCache("org1", "user1")
+	_, ok := getCachedPermission(permissionSourceRBAC, "org1:user1:rbac:patch:*:read")
+	assert.False(t, ok)
+	_, ok = getCachedPermission(permissionSourceRBAC, "org1:user2:rbac:patch:*:read")
+	assert.True(t, ok)
+
+	InvalidatePermissionCache("org1")
+	_, ok = getCachedPermission(permissionSourceRBAC, "org1:user2:rbac:patch:*:read")
+	assert.False(t, ok)
+	_, ok = getCachedPermission(permissionSourceRBAC, "org2:user1:rbac:patch:*:read")
+	assert.True(t, ok)
+}
+
+func TestHasPermissionKesselCached(t *testing.T) {
+	cleanup := withTestKesselServer(t, []string{"aaaaaaaa-0000-0000-0000-000000000001"})
+	c, _ := newKesselTestContext()
+	hasPermissionKessel(c)
+	assert.False(t, c.IsAborted())
+	// stop kessel server, decision is taken from cache
+	cleanup()
+	key := "11789772:6089719:kessel:patch_system_view"
+	cachePermission(permissionSourceKessel, key, true, []string{"aaaaaaaa-0000-0000-0000-000000000001"})
+	defer permissionCache.Invalidate("")
+
+	c, _ = newKesselTestContext()
+	hasPermissionKessel(c)
+	assert.False(t, c.IsAborted())
+	assert.Equal(t, []string{"aaaaaaaa-0000-0000-0000-000000000001"}, c.GetStringSlice("workspaceIDs"))
+}
@@ -57,10 +57,54 @@ var PackageAccountDataCnt = prometheus.NewCounterVec(prometheus.CounterOpts{
 	Name:      "package_account_data_cache",
 }, []string{"type"})
 
+var kesselCheckCnt = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Help:      "How many Kessel host checks were allowed/denied/failed",
+	Namespace: "patchman_engine",
+	Subsystem: "manager",
+	Name:      "kessel_check",
+}, []string{"result"})
+
+var kesselCheckDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Help:      "Kessel host check durations",
+	Namespace: "patchman_engine",
+	Subsystem: "manager",
+	Name:      "kessel_check_duration_seconds",
+	Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+})
+
+var permissionCacheCnt = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Help:      "How many permission decisions hit/miss cache",
+	Namespace: "patchman_engine",
+	Subsystem: "manager",
+	Name:      "permission_cache",
+}, []string{"source", "type"})
+
+var permissionCacheGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+	Help:      "Permission decision cache size",
+	Namespace: "patchman_engine",
+	Subsystem: "manager",
+	Name:      "permission_cache_size",
+})
+
+var dbRequestCnt = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Help:      "How many requests were served by primary/replica database",
+	Namespace: "patchman_engine",
+	Subsystem: "manager",
+	Name:      "db_requests",
+}, []string{"endpoint", "db"})
+
+var replicaLagGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+	Help:      "Last measured replication lag of read replica, -1 when the measurement failed",
+	Namespace: "patchman_engine",
+	Subsystem: "manager",
+	Name:      "db_replica_lag_seconds",
+})
+
 // Create and configure Prometheus middleware to expose metrics
 func Prometheus() *ginprometheus.Prometheus {
 	prometheus.MustRegister(serviceErrorCnt, requestDurations, callerSourceCnt,
-		AdvisoryDetailCnt, AdvisoryDetailGauge, AdvisoryAccountDataCnt, PackageAccountDataCnt)
+		AdvisoryDetailCnt, AdvisoryDetailGauge, AdvisoryAccountDataCnt, PackageAccountDataCnt,
+		kesselCheckCnt, kesselCheckDuration, permissionCacheCnt, permissionCacheGauge, dbRequestCnt, replicaLagGauge)
 
 	p := ginprometheus.NewPrometheus("patchman_engine")
 	p.MetricsPath = utils.CoreCfg.MetricsPath
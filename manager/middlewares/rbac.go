@@ -32,9 +32,13 @@ const patchWritePerm = "patch:*:write"
 
 // handlerName to permissions mapping
 var granularPerms = map[string]string{
-	"TemplateSystemsUpdateHandler": "content-sources:templates:write",
-	"TemplateSystemsDeleteHandler": "content-sources:templates:write",
-	"SystemDeleteHandler":          "patch:system:write",
+	"TemplateSystemsUpdateHandler":   "content-sources:templates:write",
+	"TemplateSystemsDeleteHandler":   "content-sources:templates:write",
+	"SystemDeleteHandler":            "patch:system:write",
+	"PatchPlanCreateHandler":         "patch:*:write",
+	"BaselineCreateHandler":          "patch:*:write",
+	"AdvisoryExclusionCreateHandler": "patch:*:write",
+	"PackageHoldCreateHandler":       "patch:*:write",
 }
 
 // Make RBAC client on demand, with specified identity
@@ -45,6 +49,7 @@ func makeClient(identity string) *api.Client {
 		HTTPClient:     httpClient,
 		Debug:          debugRequest,
 		DefaultHeaders: map[string]string{xRHIdentity: identity},
+		Upstream:       api.GetUpstream(api.UpstreamRbac),
 	}
 	if rbacURL == "" {
 		rbacURL = utils.FailIfEmpty(utils.CoreCfg.RbacAddress, "RBAC_ADDRESS") + base.RBACApiPrefix +
@@ -68,6 +73,23 @@ func expandedPermission(perm string) []string {
 	return expandedPerm
 }
 
+// Permission needed by API handler, empty when the handler can't be accessed
+func patchNeededPerm(handlerName, method string) string {
+	if p, has := granularPerms[handlerName]; has {
+		return p
+	}
+	// not granular
+	// require read permissions for GET and POST
+	// require write permissions for PUT and DELETE
+	switch method {
+	case "GET", "POST":
+		return patchReadPerm
+	case "PUT", "DELETE":
+		return patchWritePerm
+	}
+	return ""
+}
+
 func checkPermissions(access *rbac.AccessPagination, handlerName, method string) bool {
 	// always need inventory:hosts:read
 	grantedInventory := false
@@ -76,18 +98,8 @@ func checkPermissions(access *rbac.AccessPagination, handlerName, method string)
 	// API handler specific permission
 	grantedPatch := false
 	patchNeededPerms := []string{}
-	if p, has := granularPerms[handlerName]; has {
+	if p := patchNeededPerm(handlerName, method); p != "" {
 		patchNeededPerms = expandedPermission(p)
-	} else {
-		// not granular
-		// require read permissions for GET and POST
-		// require write permissions for PUT and DELETE
-		switch method {
-		case "GET", "POST":
-			patchNeededPerms = expandedPermission(patchReadPerm)
-		case "PUT", "DELETE":
-			patchNeededPerms = expandedPermission(patchWritePerm)
-		}
 	}
 
 	for _, a := range access.Data {
@@ -105,9 +117,29 @@ func checkPermissions(access *rbac.AccessPagination, handlerName, method string)
 }
 
 func isAccessGranted(c *gin.Context) bool {
+	nameSplitted := strings.Split(c.HandlerName(), ".")
+	handlerName := nameSplitted[len(nameSplitted)-1]
+
+	// decisions are cached only for identities with org_id and user_id
+	var key string
+	cacheable := false
+	if xrhid, err := utils.ParseXRHID(c.GetHeader("x-rh-identity")); err == nil {
+		invalidateOnNoCache(c, xrhid)
+		key, cacheable = permissionCacheKey(xrhid, permissionSourceRBAC, patchNeededPerm(handlerName, c.Request.Method))
+	}
+	if cacheable {
+		if decision, ok := getCachedPermission(permissionSourceRBAC, key); ok {
+			if decision.Granted {
+				c.Set(utils.KeyInventoryWorkspaces, decision.WorkspaceIDs)
+			}
+			return decision.Granted
+		}
+	}
+
 	client := makeClient(c.GetHeader("x-rh-identity"))
 	access := rbac.AccessPagination{}
-	res, err := client.Request(&base.Context, http.MethodGet, rbacURL, nil, &access)
+	ctx := c.Request.Context()
+	res, err := client.Request(&ctx, http.MethodGet, rbacURL, nil, &access)
 	if res != nil && res.Body != nil {
 		defer res.Body.Close()
 	}
@@ -121,18 +153,19 @@ func isAccessGranted(c *gin.Context) bool {
 		serviceErrorCnt.WithLabelValues("rbac", strconv.Itoa(status)).Inc()
 		return false
 	}
-	nameSplitted := strings.Split(c.HandlerName(), ".")
-	handlerName := nameSplitted[len(nameSplitted)-1]
-
 	granted := checkPermissions(&access, handlerName, c.Request.Method)
+	var workspaces []string
 	if granted {
-		workspaces, err := findInventoryWorkspaces(&access)
+		workspaces, err = findInventoryWorkspaces(&access)
 		if err != nil {
 			utils.LogError("err", err, "RBAC")
 			granted = false
 		}
 		c.Set(utils.KeyInventoryWorkspaces, workspaces)
 	}
+	if cacheable {
+		cachePermission(permissionSourceRBAC, key, granted, workspaces)
+	}
 	return granted
 }
 
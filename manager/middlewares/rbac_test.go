@@ -46,6 +46,10 @@ func TestRBACPut(t *testing.T) {
 	testRBAC(t, "PUT", http.StatusUnauthorized)
 }
 
+func restoreGranularPerms(perms map[string]string) {
+	granularPerms = perms
+}
+
 func TestPermissionsSingleWrite(t *testing.T) {
 	// handler needs `content-sources:templates:write`
 	handler := "TemplateSystemsUpdateHandler"
@@ -100,6 +104,7 @@ func TestPermissionsSingleWrite(t *testing.T) {
 func TestPermissionsSingleRead(t *testing.T) {
 	// handler needs `patch:single:read`
 	handler := "SingleRead"
+	defer restoreGranularPerms(granularPerms)
 	granularPerms = map[string]string{"SingleRead": "patch:single:read"}
 	access := rbac.AccessPagination{
 		Data: []rbac.Access{
@@ -154,6 +159,7 @@ func TestPermissionsSingleRead(t *testing.T) {
 func TestPermissionsSingleReadWrite(t *testing.T) {
 	// handler needs `patch:single:read`
 	handler := "SingleReadWrite"
+	defer restoreGranularPerms(granularPerms)
 	granularPerms = map[string]string{"SingleReadWrite": "patch:single:*"}
 	access := rbac.AccessPagination{
 		Data: []rbac.Access{
@@ -215,6 +221,7 @@ func TestPermissionsSingleReadWrite(t *testing.T) {
 func TestPermissionsRead(t *testing.T) {
 	// handler needs `patch:single:read`
 	handler := "Read"
+	defer restoreGranularPerms(granularPerms)
 	granularPerms = map[string]string{"Read": "patch:*:read"}
 	access := rbac.AccessPagination{
 		Data: []rbac.Access{
@@ -491,3 +498,35 @@ func TestPermissionsAllowedOperations(t *testing.T) {
 		rootWorkspaceID,
 	}, workspaces)
 }
+
+// POST handlers which modify data are denied with read only patch permission
+func testPermissionsCreate(t *testing.T, handler string) {
+	access := rbac.AccessPagination{
+		Data: []rbac.Access{
+			{Permission: "patch:*:read"},
+			{Permission: "inventory:*:*"},
+		},
+	}
+	assert.False(t, checkPermissions(&access, handler, "POST"))
+
+	access = rbac.AccessPagination{
+		Data: []rbac.Access{
+			{Permission: "patch:*:write"},
+			{Permission: "inventory:*:*"},
+		},
+	}
+	assert.True(t, checkPermissions(&access, handler, "POST"))
+	assert.True(t, postEditHandlers[handler])
+}
+
+func TestPermissionsBaselineCreate(t *testing.T) {
+	testPermissionsCreate(t, "BaselineCreateHandler")
+}
+
+func TestPermissionsAdvisoryExclusionCreate(t *testing.T) {
+	testPermissionsCreate(t, "AdvisoryExclusionCreateHandler")
+}
+
+func TestPermissionsPackageHoldCreate(t *testing.T) {
+	testPermissionsCreate(t, "PackageHoldCreateHandler")
+}
@@ -0,0 +1,21 @@
+package middlewares
+
+import (
+	"app/base/api"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestID propagates request id of the API request to upstream services called within the request,
+// new id is generated when the request has none
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(api.RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		c.Request = c.Request.WithContext(api.ContextWithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"app/base/api"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	var upstreamRequestID string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamRequestID = r.Header.Get(api.RequestIDHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	router := gin.Default()
+	router.Use(RequestID())
+	router.GET("/", func(c *gin.Context) {
+		client := api.Client{HTTPClient: &http.Client{}}
+		ctx := c.Request.Context()
+		resp, err := client.Request(&ctx, http.MethodGet, upstream.URL, nil, nil)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(api.RequestIDHeader, "request-1")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "request-1", upstreamRequestID)
+}
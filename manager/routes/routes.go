@@ -36,20 +36,68 @@ func InitAPI(api *gin.RouterGroup, config docs.EndpointsConfig) { // nolint: fun
 	systems := userAuth.Group("/systems")
 	systems.GET("", controllers.SystemsListHandler)
 	systems.POST("", controllers.SystemsListPostHandler)
+	systems.GET("/rejected", controllers.RejectedSystemsListHandler)
 	systems.GET("/:inventory_id", controllers.SystemDetailHandler)
 	systems.GET("/:inventory_id/advisories", controllers.SystemAdvisoriesHandler)
+	systems.GET("/:inventory_id/reboot_plan", controllers.SystemRebootPlanHandler)
 	systems.GET("/:inventory_id/packages", controllers.SystemPackagesHandler)
+	systems.GET("/:inventory_id/repos", controllers.SystemReposHandler)
+	systems.GET("/:inventory_id/package_history", controllers.SystemPackageHistoryHandler)
 	systems.GET("/:inventory_id/vmaas_json", controllers.SystemVmaasJSONHandler)
 	systems.GET("/:inventory_id/yum_updates", controllers.SystemYumUpdatesHandler)
 
 	userAuth.GET("/tags", controllers.SystemTagListHandler)
 
+	patchPlans := userAuth.Group("/patch-plans")
+	patchPlans.GET("", controllers.PatchPlansListHandler)
+	patchPlans.POST("", controllers.PatchPlanCreateHandler)
+	patchPlans.GET("/:plan_id", controllers.PatchPlanDetailHandler)
+	patchPlans.GET("/:plan_id/systems", controllers.PatchPlanSystemsHandler)
+	patchPlans.DELETE("/:plan_id", controllers.PatchPlanDeleteHandler)
+
+	baselines := userAuth.Group("/baselines")
+	baselines.GET("", controllers.BaselinesListHandler)
+	baselines.POST("", controllers.BaselineCreateHandler)
+	baselines.GET("/:baseline_id", controllers.BaselineDetailHandler)
+	baselines.PUT("/:baseline_id", controllers.BaselineUpdateHandler)
+	baselines.DELETE("/:baseline_id", controllers.BaselineDeleteHandler)
+	baselines.PUT("/:baseline_id/systems", controllers.BaselineSystemsUpdateHandler)
+	baselines.DELETE("/systems", controllers.BaselineSystemsDeleteHandler)
+
+	exclusions := userAuth.Group("/advisory-exclusions")
+	exclusions.GET("", controllers.AdvisoryExclusionsListHandler)
+	exclusions.POST("", controllers.AdvisoryExclusionCreateHandler)
+	exclusions.GET("/:exclusion_id", controllers.AdvisoryExclusionDetailHandler)
+	exclusions.DELETE("/:exclusion_id", controllers.AdvisoryExclusionDeleteHandler)
+
+	packageHolds := userAuth.Group("/package-holds")
+	packageHolds.GET("", controllers.PackageHoldsListHandler)
+	packageHolds.POST("", controllers.PackageHoldCreateHandler)
+	packageHolds.GET("/:hold_id", controllers.PackageHoldDetailHandler)
+	packageHolds.DELETE("/:hold_id", controllers.PackageHoldDeleteHandler)
+
+	culling := userAuth.Group("/culling")
+	culling.GET("/policy", controllers.CullingPolicyHandler)
+	culling.PUT("/policy", controllers.CullingPolicyUpdateHandler)
+	culling.DELETE("/policy", controllers.CullingPolicyDeleteHandler)
+	culling.GET("/systems", controllers.CullingSystemsListHandler)
+
+	remediations := userAuth.Group("/remediations")
+	remediations.POST("/playbook", controllers.RemediationPlaybookHandler)
+
 	packages := userAuth.Group("/packages")
 	packages.GET("", controllers.PackagesListHandler)
 	packages.GET("/:package_name/systems", controllers.PackageSystemsListHandler)
 	packages.GET("/:package_name/versions", controllers.PackageVersionsListHandler)
+	packages.GET("/:package_name/history", controllers.PackageHistoryHandler)
 	packages.GET("/:package_name", controllers.PackageDetailHandler)
 
+	repos := userAuth.Group("/repos")
+	repos.GET("", controllers.ReposListHandler)
+	repos.GET("/:repo_name/systems", controllers.RepoSystemsListHandler)
+	repos.PUT("/:repo_name/classification", controllers.RepoClassificationUpdateHandler)
+	repos.DELETE("/:repo_name/classification", controllers.RepoClassificationDeleteHandler)
+
 	export := userAuth.Group("export")
 	export.GET("/advisories", controllers.AdvisoriesExportHandler)
 	export.GET("/advisories/:advisory_id/systems", controllers.AdvisorySystemsExportHandler)
@@ -57,9 +105,13 @@ func InitAPI(api *gin.RouterGroup, config docs.EndpointsConfig) { // nolint: fun
 	export.GET("/systems", controllers.SystemsExportHandler)
 	export.GET("/systems/:inventory_id/advisories", controllers.SystemAdvisoriesExportHandler)
 	export.GET("/systems/:inventory_id/packages", controllers.SystemPackagesExportHandler)
+	export.GET("/systems/:inventory_id/repos", controllers.SystemReposExportHandler)
 
 	export.GET("/packages", controllers.PackagesExportHandler)
 	export.GET("/packages/:package_name/systems", controllers.PackageSystemsExportHandler)
+
+	export.GET("/repos", controllers.ReposExportHandler)
+	export.GET("/repos/:repo_name/systems", controllers.RepoSystemsExportHandler)
 	if config.EnableTemplates {
 		export.GET("/templates/:template_id/systems", controllers.TemplateSystemsExportHandler)
 	}
@@ -80,6 +132,7 @@ func InitAPI(api *gin.RouterGroup, config docs.EndpointsConfig) { // nolint: fun
 	views := userAuth.Group("/views")
 	views.POST("/systems/advisories", controllers.PostSystemsAdvisories)
 	views.POST("/advisories/systems", controllers.PostAdvisoriesSystems)
+	views.POST("/systems/drift", controllers.PostSystemsDrift)
 
 	ids := userAuth.Group("/ids")
 	ids.GET("/advisories", controllers.AdvisoriesListIDsHandler)
@@ -87,6 +140,9 @@ func InitAPI(api *gin.RouterGroup, config docs.EndpointsConfig) { // nolint: fun
 	ids.GET("/packages/:package_name/systems", controllers.PackageSystemsListIDsHandler)
 	ids.GET("/systems", controllers.SystemsListIDsHandler)
 	ids.GET("/systems/:inventory_id/advisories", controllers.SystemAdvisoriesIDsHandler)
+	ids.GET("/systems/:inventory_id/repos", controllers.SystemReposIDsHandler)
+	ids.GET("/repos", controllers.ReposListIDsHandler)
+	ids.GET("/repos/:repo_name/systems", controllers.RepoSystemsListIDsHandler)
 	if config.EnableTemplates {
 		ids.GET("/templates/:template_id/systems", controllers.TemplateSystemsListIDsHandler)
 	}
@@ -107,6 +163,8 @@ func InitAdmin(app *gin.Engine, enableTurnpikeAuth bool) {
 	api.PUT("/refresh-packages/:account", admin.RefreshPackagesAccountHandler)
 	api.GET("/repack/:table_name", admin.RepackHandler)
 	api.DELETE("/system/:inventory_id", admin.SystemDeleteHandler)
+	api.PUT("/system/:inventory_id/restore", admin.SystemRestoreHandler)
+	api.PUT("/system/:inventory_id/replay", admin.RejectedUploadReplayHandler)
 
 	pprof := api.Group("/pprof")
 	pprof.GET("/evaluator_upload/:param", admin.GetEvaluatorUploadPprof)
@@ -119,4 +177,7 @@ func InitAdmin(app *gin.Engine, enableTurnpikeAuth bool) {
 	dbgroup.GET("/sessions", admin.GetActiveSessionsHandler)
 	dbgroup.GET("/sessions/:search", admin.GetActiveSessionsHandler)
 	dbgroup.DELETE("/sessions/:pid", admin.TerminateSessionHandler)
+	dbgroup.GET("/partitions/:table_name", admin.PartitionsReportHandler)
+	dbgroup.PUT("/partitions/:table_name/split/:account", admin.PartitionsSplitHandler)
+	dbgroup.PUT("/partitions/repack", admin.PartitionsRepackHandler)
 }
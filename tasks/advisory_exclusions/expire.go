@@ -0,0 +1,64 @@
+package advisory_exclusions
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"app/tasks"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+func RunExpireAdvisoryExclusions() error {
+	tasks.HandleContextCancel(tasks.WaitAndExit)
+	core.ConfigureApp()
+	defer utils.LogPanics(true)
+
+	utils.LogInfo("Expiring advisory exclusions")
+	nExpired, err := expireAdvisoryExclusions()
+	if err != nil {
+		return errors.Wrapf(err, "Expiring advisory exclusions failed after %d expired", nExpired)
+	}
+	utils.LogInfo("nExpired", nExpired, "Advisory exclusions expired")
+	return nil
+}
+
+func expireAdvisoryExclusions() (int, error) {
+	var expired []models.AdvisoryExclusion
+	err := tasks.CancelableDB().Where("expires <= now()").Order("rh_account_id, id").Find(&expired).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range expired {
+		// each exclusion in own transaction so already processed ones are not reverted on failure
+		err = tasks.WithTx(func(tx *gorm.DB) error {
+			return expireAdvisoryExclusion(tx, &expired[i])
+		})
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(expired), nil
+}
+
+// Remove exclusion and recount the advisory for systems it covered
+func expireAdvisoryExclusion(tx *gorm.DB, exclusion *models.AdvisoryExclusion) error {
+	var systemIDs []int64
+	err := tx.Model(&models.AdvisoryExclusionSystem{}).
+		Where("rh_account_id = ? AND exclusion_id = ?", exclusion.RhAccountID, exclusion.ID).
+		Pluck("system_id", &systemIDs).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Where("rh_account_id = ? AND id = ?", exclusion.RhAccountID, exclusion.ID).
+		Delete(&models.AdvisoryExclusion{}).Error
+	if err != nil {
+		return err
+	}
+
+	return database.RefreshAdvisoryExclusionCaches(tx, exclusion.RhAccountID, exclusion.AdvisoryID, systemIDs)
+}
@@ -0,0 +1,28 @@
+package advisory_exclusions
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpireAdvisoryExclusions(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	expired := time.Now().Add(-time.Hour)
+	expires := time.Now().Add(time.Hour)
+	expiredID := database.CreateAdvisoryExclusion(t, 1, 1, &expired, []int64{1})
+	activeID := database.CreateAdvisoryExclusion(t, 1, 3, &expires, []int64{1})
+	defer database.DeleteAdvisoryExclusion(t, 1, activeID)
+
+	nExpired, err := expireAdvisoryExclusions()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, nExpired)
+	database.CheckAdvisoryExclusionSystems(t, 1, expiredID, 0)
+	database.CheckAdvisoryExclusionSystems(t, 1, activeID, 1)
+}
@@ -6,17 +6,27 @@ import (
 	"app/base/utils"
 	"app/tasks"
 	"sync"
+	"sync/atomic"
 
+	"github.com/pkg/errors"
 	"gorm.io/gorm"
 )
 
-func BackfillAccountAdvisory() {
+func BackfillAccountAdvisory() error {
 	var wg sync.WaitGroup
-	backfillAccountAdvisoryPerAccounts(&wg)
+	var nFailed atomic.Int64
+	err := backfillAccountAdvisoryPerAccounts(&wg, &nFailed)
 	wg.Wait()
+	if err != nil {
+		return err
+	}
+	if n := nFailed.Load(); n > 0 {
+		return errors.Errorf("account_advisory backfill failed for %d accounts", n)
+	}
+	return nil
 }
 
-func backfillAccountAdvisoryPerAccounts(wg *sync.WaitGroup) {
+func backfillAccountAdvisoryPerAccounts(wg *sync.WaitGroup, nFailed *atomic.Int64) error {
 	var rhAccountIDs []int
 	err := tasks.WithReadReplicaTx(func(tx *gorm.DB) error {
 		return tx.Table("rh_account").
@@ -24,8 +34,7 @@ func backfillAccountAdvisoryPerAccounts(wg *sync.WaitGroup) {
 			Pluck("id", &rhAccountIDs).Error
 	})
 	if err != nil {
-		utils.LogError("err", err, "unable to load rh_account IDs for account_advisory backfill")
-		return
+		return errors.Wrap(err, "unable to load rh_account IDs for account_advisory backfill")
 	}
 
 	utils.LogInfo("accounts", len(rhAccountIDs), "starting account_advisory backfill")
@@ -47,6 +56,7 @@ func backfillAccountAdvisoryPerAccounts(wg *sync.WaitGroup) {
 			})
 			if err != nil {
 				utils.LogError("err", err, "rh_account_id", rhAccountID, "failed to backfill account_advisory")
+				nFailed.Add(1)
 				return
 			}
 			utils.LogInfo("i", i, "rh_account_id", rhAccountID, "backfilled account_advisory")
@@ -57,9 +67,11 @@ func backfillAccountAdvisoryPerAccounts(wg *sync.WaitGroup) {
 				Distinct("advisory_id").
 				Pluck("advisory_id", &advisoryIDs).Error; err != nil {
 				utils.LogError("err", err, "rh_account_id", rhAccountID, "failed to load advisory IDs for drift check")
+				nFailed.Add(1)
 				return
 			}
 			aggregator.CheckAdvisoryDrift(rhAccountID, advisoryIDs)
 		}(i, rhAccountID)
 	}
+	return nil
 }
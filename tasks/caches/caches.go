@@ -4,6 +4,8 @@ import (
 	"app/base/core"
 	"app/base/utils"
 	"app/tasks"
+
+	"github.com/pkg/errors"
 )
 
 var (
@@ -14,22 +16,25 @@ func configure() {
 	core.ConfigureApp()
 }
 
-func RunAdvisoryRefresh() {
+func RunAdvisoryRefresh() error {
 	tasks.HandleContextCancel(tasks.WaitAndExit)
 	configure()
 	utils.LogInfo("Refreshing advisory cache")
-	RefreshAdvisoryCaches()
+	return RefreshAdvisoryCaches()
 }
 
-func RunAccountAdvisoryBackfill() {
+func RunAccountAdvisoryBackfill() error {
 	tasks.HandleContextCancel(tasks.WaitAndExit)
 	configure()
 	utils.LogInfo("Starting account_advisory backfill")
-	BackfillAccountAdvisory()
+	if err := BackfillAccountAdvisory(); err != nil {
+		return err
+	}
 	utils.LogInfo("Finished account_advisory backfill")
+	return nil
 }
 
-func RunPackageRefresh() {
+func RunPackageRefresh() error {
 	tasks.HandleContextCancel(tasks.WaitAndExit)
 	configure()
 	utils.LogInfo("Refreshing package cache")
@@ -38,8 +43,8 @@ func RunPackageRefresh() {
 		utils.LogInfo("err", err, "Could not push to pushgateway")
 	}
 	if errRefresh != nil {
-		utils.LogError("err", errRefresh, "Refresh account packages caches")
-		return
+		return errors.Wrap(errRefresh, "Refresh account packages caches")
 	}
 	utils.LogInfo("Refreshed account packages caches")
+	return nil
 }
@@ -4,18 +4,27 @@ import (
 	"app/base/utils"
 	"app/tasks"
 	"sync"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 )
 
-func RefreshAdvisoryCaches() {
+func RefreshAdvisoryCaches() error {
 	var wg sync.WaitGroup
-	refreshAdvisoryCachesPerAccounts(&wg)
+	var nFailed atomic.Int64
+	err := refreshAdvisoryCachesPerAccounts(&wg, &nFailed)
 	wg.Wait()
+	if err != nil {
+		return err
+	}
+	if n := nFailed.Load(); n > 0 {
+		return errors.Errorf("advisory cache refresh failed for %d accounts", n)
+	}
+	return nil
 }
 
-func refreshAdvisoryCachesPerAccounts(wg *sync.WaitGroup) {
+func refreshAdvisoryCachesPerAccounts(wg *sync.WaitGroup, nFailed *atomic.Int64) error {
 	var rhAccountIDs []int
 	err := tasks.WithReadReplicaTx(func(tx *gorm.DB) error {
 		return tx.Table("rh_account").
@@ -29,8 +38,7 @@ func refreshAdvisoryCachesPerAccounts(wg *sync.WaitGroup) {
 	}
 	utils.LogInfo("accounts", len(rhAccountIDs), "Starting advisory cache refresh for accounts")
 	if err != nil {
-		utils.LogError("err", err, "Unable to load rh_account table ids to refresh caches")
-		return
+		return errors.Wrap(err, "Unable to load rh_account table ids to refresh caches")
 	}
 
 	// use max 4 goroutines for cache refresh
@@ -45,22 +53,25 @@ func refreshAdvisoryCachesPerAccounts(wg *sync.WaitGroup) {
 				wg.Done()
 			}()
 
-			err = tasks.WithTx(func(tx *gorm.DB) error {
+			err := tasks.WithTx(func(tx *gorm.DB) error {
 				utils.LogInfo("i", i, "rh_account_id", rhAccountID, "Refreshing account advisory cache")
 				return tx.Exec("select refresh_advisory_caches(NULL, ?)", rhAccountID).Error
 			})
 			if err != nil {
 				utils.LogError("err", err, "rh_account_id", rhAccountID,
 					"Refreshed account advisory caches")
+				nFailed.Add(1)
 				return
 			}
 			if err := updateAdvisoryCacheValidity(rhAccountID); err != nil {
 				utils.LogError("err", err, "rh_account_id", rhAccountID, "Refresh failed")
+				nFailed.Add(1)
 				return
 			}
 			utils.LogInfo("i", i, "rh_account_id", rhAccountID, "Refreshed account advisory cache")
 		}(i, rhAccountID)
 	}
+	return nil
 }
 
 func updateAdvisoryCacheValidity(accID int) error {
@@ -5,7 +5,6 @@ import (
 	"app/base/database"
 	"app/base/models"
 	"app/base/utils"
-	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,9 +23,7 @@ func TestRefreshAdvisoryCachesPerAccounts(t *testing.T) {
 	assert.Nil(t, database.DB.Model(&models.AdvisoryAccountData{}).
 		Where("advisory_id = 3 AND rh_account_id = 1").Update("systems_installable", 8).Error)
 
-	var wg sync.WaitGroup
-	refreshAdvisoryCachesPerAccounts(&wg)
-	wg.Wait()
+	assert.NoError(t, RefreshAdvisoryCaches())
 
 	assert.Equal(t, 1, database.PluckInt(database.DB.Table("advisory_account_data").
 		Where("advisory_id = 1 AND rh_account_id = 2"), "systems_installable"))
@@ -5,19 +5,21 @@ import (
 	"app/base/models"
 	"app/base/utils"
 	"app/tasks"
+
+	"github.com/pkg/errors"
 )
 
-func RunCleanAdvisoryAccountData() {
+func RunCleanAdvisoryAccountData() error {
 	tasks.HandleContextCancel(tasks.WaitAndExit)
 	core.ConfigureApp()
 	defer utils.LogPanics(true)
 	utils.LogInfo("Deleting advisory rows with 0 applicable systems from advisory_account_data")
 
 	if err := CleanAdvisoryAccountData(); err != nil {
-		utils.LogError("err", err, "Cleaning advisory account data")
-		return
+		return errors.Wrap(err, "Cleaning advisory account data")
 	}
 	utils.LogInfo("CleanAdvisoryAccountData task performed successfully")
+	return nil
 }
 
 func CleanAdvisoryAccountData() error {
@@ -4,17 +4,25 @@ import (
 	"app/base/models"
 	"app/base/utils"
 	"app/tasks"
+
+	"github.com/pkg/errors"
 )
 
-func RunDeleteUnusedData() {
+func RunDeleteUnusedData() error {
 	defer utils.LogPanics(true)
 	utils.LogInfo("Deleting unused data")
 
-	deleteUnusedPackages()
-	deleteUnusedAdvisories()
+	errPackages := deleteUnusedPackages()
+	if errPackages != nil {
+		utils.LogError("err", errPackages, "DeleteUnusedPackages")
+	}
+	if err := deleteUnusedAdvisories(); err != nil {
+		return errors.Wrap(err, "DeleteUnusedAdvisories")
+	}
+	return errors.Wrap(errPackages, "DeleteUnusedPackages")
 }
 
-func deleteUnusedPackages() {
+func deleteUnusedPackages() error {
 	tx := tasks.CancelableDB().Begin()
 	defer tx.Rollback()
 
@@ -24,21 +32,22 @@ func deleteUnusedPackages() {
 		Where("synced = ?", false).
 		Where("NOT EXISTS" +
 			" (SELECT 1 FROM system_package2 sp WHERE" +
-			" p.id = sp.package_id OR p.id = sp.installable_id OR p.id = sp.applicable_id)",
+			" p.id = sp.package_id OR p.id = sp.installable_id OR p.id = sp.applicable_id" +
+			" OR p.id = sp.held_id)",
 		).Limit(tasks.DeleteUnusedDataLimit)
 
 	err := tx.Delete(&models.Package{}, "id IN (?)", subq).Error
 
 	if err != nil {
-		utils.LogError("err", err, "DeleteUnusedPackages")
-		return
+		return err
 	}
 
 	tx.Commit()
 	utils.LogInfo("DeleteUnusedPackages tasks performed successfully")
+	return nil
 }
 
-func deleteUnusedAdvisories() {
+func deleteUnusedAdvisories() error {
 	tx := tasks.CancelableDB().Begin()
 	defer tx.Rollback()
 
@@ -58,10 +67,10 @@ func deleteUnusedAdvisories() {
 	err := tx.Delete(&models.AdvisoryMetadata{}, "id IN (?)", subq).Error
 
 	if err != nil {
-		utils.LogError("err", err, "DeleteUnusedAdvisories")
-		return
+		return err
 	}
 
 	tx.Commit()
 	utils.LogInfo("DeleteUnusedAdvisories tasks performed successfully")
+	return nil
 }
@@ -34,7 +34,7 @@ func TestCleanUnusedPackages(t *testing.T) {
 	database.CheckEVRAsInDBSynced(t, 1, false, evra)
 
 	// delete unused
-	deleteUnusedPackages()
+	assert.NoError(t, deleteUnusedPackages())
 
 	// is package deleted?
 	database.CheckEVRAsInDB(t, 0, evra)
@@ -48,6 +48,29 @@ func TestCleanUnusedPackages(t *testing.T) {
 	assert.Equal(t, beforePkgCount, afterPkgCount+2)
 }
 
+func TestCleanUnusedPackagesKeptByHold(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	evra := "0000.0.1-0.x86_64"
+	heldPkg := models.Package{NameID: 101, EVRA: evra, Synced: false}
+	assert.NoError(t, database.DB.Create(&heldPkg).Error)
+
+	// hold the unsynced package on a system package
+	var sp models.SystemPackage
+	assert.NoError(t, database.DB.Where("held_id IS NULL").Order("system_id, package_id").First(&sp).Error)
+	assert.NoError(t, database.DB.Model(&sp).Update("held_id", heldPkg.ID).Error)
+
+	assert.NoError(t, deleteUnusedPackages())
+
+	// held package is kept
+	database.CheckEVRAsInDBSynced(t, 1, false, evra)
+
+	// cleanup
+	assert.NoError(t, database.DB.Model(&sp).Update("held_id", nil).Error)
+	assert.NoError(t, database.DB.Delete(&heldPkg).Error)
+}
+
 // Test for making sure system culling works
 func TestCleanUnusedAdvisories(t *testing.T) {
 	utils.SkipWithoutDB(t)
@@ -81,7 +104,7 @@ func TestCleanUnusedAdvisories(t *testing.T) {
 	database.DB.Model(models.AdvisoryMetadata{}).Where("name = ?", "RH-100").Count(&rh100count)
 
 	// delete unused
-	deleteUnusedAdvisories()
+	assert.NoError(t, deleteUnusedAdvisories())
 
 	// is custom advisory deleted?
 	var count int64
@@ -125,7 +148,7 @@ func TestDeleteUnusedAdvisoriesKeptByAccountAdvisory(t *testing.T) {
 	err = database.DB.Create(&aa).Error
 	assert.Nil(t, err)
 
-	deleteUnusedAdvisories()
+	assert.NoError(t, deleteUnusedAdvisories())
 
 	var count int64
 	err = database.DB.Model(models.AdvisoryMetadata{}).Where("name = ?", advisory).Count(&count).Error
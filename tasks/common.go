@@ -11,6 +11,8 @@ import (
 	"gorm.io/gorm"
 )
 
+const LastRunPrefix = "last_run_"
+
 func HandleContextCancel(fn func()) {
 	go func() {
 		<-base.Context.Done()
@@ -19,6 +21,14 @@ func HandleContextCancel(fn func()) {
 	}()
 }
 
+// RecordRun stores start of the last successful job run to timestamp_kv, reported in /status/detail
+func RecordRun(job string, start time.Time) {
+	if database.DB == nil {
+		return
+	}
+	database.UpdateTimestampKVValue(LastRunPrefix+job, start)
+}
+
 func WaitAndExit() {
 	time.Sleep(time.Second) // give some time to close eventual db connections
 	os.Exit(0)
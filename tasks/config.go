@@ -42,6 +42,23 @@ var (
 	MaxChangedPackages = utils.PodConfig.GetInt("max_changed_packages", 30000)
 	// prune deleted_system table records older than threshold
 	DeletedSystemsThreshold = time.Hour * time.Duration(utils.PodConfig.GetInt("system_delete_hrs", 4))
+	// prune soft deleted systems archived longer than retention
+	SystemArchiveRetention = 24 * time.Hour * time.Duration(utils.PodConfig.GetInt("system_archive_retention_days", 30))
+	// prune rejected uploads of hosts not uploaded again within retention
+	RejectedUploadRetention = 24 * time.Hour * time.Duration(utils.PodConfig.GetInt("rejected_upload_retention_days", 30))
+	// prune package history of systems older than retention
+	PackageChangeRetention = 24 * time.Hour * time.Duration(utils.PodConfig.GetInt("package_change_retention_days", 90))
+	// Time budget of partition repack in partition_maintenance job
+	PartitionRepackBudget = time.Minute * time.Duration(utils.PodConfig.GetInt("partition_repack_budget_minutes", 60))
+	// Repack only partitions larger than the size
+	PartitionRepackMinSize = int64(utils.PodConfig.GetInt("partition_repack_min_size_mb", 1024)) << 20
+	// Expected pg_repack throughput, estimates duration of the first repack in the job
+	PartitionRepackThroughput = int64(utils.PodConfig.GetInt("partition_repack_throughput_mb", 50)) << 20
+	// Account is hot when it has at least the percentage of rows and min rows of its hash partition
+	PartitionHotAccountPct     = utils.PodConfig.GetInt("partition_hot_account_pct", 30)
+	PartitionHotAccountMinRows = utils.PodConfig.GetInt("partition_hot_account_min_rows", 10000000)
+	// Lock wait limit of partition split, the split fails instead of blocking app queries
+	PartitionSplitLockTimeoutMs = utils.PodConfig.GetInt("partition_split_lock_timeout_ms", 10000)
 	// One-off: publish recalc for non-stale system_advisories hash remainder 0 (default off)
 	EnableSystemAdvisories0Recovery = utils.PodConfig.GetBool("system_advisories_0_recovery", false)
 )
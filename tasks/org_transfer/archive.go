@@ -0,0 +1,188 @@
+package org_transfer
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// FormatVersion of the archive, increased on incompatible change of the archive layout or row encoding
+const FormatVersion = 2
+
+const manifestFile = "manifest.json"
+
+// tables stored in the archive, one JSON document per line
+const (
+	tablePackageName      = "package_name"
+	tablePackage          = "package"
+	tableAdvisoryMetadata = "advisory_metadata"
+	tableRepo             = "repo"
+	tableTemplate         = "template"
+	tableTemplateAdvisory = "template_advisory"
+	tableSystemInventory  = "system_inventory"
+	tableSystemPatch      = "system_patch"
+	tableSystemRepo       = "system_repo"
+	tableSystemAdvisories = "system_advisories"
+	tableSystemPackage    = "system_package2"
+	tableAccountAdvisory  = "account_advisory"
+	tableBaseline         = "baseline"
+	tableCullingPolicy    = "culling_policy"
+	tableExclusion        = "advisory_exclusion"
+	tableExclusionSystem  = "advisory_exclusion_system"
+	tablePackageHold      = "package_hold"
+	tableHoldSystem       = "package_hold_system"
+	tablePatchPlan        = "patch_plan"
+	tablePatchPlanItem    = "patch_plan_item"
+	tablePackageChange    = "system_package_change"
+	tableRepoClass        = "repo_classification"
+	tableCullingNotice    = "system_culling_notice"
+)
+
+const importBatchSize = 1000
+
+type Manifest struct {
+	FormatVersion int    `json:"format_version"`
+	SchemaVersion int    `json:"schema_version"`
+	OrgID         string `json:"org_id"`
+	// account name, optional for orgs created by org_id only
+	AccountName *string          `json:"account_name,omitempty"`
+	Exported    time.Time        `json:"exported"`
+	Tables      map[string]int64 `json:"tables"`
+}
+
+// rows of tables with columns missing in app models
+
+type templateRow struct {
+	ID            int64
+	RhAccountID   int
+	UUID          string
+	Name          string
+	Description   *string
+	Config        []byte
+	Creator       *string
+	Published     *time.Time
+	LastEdited    *time.Time
+	EnvironmentID string
+	Arch          *string
+	Version       *string
+}
+
+type templateAdvisoryRow struct {
+	RhAccountID int
+	TemplateID  int64
+	AdvisoryID  int64
+}
+
+type systemAdvisoryRow struct {
+	RhAccountID   int
+	SystemID      int64
+	AdvisoryID    int64
+	FirstReported time.Time
+	StatusID      int
+}
+
+// writeRows streams rows returned by query to the archive file `table`.jsonl
+func writeRows[T any](zw *zip.Writer, table string, query *gorm.DB) (int64, error) {
+	w, err := zw.Create(table + ".jsonl")
+	if err != nil {
+		return 0, errors.Wrap(err, "Create archive file")
+	}
+	rows, err := query.Rows()
+	if err != nil {
+		return 0, errors.Wrapf(err, "Query %s", table)
+	}
+	defer rows.Close()
+
+	enc := json.NewEncoder(w)
+	var count int64
+	for rows.Next() {
+		var row T
+		if err = query.ScanRows(rows, &row); err != nil {
+			return count, errors.Wrapf(err, "Scan %s", table)
+		}
+		if err = enc.Encode(&row); err != nil {
+			return count, errors.Wrapf(err, "Write %s", table)
+		}
+		count++
+	}
+	return count, errors.Wrapf(rows.Err(), "Read %s", table)
+}
+
+// readRows calls process with batches of rows from the archive file `table`.jsonl
+func readRows[T any](zr *zip.Reader, table string, process func(batch []T) error) (int64, error) {
+	f, err := zr.Open(table + ".jsonl")
+	if err != nil {
+		return 0, errors.Wrapf(err, "Open %s", table)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+	batch := make([]T, 0, importBatchSize)
+	var count int64
+	for {
+		var row T
+		err = dec.Decode(&row)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return count, errors.Wrapf(err, "Decode %s", table)
+		}
+		batch = append(batch, row)
+		count++
+		if len(batch) == importBatchSize {
+			if err = process(batch); err != nil {
+				return count, err
+			}
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		if err = process(batch); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// readAll loads whole archive file, used for lookup tables referenced by the org
+func readAll[T any](zr *zip.Reader, table string) ([]T, error) {
+	var all []T
+	_, err := readRows(zr, table, func(batch []T) error {
+		all = append(all, batch...)
+		return nil
+	})
+	return all, err
+}
+
+func readManifest(zr *zip.Reader) (Manifest, error) {
+	var manifest Manifest
+	f, err := zr.Open(manifestFile)
+	if err != nil {
+		return manifest, errors.Wrap(err, "Open manifest")
+	}
+	defer f.Close()
+	if err = json.NewDecoder(f).Decode(&manifest); err != nil {
+		return manifest, errors.Wrap(err, "Decode manifest")
+	}
+	if manifest.FormatVersion != FormatVersion {
+		return manifest, errors.Errorf("unsupported archive format version %d, expected %d",
+			manifest.FormatVersion, FormatVersion)
+	}
+	return manifest, nil
+}
+
+func writeManifest(zw *zip.Writer, manifest *Manifest) error {
+	w, err := zw.Create(manifestFile)
+	if err != nil {
+		return errors.Wrap(err, "Create manifest")
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return errors.Wrap(enc.Encode(manifest), "Write manifest")
+}
@@ -0,0 +1,173 @@
+package org_transfer
+
+import (
+	"app/base/core"
+	"app/base/models"
+	"app/base/utils"
+	"app/tasks"
+	"archive/zip"
+	"database/sql"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// ids of all packages referenced by system_package2 of the account
+const packageIDsQuery = `SELECT DISTINCT unnest(ARRAY[package_id, installable_id, applicable_id, held_id])
+	FROM system_package2 WHERE rh_account_id = @account`
+
+// ids of advisories referenced by the account and by its packages
+const advisoryIDsQuery = `SELECT advisory_id FROM system_advisories WHERE rh_account_id = @account
+	UNION SELECT advisory_id FROM account_advisory WHERE rh_account_id = @account
+	UNION SELECT advisory_id FROM template_advisory WHERE rh_account_id = @account
+	UNION SELECT advisory_id FROM advisory_exclusion WHERE rh_account_id = @account
+	UNION SELECT advisory_id FROM patch_plan_item WHERE rh_account_id = @account
+	UNION SELECT advisory_id FROM package WHERE id IN (` + packageIDsQuery + `) AND advisory_id IS NOT NULL`
+
+// ids of package names referenced by packages of the account, by its package holds and package changes
+const packageNameIDsQuery = `SELECT name_id FROM package WHERE id IN (` + packageIDsQuery + `)
+	UNION SELECT name_id FROM package_hold WHERE rh_account_id = @account
+	UNION SELECT name_id FROM system_package_change WHERE rh_account_id = @account`
+
+// ids of repos used by systems of the account and classified by the account
+const repoIDsQuery = `SELECT repo_id FROM system_repo WHERE rh_account_id = @account
+	UNION SELECT repo_id FROM repo_classification WHERE rh_account_id = @account`
+
+// RunExport writes patch data of the org to the archive at path
+func RunExport(orgID, path string) {
+	core.ConfigureAdminApp()
+
+	f, err := os.Create(path)
+	if err != nil {
+		utils.LogFatal("err", err, "path", path, "Unable to create archive")
+	}
+	manifest, err := Export(tasks.CancelableDB(), orgID, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		utils.LogFatal("err", err, "org_id", orgID, "Org export failed")
+	}
+	utils.LogInfo("org_id", orgID, "path", path, "schema_version", manifest.SchemaVersion, "tables", manifest.Tables,
+		"Org exported")
+}
+
+// Export writes systems, templates, baselines, advisory exclusions, package holds, patch plans, culling policy,
+// repo classifications and advisory and package data of the org with lookup rows they reference.
+// All tables are read in one repeatable read transaction to get consistent snapshot of the org.
+func Export(db *gorm.DB, orgID string, w io.Writer) (Manifest, error) {
+	manifest := Manifest{FormatVersion: FormatVersion, OrgID: orgID, Exported: time.Now(), Tables: map[string]int64{}}
+	tx := db.Begin(&sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if tx.Error != nil {
+		return manifest, errors.Wrap(tx.Error, "Begin transaction")
+	}
+	defer tx.Rollback()
+
+	var account models.RhAccount
+	if err := tx.Where("org_id = ?", orgID).Take(&account).Error; err != nil {
+		return manifest, errors.Wrap(err, "Load org")
+	}
+	manifest.AccountName = account.Name
+	if err := tx.Raw("SELECT version FROM schema_migrations").Scan(&manifest.SchemaVersion).Error; err != nil {
+		return manifest, errors.Wrap(err, "Load schema version")
+	}
+
+	acc := sql.Named("account", account.ID)
+	accountRows := func(table string) *gorm.DB {
+		return tx.Table(table).Where("rh_account_id = ?", account.ID)
+	}
+	zw := zip.NewWriter(w)
+	exports := []struct {
+		table string
+		write func(table string) (int64, error)
+	}{
+		{tableAdvisoryMetadata, func(table string) (int64, error) {
+			return writeRows[models.AdvisoryMetadata](zw, table,
+				tx.Table(table).Where("id IN ("+advisoryIDsQuery+")", acc))
+		}},
+		{tablePackageName, func(table string) (int64, error) {
+			return writeRows[models.PackageName](zw, table,
+				tx.Table(table).Where("id IN ("+packageNameIDsQuery+")", acc))
+		}},
+		{tablePackage, func(table string) (int64, error) {
+			return writeRows[models.Package](zw, table, tx.Table(table).Where("id IN ("+packageIDsQuery+")", acc))
+		}},
+		{tableRepo, func(table string) (int64, error) {
+			return writeRows[models.Repo](zw, table, tx.Table(table).Where("id IN ("+repoIDsQuery+")", acc))
+		}},
+		{tableTemplate, func(table string) (int64, error) {
+			return writeRows[templateRow](zw, table, accountRows(table))
+		}},
+		{tableTemplateAdvisory, func(table string) (int64, error) {
+			return writeRows[templateAdvisoryRow](zw, table, accountRows(table))
+		}},
+		{tableSystemInventory, func(table string) (int64, error) {
+			return writeRows[models.SystemInventory](zw, table, accountRows(table))
+		}},
+		{tableSystemPatch, func(table string) (int64, error) {
+			return writeRows[models.SystemPatch](zw, table, accountRows(table))
+		}},
+		{tableSystemRepo, func(table string) (int64, error) {
+			return writeRows[models.SystemRepo](zw, table, accountRows(table))
+		}},
+		{tableSystemAdvisories, func(table string) (int64, error) {
+			return writeRows[systemAdvisoryRow](zw, table, accountRows(table))
+		}},
+		{tableSystemPackage, func(table string) (int64, error) {
+			return writeRows[models.SystemPackage](zw, table, accountRows(table))
+		}},
+		{tablePackageChange, func(table string) (int64, error) {
+			return writeRows[models.SystemPackageChange](zw, table, accountRows(table))
+		}},
+		{tableCullingNotice, func(table string) (int64, error) {
+			return writeRows[models.SystemCullingNotice](zw, table, accountRows(table))
+		}},
+		{tableAccountAdvisory, func(table string) (int64, error) {
+			return writeRows[models.AccountAdvisory](zw, table, accountRows(table))
+		}},
+		{tableRepoClass, func(table string) (int64, error) {
+			return writeRows[models.RepoClassification](zw, table, accountRows(table))
+		}},
+		{tableBaseline, func(table string) (int64, error) {
+			return writeRows[models.Baseline](zw, table, accountRows(table))
+		}},
+		{tableCullingPolicy, func(table string) (int64, error) {
+			return writeRows[models.CullingPolicy](zw, table, accountRows(table))
+		}},
+		{tableExclusion, func(table string) (int64, error) {
+			return writeRows[models.AdvisoryExclusion](zw, table, accountRows(table))
+		}},
+		{tableExclusionSystem, func(table string) (int64, error) {
+			return writeRows[models.AdvisoryExclusionSystem](zw, table, accountRows(table))
+		}},
+		{tablePackageHold, func(table string) (int64, error) {
+			return writeRows[models.PackageHold](zw, table, accountRows(table))
+		}},
+		{tableHoldSystem, func(table string) (int64, error) {
+			return writeRows[models.PackageHoldSystem](zw, table, accountRows(table))
+		}},
+		{tablePatchPlan, func(table string) (int64, error) {
+			return writeRows[models.PatchPlan](zw, table, accountRows(table))
+		}},
+		{tablePatchPlanItem, func(table string) (int64, error) {
+			return writeRows[models.PatchPlanItem](zw, table, accountRows(table))
+		}},
+	}
+	for _, export := range exports {
+		count, err := export.write(export.table)
+		if err != nil {
+			return manifest, err
+		}
+		manifest.Tables[export.table] = count
+		utils.LogDebug("table", export.table, "rows", count, "Table exported")
+	}
+
+	if err := writeManifest(zw, &manifest); err != nil {
+		return manifest, err
+	}
+	return manifest, errors.Wrap(zw.Close(), "Close archive")
+}
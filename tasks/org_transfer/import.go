@@ -0,0 +1,665 @@
+package org_transfer
+
+import (
+	"app/base/core"
+	"app/base/models"
+	"app/base/utils"
+	"app/tasks"
+	"archive/zip"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// import systems with new inventory IDs, e.g. into database containing the exported org
+var importNewInventoryIDs = utils.PodConfig.GetBool("org_import_new_inventory_ids", false)
+
+// RunImport loads the archive at path into the org, org of the archive is used when orgID is empty
+func RunImport(path, orgID string) {
+	core.ConfigureAdminApp()
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		utils.LogFatal("err", err, "path", path, "Unable to open archive")
+	}
+	defer zr.Close()
+
+	manifest, counts, err := Import(tasks.CancelableDB(), &zr.Reader, orgID, importNewInventoryIDs)
+	if err != nil {
+		utils.LogFatal("err", err, "path", path, "Org import failed")
+	}
+	utils.LogInfo("org_id", manifest.OrgID, "path", path, "tables", counts, "Org imported")
+}
+
+type importer struct {
+	tx        *gorm.DB
+	zr        *zip.Reader
+	accountID int
+	counts    map[string]int64
+	// systems get new inventory IDs instead of the exported ones
+	newInventoryIDs bool
+	// archive IDs mapped to IDs in the database
+	advisories   map[int64]int64
+	packageNames map[int64]int64
+	packages     map[int64]int64
+	repos        map[int64]int64
+	templates    map[int64]int64
+	systems      map[int64]int64
+	baselines    map[int64]int64
+	exclusions   map[int64]int64
+	holds        map[int64]int64
+	plans        map[int64]int64
+}
+
+// Import inserts data of the archive to the org in one transaction. The org is created when missing,
+// existing org must not have any systems. Packages, advisories and repos are matched by their names
+// and missing ones are inserted, systems, templates, baselines, exclusions, holds and plans get new IDs.
+// Inventory IDs of systems must not exist in the database unless newInventoryIDs is set.
+// Cached counts of the org are invalidated and recomputed by cache refresh jobs.
+func Import(db *gorm.DB, zr *zip.Reader, orgID string, newInventoryIDs bool) (Manifest, map[string]int64, error) {
+	manifest, err := readManifest(zr)
+	if err != nil {
+		return manifest, nil, err
+	}
+	var schemaVersion int
+	if err = db.Raw("SELECT version FROM schema_migrations").Scan(&schemaVersion).Error; err != nil {
+		return manifest, nil, errors.Wrap(err, "Load schema version")
+	}
+	if schemaVersion != manifest.SchemaVersion {
+		// rows are stored as app models, columns missing in the archive get their default values
+		utils.LogWarn("archive", manifest.SchemaVersion, "database", schemaVersion, "Schema version differs")
+	}
+	if orgID == "" {
+		orgID = manifest.OrgID
+	}
+
+	im := importer{zr: zr, counts: map[string]int64{}, newInventoryIDs: newInventoryIDs}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		im.tx = tx
+		if err := im.prepareAccount(orgID, &manifest); err != nil {
+			return err
+		}
+		steps := []func() error{
+			im.checkInventoryIDs, im.importAdvisories, im.importPackageNames, im.importPackages, im.importRepos,
+			im.importTemplates, im.importSystems, im.importBaselines, im.importAccountRows, im.importSystemRows,
+		}
+		for _, step := range steps {
+			if err := step(); err != nil {
+				return err
+			}
+		}
+		return errors.Wrap(tx.Model(&models.RhAccount{}).Where("id = ?", im.accountID).
+			Updates(map[string]interface{}{"valid_package_cache": false, "valid_advisory_cache": false}).Error,
+			"Invalidate caches")
+	})
+	manifest.OrgID = orgID
+	return manifest, im.counts, err
+}
+
+func (im *importer) prepareAccount(orgID string, manifest *Manifest) error {
+	var account models.RhAccount
+	err := im.tx.Where("org_id = ?", orgID).Limit(1).Find(&account).Error
+	if err != nil {
+		return errors.Wrap(err, "Load org")
+	}
+	if account.ID == 0 {
+		account.OrgID = &orgID
+		if orgID == manifest.OrgID {
+			account.Name = manifest.AccountName
+		}
+		if err = im.tx.Create(&account).Error; err != nil {
+			return errors.Wrap(err, "Create org")
+		}
+	}
+	im.accountID = account.ID
+
+	var systems int64
+	err = im.tx.Model(&models.SystemInventory{}).Where("rh_account_id = ?", account.ID).Count(&systems).Error
+	if err != nil {
+		return errors.Wrap(err, "Count org systems")
+	}
+	if systems > 0 {
+		return errors.Errorf("org %s already has %d systems", orgID, systems)
+	}
+	return nil
+}
+
+// checkInventoryIDs fails before anything is imported when inventory IDs of the archive systems already exist,
+// e.g. when the archive is imported to the database of the exported org
+func (im *importer) checkInventoryIDs() error {
+	if im.newInventoryIDs {
+		return nil
+	}
+	var conflicts int64
+	var example string
+	_, err := readRows(im.zr, tableSystemInventory, func(batch []models.SystemInventory) error {
+		inventoryIDs := make([]uuid.UUID, len(batch))
+		for i := range batch {
+			inventoryIDs[i] = batch[i].InventoryID
+		}
+		var existing []uuid.UUID
+		err := im.tx.Model(&models.SystemInventory{}).Where("inventory_id IN ?", inventoryIDs).
+			Pluck("inventory_id", &existing).Error
+		if len(existing) > 0 && example == "" {
+			example = existing[0].String()
+		}
+		conflicts += int64(len(existing))
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "Check inventory IDs")
+	}
+	if conflicts > 0 {
+		return errors.Errorf("%d systems of the archive already exist (e.g. inventory_id %s), "+
+			"import them with org_import_new_inventory_ids", conflicts, example)
+	}
+	return nil
+}
+
+// lookup inserts rows missing in the database and maps archive IDs of the rows to IDs in the database
+// matched by unique key
+func lookup[T any](tx *gorm.DB, rows []T, id func(*T) *int64, key func(*T) string,
+	load func(tx *gorm.DB, batch []T) (map[string]int64, error)) (map[int64]int64, error) {
+	ids := make(map[int64]int64, len(rows))
+	archiveIDs := make([]int64, len(rows))
+	for i := range rows {
+		archiveIDs[i] = *id(&rows[i])
+		*id(&rows[i]) = 0
+	}
+	for start := 0; start < len(rows); start += importBatchSize {
+		batch := rows[start:min(start+importBatchSize, len(rows))]
+		// IDs returned to rows are not used, rows skipped on conflict would shift them
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&batch).Error; err != nil {
+			return nil, err
+		}
+		existing, err := load(tx, batch)
+		if err != nil {
+			return nil, err
+		}
+		for i := range batch {
+			dbID, ok := existing[key(&batch[i])]
+			if !ok {
+				return nil, errors.Errorf("row %s not found after insert", key(&batch[i]))
+			}
+			ids[archiveIDs[start+i]] = dbID
+		}
+	}
+	return ids, nil
+}
+
+func (im *importer) importAdvisories() error {
+	rows, err := readAll[models.AdvisoryMetadata](im.zr, tableAdvisoryMetadata)
+	if err != nil {
+		return err
+	}
+	im.advisories, err = lookup(im.tx, rows,
+		func(r *models.AdvisoryMetadata) *int64 { return &r.ID },
+		func(r *models.AdvisoryMetadata) string { return r.Name },
+		func(tx *gorm.DB, batch []models.AdvisoryMetadata) (map[string]int64, error) {
+			names := make([]string, len(batch))
+			for i := range batch {
+				names[i] = batch[i].Name
+			}
+			var existing []models.AdvisoryMetadata
+			err := tx.Select("id, name").Where("name IN ?", names).Find(&existing).Error
+			ids := make(map[string]int64, len(existing))
+			for _, r := range existing {
+				ids[r.Name] = r.ID
+			}
+			return ids, err
+		})
+	im.counts[tableAdvisoryMetadata] = int64(len(rows))
+	return errors.Wrap(err, "Import advisories")
+}
+
+func (im *importer) importPackageNames() error {
+	rows, err := readAll[models.PackageName](im.zr, tablePackageName)
+	if err != nil {
+		return err
+	}
+	im.packageNames, err = lookup(im.tx, rows,
+		func(r *models.PackageName) *int64 { return &r.ID },
+		func(r *models.PackageName) string { return r.Name },
+		func(tx *gorm.DB, batch []models.PackageName) (map[string]int64, error) {
+			names := make([]string, len(batch))
+			for i := range batch {
+				names[i] = batch[i].Name
+			}
+			var existing []models.PackageName
+			err := tx.Select("id, name").Where("name IN ?", names).Find(&existing).Error
+			ids := make(map[string]int64, len(existing))
+			for _, r := range existing {
+				ids[r.Name] = r.ID
+			}
+			return ids, err
+		})
+	im.counts[tablePackageName] = int64(len(rows))
+	return errors.Wrap(err, "Import package names")
+}
+
+func (im *importer) importPackages() error {
+	rows, err := readAll[models.Package](im.zr, tablePackage)
+	if err != nil {
+		return err
+	}
+	for i := range rows {
+		if rows[i].NameID, err = remap(im.packageNames, rows[i].NameID, tablePackageName); err != nil {
+			return err
+		}
+		if rows[i].AdvisoryID != nil {
+			if advisoryID, ok := im.advisories[*rows[i].AdvisoryID]; ok {
+				rows[i].AdvisoryID = &advisoryID
+			} else {
+				rows[i].AdvisoryID = nil
+			}
+		}
+		// strings table with descriptions is not exported, it is filled by vmaas_sync
+		rows[i].DescriptionHash, rows[i].SummaryHash = nil, nil
+	}
+	key := func(nameID int64, evra string) string { return strconv.FormatInt(nameID, 10) + "/" + evra }
+	im.packages, err = lookup(im.tx, rows,
+		func(r *models.Package) *int64 { return &r.ID },
+		func(r *models.Package) string { return key(r.NameID, r.EVRA) },
+		func(tx *gorm.DB, batch []models.Package) (map[string]int64, error) {
+			nameEvras := make([][]interface{}, len(batch))
+			for i := range batch {
+				nameEvras[i] = []interface{}{batch[i].NameID, batch[i].EVRA}
+			}
+			var existing []models.Package
+			err := tx.Select("id, name_id, evra").Where("(name_id, evra) IN ?", nameEvras).Find(&existing).Error
+			ids := make(map[string]int64, len(existing))
+			for _, r := range existing {
+				ids[key(r.NameID, r.EVRA)] = r.ID
+			}
+			return ids, err
+		})
+	im.counts[tablePackage] = int64(len(rows))
+	return errors.Wrap(err, "Import packages")
+}
+
+func (im *importer) importRepos() error {
+	rows, err := readAll[models.Repo](im.zr, tableRepo)
+	if err != nil {
+		return err
+	}
+	im.repos, err = lookup(im.tx, rows,
+		func(r *models.Repo) *int64 { return &r.ID },
+		func(r *models.Repo) string { return r.Name },
+		func(tx *gorm.DB, batch []models.Repo) (map[string]int64, error) {
+			names := make([]string, len(batch))
+			for i := range batch {
+				names[i] = batch[i].Name
+			}
+			var existing []models.Repo
+			err := tx.Select("id, name").Where("name IN ?", names).Find(&existing).Error
+			ids := make(map[string]int64, len(existing))
+			for _, r := range existing {
+				ids[r.Name] = r.ID
+			}
+			return ids, err
+		})
+	im.counts[tableRepo] = int64(len(rows))
+	return errors.Wrap(err, "Import repos")
+}
+
+func (im *importer) importTemplates() error {
+	im.templates = map[int64]int64{}
+	count, err := readRows(im.zr, tableTemplate, func(batch []templateRow) error {
+		archiveIDs := make([]int64, len(batch))
+		for i := range batch {
+			archiveIDs[i], batch[i].ID = batch[i].ID, 0
+			batch[i].RhAccountID = im.accountID
+		}
+		if err := im.tx.Table(tableTemplate).Create(&batch).Error; err != nil {
+			return err
+		}
+		for i := range batch {
+			im.templates[archiveIDs[i]] = batch[i].ID
+		}
+		return nil
+	})
+	im.counts[tableTemplate] = count
+	return errors.Wrap(err, "Import templates")
+}
+
+func (im *importer) importSystems() error {
+	im.systems = map[int64]int64{}
+	count, err := readRows(im.zr, tableSystemInventory, func(batch []models.SystemInventory) error {
+		archiveIDs := make([]int64, len(batch))
+		for i := range batch {
+			archiveIDs[i], batch[i].ID = batch[i].ID, 0
+			batch[i].RhAccountID = im.accountID
+			if im.newInventoryIDs {
+				batch[i].InventoryID = uuid.New()
+			}
+		}
+		if err := im.tx.Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Create(&batch).Error; err != nil {
+			return err
+		}
+		for i := range batch {
+			im.systems[archiveIDs[i]] = batch[i].ID
+		}
+		return nil
+	})
+	im.counts[tableSystemInventory] = count
+	return errors.Wrap(err, "Import systems")
+}
+
+// insertWithIDs inserts rows of the archive table with new IDs and returns archive IDs mapped to the new ones,
+// prepare sets account and remaps references of each row
+func insertWithIDs[T any](im *importer, table string, id func(*T) *int64, prepare func(*T) error,
+) (map[int64]int64, error) {
+	ids := map[int64]int64{}
+	count, err := readRows(im.zr, table, func(batch []T) error {
+		archiveIDs := make([]int64, len(batch))
+		for i := range batch {
+			archiveIDs[i], *id(&batch[i]) = *id(&batch[i]), 0
+			if err := prepare(&batch[i]); err != nil {
+				return err
+			}
+		}
+		if err := im.tx.Create(&batch).Error; err != nil {
+			return err
+		}
+		for i := range batch {
+			ids[archiveIDs[i]] = *id(&batch[i])
+		}
+		return nil
+	})
+	im.counts[table] = count
+	return ids, errors.Wrapf(err, "Import %s", table)
+}
+
+// importBaselines imports baselines referenced by system_patch
+func (im *importer) importBaselines() (err error) {
+	im.baselines, err = insertWithIDs(im, tableBaseline,
+		func(r *models.Baseline) *int64 { return &r.ID },
+		func(r *models.Baseline) error {
+			r.RhAccountID = im.accountID
+			if r.ReferenceSystemID != nil {
+				// reference system deleted before export keeps dangling reference matching no system
+				systemID := im.systems[*r.ReferenceSystemID]
+				r.ReferenceSystemID = &systemID
+			}
+			return nil
+		})
+	return err
+}
+
+// importAccountRows imports culling policy, advisory exclusions, package holds and patch plans of the org
+func (im *importer) importAccountRows() (err error) {
+	count, err := readRows(im.zr, tableCullingPolicy, func(batch []models.CullingPolicy) error {
+		for i := range batch {
+			batch[i].RhAccountID = im.accountID
+		}
+		return im.tx.Create(&batch).Error
+	})
+	im.counts[tableCullingPolicy] = count
+	if err != nil {
+		return errors.Wrapf(err, "Import %s", tableCullingPolicy)
+	}
+	im.exclusions, err = insertWithIDs(im, tableExclusion,
+		func(r *models.AdvisoryExclusion) *int64 { return &r.ID },
+		func(r *models.AdvisoryExclusion) (err error) {
+			r.RhAccountID = im.accountID
+			r.AdvisoryID, err = remap(im.advisories, r.AdvisoryID, tableAdvisoryMetadata)
+			return err
+		})
+	if err != nil {
+		return err
+	}
+	im.holds, err = insertWithIDs(im, tablePackageHold,
+		func(r *models.PackageHold) *int64 { return &r.ID },
+		func(r *models.PackageHold) (err error) {
+			r.RhAccountID = im.accountID
+			r.NameID, err = remap(im.packageNames, r.NameID, tablePackageName)
+			return err
+		})
+	if err != nil {
+		return err
+	}
+	im.plans, err = insertWithIDs(im, tablePatchPlan,
+		func(r *models.PatchPlan) *int64 { return &r.ID },
+		func(r *models.PatchPlan) error {
+			r.RhAccountID = im.accountID
+			return nil
+		})
+	return err
+}
+
+// importSystemRows imports rows referencing systems, templates and lookup tables
+func (im *importer) importSystemRows() error {
+	imports := []struct {
+		table string
+		read  func(table string) (int64, error)
+	}{
+		{tableTemplateAdvisory, func(table string) (int64, error) {
+			return readRows(im.zr, table, func(batch []templateAdvisoryRow) (err error) {
+				for i := range batch {
+					r := &batch[i]
+					r.RhAccountID = im.accountID
+					if r.TemplateID, err = remap(im.templates, r.TemplateID, tableTemplate); err != nil {
+						return err
+					}
+					if r.AdvisoryID, err = remap(im.advisories, r.AdvisoryID, tableAdvisoryMetadata); err != nil {
+						return err
+					}
+				}
+				return im.tx.Table(table).Create(&batch).Error
+			})
+		}},
+		{tableSystemPatch, func(table string) (int64, error) {
+			return readRows(im.zr, table, func(batch []models.SystemPatch) (err error) {
+				for i := range batch {
+					r := &batch[i]
+					r.RhAccountID = im.accountID
+					if r.SystemID, err = remap(im.systems, r.SystemID, tableSystemInventory); err != nil {
+						return err
+					}
+					if r.TemplateID != nil {
+						templateID, err := remap(im.templates, *r.TemplateID, tableTemplate)
+						if err != nil {
+							return err
+						}
+						r.TemplateID = &templateID
+					}
+					if r.BaselineID != nil {
+						baselineID, err := remap(im.baselines, *r.BaselineID, tableBaseline)
+						if err != nil {
+							return err
+						}
+						r.BaselineID = &baselineID
+					}
+				}
+				return im.tx.Create(&batch).Error
+			})
+		}},
+		{tableSystemRepo, func(table string) (int64, error) {
+			return readRows(im.zr, table, func(batch []models.SystemRepo) (err error) {
+				for i := range batch {
+					r := &batch[i]
+					r.RhAccountID = int64(im.accountID)
+					if r.SystemID, err = remap(im.systems, r.SystemID, tableSystemInventory); err != nil {
+						return err
+					}
+					if r.RepoID, err = remap(im.repos, r.RepoID, tableRepo); err != nil {
+						return err
+					}
+				}
+				return im.tx.Create(&batch).Error
+			})
+		}},
+		{tableSystemAdvisories, func(table string) (int64, error) {
+			return readRows(im.zr, table, func(batch []systemAdvisoryRow) (err error) {
+				for i := range batch {
+					r := &batch[i]
+					r.RhAccountID = im.accountID
+					if r.SystemID, err = remap(im.systems, r.SystemID, tableSystemInventory); err != nil {
+						return err
+					}
+					if r.AdvisoryID, err = remap(im.advisories, r.AdvisoryID, tableAdvisoryMetadata); err != nil {
+						return err
+					}
+				}
+				return im.tx.Table(table).Create(&batch).Error
+			})
+		}},
+		{tableSystemPackage, func(table string) (int64, error) {
+			return readRows(im.zr, table, func(batch []models.SystemPackage) (err error) {
+				for i := range batch {
+					if err = im.remapSystemPackage(&batch[i]); err != nil {
+						return err
+					}
+				}
+				return im.tx.Create(&batch).Error
+			})
+		}},
+		{tablePackageChange, func(table string) (int64, error) {
+			return readRows(im.zr, table, func(batch []models.SystemPackageChange) (err error) {
+				for i := range batch {
+					r := &batch[i]
+					r.RhAccountID = im.accountID
+					if r.SystemID, err = remap(im.systems, r.SystemID, tableSystemInventory); err != nil {
+						return err
+					}
+					if r.NameID, err = remap(im.packageNames, r.NameID, tablePackageName); err != nil {
+						return err
+					}
+				}
+				// changes get new IDs from the identity column
+				return im.tx.Omit("id").Create(&batch).Error
+			})
+		}},
+		{tableCullingNotice, func(table string) (int64, error) {
+			return readRows(im.zr, table, func(batch []models.SystemCullingNotice) (err error) {
+				for i := range batch {
+					r := &batch[i]
+					r.RhAccountID = im.accountID
+					if r.SystemID, err = remap(im.systems, r.SystemID, tableSystemInventory); err != nil {
+						return err
+					}
+				}
+				return im.tx.Create(&batch).Error
+			})
+		}},
+		{tableAccountAdvisory, func(table string) (int64, error) {
+			return readRows(im.zr, table, func(batch []models.AccountAdvisory) (err error) {
+				for i := range batch {
+					r := &batch[i]
+					r.RhAccountID = im.accountID
+					if r.AdvisoryID, err = remap(im.advisories, r.AdvisoryID, tableAdvisoryMetadata); err != nil {
+						return err
+					}
+				}
+				return im.tx.Create(&batch).Error
+			})
+		}},
+		{tableRepoClass, func(table string) (int64, error) {
+			return readRows(im.zr, table, func(batch []models.RepoClassification) (err error) {
+				for i := range batch {
+					r := &batch[i]
+					r.RhAccountID = im.accountID
+					if r.RepoID, err = remap(im.repos, r.RepoID, tableRepo); err != nil {
+						return err
+					}
+				}
+				return im.tx.Create(&batch).Error
+			})
+		}},
+		{tableExclusionSystem, func(table string) (int64, error) {
+			return readRows(im.zr, table, func(batch []models.AdvisoryExclusionSystem) (err error) {
+				for i := range batch {
+					r := &batch[i]
+					r.RhAccountID = im.accountID
+					if r.ExclusionID, err = remap(im.exclusions, r.ExclusionID, tableExclusion); err != nil {
+						return err
+					}
+					if r.SystemID, err = remap(im.systems, r.SystemID, tableSystemInventory); err != nil {
+						return err
+					}
+					if r.AdvisoryID, err = remap(im.advisories, r.AdvisoryID, tableAdvisoryMetadata); err != nil {
+						return err
+					}
+				}
+				return im.tx.Create(&batch).Error
+			})
+		}},
+		{tableHoldSystem, func(table string) (int64, error) {
+			return readRows(im.zr, table, func(batch []models.PackageHoldSystem) (err error) {
+				for i := range batch {
+					r := &batch[i]
+					r.RhAccountID = im.accountID
+					if r.HoldID, err = remap(im.holds, r.HoldID, tablePackageHold); err != nil {
+						return err
+					}
+					if r.SystemID, err = remap(im.systems, r.SystemID, tableSystemInventory); err != nil {
+						return err
+					}
+				}
+				return im.tx.Create(&batch).Error
+			})
+		}},
+		{tablePatchPlanItem, func(table string) (int64, error) {
+			return readRows(im.zr, table, func(batch []models.PatchPlanItem) (err error) {
+				for i := range batch {
+					r := &batch[i]
+					r.RhAccountID = im.accountID
+					if r.PlanID, err = remap(im.plans, r.PlanID, tablePatchPlan); err != nil {
+						return err
+					}
+					if r.SystemID, err = remap(im.systems, r.SystemID, tableSystemInventory); err != nil {
+						return err
+					}
+					if r.AdvisoryID, err = remap(im.advisories, r.AdvisoryID, tableAdvisoryMetadata); err != nil {
+						return err
+					}
+				}
+				return im.tx.Create(&batch).Error
+			})
+		}},
+	}
+	for _, imp := range imports {
+		count, err := imp.read(imp.table)
+		if err != nil {
+			return errors.Wrapf(err, "Import %s", imp.table)
+		}
+		im.counts[imp.table] = count
+	}
+	return nil
+}
+
+func (im *importer) remapSystemPackage(r *models.SystemPackage) (err error) {
+	r.RhAccountID = im.accountID
+	if r.SystemID, err = remap(im.systems, r.SystemID, tableSystemInventory); err != nil {
+		return err
+	}
+	if r.NameID, err = remap(im.packageNames, r.NameID, tablePackageName); err != nil {
+		return err
+	}
+	if r.PackageID, err = remap(im.packages, r.PackageID, tablePackage); err != nil {
+		return err
+	}
+	for _, id := range []**int64{&r.InstallableID, &r.ApplicableID, &r.HeldID} {
+		if *id == nil {
+			continue
+		}
+		packageID, err := remap(im.packages, **id, tablePackage)
+		if err != nil {
+			return err
+		}
+		*id = &packageID
+	}
+	return nil
+}
+
+func remap(ids map[int64]int64, id int64, table string) (int64, error) {
+	dbID, ok := ids[id]
+	if !ok {
+		return 0, errors.Errorf("%s id %d missing in archive", table, id)
+	}
+	return dbID, nil
+}
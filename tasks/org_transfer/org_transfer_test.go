@@ -0,0 +1,132 @@
+package org_transfer
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"app/base/utils"
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const importedOrgID = "org_transfer_test"
+
+func zipReader(t *testing.T, files map[string][]byte) *zip.Reader {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return zr
+}
+
+func TestReadManifestVersion(t *testing.T) {
+	manifest, err := json.Marshal(Manifest{FormatVersion: FormatVersion + 1, OrgID: "org_1"})
+	require.NoError(t, err)
+	_, err = readManifest(zipReader(t, map[string][]byte{manifestFile: manifest}))
+	assert.ErrorContains(t, err, "unsupported archive format version")
+}
+
+func TestReadRowsBatches(t *testing.T) {
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	for i := range importBatchSize + 5 {
+		require.NoError(t, enc.Encode(models.Repo{ID: int64(i), Name: "repo"}))
+	}
+	zr := zipReader(t, map[string][]byte{tableRepo + ".jsonl": data.Bytes()})
+
+	var batches []int
+	count, err := readRows(zr, tableRepo, func(batch []models.Repo) error {
+		batches = append(batches, len(batch))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(importBatchSize+5), count)
+	assert.Equal(t, []int{importBatchSize, 5}, batches)
+}
+
+func deleteImportedOrg(t *testing.T) {
+	var account models.RhAccount
+	assert.NoError(t, database.DB.Where("org_id = ?", importedOrgID).Limit(1).Find(&account).Error)
+	if account.ID == 0 {
+		return
+	}
+	for _, table := range []string{tableAccountAdvisory, tableSystemPackage, tableSystemAdvisories, tableSystemRepo,
+		tablePackageChange, tableCullingNotice, tableRepoClass, tablePatchPlanItem, tablePatchPlan, tableHoldSystem,
+		tablePackageHold, tableExclusionSystem, tableExclusion, tableCullingPolicy, tableSystemPatch, tableBaseline,
+		tableTemplateAdvisory, tableSystemInventory, tableTemplate, "rh_account"} {
+		column := "rh_account_id"
+		if table == "rh_account" {
+			column = "id"
+		}
+		assert.NoError(t, database.DB.Exec("DELETE FROM "+table+" WHERE "+column+" = ?", account.ID).Error)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	// repo not used by any system of the org is exported with its classification
+	classification := models.RepoClassification{RhAccountID: 1, RepoID: 3, Classification: "third_party"}
+	notice := models.SystemCullingNotice{RhAccountID: 1, SystemID: 2, CullingDate: time.Now().Add(24 * time.Hour)}
+	require.NoError(t, database.DB.Create(&classification).Error)
+	defer database.DB.Delete(&classification)
+	require.NoError(t, database.DB.Create(&notice).Error)
+	defer database.DB.Delete(&notice)
+
+	var archive bytes.Buffer
+	exported, err := Export(database.DB, "org_1", &archive)
+	require.NoError(t, err)
+	assert.Positive(t, exported.Tables[tableSystemInventory])
+	assert.Positive(t, exported.Tables[tableSystemAdvisories])
+	assert.Positive(t, exported.Tables[tableTemplate])
+	assert.Positive(t, exported.Tables[tablePackageChange])
+	assert.Equal(t, int64(1), exported.Tables[tableRepoClass])
+	assert.Equal(t, int64(1), exported.Tables[tableCullingNotice])
+
+	zr, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	require.NoError(t, err)
+
+	// org_1 already has systems
+	_, _, err = Import(database.DB, zr, "", false)
+	assert.ErrorContains(t, err, "already has")
+	// systems of org_1 exist in the database
+	_, _, err = Import(database.DB, zr, importedOrgID, false)
+	assert.ErrorContains(t, err, "already exist")
+
+	defer deleteImportedOrg(t)
+	imported, counts, err := Import(database.DB, zr, importedOrgID, true)
+	require.NoError(t, err)
+	assert.Equal(t, importedOrgID, imported.OrgID)
+	assert.Equal(t, exported.Tables, counts)
+
+	var account models.RhAccount
+	require.NoError(t, database.DB.Where("org_id = ?", importedOrgID).Take(&account).Error)
+	assert.False(t, account.ValidAdvisoryCache)
+	for _, table := range []string{tableSystemInventory, tableSystemAdvisories, tableSystemPackage, tableTemplate,
+		tablePackageChange, tableRepoClass, tableCullingNotice} {
+		var n int64
+		assert.NoError(t, database.DB.Table(table).Where("rh_account_id = ?", account.ID).Count(&n).Error)
+		assert.Equal(t, exported.Tables[table], n, table)
+	}
+
+	// org_1 systems in the new org keep their templates
+	var withTemplate int64
+	assert.NoError(t, database.DB.Table("system_patch sp").
+		Joins("JOIN template t ON t.rh_account_id = sp.rh_account_id AND t.id = sp.template_id").
+		Where("sp.rh_account_id = ?", account.ID).Count(&withTemplate).Error)
+	assert.Positive(t, withTemplate)
+}
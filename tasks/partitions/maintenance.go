@@ -0,0 +1,115 @@
+package partitions
+
+import (
+	"app/base/core"
+	"app/base/utils"
+	"app/tasks"
+	"app/tasks/repack"
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type RepackResult struct {
+	Partition       string  `json:"partition"`
+	SizeBytes       int64   `json:"size_bytes"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// allows to replace pg_repack call in tests
+var repackPartition = repack.RepackPartition
+
+// RepackLargest repacks the largest partitions larger than partition_repack_min_size_mb. Next partition is started
+// only when its duration estimated from throughput of previous repacks fits in the remaining budget.
+func RepackLargest(reports []Report, budget time.Duration) []RepackResult {
+	type candidate struct {
+		table     string
+		partition Partition
+	}
+	var candidates []candidate
+	for _, report := range reports {
+		for _, p := range report.Partitions {
+			if p.SizeBytes >= tasks.PartitionRepackMinSize {
+				candidates = append(candidates, candidate{report.Table, p})
+			}
+		}
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(b.partition.SizeBytes, a.partition.SizeBytes)
+	})
+
+	results := []RepackResult{}
+	throughput := float64(tasks.PartitionRepackThroughput) // bytes per second
+	deadline := time.Now().Add(budget)
+	for _, c := range candidates {
+		// seconds, time.Duration would overflow for slow throughput
+		estimate := float64(c.partition.SizeBytes) / throughput
+		if estimate > time.Until(deadline).Seconds() {
+			utils.LogInfo("partition", c.partition.Name, "estimate_seconds", estimate, "Repack budget exceeded")
+			continue
+		}
+		start := time.Now()
+		err := repackPartition(c.partition.Name, repack.ClusterColumns[c.table])
+		duration := time.Since(start)
+		result := RepackResult{Partition: c.partition.Name, SizeBytes: c.partition.SizeBytes,
+			DurationSeconds: duration.Seconds()}
+		if err != nil {
+			result.Error = err.Error()
+			utils.LogError("err", err, "partition", c.partition.Name, "Partition repack failed")
+		} else if duration > 0 {
+			throughput = float64(c.partition.SizeBytes) / duration.Seconds()
+			utils.LogInfo("partition", c.partition.Name, "duration", duration.String(), "Partition repacked")
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Reports returns report of all managed tables
+func Reports() ([]Report, error) {
+	reports := make([]Report, 0, len(Tables))
+	for _, name := range slices.Sorted(maps.Keys(Tables)) {
+		report, err := GetReport(tasks.CancelableDB(), Tables[name])
+		if err != nil {
+			return nil, err
+		}
+		utils.LogInfo("table", report.Table, "size", report.SizeBytes, "skew", report.Skew,
+			"hot_accounts", len(report.HotAccounts), "Partition report")
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func RunPartitionMaintenance() error {
+	tasks.HandleContextCancel(tasks.WaitAndExit)
+	core.ConfigureAdminApp()
+	utils.LogInfo("Starting partition maintenance job")
+
+	reports, err := Reports()
+	if err != nil {
+		return errors.Wrap(err, "Partition report failed")
+	}
+	for _, report := range reports {
+		for _, account := range report.HotAccounts {
+			// split locks the table, it is run offline via admin API
+			utils.LogInfo("table", report.Table, "account", account.RhAccountID, "share", account.Share,
+				"Hot account, consider moving it to dedicated partition in maintenance window")
+		}
+	}
+	results := RepackLargest(reports, tasks.PartitionRepackBudget)
+	nFailed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			nFailed++
+		}
+	}
+	utils.LogInfo("repacked", len(results), "failed", nFailed, "Partition maintenance finished")
+	if nFailed > 0 {
+		return errors.Errorf("partition maintenance failed for %d partitions", nFailed)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package partitions
+
+import (
+	"app/base/utils"
+	"app/tasks"
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// Table partitioned by rh_account_id hash, hash partitions can be split to list partitions of hot accounts
+type Table struct {
+	Name string
+	// estimate of table rows of an account computed from system_patch
+	AccountRows string
+}
+
+var Tables = map[string]Table{
+	"system_package2":   {Name: "system_package2", AccountRows: "sum(packages_installed)"},
+	"system_advisories": {Name: "system_advisories", AccountRows: "sum(applicable_advisory_count_cache)"},
+}
+
+type Partition struct {
+	Name   string `json:"name"`
+	Parent string `json:"parent"`
+	// remainder of the hash partition containing this partition
+	Remainder int `json:"remainder"`
+	// account of dedicated list partition
+	RhAccountID *int  `json:"rh_account_id,omitempty"`
+	SizeBytes   int64 `json:"size_bytes"`
+	Rows        int64 `json:"rows_estimate"`
+}
+
+type HotAccount struct {
+	RhAccountID int   `json:"rh_account_id"`
+	Remainder   int   `json:"remainder"`
+	Rows        int64 `json:"rows_estimate"`
+	// share of the account in rows of its hash partition
+	Share float64 `json:"share"`
+}
+
+type Report struct {
+	Table     string `json:"table"`
+	Modulus   int    `json:"modulus"`
+	SizeBytes int64  `json:"size_bytes"`
+	// size of the largest hash partition divided by mean size of hash partitions
+	Skew float64 `json:"skew"`
+	// leaf partitions ordered by size, largest first
+	Partitions []Partition `json:"partitions"`
+	// accounts without dedicated partition exceeding partition_hot_account_pct of their hash partition rows
+	HotAccounts []HotAccount `json:"hot_accounts"`
+}
+
+var (
+	hashBoundRe = regexp.MustCompile(`(?i)modulus (\d+), remainder (\d+)`)
+	listBoundRe = regexp.MustCompile(`(?i)FOR VALUES IN \((\d+)\)`)
+)
+
+type partitionRow struct {
+	Name        string
+	Parent      string
+	Bound       string
+	ParentBound string
+	SizeBytes   int64
+	Rows        int64
+}
+
+func GetTable(name string) (Table, error) {
+	table, ok := Tables[name]
+	if !ok {
+		return table, errors.Errorf("table %s is not managed", name)
+	}
+	return table, nil
+}
+
+// GetReport reports sizes of leaf partitions of the table, partition skew and hot accounts
+func GetReport(db *gorm.DB, table Table) (Report, error) {
+	report := Report{Table: table.Name, Partitions: []Partition{}, HotAccounts: []HotAccount{}}
+	var rows []partitionRow
+	err := db.Raw(`SELECT c.relname AS name, p.relname AS parent,
+			pg_get_expr(c.relpartbound, c.oid) AS bound, pg_get_expr(p.relpartbound, p.oid) AS parent_bound,
+			pg_total_relation_size(c.oid) AS size_bytes, greatest(c.reltuples, 0)::bigint AS rows
+		FROM pg_partition_tree(?::regclass) t
+		JOIN pg_class c ON c.oid = t.relid
+		JOIN pg_class p ON p.oid = t.parentrelid
+		WHERE t.isleaf`, table.Name).Scan(&rows).Error
+	if err != nil {
+		return report, errors.Wrap(err, "Load partitions")
+	}
+
+	remainderSizes := map[int]int64{}
+	for _, row := range rows {
+		partition := Partition{Name: row.Name, Parent: row.Parent, SizeBytes: row.SizeBytes, Rows: row.Rows}
+		bound := row.Bound
+		if m := listBoundRe.FindStringSubmatch(row.Bound); m != nil {
+			accountID, _ := strconv.Atoi(m[1])
+			partition.RhAccountID = &accountID
+		}
+		if !hashBoundRe.MatchString(bound) {
+			// list partition of split hash partition
+			bound = row.ParentBound
+		}
+		if m := hashBoundRe.FindStringSubmatch(bound); m != nil {
+			report.Modulus, _ = strconv.Atoi(m[1])
+			partition.Remainder, _ = strconv.Atoi(m[2])
+		}
+		remainderSizes[partition.Remainder] += partition.SizeBytes
+		report.SizeBytes += partition.SizeBytes
+		report.Partitions = append(report.Partitions, partition)
+	}
+	slices.SortFunc(report.Partitions, func(a, b Partition) int {
+		return cmp.Compare(b.SizeBytes, a.SizeBytes)
+	})
+	report.Skew = skew(remainderSizes)
+
+	if report.Modulus > 0 {
+		report.HotAccounts, err = hotAccounts(db, table, report.Modulus, report.Partitions)
+	}
+	return report, err
+}
+
+func skew(sizes map[int]int64) float64 {
+	var total, largest int64
+	for _, size := range sizes {
+		total += size
+		largest = max(largest, size)
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(largest) * float64(len(sizes)) / float64(total)
+}
+
+// hotAccounts estimates rows of accounts from system_patch, table itself is too large to be counted
+func hotAccounts(db *gorm.DB, table Table, modulus int, partitions []Partition) ([]HotAccount, error) {
+	accounts := []HotAccount{}
+	err := db.Raw(fmt.Sprintf(`SELECT rh_account_id, remainder, rows, share FROM (
+			SELECT rh_account_id, remainder, rows,
+				rows::float / nullif(sum(rows) OVER (PARTITION BY remainder), 0) AS share
+			FROM (SELECT rh_account_id, hash_partition_id(rh_account_id, ?) AS remainder, %s AS rows
+				FROM system_patch GROUP BY rh_account_id) a
+		) s
+		WHERE share * 100 >= ? AND rows >= ?
+		ORDER BY rows DESC`, table.AccountRows),
+		modulus, tasks.PartitionHotAccountPct, tasks.PartitionHotAccountMinRows).Scan(&accounts).Error
+	if err != nil {
+		return accounts, errors.Wrap(err, "Load hot accounts")
+	}
+	// accounts already moved to dedicated partitions are not hot anymore
+	return slices.DeleteFunc(accounts, func(a HotAccount) bool {
+		return slices.ContainsFunc(partitions, func(p Partition) bool {
+			return p.RhAccountID != nil && *p.RhAccountID == a.RhAccountID
+		})
+	}), nil
+}
+
+// SplitAccount moves rows of the account to dedicated list partition of its hash partition.
+// Hash partition `<table>_<remainder>` is replaced by table partitioned by list of accounts with the original
+// partition attached as `<table>_<remainder>_default` partition. The table is locked with ACCESS EXCLUSIVE lock
+// until the rows are moved, it is an offline operation run in maintenance window with app components stopped.
+func SplitAccount(db *gorm.DB, table Table, accountID int) (string, error) {
+	report, err := GetReport(db, table)
+	if err != nil {
+		return "", err
+	}
+	if report.Modulus == 0 {
+		return "", errors.Errorf("table %s is not hash partitioned", table.Name)
+	}
+	var remainder int
+	if err = db.Raw("SELECT hash_partition_id(?, ?)", accountID, report.Modulus).Scan(&remainder).Error; err != nil {
+		return "", errors.Wrap(err, "Compute hash partition")
+	}
+	hashPartition := fmt.Sprintf("%s_%d", table.Name, remainder)
+	defaultPartition := hashPartition + "_default"
+	accountPartition := fmt.Sprintf("%s_acc_%d", hashPartition, accountID)
+	split := false
+	for _, p := range report.Partitions {
+		if p.RhAccountID != nil && *p.RhAccountID == accountID {
+			return p.Name, errors.Errorf("account %d already has partition %s", accountID, p.Name)
+		}
+		split = split || p.Name == defaultPartition
+	}
+
+	statements := []string{fmt.Sprintf("SET LOCAL lock_timeout = %d", tasks.PartitionSplitLockTimeoutMs)}
+	if split {
+		statements = append(statements,
+			fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", hashPartition, defaultPartition))
+	} else {
+		statements = append(statements,
+			fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", table.Name, hashPartition),
+			fmt.Sprintf("ALTER TABLE %s RENAME TO %s", hashPartition, defaultPartition),
+			fmt.Sprintf(`CREATE TABLE %s PARTITION OF %s FOR VALUES WITH (MODULUS %d, REMAINDER %d)
+				PARTITION BY LIST (rh_account_id)`, hashPartition, table.Name, report.Modulus, remainder))
+	}
+	statements = append(statements,
+		fmt.Sprintf("CREATE TABLE %s PARTITION OF %s FOR VALUES IN (%d)", accountPartition, hashPartition, accountID),
+		fmt.Sprintf("INSERT INTO %s SELECT * FROM %s WHERE rh_account_id = %d",
+			accountPartition, defaultPartition, accountID),
+		fmt.Sprintf("DELETE FROM %s WHERE rh_account_id = %d", defaultPartition, accountID),
+		fmt.Sprintf("ALTER TABLE %s ATTACH PARTITION %s DEFAULT", hashPartition, defaultPartition))
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range statements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return errors.Wrapf(err, "Split partition: %s", stmt)
+			}
+		}
+		return grantLikeTable(tx, table.Name, hashPartition, accountPartition)
+	})
+	if err != nil {
+		return "", err
+	}
+	utils.LogInfo("table", table.Name, "account", accountID, "partition", accountPartition, "Account partition created")
+	return accountPartition, nil
+}
+
+// grantLikeTable grants privileges of app users on the table to its new partitions
+func grantLikeTable(tx *gorm.DB, table string, partitions ...string) error {
+	var grants []struct {
+		Grantee    string
+		Privileges string
+	}
+	err := tx.Raw(`SELECT grantee, string_agg(privilege_type, ', ') AS privileges
+		FROM information_schema.role_table_grants
+		WHERE table_schema = 'public' AND table_name = ? AND grantee <> current_user
+		GROUP BY grantee`, table).Scan(&grants).Error
+	if err != nil {
+		return errors.Wrap(err, "Load table privileges")
+	}
+	for _, partition := range partitions {
+		for _, g := range grants {
+			stmt := fmt.Sprintf("GRANT %s ON %s TO %s", g.Privileges, partition, g.Grantee)
+			if err = tx.Exec(stmt).Error; err != nil {
+				return errors.Wrapf(err, "Grant: %s", stmt)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package partitions
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/utils"
+	"app/tasks"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkew(t *testing.T) {
+	assert.Equal(t, 0.0, skew(map[int]int64{0: 0, 1: 0}))
+	assert.Equal(t, 1.0, skew(map[int]int64{0: 10, 1: 10}))
+	assert.Equal(t, 1.5, skew(map[int]int64{0: 30, 1: 10}))
+}
+
+func TestRepackLargest(t *testing.T) {
+	minSize, throughput, repackFn := tasks.PartitionRepackMinSize, tasks.PartitionRepackThroughput, repackPartition
+	defer func() {
+		tasks.PartitionRepackMinSize, tasks.PartitionRepackThroughput, repackPartition = minSize, throughput, repackFn
+	}()
+	tasks.PartitionRepackMinSize = 100
+	tasks.PartitionRepackThroughput = 1 // 1 byte per second, only small partitions fit in the budget
+
+	var repacked []string
+	repackPartition = func(partition, _ string) error {
+		repacked = append(repacked, partition)
+		if partition == "t_1" {
+			return errors.New("repack failed")
+		}
+		return nil
+	}
+	reports := []Report{
+		{Table: "t", Partitions: []Partition{{Name: "t_0", SizeBytes: 1 << 40}, {Name: "t_1", SizeBytes: 200},
+			{Name: "t_2", SizeBytes: 50}}},
+		{Table: "u", Partitions: []Partition{{Name: "u_0", SizeBytes: 150}}},
+	}
+	results := RepackLargest(reports, time.Hour)
+	// t_0 does not fit in the budget, t_2 is too small
+	assert.Equal(t, []string{"t_1", "u_0"}, repacked)
+	require.Len(t, results, 2)
+	assert.Equal(t, "repack failed", results[0].Error)
+	assert.Equal(t, int64(150), results[1].SizeBytes)
+	assert.Empty(t, results[1].Error)
+}
+
+func TestGetReport(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	report, err := GetReport(database.DB, Tables["system_advisories"])
+	require.NoError(t, err)
+	assert.Equal(t, 32, report.Modulus)
+	assert.Len(t, report.Partitions, 32)
+	assert.Empty(t, report.HotAccounts)
+
+	_, err = GetTable("system_platform")
+	assert.Error(t, err)
+}
+
+func TestSplitAccount(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	// DDL is transactional, split is reverted at the end of the test
+	tx := database.DB.Begin()
+	defer tx.Rollback()
+
+	table := Tables["system_package2"]
+	var before int64
+	require.NoError(t, tx.Table(table.Name).Where("rh_account_id = 3").Count(&before).Error)
+
+	partition, err := SplitAccount(tx, table, 3)
+	require.NoError(t, err)
+
+	var after, moved int64
+	require.NoError(t, tx.Table(table.Name).Where("rh_account_id = 3").Count(&after).Error)
+	require.NoError(t, tx.Table(partition).Count(&moved).Error)
+	assert.Equal(t, before, after)
+	assert.Equal(t, before, moved)
+
+	report, err := GetReport(tx, table)
+	require.NoError(t, err)
+	assert.True(t, func() bool {
+		for _, p := range report.Partitions {
+			if p.Name == partition {
+				return p.RhAccountID != nil && *p.RhAccountID == 3
+			}
+		}
+		return false
+	}())
+
+	_, err = SplitAccount(tx, table, 3)
+	assert.ErrorContains(t, err, "already has partition")
+}
@@ -7,6 +7,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 var pgRepackArgs = []string{
@@ -59,25 +63,47 @@ func Repack(table string, columns string) error {
 	return nil
 }
 
+// RepackPartition runs pg_repack of a single partition (or other non-partitioned table), the rows are ordered by
+// columns when provided.
+func RepackPartition(partition string, columns string) error {
+	args := []string{"-t", partition}
+	if len(columns) > 0 {
+		args = append(args, "-o", columns)
+	}
+	cmd := exec.Command("pg_repack", append(slices.Clone(pgRepackArgs), args...)...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("PGPASSWORD=%s", utils.CoreCfg.DBAdminPassword))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// ClusterColumns of repacked tables
+var ClusterColumns = map[string]string{
+	"system_package2":   "rh_account_id,system_id",
+	"system_inventory":  "rh_account_id,id,inventory_id",
+	"system_patch":      "rh_account_id,system_id",
+	"system_advisories": "rh_account_id,system_id",
+}
+
 // RunRepack wraps Repack call for a job.
-func RunRepack() {
+func RunRepack() error {
 	tasks.HandleContextCancel(tasks.WaitAndExit)
 	utils.LogInfo("Starting repack job")
 	configure()
 
-	TABLES := map[string]string{
-		"system_package2":   "rh_account_id,system_id",
-		"system_inventory":  "rh_account_id,id,inventory_id",
-		"system_patch":      "rh_account_id,system_id",
-		"system_advisories": "rh_account_id,system_id",
-	}
-
-	for table, columns := range TABLES {
+	var failed []string
+	for table, columns := range ClusterColumns {
 		err := Repack(table, columns)
 		if err != nil {
 			utils.LogError("err", err, fmt.Sprintf("Failed to repack table %s", table))
+			failed = append(failed, table)
 			continue
 		}
 		utils.LogInfo(fmt.Sprintf("Successfully repacked table %s", table))
 	}
+	if len(failed) > 0 {
+		slices.Sort(failed)
+		return errors.Errorf("failed to repack tables %s", strings.Join(failed, ", "))
+	}
+	return nil
 }
@@ -7,6 +7,8 @@ import (
 	"app/base/utils"
 	"app/tasks"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -24,22 +26,22 @@ func Configure() {
 	evalWriter = mqueue.NewKafkaWriterFromEnv(evalTopic)
 }
 
-func Run() {
+func Run() error {
 	tasks.HandleContextCancel(tasks.WaitAndExit)
 	Configure()
 	defer utils.LogPanics(true)
 
 	if !tasks.EnableSystemAdvisories0Recovery {
 		utils.LogInfo("system_advisories_0_recovery disabled (set system_advisories_0_recovery=true in JOBS_CONFIG), skipping") //nolint:lll
-		return
+		return nil
 	}
 
 	utils.LogInfo("Starting system_advisories_0 recovery recalc publish")
 	if err := publishBucket0Recalc(); err != nil {
-		utils.LogError("err", err, "system_advisories_0 recovery failed")
-		return
+		return errors.Wrap(err, "system_advisories_0 recovery failed")
 	}
 	utils.LogInfo("system_advisories_0 recovery recalc publish finished")
+	return nil
 }
 
 func publishBucket0Recalc() error {
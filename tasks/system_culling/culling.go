@@ -8,14 +8,16 @@ import (
 
 func configure() {
 	core.ConfigureApp()
+	configureNotifications()
 }
 
-func RunSystemCulling() {
+func RunSystemCulling() error {
 	tasks.HandleContextCancel(tasks.WaitAndExit)
 	configure()
 
-	runSystemCulling()
+	errCulling := runSystemCulling()
 	if err := Metrics().Add(); err != nil {
 		utils.LogInfo("err", err, "Could not push to pushgateway")
 	}
+	return errCulling
 }
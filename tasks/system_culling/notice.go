@@ -0,0 +1,147 @@
+package system_culling
+
+import (
+	"app/base"
+	"app/base/database"
+	"app/base/models"
+	"app/base/mqueue"
+	ntf "app/base/notification"
+	"app/base/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+var notificationsPublisher mqueue.Writer
+
+func configureNotifications() {
+	if topic := utils.CoreCfg.NotificationsTopic; topic != "" {
+		notificationsPublisher = mqueue.NewKafkaWriterFromEnv(topic)
+	}
+}
+
+type cullingNoticeCandidate struct {
+	RhAccountID int       `gorm:"column:rh_account_id"`
+	OrgID       *string   `gorm:"column:org_id"`
+	ID          int64     `gorm:"column:id"`
+	InventoryID uuid.UUID `gorm:"column:inventory_id"`
+	DisplayName string    `gorm:"column:display_name"`
+	CullingDate time.Time `gorm:"column:culling_date"`
+}
+
+// systems of orgs with notice days set whose culling date is closer than notice days
+// and the org has not been notified about this culling date yet
+func loadCullingNoticeCandidates(tx *gorm.DB, limit int) ([]cullingNoticeCandidate, error) {
+	var candidates []cullingNoticeCandidate
+	err := database.JoinCullingPolicy(tx.Table("system_inventory si")).
+		Select("si.rh_account_id, ra.org_id, si.id, si.inventory_id, si.display_name, "+
+			database.CullingDateExpr+" as culling_date").
+		Joins("JOIN rh_account ra ON ra.id = si.rh_account_id").
+		Joins("LEFT JOIN system_culling_notice scn ON scn.rh_account_id = si.rh_account_id AND scn.system_id = si.id").
+		Where("cp.notice_days > 0 AND si.culled_timestamp IS NOT NULL").
+		Where(database.CullingDateExpr+" - make_interval(days => cp.notice_days) < ?", time.Now()).
+		Where("scn.culling_date IS DISTINCT FROM " + database.CullingDateExpr).
+		Order("si.rh_account_id").Order("si.id").
+		Limit(limit).
+		Find(&candidates).Error
+	return candidates, err
+}
+
+// notices of an org are committed before its notification is sent, when sending fails they are removed
+// so the org is notified in the next run
+func noticeCulledSystems(db *gorm.DB, limit int) (nNoticed int64, err error) {
+	if notificationsPublisher == nil {
+		return 0, nil
+	}
+
+	candidates, err := loadCullingNoticeCandidates(db, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	byAccount := make(map[int][]cullingNoticeCandidate)
+	accounts := make([]int, 0)
+	for _, c := range candidates {
+		if _, ok := byAccount[c.RhAccountID]; !ok {
+			accounts = append(accounts, c.RhAccountID)
+		}
+		byAccount[c.RhAccountID] = append(byAccount[c.RhAccountID], c)
+	}
+
+	for _, accountID := range accounts {
+		systems := byAccount[accountID]
+		if err := saveCullingNotices(db, systems); err != nil {
+			return nNoticed, errors.Wrap(err, "saving culling notices failed")
+		}
+		if err := sendCullingNotification(systems); err != nil {
+			utils.LogWarn("rhAccountID", accountID, "err", err, "Send culling notification")
+			if err := deleteCullingNotices(db, systems); err != nil {
+				return nNoticed, errors.Wrap(err, "removing unsent culling notices failed")
+			}
+			continue
+		}
+		nNoticed += int64(len(systems))
+	}
+	return nNoticed, nil
+}
+
+func sendCullingNotification(systems []cullingNoticeCandidate) error {
+	orgID := ""
+	if systems[0].OrgID != nil {
+		orgID = *systems[0].OrgID
+	}
+
+	events := make([]ntf.Event, 0, len(systems))
+	for _, s := range systems {
+		events = append(events, ntf.Event{
+			Payload: ntf.CulledSystem{
+				InventoryID: s.InventoryID,
+				DisplayName: s.DisplayName,
+				CullingDate: s.CullingDate.Format(time.RFC3339),
+			},
+			Metadata: ntf.Metadata{},
+		})
+	}
+
+	notif, err := ntf.MakeAccountNotification(orgID, ntf.SystemsCullingEvent, events)
+	if err != nil {
+		return errors.Wrap(err, "creating notification failed")
+	}
+
+	msg, err := mqueue.MessageFromJSON(orgID, notif, nil)
+	if err != nil {
+		return errors.Wrap(err, "creating message from notification failed")
+	}
+
+	err = notificationsPublisher.WriteMessages(base.Context, msg)
+	if err != nil {
+		return errors.Wrap(err, "writing message to notifications publisher failed")
+	}
+	return nil
+}
+
+func saveCullingNotices(tx *gorm.DB, systems []cullingNoticeCandidate) error {
+	now := time.Now()
+	notices := make([]models.SystemCullingNotice, 0, len(systems))
+	for _, s := range systems {
+		notices = append(notices, models.SystemCullingNotice{
+			RhAccountID: s.RhAccountID,
+			SystemID:    s.ID,
+			CullingDate: s.CullingDate,
+			Notified:    now,
+		})
+	}
+	return database.OnConflictUpdateMulti(tx, []string{"rh_account_id", "system_id"}, "culling_date", "notified").
+		Create(&notices).Error
+}
+
+func deleteCullingNotices(tx *gorm.DB, systems []cullingNoticeCandidate) error {
+	systemIDs := make([]int64, 0, len(systems))
+	for _, s := range systems {
+		systemIDs = append(systemIDs, s.ID)
+	}
+	return tx.Delete(&models.SystemCullingNotice{}, "rh_account_id = ? AND system_id IN (?)",
+		systems[0].RhAccountID, systemIDs).Error
+}
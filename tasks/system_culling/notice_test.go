@@ -0,0 +1,93 @@
+package system_culling
+
+import (
+	"app/base/core"
+	"app/base/database"
+	"app/base/models"
+	"app/base/mqueue"
+	ntf "app/base/notification"
+	"app/base/utils"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{}
+
+func (w *failingWriter) WriteMessages(_ context.Context, _ ...mqueue.KafkaMessage) error {
+	return errors.New("kafka unavailable")
+}
+
+func TestNoticeCulledSystems(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	mockWriter := mqueue.MockKafkaWriter{}
+	notificationsPublisher = &mockWriter
+	defer func() { notificationsPublisher = nil }()
+
+	culled := time.Now().Add(24 * time.Hour)
+	inv := models.SystemInventory{
+		InventoryID:     uuid.MustParse("00000000-0000-0000-0000-000000000de1"),
+		RhAccountID:     1,
+		DisplayName:     "culling-notice",
+		Tags:            []byte("[]"),
+		WorkspaceID:     database.TestWorkspace1IDPtr(),
+		WorkspaceName:   database.TestWorkspace1NamePtr(),
+		CulledTimestamp: &culled,
+	}
+	assert.NoError(t, database.DB.Create(&inv).Error)
+	defer database.DB.Exec("select delete_system(?)", inv.InventoryID)
+
+	// no notice days, nothing to notify about
+	nNoticed, err := noticeCulledSystems(database.DB, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), nNoticed)
+
+	// culled tomorrow, deleted in 3 days, org notified 5 days before deletion
+	database.CreateCullingPolicy(t, 1, 2, 5)
+	defer database.DeleteCullingPolicy(t, 1)
+	nNoticed, err = noticeCulledSystems(database.DB, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), nNoticed)
+	assert.Len(t, mockWriter.Messages, 1)
+
+	var notif ntf.Notification
+	assert.NoError(t, sonic.Unmarshal(mockWriter.Messages[0].Value, &notif))
+	assert.Equal(t, ntf.SystemsCullingEvent, notif.EventType)
+	assert.Equal(t, "org_1", notif.OrgID)
+	assert.Len(t, notif.Events, 1)
+
+	var notice models.SystemCullingNotice
+	assert.NoError(t, database.DB.Find(&notice, "rh_account_id = ? AND system_id = ?", 1, inv.ID).Error)
+	assert.Equal(t, inv.ID, notice.SystemID)
+
+	// already notified about the same culling date
+	nNoticed, err = noticeCulledSystems(database.DB, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), nNoticed)
+	assert.Len(t, mockWriter.Messages, 1)
+
+	// culling date moved, org is notified again
+	database.CreateCullingPolicy(t, 1, 3, 5)
+	nNoticed, err = noticeCulledSystems(database.DB, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), nNoticed)
+	assert.Len(t, mockWriter.Messages, 2)
+
+	// notification not sent, notice is removed to retry in next run
+	notificationsPublisher = &failingWriter{}
+	database.CreateCullingPolicy(t, 1, 4, 5)
+	nNoticed, err = noticeCulledSystems(database.DB, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), nNoticed)
+	var cnt int64
+	assert.NoError(t, database.DB.Model(&models.SystemCullingNotice{}).
+		Where("rh_account_id = ? AND system_id = ?", 1, inv.ID).Count(&cnt).Error)
+	assert.Equal(t, int64(0), cnt)
+}
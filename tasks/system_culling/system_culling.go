@@ -1,6 +1,7 @@
 package system_culling
 
 import (
+	"app/base/database"
 	"app/base/models"
 	"app/base/utils"
 	"app/tasks"
@@ -11,9 +12,18 @@ import (
 	"gorm.io/gorm"
 )
 
-func runSystemCulling() {
+func runSystemCulling() error {
 	defer utils.LogPanics(true)
 
+	// notify orgs about systems deleted within notice days of their culling policy,
+	// outside of the transaction below so notifications are sent only for committed notices
+	nNoticed, errNotice := noticeCulledSystems(tasks.CancelableDB(), tasks.DeleteCulledSystemsLimit)
+	if errNotice != nil {
+		utils.LogError("err", errNotice, "Notice culled")
+	} else {
+		utils.LogInfo("nNoticed", nNoticed, "Culling notices sent")
+	}
+
 	err := tasks.WithTx(func(tx *gorm.DB) error {
 		nDeleted, err := deleteCulledSystems(tx, tasks.DeleteCulledSystemsLimit)
 		if err != nil {
@@ -37,24 +47,48 @@ func runSystemCulling() {
 		}
 		utils.LogInfo("nPruned", nPruned, "Deleted_systems items pruned")
 
+		// pruning system_archive
+		nPrunedArchive, err := pruneArchivedSystems(tx, tasks.DeleteCulledSystemsLimit)
+		if err != nil {
+			return errors.Wrap(err, "Prune system_archive")
+		}
+		utils.LogInfo("nPruned", nPrunedArchive, "Archived systems pruned")
+
+		// pruning rejected_upload
+		nPrunedRejected, err := pruneRejectedUploads(tx, tasks.DeleteCulledSystemsLimit)
+		if err != nil {
+			return errors.Wrap(err, "Prune rejected_upload")
+		}
+		utils.LogInfo("nPruned", nPrunedRejected, "Rejected uploads pruned")
+
+		// pruning system_package_change
+		nPrunedChanges, err := pruneSystemPackageChanges(tx, tasks.DeleteCulledSystemsLimit)
+		if err != nil {
+			return errors.Wrap(err, "Prune system_package_change")
+		}
+		utils.LogInfo("nPruned", nPrunedChanges, "Package changes pruned")
+
 		return nil
 	})
 
 	if err != nil {
-		utils.LogError("err", err, "System culling")
-	} else {
-		utils.LogInfo("System culling tasks performed successfully")
+		return errors.Wrap(err, "System culling")
+	}
+	if errNotice != nil {
+		return errors.Wrap(errNotice, "Notice culled")
 	}
+	utils.LogInfo("System culling tasks performed successfully")
+	return nil
 }
 
 // systems are deleted in independent transactions to avoid locking multiple rows for long time
 func deleteCulledSystems(tx *gorm.DB, limitDeleted int) (nDeleted int64, err error) {
 	var inventoryIDs []uuid.UUID
-	err = tx.Model(&models.SystemInventory{}).
-		Where("culled_timestamp < ?", time.Now()).
-		Order("id").
+	err = database.JoinCullingPolicy(tx.Table("system_inventory si")).
+		Where(database.CullingDateExpr+" < ?", time.Now()).
+		Order("si.id").
 		Limit(limitDeleted).
-		Pluck("inventory_id", &inventoryIDs).Error
+		Pluck("si.inventory_id", &inventoryIDs).Error
 	if err != nil {
 		return 0, err
 	}
@@ -62,7 +96,7 @@ func deleteCulledSystems(tx *gorm.DB, limitDeleted int) (nDeleted int64, err err
 	for _, id := range inventoryIDs {
 		var rowsAffected int64
 		delErr := tasks.CancelableDB().Transaction(func(tx2 *gorm.DB) error {
-			res := tx2.Exec("select delete_system(?)", id)
+			res := database.DeleteSystem(tx2, id)
 			if res.Error != nil {
 				return res.Error
 			}
@@ -128,3 +162,30 @@ func pruneDeletedSystems(tx *gorm.DB, limitDeleted int) (int64, error) {
 	query := tx.Delete(&models.DeletedSystem{}, "inventory_id in (?)", subQ)
 	return query.RowsAffected, query.Error
 }
+
+func pruneArchivedSystems(tx *gorm.DB, limitDeleted int) (int64, error) {
+	subQ := tx.Model(&models.SystemArchive{}).
+		Where("archived < ?", time.Now().Add(-tasks.SystemArchiveRetention)).
+		Limit(limitDeleted).
+		Select("inventory_id")
+	query := tx.Delete(&models.SystemArchive{}, "inventory_id in (?)", subQ)
+	return query.RowsAffected, query.Error
+}
+
+func pruneRejectedUploads(tx *gorm.DB, limitDeleted int) (int64, error) {
+	subQ := tx.Model(&models.RejectedUpload{}).
+		Where("last_rejected < ?", time.Now().Add(-tasks.RejectedUploadRetention)).
+		Limit(limitDeleted).
+		Select("rh_account_id, inventory_id")
+	query := tx.Delete(&models.RejectedUpload{}, "(rh_account_id, inventory_id) in (?)", subQ)
+	return query.RowsAffected, query.Error
+}
+
+func pruneSystemPackageChanges(tx *gorm.DB, limitDeleted int) (int64, error) {
+	subQ := tx.Model(&models.SystemPackageChange{}).
+		Where("changed < ?", time.Now().Add(-tasks.PackageChangeRetention)).
+		Limit(limitDeleted).
+		Select("rh_account_id, system_id, id")
+	query := tx.Delete(&models.SystemPackageChange{}, "(rh_account_id, system_id, id) in (?)", subQ)
+	return query.RowsAffected, query.Error
+}
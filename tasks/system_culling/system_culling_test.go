@@ -6,6 +6,7 @@ import (
 	"app/base/models"
 	"app/base/types"
 	"app/base/utils"
+	"app/tasks"
 	"fmt"
 	"testing"
 	"time"
@@ -244,3 +245,131 @@ func TestPruneDeletedSystems(t *testing.T) {
 	// clean data from table
 	assert.NoError(t, database.DB.Delete(&models.DeletedSystem{}, "1=1").Error)
 }
+
+func TestCullSystemsPolicyDelay(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	culled := time.Now().Add(-48 * time.Hour)
+	inv := models.SystemInventory{
+		InventoryID:     uuid.MustParse("00000000-0000-0000-0000-000000000de1"),
+		RhAccountID:     1,
+		DisplayName:     "culling-delayed",
+		Tags:            []byte("[]"),
+		WorkspaceID:     database.TestWorkspace1IDPtr(),
+		WorkspaceName:   database.TestWorkspace1NamePtr(),
+		CulledTimestamp: &culled,
+	}
+	assert.NoError(t, database.DB.Create(&inv).Error)
+	assert.NoError(t, database.DB.Create(&models.SystemPatch{SystemID: inv.ID, RhAccountID: 1}).Error)
+
+	// culled 2 days ago but org keeps culled systems for 7 more days
+	database.CreateCullingPolicy(t, 1, 7, 0)
+	nDeleted, err := deleteCulledSystems(database.DB, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), nDeleted)
+
+	database.DeleteCullingPolicy(t, 1)
+	nDeleted, err = deleteCulledSystems(database.DB, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), nDeleted)
+}
+
+func TestPruneArchivedSystems(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	for i, archived := range []time.Time{staleDate, staleDate, time.Now()} {
+		assert.NoError(t, database.DB.Create(&models.SystemArchive{
+			InventoryID: uuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-000000000de%d", i+1)),
+			RhAccountID: 1,
+			SystemID:    int64(1000 + i),
+			Archived:    archived,
+			Inventory:   []byte("{}"),
+			Advisories:  []byte("[]"),
+			Packages:    []byte("[]"),
+			Repos:       []byte("[]"),
+		}).Error)
+	}
+
+	nPruned, err := pruneArchivedSystems(database.DB, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), nPruned)
+
+	// last system is within retention
+	nPruned, err = pruneArchivedSystems(database.DB, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), nPruned)
+
+	var cnt int64
+	assert.NoError(t, database.DB.Model(&models.SystemArchive{}).Count(&cnt).Error)
+	assert.Equal(t, int64(1), cnt)
+
+	// clean data from table
+	assert.NoError(t, database.DB.Delete(&models.SystemArchive{}, "1=1").Error)
+}
+
+func TestPruneRejectedUploads(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	for i, rejected := range []time.Time{staleDate, time.Now()} {
+		assert.NoError(t, database.DB.Create(&models.RejectedUpload{
+			RhAccountID:   1,
+			InventoryID:   uuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-000000000de%d", i+1)),
+			Reporter:      "puptoo",
+			Reason:        "no-packages",
+			Event:         []byte("{}"),
+			FirstRejected: rejected,
+			LastRejected:  rejected,
+			RejectedCount: 1,
+		}).Error)
+	}
+
+	// last upload is within retention
+	nPruned, err := pruneRejectedUploads(database.DB, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), nPruned)
+
+	// clean data from table
+	assert.NoError(t, database.DB.Delete(&models.RejectedUpload{}, "1=1").Error)
+}
+
+func TestPruneSystemPackageChanges(t *testing.T) {
+	utils.SkipWithoutDB(t)
+	core.SetupTestEnvironment()
+
+	// changes of test data are within retention
+	retention := tasks.PackageChangeRetention
+	tasks.PackageChangeRetention = time.Since(time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer func() { tasks.PackageChangeRetention = retention }()
+
+	var before int64
+	assert.NoError(t, database.DB.Model(&models.SystemPackageChange{}).Count(&before).Error)
+	for _, changed := range []time.Time{staleDate, staleDate, time.Now()} {
+		assert.NoError(t, database.DB.Omit("id").Create(&models.SystemPackageChange{
+			RhAccountID: 1,
+			SystemID:    2,
+			NameID:      101,
+			Change:      models.PackageChangeInstalled,
+			NewEvra:     utils.PtrString("5.6.13-200.fc31.x86_64"),
+			Changed:     changed,
+		}).Error)
+	}
+
+	nPruned, err := pruneSystemPackageChanges(database.DB, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), nPruned)
+
+	// last change is within retention
+	nPruned, err = pruneSystemPackageChanges(database.DB, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), nPruned)
+
+	var cnt int64
+	assert.NoError(t, database.DB.Model(&models.SystemPackageChange{}).Count(&cnt).Error)
+	assert.Equal(t, before+1, cnt)
+
+	// clean data from table
+	assert.NoError(t, database.DB.Delete(&models.SystemPackageChange{}, "changed > ?", staleDate.AddDate(20, 0, 0)).Error)
+}
@@ -25,7 +25,7 @@ var advisoryTypeRemap = map[string]string{
 }
 
 func syncAdvisories(syncStart time.Time, modifiedSince *string) error {
-	if vmaasClient == nil {
+	if vmaasClient == nil && vmaasDump == nil {
 		panic("VMaaS client is nil")
 	}
 
@@ -293,13 +293,21 @@ func vmaasErrataRequest(iPage int, modifiedSince *string, pageSize int) (*vmaas.
 		ModifiedSince: modifiedSince,
 	}
 
+	if vmaasDump != nil {
+		dump, err := vmaasDump.Load()
+		if err != nil {
+			return nil, errors.Wrap(err, "Loading erratas")
+		}
+		return dump.Errata(&errataRequest), nil
+	}
+
 	vmaasCallFunc := func() (interface{}, *http.Response, error) {
 		vmaasData := vmaas.ErrataResponse{}
 		resp, err := vmaasClient.Request(&base.Context, http.MethodPost, vmaasErratasURL, &errataRequest, &vmaasData)
 		return &vmaasData, resp, err
 	}
 
-	vmaasDataPtr, err := utils.HTTPCallRetry(vmaasCallFunc, tasks.VmaasCallExpRetry, tasks.VmaasCallMaxRetries)
+	vmaasDataPtr, err := vmaasClient.CallRetry(vmaasCallFunc, tasks.VmaasCallExpRetry, tasks.VmaasCallMaxRetries)
 	if err != nil {
 		vmaasCallCnt.WithLabelValues("error-download-errata").Inc()
 		return nil, errors.Wrap(err, "Downloading erratas")
@@ -20,17 +20,25 @@ func isSyncNeeded(dbExportedTS *types.Rfc3339TimestampWithZ, vmaasExportedTS *ty
 }
 
 func vmaasDBChangeRequest() (*vmaas.DBChangeResponse, error) {
-	if vmaasClient == nil {
+	if vmaasClient == nil && vmaasDump == nil {
 		panic("VMaaS client is nil")
 	}
 
+	if vmaasDump != nil {
+		dump, err := vmaasDump.Load()
+		if err != nil {
+			return nil, errors.Wrap(err, "Checking DBChange")
+		}
+		return dump.DBChange(), nil
+	}
+
 	vmaasCallFunc := func() (interface{}, *http.Response, error) {
 		response := vmaas.DBChangeResponse{}
 		resp, err := vmaasClient.Request(&base.Context, http.MethodGet, vmaasDBChangeURL, nil, &response)
 		return &response, resp, err
 	}
 
-	vmaasDataPtr, err := utils.HTTPCallRetry(vmaasCallFunc, tasks.VmaasCallExpRetry, tasks.VmaasCallMaxRetries)
+	vmaasDataPtr, err := vmaasClient.CallRetry(vmaasCallFunc, tasks.VmaasCallExpRetry, tasks.VmaasCallMaxRetries)
 	if err != nil {
 		vmaasCallCnt.WithLabelValues("error-dbchange").Inc()
 		return nil, errors.Wrap(err, "Checking DBChange")
@@ -17,8 +17,8 @@ func TestTableSizes(t *testing.T) {
 	for _, item := range tableSizes {
 		uniqueTables[item.Key] = true
 	}
-	assert.Equal(t, 280, len(tableSizes))
-	assert.Equal(t, 280, len(uniqueTables))
+	assert.Equal(t, 422, len(tableSizes))
+	assert.Equal(t, 422, len(uniqueTables))
 	assert.True(t, uniqueTables["public.system_inventory"]) // check whether table names were loaded
 	assert.True(t, uniqueTables["public.system_patch"])     // check whether table names were loaded
 	assert.True(t, uniqueTables["public.package"])
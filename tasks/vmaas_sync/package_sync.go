@@ -19,7 +19,7 @@ import (
 const chunkSize = 10 * 1024
 
 func syncPackages(syncStart time.Time, modifiedSince *string) error {
-	if vmaasClient == nil {
+	if vmaasClient == nil && vmaasDump == nil {
 		panic("VMaaS client is nil")
 	}
 
@@ -93,13 +93,21 @@ func vmaasPkgListRequest(iPage int, modifiedSince *string) (*vmaas.PkgListRespon
 		ModifiedSince: modifiedSince,
 	}
 
+	if vmaasDump != nil {
+		dump, err := vmaasDump.Load()
+		if err != nil {
+			return nil, errors.Wrap(err, "Loading pkglist response")
+		}
+		return dump.PkgList(&request), nil
+	}
+
 	vmaasCallFunc := func() (interface{}, *http.Response, error) {
 		vmaasData := vmaas.PkgListResponse{}
 		resp, err := vmaasClient.Request(&base.Context, http.MethodPost, vmaasPkgListURL, &request, &vmaasData)
 		return &vmaasData, resp, err
 	}
 
-	vmaasDataPtr, err := utils.HTTPCallRetry(vmaasCallFunc, tasks.VmaasCallExpRetry, tasks.VmaasCallMaxRetries)
+	vmaasDataPtr, err := vmaasClient.CallRetry(vmaasCallFunc, tasks.VmaasCallExpRetry, tasks.VmaasCallMaxRetries)
 	if err != nil {
 		vmaasCallCnt.WithLabelValues("error-download-pkglist-response").Inc()
 		return nil, errors.Wrap(err, "Downloading pkglist response")
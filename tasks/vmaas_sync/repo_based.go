@@ -116,18 +116,10 @@ func getVmaasRepos(syncStart time.Time, modifiedSince *string, thirdParty bool,
 			ShowPackages:   true,
 		}
 
-		vmaasCallFunc := func() (interface{}, *http.Response, error) {
-			vmaasData := vmaas.ReposResponse{}
-			resp, err := vmaasClient.Request(&base.Context, http.MethodPost, vmaasReposURL, &reposReq, &vmaasData)
-			return &vmaasData, resp, err
-		}
-
-		vmaasDataPtr, err := utils.HTTPCallRetry(vmaasCallFunc, tasks.VmaasCallExpRetry, tasks.VmaasCallMaxRetries)
+		repos, err := vmaasReposRequest(&reposReq)
 		if err != nil {
 			return nil, nil, err
 		}
-		vmaasCallCnt.WithLabelValues("success").Inc()
-		repos := vmaasDataPtr.(*vmaas.ReposResponse)
 		if repos.Pages < 1 {
 			utils.LogInfo("No repos returned from VMaaS")
 			break
@@ -178,6 +170,29 @@ func getVmaasRepos(syncStart time.Time, modifiedSince *string, thirdParty bool,
 	return repoPackages, latestRepoChange, nil
 }
 
+func vmaasReposRequest(reposReq *vmaas.ReposRequest) (*vmaas.ReposResponse, error) {
+	if vmaasDump != nil {
+		dump, err := vmaasDump.Load()
+		if err != nil {
+			return nil, err
+		}
+		return dump.Repos(reposReq), nil
+	}
+
+	vmaasCallFunc := func() (interface{}, *http.Response, error) {
+		vmaasData := vmaas.ReposResponse{}
+		resp, err := vmaasClient.Request(&base.Context, http.MethodPost, vmaasReposURL, reposReq, &vmaasData)
+		return &vmaasData, resp, err
+	}
+
+	vmaasDataPtr, err := vmaasClient.CallRetry(vmaasCallFunc, tasks.VmaasCallExpRetry, tasks.VmaasCallMaxRetries)
+	if err != nil {
+		return nil, err
+	}
+	vmaasCallCnt.WithLabelValues("success").Inc()
+	return vmaasDataPtr.(*vmaas.ReposResponse), nil
+}
+
 func getRepoUpdatedPackages(repo map[string]interface{}) []string {
 	var repoPackages []string
 	if value, ok := repo["updated_package_names"]; ok {
@@ -8,6 +8,7 @@ import (
 	"app/base/mqueue"
 	"app/base/types"
 	"app/base/utils"
+	"app/base/vmaas_dump"
 	"app/tasks"
 	"app/tasks/caches"
 	"net/http"
@@ -24,24 +25,32 @@ var (
 	vmaasReposURL    string
 	vmaasDBChangeURL string
 	evalWriter       mqueue.Writer
+	// offline vmaas data used instead of vmaas API when VMAAS_DUMP_PATH is set
+	vmaasDump *vmaas_dump.Source
 )
 
 func Configure() {
 	core.ConfigureApp()
-	vmaasClient = &api.Client{
-		HTTPClient: &http.Client{},
-		Debug:      tasks.UseTraceLevel,
+	if utils.CoreCfg.VmaasDumpPath != "" {
+		utils.LogInfo("path", utils.CoreCfg.VmaasDumpPath, "Syncing from offline vmaas dump")
+		vmaasDump = vmaas_dump.NewSource(utils.CoreCfg.VmaasDumpPath)
+	} else {
+		vmaasClient = &api.Client{
+			HTTPClient: &http.Client{},
+			Debug:      tasks.UseTraceLevel,
+			Upstream:   api.GetUpstream(api.UpstreamVmaas),
+		}
+		vmaasAddress := utils.FailIfEmpty(utils.CoreCfg.VmaasAddress, "VMAAS_ADDRESS")
+		vmaasErratasURL = vmaasAddress + base.VMaaSAPIPrefix + "/errata"
+		vmaasPkgListURL = vmaasAddress + base.VMaaSAPIPrefix + "/pkglist"
+		vmaasReposURL = vmaasAddress + base.VMaaSAPIPrefix + "/repos"
+		vmaasDBChangeURL = vmaasAddress + base.VMaaSAPIPrefix + "/dbchange"
 	}
-	vmaasAddress := utils.FailIfEmpty(utils.CoreCfg.VmaasAddress, "VMAAS_ADDRESS")
-	vmaasErratasURL = vmaasAddress + base.VMaaSAPIPrefix + "/errata"
-	vmaasPkgListURL = vmaasAddress + base.VMaaSAPIPrefix + "/pkglist"
-	vmaasReposURL = vmaasAddress + base.VMaaSAPIPrefix + "/repos"
-	vmaasDBChangeURL = vmaasAddress + base.VMaaSAPIPrefix + "/dbchange"
 	evalTopic := utils.FailIfEmpty(utils.CoreCfg.EvalTopic, "EVAL_TOPIC")
 	evalWriter = mqueue.NewKafkaWriterFromEnv(evalTopic)
 }
 
-func runSync() {
+func runSync() error {
 	utils.LogInfo("Starting vmaas-sync job")
 
 	var lastModified *types.Rfc3339TimestampWithZ
@@ -58,9 +67,10 @@ func runSync() {
 
 		err = SendReevaluationMessages()
 		if err != nil {
-			utils.LogError("err", err, "re-evaluation sending routine failed")
+			return errors.Wrap(err, "re-evaluation sending routine failed")
 		}
 	}
+	return nil
 }
 
 func GetLastSync(key string) *types.Rfc3339TimestampWithZ {
@@ -114,7 +124,10 @@ func SyncData(lastModifiedTS *types.Rfc3339TimestampWithZ, vmaasExportedTS *type
 
 	// refresh caches
 	if tasks.EnableAdvisoryCacheRefresh {
-		caches.RefreshAdvisoryCaches()
+		// accounts with failed refresh keep invalid cache and are refreshed again by next run
+		if err := caches.RefreshAdvisoryCaches(); err != nil {
+			utils.LogError("err", err, "Advisory cache refresh")
+		}
 	} else {
 		utils.LogInfo("Advisory cache refresh is disabled")
 	}
@@ -123,12 +136,13 @@ func SyncData(lastModifiedTS *types.Rfc3339TimestampWithZ, vmaasExportedTS *type
 	return nil
 }
 
-func RunVmaasSync() {
+func RunVmaasSync() error {
 	tasks.HandleContextCancel(tasks.WaitAndExit)
 	Configure()
 
-	runSync()
+	errSync := runSync()
 	if err := Metrics().Add(); err != nil {
 		utils.LogInfo("err", err, "Could not push to pushgateway")
 	}
+	return errSync
 }
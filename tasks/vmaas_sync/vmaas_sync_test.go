@@ -40,7 +40,7 @@ func TestSyncDates(t *testing.T) {
 	ts := GetLastSync(VmaasExported)
 	assert.Nil(t, ts)
 
-	runSync()
+	assert.NoError(t, runSync())
 
 	ts = GetLastSync(VmaasExported)
 	assert.Equal(t, "2222-04-16 20:07:59.235962 +0000 UTC", ts.Time().String())
@@ -64,7 +64,7 @@ func TestSync(t *testing.T) {
 
 	evalWriter = &mockKafkaWriter{}
 
-	runSync()
+	assert.NoError(t, runSync())
 
 	expected := []string{"RH-100"}
 	database.CheckAdvisoriesInDB(t, expected)
@@ -31,6 +31,8 @@ func RunAdminAPI() {
 
 	utils.LogInfo("port", utils.CoreCfg.PublicPort, "Manager-admin starting")
 	app := gin.New()
+	app.ContextWithFallback = true
+	app.Use(middlewares.Tracing())
 	app.Use(middlewares.RequestResponseLogger())
 	middlewares.SetAdminSwagger(app)
 
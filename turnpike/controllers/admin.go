@@ -1,7 +1,10 @@
 package controllers
 
 import (
+	"app/base"
 	"app/base/database"
+	"app/base/models"
+	"app/base/mqueue"
 	"app/base/utils"
 	"app/manager/middlewares"
 	"app/tasks/caches"
@@ -12,6 +15,7 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -201,7 +205,7 @@ func CleanAADHandler(c *gin.Context) {
 }
 
 // @Summary Delete system by inventory id
-// @Description Delete system by inventory id
+// @Description Delete system by inventory id, system data are archived when soft delete is enabled
 // @ID deletesystem
 // @Security RhIdentity
 // @Accept   json
@@ -241,7 +245,7 @@ func SystemDeleteHandler(c *gin.Context) {
 		return
 	}
 
-	query := tx.Exec("select delete_system(?)", systemInventoryID[0])
+	query := database.DeleteSystem(tx, systemInventoryID[0])
 	if err := query.Error; err != nil {
 		utils.LogAndRespError(c, err, "Could not delete system")
 		return
@@ -259,3 +263,118 @@ func SystemDeleteHandler(c *gin.Context) {
 		return
 	}
 }
+
+// @Summary Restore soft deleted system by inventory id
+// @Description Restore archived system data, e.g. after an accidental inventory delete,
+// @Description without waiting for a new upload
+// @ID restoresystem
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    inventory_id    path    string   true "Inventory ID"
+// @Success 200
+// @Failure 400 {object}	string
+// @Failure 404 {object}	string
+// @Failure 409 {object}	string
+// @Failure 500 {object}	string
+// @Router /systems/{inventory_id}/restore [put]
+func SystemRestoreHandler(c *gin.Context) {
+	inventoryID, err := uuid.Parse(c.Param("inventory_id"))
+	if err != nil {
+		// catches both empty and malformed inventory_id errors
+		utils.LogAndRespBadRequest(c, err, "incorrect inventory_id format")
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	tx := db.Begin()
+
+	defer tx.Rollback()
+
+	var exists int64
+	if err = tx.Model(&models.SystemInventory{}).Where("inventory_id = ?", inventoryID).Count(&exists).Error; err != nil {
+		utils.LogAndRespError(c, err, "could not query database for system")
+		return
+	}
+	if exists > 0 {
+		utils.LogAndRespStatusError(c, http.StatusConflict, errors.New("system exists"), "system already exists")
+		return
+	}
+
+	restored, err := database.RestoreSystem(tx, inventoryID)
+	if err != nil {
+		utils.LogAndRespError(c, err, "Could not restore system")
+		return
+	}
+	if restored == nil {
+		utils.LogAndRespNotFound(c, errors.New("no rows returned"), "archived system not found")
+		return
+	}
+
+	// allow listener to process uploads of the restored system again
+	if err = tx.Delete(&models.DeletedSystem{}, "inventory_id = ?", inventoryID).Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not restore system")
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		utils.LogAndRespError(c, err, "Could not restore system")
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Summary Replay rejected upload by inventory id
+// @Description Send the last rejected upload of the system to listener again, e.g. after package profile
+// @Description parsing was fixed
+// @ID replayrejectedupload
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    inventory_id    path    string   true "Inventory ID"
+// @Success 200
+// @Failure 400 {object}	string
+// @Failure 404 {object}	string
+// @Failure 500 {object}	string
+// @Router /systems/{inventory_id}/replay [put]
+func RejectedUploadReplayHandler(c *gin.Context) {
+	inventoryID, err := uuid.Parse(c.Param("inventory_id"))
+	if err != nil {
+		// catches both empty and malformed inventory_id errors
+		utils.LogAndRespBadRequest(c, err, "incorrect inventory_id format")
+		return
+	}
+
+	db := middlewares.DBFromContext(c)
+	var rejected []models.RejectedUpload
+	if err = db.Where("inventory_id = ?", inventoryID).Find(&rejected).Error; err != nil {
+		utils.LogAndRespError(c, err, "could not query database for rejected upload")
+		return
+	}
+	if len(rejected) == 0 {
+		utils.LogAndRespNotFound(c, errors.New("no rows returned"), "rejected upload not found")
+		return
+	}
+
+	writer, err := getReplayWriter()
+	if err != nil {
+		utils.LogAndRespError(c, err, err.Error())
+		return
+	}
+	msgs := make([]mqueue.KafkaMessage, 0, len(rejected))
+	for _, r := range rejected {
+		msgs = append(msgs, mqueue.KafkaMessage{Key: []byte(inventoryID.String()), Value: r.Event})
+	}
+	if err = writer.WriteMessages(base.Context, msgs...); err != nil {
+		utils.LogAndRespError(c, err, "Could not replay rejected upload")
+		return
+	}
+
+	err = db.Model(&models.RejectedUpload{}).Where("inventory_id = ?", inventoryID).
+		Update("replayed", time.Now()).Error
+	if err != nil {
+		utils.LogAndRespError(c, err, "Could not update rejected upload")
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -4,10 +4,12 @@ import (
 	"app/base/core"
 	"app/base/database"
 	"app/base/models"
+	"app/base/mqueue"
 	"app/base/utils"
 	managerTestUtils "app/manager/controllers"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -70,3 +72,155 @@ func TestSystemDeleteUnknown(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func checkSystemsCount(t *testing.T, inventoryID string, count int64) {
+	var cnt int64
+	assert.NoError(t, database.DB.Model(&models.SystemInventory{}).Where("inventory_id = ?", inventoryID).
+		Count(&cnt).Error)
+	assert.Equal(t, count, cnt)
+}
+
+func TestSystemSoftDeleteRestore(t *testing.T) {
+	core.SetupTest(t)
+	database.SoftDeleteSystems = true
+	defer func() { database.SoftDeleteSystems = false }()
+
+	const archived = "99c0ffee-0000-0000-0000-000000000de2"
+	inv := models.SystemInventory{
+		InventoryID:   uuid.MustParse(archived),
+		RhAccountID:   1,
+		DisplayName:   archived,
+		Tags:          []byte("[]"),
+		WorkspaceID:   database.TestWorkspace1IDPtr(),
+		WorkspaceName: database.TestWorkspace1NamePtr(),
+	}
+	assert.NoError(t, database.DB.Create(&inv).Error)
+	assert.NoError(t, database.DB.Create(&models.SystemPatch{SystemID: inv.ID, RhAccountID: 1}).Error)
+	defer database.DB.Delete(&models.SystemArchive{}, "inventory_id = ?", archived)
+	defer database.DB.Exec("select delete_system(?)", archived)
+
+	w := managerTestUtils.CreateRequestRouterWithParams(
+		"DELETE", "/systems/:inventory_id", archived, "", nil, "", SystemDeleteHandler, 1,
+	)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var archive models.SystemArchive
+	assert.NoError(t, database.DB.Find(&archive, "inventory_id = ?", archived).Error)
+	assert.Equal(t, inv.ID, archive.SystemID)
+	checkSystemsCount(t, archived, 0)
+
+	w = managerTestUtils.CreateRequestRouterWithParams(
+		"PUT", "/systems/:inventory_id/restore", archived, "", nil, "", SystemRestoreHandler, 1,
+	)
+	assert.Equal(t, http.StatusOK, w.Code)
+	checkSystemsCount(t, archived, 1)
+
+	// system exists again
+	w = managerTestUtils.CreateRequestRouterWithParams(
+		"PUT", "/systems/:inventory_id/restore", archived, "", nil, "", SystemRestoreHandler, 1,
+	)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestSystemRestoreDeletedReferences(t *testing.T) {
+	core.SetupTest(t)
+	database.SoftDeleteSystems = true
+	defer func() { database.SoftDeleteSystems = false }()
+
+	const archived = "99c0ffee-0000-0000-0000-000000000de4"
+	inv := models.SystemInventory{
+		InventoryID:   uuid.MustParse(archived),
+		RhAccountID:   1,
+		DisplayName:   archived,
+		Tags:          []byte("[]"),
+		WorkspaceID:   database.TestWorkspace1IDPtr(),
+		WorkspaceName: database.TestWorkspace1NamePtr(),
+	}
+	template := models.Template{
+		TemplateBase:  models.TemplateBase{RhAccountID: 1, UUID: archived, Name: archived},
+		EnvironmentID: "99c0ffee000000000000000000000de4",
+		Arch:          "x86_64",
+		Version:       "8",
+	}
+	installable := models.Package{NameID: 101, EVRA: "0000.0.2-0.x86_64", Synced: false}
+	assert.NoError(t, database.DB.Create(&inv).Error)
+	assert.NoError(t, database.DB.Create(&template).Error)
+	assert.NoError(t, database.DB.Create(&installable).Error)
+	assert.NoError(t, database.DB.Create(&models.SystemPatch{SystemID: inv.ID, RhAccountID: 1,
+		TemplateID: &template.ID}).Error)
+	assert.NoError(t, database.DB.Create(&models.SystemPackage{RhAccountID: 1, SystemID: inv.ID, PackageID: 1,
+		NameID: 101, InstallableID: &installable.ID}).Error)
+	defer database.DB.Delete(&models.SystemArchive{}, "inventory_id = ?", archived)
+	defer database.DB.Exec("select delete_system(?)", archived)
+
+	w := managerTestUtils.CreateRequestRouterWithParams(
+		"DELETE", "/systems/:inventory_id", archived, "", nil, "", SystemDeleteHandler, 1,
+	)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// referenced rows are deleted while the system is archived
+	assert.NoError(t, database.DB.Delete(&template).Error)
+	assert.NoError(t, database.DB.Delete(&installable).Error)
+
+	w = managerTestUtils.CreateRequestRouterWithParams(
+		"PUT", "/systems/:inventory_id/restore", archived, "", nil, "", SystemRestoreHandler, 1,
+	)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var patch models.SystemPatch
+	assert.NoError(t, database.DB.Find(&patch, "rh_account_id = 1 AND system_id = ?", inv.ID).Error)
+	assert.Nil(t, patch.TemplateID)
+	var pkg models.SystemPackage
+	assert.NoError(t, database.DB.Find(&pkg, "rh_account_id = 1 AND system_id = ?", inv.ID).Error)
+	assert.Equal(t, int64(1), pkg.PackageID)
+	assert.Nil(t, pkg.InstallableID)
+}
+
+func TestSystemRestoreNotFound(t *testing.T) {
+	core.SetupTest(t)
+	w := managerTestUtils.CreateRequestRouterWithParams(
+		"PUT", "/systems/:inventory_id/restore", "99c0ffee-0000-0000-0000-000000000de3", "", nil, "",
+		SystemRestoreHandler, 1,
+	)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRejectedUploadReplay(t *testing.T) {
+	core.SetupTest(t)
+	mockWriter := mqueue.MockKafkaWriter{}
+	replayWriter = &mockWriter
+	defer func() { replayWriter = nil }()
+
+	const rejected = "99c0ffee-0000-0000-0000-0000000000e1"
+	assert.NoError(t, database.DB.Create(&models.RejectedUpload{
+		RhAccountID:   1,
+		InventoryID:   uuid.MustParse(rejected),
+		Reporter:      "puptoo",
+		Reason:        "malformed-packages",
+		Event:         []byte(`{"type": "created"}`),
+		FirstRejected: time.Now(),
+		LastRejected:  time.Now(),
+		RejectedCount: 1,
+	}).Error)
+	defer database.DB.Delete(&models.RejectedUpload{}, "inventory_id = ?", rejected)
+
+	w := managerTestUtils.CreateRequestRouterWithParams(
+		"PUT", "/systems/:inventory_id/replay", rejected, "", nil, "", RejectedUploadReplayHandler, 1,
+	)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, mockWriter.Messages, 1)
+	assert.JSONEq(t, `{"type": "created"}`, string(mockWriter.Messages[0].Value))
+
+	var upload models.RejectedUpload
+	assert.NoError(t, database.DB.Find(&upload, "inventory_id = ?", rejected).Error)
+	assert.NotNil(t, upload.Replayed)
+}
+
+func TestRejectedUploadReplayNotFound(t *testing.T) {
+	core.SetupTest(t)
+	w := managerTestUtils.CreateRequestRouterWithParams(
+		"PUT", "/systems/:inventory_id/replay", "99c0ffee-0000-0000-0000-0000000000e2", "", nil, "",
+		RejectedUploadReplayHandler, 1,
+	)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"app/base/database"
+	"app/base/utils"
+	"app/tasks"
+	"app/tasks/partitions"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Report table partitions
+// @Description Report sizes of partitions, skew of hash partitions and accounts suitable for dedicated partition
+// @ID partitionsReport
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    table_name path string true "Partitioned table" Enums(system_package2, system_advisories)
+// @Success 200 {object} partitions.Report
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} map[string]interface{}
+// @Router /database/partitions/{table_name} [get]
+func PartitionsReportHandler(c *gin.Context) {
+	table, err := partitions.GetTable(c.Param("table_name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse{Error: err.Error()})
+		return
+	}
+	report, err := partitions.GetReport(database.DB, table)
+	if err != nil {
+		utils.LogError("err", err, "Partition report failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Move account to dedicated partition
+// @Description Move rows of the account to dedicated list partition of its hash partition.
+// @Description The table is locked with ACCESS EXCLUSIVE lock until the rows are moved, run it offline
+// @Description in maintenance window with app components stopped.
+// @ID partitionsSplit
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    table_name path string true "Partitioned table" Enums(system_package2, system_advisories)
+// @Param    account    path int    true "rh_account_id"
+// @Success 200 {object} string
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} map[string]interface{}
+// @Router /database/partitions/{table_name}/split/{account} [put]
+func PartitionsSplitHandler(c *gin.Context) {
+	table, err := partitions.GetTable(c.Param("table_name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse{Error: err.Error()})
+		return
+	}
+	account, err := strconv.Atoi(c.Param("account"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse{Error: "invalid account"})
+		return
+	}
+	partition, err := partitions.SplitAccount(database.DB, table, account)
+	if err != nil {
+		utils.LogError("err", err, "account", account, "Partition split failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, fmt.Sprintf("account %d moved to %s", account, partition))
+}
+
+// repack started by admin API is running
+var partitionsRepackRunning atomic.Bool
+
+// @Summary Repack largest partitions
+// @Description Start repack of the largest partitions of managed tables within the time budget in background,
+// @Description results are logged
+// @ID partitionsRepack
+// @Security RhIdentity
+// @Accept   json
+// @Produce  json
+// @Param    budget_minutes query int false "Time budget, partition_repack_budget_minutes by default"
+// @Success 202 {object} string
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} map[string]interface{}
+// @Router /database/partitions/repack [put]
+func PartitionsRepackHandler(c *gin.Context) {
+	budget := tasks.PartitionRepackBudget
+	if param := c.Query("budget_minutes"); param != "" {
+		minutes, err := strconv.Atoi(param)
+		if err != nil || minutes <= 0 {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse{Error: "invalid budget_minutes"})
+			return
+		}
+		budget = time.Duration(minutes) * time.Minute
+	}
+	if !partitionsRepackRunning.CompareAndSwap(false, true) {
+		c.JSON(http.StatusConflict, utils.ErrorResponse{Error: "partition repack is already running"})
+		return
+	}
+	reports, err := partitions.Reports()
+	if err != nil {
+		partitionsRepackRunning.Store(false)
+		utils.LogError("err", err, "Partition report failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	go func() {
+		defer partitionsRepackRunning.Store(false)
+		defer utils.LogPanics(false)
+		results := partitions.RepackLargest(reports, budget)
+		utils.LogInfo("results", results, "Partition repack finished")
+	}()
+	c.JSON(http.StatusAccepted, fmt.Sprintf("repacking partitions within %s", budget))
+}
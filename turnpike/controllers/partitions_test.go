@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"app/base/core"
+	managerTestUtils "app/manager/controllers"
+	"app/tasks/partitions"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionsReport(t *testing.T) {
+	core.SetupTest(t)
+	w := managerTestUtils.CreateRequestRouterWithParams(
+		"GET", "/database/partitions/:table_name", "system_package2", "", nil, "", PartitionsReportHandler, 1,
+	)
+
+	var report partitions.Report
+	managerTestUtils.CheckResponse(t, w, http.StatusOK, &report)
+	assert.Equal(t, "system_package2", report.Table)
+	assert.Positive(t, report.Modulus)
+}
+
+func TestPartitionsReportUnknownTable(t *testing.T) {
+	core.SetupTest(t)
+	w := managerTestUtils.CreateRequestRouterWithParams(
+		"GET", "/database/partitions/:table_name", "system_platform", "", nil, "", PartitionsReportHandler, 1,
+	)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPartitionsRepackInvalidBudget(t *testing.T) {
+	core.SetupTest(t)
+	w := managerTestUtils.CreateRequestRouterWithParams(
+		"PUT", "/database/partitions/repack", "", "?budget_minutes=0", nil, "", PartitionsRepackHandler, 1,
+	)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPartitionsRepackRunning(t *testing.T) {
+	core.SetupTest(t)
+	partitionsRepackRunning.Store(true)
+	defer partitionsRepackRunning.Store(false)
+	w := managerTestUtils.CreateRequestRouterWithParams(
+		"PUT", "/database/partitions/repack", "", "", nil, "", PartitionsRepackHandler, 1,
+	)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
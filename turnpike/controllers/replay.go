@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"app/base/mqueue"
+	"app/base/utils"
+	"errors"
+	"sync"
+)
+
+var (
+	replayWriter     mqueue.Writer
+	replayWriterOnce sync.Once
+)
+
+// replay writer is created once, concurrent requests share it
+func getReplayWriter() (mqueue.Writer, error) {
+	replayWriterOnce.Do(func() {
+		if topic := utils.CoreCfg.ReplayTopic; topic != "" && replayWriter == nil {
+			replayWriter = mqueue.NewKafkaWriterFromEnv(topic)
+		}
+	})
+	if replayWriter == nil {
+		return nil, errors.New("REPLAY_TOPIC is not configured")
+	}
+	return replayWriter, nil
+}